@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/money"
+)
+
+const (
+	// maxImportSize bounds the files of product imports.
+	maxImportSize = 32 << 20
+	// maxImportLine bounds the lines of JSON Lines imports.
+	maxImportLine = 1 << 20
+	// exportPageSize is the number of products an export reads at a time.
+	exportPageSize = 100
+)
+
+// productColumns are the columns of catalog CSV files, in the order exports
+// write them. Imports accept any subset in any order, with a sku column; the
+// id column is ignored, products are matched by sku.
+var productColumns = []string{
+	"id", "sku", "name", "description", "price", "currency", "count_in_stock",
+	"category_id", "tax_category", "image", "weight", "length", "width", "height",
+}
+
+// importRow is a row of an imported file. err tells why the row cannot be
+// parsed, the other rows are still imported.
+type importRow struct {
+	line    int64
+	product ProductReq
+	err     error
+}
+
+// productReader reads the rows of an imported file. next returns io.EOF after
+// the last row, and other errors when the rest of the file cannot be read.
+type productReader interface {
+	next() (importRow, error)
+}
+
+// importProducts creates or updates the products of a CSV or JSON Lines file
+// by sku. The rows of existing products patch them as PATCH /products/{id}
+// does: empty cells and zero values keep the current values. The format is
+// the format query parameter, csv or jsonl, or else the Content-Type of the
+// body. With dry_run=true the rows are validated without being saved.
+func (h *handler) importProducts(w http.ResponseWriter, r *http.Request) {
+	q := queryParams{Values: r.URL.Query()}
+	dryRun := q.bool("dry_run")
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	var rows productReader
+	switch importFormat(r) {
+	case "csv":
+		cr, err := newCSVProducts(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rows = cr
+	case "jsonl":
+		rows = newJSONLProducts(r.Body)
+	default:
+		http.Error(w, "file must be CSV (text/csv) or JSON Lines (application/jsonl)", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	stream, err := h.client.ImportProducts(h.ctx)
+	if err != nil {
+		writeGRPCError(w, err, "error importing products")
+		return
+	}
+
+	var parseErrors []ImportRowError
+	for {
+		row, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// the rows sent so far are imported, the report tells where
+			// the file stopped being read
+			parseErrors = append(parseErrors, ImportRowError{Row: row.line, Error: fmt.Sprintf("error reading file: %v", err)})
+			break
+		}
+		if row.err != nil {
+			parseErrors = append(parseErrors, ImportRowError{Row: row.line, SKU: row.product.SKU, Error: row.err.Error()})
+			continue
+		}
+
+		err = stream.Send(&pb.ImportProductsReq{
+			Row:     row.line,
+			Product: toPBProductReq(row.product),
+			DryRun:  dryRun != nil && *dryRun,
+		})
+		if err != nil {
+			// the server ended the import, CloseAndRecv returns why
+			break
+		}
+	}
+
+	ipr, err := stream.CloseAndRecv()
+	if err != nil {
+		writeGRPCError(w, err, "error importing products")
+		return
+	}
+
+	res := ImportProductsRes{
+		DryRun:  ipr.GetDryRun(),
+		Created: ipr.GetCreated(),
+		Updated: ipr.GetUpdated(),
+		Errors:  parseErrors,
+	}
+	for _, e := range ipr.GetErrors() {
+		res.Errors = append(res.Errors, ImportRowError{Row: e.GetRow(), SKU: e.GetSku(), Error: e.GetError()})
+	}
+	slices.SortStableFunc(res.Errors, func(a, b ImportRowError) int { return cmp.Compare(a.Row, b.Row) })
+	res.Failed = len(res.Errors)
+	if res.Errors == nil {
+		res.Errors = []ImportRowError{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// importFormat returns the format of an imported file, csv or jsonl, or ""
+// if the request names none.
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "text/csv":
+		return "csv"
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return "jsonl"
+	}
+	return ""
+}
+
+// csvProducts reads the rows of a CSV file whose first line names its
+// columns.
+type csvProducts struct {
+	r       *csv.Reader
+	columns []string
+}
+
+func newCSVProducts(body io.Reader) (*csvProducts, error) {
+	r := csv.NewReader(body)
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %v", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheets save CSV files with a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(productColumns, name):
+			return nil, fmt.Errorf("unknown column %q", name)
+		case slices.Contains(columns[:i], name):
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[i] = name
+	}
+	if !slices.Contains(columns, "sku") {
+		return nil, errors.New("sku column is required")
+	}
+	r.ReuseRecord = true
+
+	return &csvProducts{r: r, columns: columns}, nil
+}
+
+func (c *csvProducts) next() (importRow, error) {
+	record, err := c.r.Read()
+	var pe *csv.ParseError
+	if errors.As(err, &pe) && errors.Is(pe.Err, csv.ErrFieldCount) {
+		row := importRow{line: int64(pe.StartLine), err: fmt.Errorf("expected %d fields, got %d", len(c.columns), len(record))}
+		if i := slices.Index(c.columns, "sku"); i < len(record) {
+			row.product.SKU = record[i]
+		}
+		return row, nil
+	}
+	if pe != nil {
+		return importRow{line: int64(pe.StartLine)}, pe.Err
+	}
+	if err != nil {
+		return importRow{}, err
+	}
+
+	line, _ := c.r.FieldPos(0)
+	row := importRow{line: int64(line)}
+	for i, value := range record {
+		err = setProductColumn(&row.product, c.columns[i], strings.TrimSpace(value))
+		if err != nil && row.err == nil {
+			row.err = err
+		}
+	}
+	return row, nil
+}
+
+// setProductColumn sets the field of a product of a CSV column. Empty cells
+// leave it zero.
+func setProductColumn(p *ProductReq, column, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+	switch column {
+	case "id":
+		// exported for reference only, rows are matched by sku
+	case "sku":
+		p.SKU = value
+	case "name":
+		p.Name = value
+	case "description":
+		p.Description = value
+	case "price":
+		p.Price, err = money.Parse(value)
+	case "currency":
+		p.Currency = value
+	case "count_in_stock":
+		p.CountInStock, err = strconv.ParseInt(value, 10, 64)
+	case "category_id":
+		p.CategoryID, err = strconv.ParseInt(value, 10, 64)
+	case "tax_category":
+		p.TaxCategory = value
+	case "image":
+		p.Image = value
+	case "weight":
+		p.Weight, err = strconv.ParseInt(value, 10, 64)
+	case "length":
+		p.Length, err = strconv.ParseInt(value, 10, 64)
+	case "width":
+		p.Width, err = strconv.ParseInt(value, 10, 64)
+	case "height":
+		p.Height, err = strconv.ParseInt(value, 10, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", column, value)
+	}
+	return nil
+}
+
+// jsonlProducts reads the rows of a JSON Lines file, one product object per
+// line. Blank lines are skipped.
+type jsonlProducts struct {
+	s    *bufio.Scanner
+	line int64
+}
+
+func newJSONLProducts(body io.Reader) *jsonlProducts {
+	s := bufio.NewScanner(body)
+	s.Buffer(make([]byte, 0, 64<<10), maxImportLine)
+	return &jsonlProducts{s: s}
+}
+
+func (j *jsonlProducts) next() (importRow, error) {
+	for j.s.Scan() {
+		j.line++
+		data := bytes.TrimSpace(j.s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := importRow{line: j.line}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&row.product)
+		switch {
+		case err != nil:
+			row.err = fmt.Errorf("invalid product: %v", err)
+		case dec.More():
+			row.err = errors.New("invalid product: line holds more than one value")
+		}
+		return row, nil
+	}
+
+	if err := j.s.Err(); err != nil {
+		return importRow{line: j.line + 1}, err
+	}
+	return importRow{}, io.EOF
+}
+
+// exportProducts streams every product as CSV or JSON Lines, as the format
+// query parameter asks, in a file importProducts reads back. Stocks of
+// products with variants and images of products with a gallery are derived
+// from them, and left empty.
+func (h *handler) exportProducts(w http.ResponseWriter, r *http.Request) {
+	format := cmp.Or(r.URL.Query().Get("format"), "csv")
+	if format != "csv" && format != "jsonl" {
+		http.Error(w, "format must be csv or jsonl", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		cw  = csv.NewWriter(w)
+		enc = json.NewEncoder(w)
+		rc  = http.NewResponseController(w)
+		req = &pb.ListProductsReq{PageSize: exportPageSize}
+	)
+	for page := 0; ; page++ {
+		lpr, err := h.client.ListProducts(h.ctx, req)
+		if err != nil && page == 0 {
+			writeGRPCError(w, err, "error exporting products")
+			return
+		}
+		if err != nil {
+			// the status is sent: abort the response so that the client
+			// does not take the truncated file for the whole catalog
+			log.Printf("error exporting products: %v", err)
+			panic(http.ErrAbortHandler)
+		}
+
+		if page == 0 {
+			w.Header().Set("Content-Type", map[string]string{"csv": "text/csv", "jsonl": "application/jsonl"}[format])
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=products.%s", format))
+			if format == "csv" {
+				cw.Write(productColumns)
+			}
+		}
+		for _, p := range lpr.GetProducts() {
+			if format == "csv" {
+				cw.Write(productRecord(toExportedProduct(p)))
+			} else {
+				enc.Encode(toExportedProduct(p))
+			}
+		}
+		cw.Flush()
+		rc.Flush()
+
+		if lpr.GetNextPageToken() == "" {
+			return
+		}
+		req.PageToken = lpr.GetNextPageToken()
+	}
+}
+
+func toExportedProduct(p *pb.ProductRes) ProductReq {
+	res := ProductReq{
+		ID:           p.GetId(),
+		SKU:          p.GetSku(),
+		Name:         p.GetName(),
+		Image:        p.GetImage(),
+		CategoryID:   p.GetCategoryId(),
+		TaxCategory:  p.GetTaxCategory(),
+		Description:  p.GetDescription(),
+		Price:        money.Amount(p.GetPrice()),
+		Currency:     p.GetCurrency(),
+		CountInStock: p.GetCountInStock(),
+		Weight:       p.GetWeight(),
+		Length:       p.GetLength(),
+		Width:        p.GetWidth(),
+		Height:       p.GetHeight(),
+	}
+	if len(p.GetVariants()) > 0 {
+		res.CountInStock = 0
+	}
+	if len(p.GetImages()) > 0 {
+		res.Image = ""
+	}
+
+	return res
+}
+
+// productRecord returns the cells of a product in the order of
+// productColumns.
+func productRecord(p ProductReq) []string {
+	optional := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	}
+
+	return []string{
+		strconv.FormatInt(p.ID, 10),
+		p.SKU,
+		p.Name,
+		p.Description,
+		p.Price.String(),
+		p.Currency,
+		optional(p.CountInStock),
+		optional(p.CategoryID),
+		p.TaxCategory,
+		p.Image,
+		optional(p.Weight),
+		optional(p.Length),
+		optional(p.Width),
+		optional(p.Height),
+	}
+}
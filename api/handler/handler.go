@@ -1,14 +1,23 @@
 package handler
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/imaging"
+	"github.com/niloy104/Conduit/payment"
 	"github.com/niloy104/Conduit/token"
 	"github.com/niloy104/Conduit/util"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -55,7 +64,7 @@ func (h *handler) getProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.client.GetProduct(h.ctx, &pb.ProductReq{Id: i})
+	product, err := h.client.GetProduct(h.ctx, &pb.ProductReq{Id: i, DisplayCurrency: displayCurrency(r)})
 	if err != nil {
 		http.Error(w, "error getting product", http.StatusInternalServerError)
 		return
@@ -68,50 +77,917 @@ func (h *handler) getProduct(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *handler) listProducts(w http.ResponseWriter, r *http.Request) {
-	lpr, err := h.client.ListProducts(h.ctx, &pb.ProductReq{})
+	h.writeProducts(w, r, r.URL.Query().Get("category"))
+}
+
+// listCategoryProducts lists the products of a category and of its
+// subcategories.
+func (h *handler) listCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	h.writeProducts(w, r, chi.URLParam(r, "slug"))
+}
+
+// writeProducts lists the products of the category with the slug, or every
+// product if it is empty, as the query parameters filter and sort them.
+func (h *handler) writeProducts(w http.ResponseWriter, r *http.Request, category string) {
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListProductsReq{
+		PageSize:  q.int32("page_size"),
+		PageToken: q.Get("page_token"),
+		SortBy:    q.Get("sort"),
+		Category:  category,
+		MinPrice:  q.amount("min_price"),
+		MaxPrice:  q.amount("max_price"),
+
+		DisplayCurrency: displayCurrency(r),
+	}
+	if inStock := q.bool("in_stock"); inStock != nil {
+		req.InStock = *inStock
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lpr, err := h.client.ListProducts(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error listing products")
+		return
+	}
+
+	res := ListProductsRes{
+		Products:      make([]ProductRes, 0, len(lpr.GetProducts())),
+		NextPageToken: lpr.GetNextPageToken(),
+	}
+	for _, p := range lpr.GetProducts() {
+		res.Products = append(res.Products, toProductRes(p))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) searchProducts(w http.ResponseWriter, r *http.Request) {
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.SearchProductsReq{
+		Query:           q.Get("q"),
+		PageSize:        q.int32("page_size"),
+		PageToken:       q.Get("page_token"),
+		DisplayCurrency: displayCurrency(r),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spr, err := h.client.SearchProducts(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error searching products")
+		return
+	}
+
+	res := SearchProductsRes{
+		Results:       make([]ProductMatchRes, 0, len(spr.GetMatches())),
+		NextPageToken: spr.GetNextPageToken(),
+	}
+	for _, m := range spr.GetMatches() {
+		res.Results = append(res.Results, ProductMatchRes{
+			Product: toProductRes(m.GetProduct()),
+			Score:   m.GetScore(),
+			Snippet: m.GetSnippet(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var p ProductReq
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+	p.ID = i
+
+	updated, err := h.client.UpdateProduct(h.ctx, toPBProductReq(p))
+	if err != nil {
+		writeGRPCError(w, err, "error updating product")
+		return
+	}
+
+	res := toProductRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteProduct(h.ctx, &pb.ProductReq{Id: i})
+	if err != nil {
+		http.Error(w, "error deleting product", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createVariant(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var v VariantReq
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	req := toPBVariantReq(v)
+	req.ProductId = i
+	created, err := h.client.CreateVariant(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error creating variant")
+		return
+	}
+
+	res := toVariantRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateVariant(w http.ResponseWriter, r *http.Request) {
+	req, ok := variantPath(w, r)
+	if !ok {
+		return
+	}
+
+	var v VariantReq
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	patch := toPBVariantReq(v)
+	patch.Id, patch.ProductId = req.Id, req.ProductId
+	updated, err := h.client.UpdateVariant(h.ctx, patch)
+	if err != nil {
+		writeGRPCError(w, err, "error updating variant")
+		return
+	}
+
+	res := toVariantRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// deleteVariant deletes a variant that was never ordered.
+func (h *handler) deleteVariant(w http.ResponseWriter, r *http.Request) {
+	req, ok := variantPath(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := h.client.DeleteVariant(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error deleting variant")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// variantPath parses the product and variant IDs of the path, writing a bad
+// request if either is not a number.
+func variantPath(w http.ResponseWriter, r *http.Request) (*pb.VariantReq, bool) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return nil, false
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "variant_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing variant ID", http.StatusBadRequest)
+		return nil, false
+	}
+	return &pb.VariantReq{Id: id, ProductId: productID}, true
+}
+
+// maxUploadSize bounds the body of image uploads: an image of at most
+// imaging.MaxSize bytes and the multipart headers around it.
+const maxUploadSize = imaging.MaxSize + 64<<10
+
+// uploadProductImage adds the image of the "image" field of a multipart form
+// to the gallery of a product.
+func (h *handler) uploadProductImage(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("image")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("image must be at most %d bytes", imaging.MaxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "error reading image: expected a multipart form with an image field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	contentType, _, _ := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if !imaging.Supported(contentType) {
+		http.Error(w, "image must be a JPEG, PNG or GIF", http.StatusUnsupportedMediaType)
+		return
+	}
+	if header.Size > imaging.MaxSize {
+		http.Error(w, fmt.Sprintf("image must be at most %d bytes", imaging.MaxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "error reading image", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.client.AddProductImage(h.ctx, &pb.ProductImageReq{ProductId: i, Data: data})
+	if err != nil {
+		writeGRPCError(w, err, "error uploading image")
+		return
+	}
+
+	res := toImageRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteProductImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "image_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing image ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteProductImage(h.ctx, &pb.ProductImageReq{Id: id, ProductId: productID})
+	if err != nil {
+		writeGRPCError(w, err, "error deleting image")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reorderProductImages orders the gallery of a product and returns the
+// product.
+func (h *handler) reorderProductImages(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var o ReorderImagesReq
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.client.ReorderProductImages(h.ctx, &pb.ReorderProductImagesReq{ProductId: i, ImageIds: o.ImageIDs})
+	if err != nil {
+		writeGRPCError(w, err, "error reordering images")
+		return
+	}
+
+	res := toProductRes(p)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) createCategory(w http.ResponseWriter, r *http.Request) {
+	var c CategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.client.CreateCategory(h.ctx, toPBCategoryReq(c))
+	if err != nil {
+		writeGRPCError(w, err, "error creating category")
+		return
+	}
+
+	res := toCategoryRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		writeGRPCError(w, err, "error getting category")
+		return
+	}
+
+	res := toCategoryRes(category)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) listCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.client.ListCategories(h.ctx, &pb.ListCategoriesReq{})
+	if err != nil {
+		writeGRPCError(w, err, "error listing categories")
+		return
+	}
+
+	res := ListCategoriesRes{Categories: make([]CategoryRes, 0, len(categories.GetCategories()))}
+	for _, c := range categories.GetCategories() {
+		res.Categories = append(res.Categories, toCategoryRes(c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// updateCategory updates the category with the slug of the path, which the
+// request may change.
+func (h *handler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	var c CategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		writeGRPCError(w, err, "error getting category")
+		return
+	}
+
+	req := toPBCategoryReq(c)
+	req.Id = category.GetId()
+	updated, err := h.client.UpdateCategory(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error updating category")
+		return
+	}
+
+	res := toCategoryRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// deleteCategory deletes a category without subcategories, products or
+// coupons.
+func (h *handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.client.GetCategory(h.ctx, &pb.CategoryReq{Slug: chi.URLParam(r, "slug")})
+	if err != nil {
+		writeGRPCError(w, err, "error getting category")
+		return
+	}
+
+	_, err = h.client.DeleteCategory(h.ctx, &pb.CategoryReq{Id: category.GetId()})
+	if err != nil {
+		writeGRPCError(w, err, "error deleting category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createReview(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var rr ReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&rr); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.client.CreateReview(h.ctx, &pb.ReviewReq{
+		ProductId: i,
+		UserId:    claims.ID,
+		Rating:    rr.Rating,
+		Comment:   rr.Comment,
+	})
+	if err != nil {
+		writeGRPCError(w, err, "error creating review")
+		return
+	}
+
+	res := toReviewRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+// listProductReviews lists the published reviews of a product.
+func (h *handler) listProductReviews(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	q := queryParams{Values: r.URL.Query()}
+	published := pb.ReviewStatus_PUBLISHED
+	req := &pb.ListReviewsReq{
+		ProductId: i,
+		PageSize:  q.int32("page_size"),
+		PageToken: q.Get("page_token"),
+		SortBy:    q.Get("sort"),
+		Status:    &published,
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeReviews(w, req)
+}
+
+// listReviews lists the reviews of every product, hidden ones included, for
+// moderation.
+func (h *handler) listReviews(w http.ResponseWriter, r *http.Request) {
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListReviewsReq{
+		ProductId: q.int64("product_id"),
+		PageSize:  q.int32("page_size"),
+		PageToken: q.Get("page_token"),
+		SortBy:    q.Get("sort"),
+		Status:    q.reviewStatus("status"),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeReviews(w, req)
+}
+
+func (h *handler) writeReviews(w http.ResponseWriter, req *pb.ListReviewsReq) {
+	reviews, err := h.client.ListReviews(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error listing reviews")
+		return
+	}
+
+	res := ListReviewsRes{
+		Reviews:       make([]ReviewRes, 0, len(reviews.GetReviews())),
+		NextPageToken: reviews.GetNextPageToken(),
+	}
+	for _, rr := range reviews.GetReviews() {
+		res.Reviews = append(res.Reviews, toReviewRes(rr))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) moderateReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var rr ReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&rr); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	status, err := toPBReviewStatus(ReviewStatus(rr.Status))
+	if err != nil {
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.client.ModerateReview(h.ctx, &pb.ReviewReq{Id: i, Status: status})
+	if err != nil {
+		writeGRPCError(w, err, "error moderating review")
+		return
+	}
+
+	res := toReviewRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteReview(h.ctx, &pb.ReviewReq{Id: i})
+	if err != nil {
+		writeGRPCError(w, err, "error deleting review")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) createOrder(w http.ResponseWriter, r *http.Request) {
+	var o OrderReq
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+	po := toPBOrderReq(o)
+	po.UserId = claims.ID
+	po.UserEmail = claims.Email
+	po.Currency = cmp.Or(po.Currency, displayCurrency(r))
+
+	created, err := h.client.CreateOrder(h.ctx, po)
+	if err != nil {
+		writeGRPCError(w, err, "internal server error")
+		return
+	}
+
+	res := toOrderRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) createCoupon(w http.ResponseWriter, r *http.Request) {
+	var c CouponReq
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	req, err := toPBCouponReq(c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.client.CreateCoupon(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error creating coupon")
+		return
+	}
+
+	res := toCouponRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getCoupon(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	coupon, err := h.client.GetCoupon(h.ctx, &pb.CouponReq{Id: i})
+	if err != nil {
+		writeGRPCError(w, err, "error getting coupon")
+		return
+	}
+
+	res := toCouponRes(coupon)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) listCoupons(w http.ResponseWriter, r *http.Request) {
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListCouponsReq{
+		PageSize:  q.int32("page_size"),
+		PageToken: q.Get("page_token"),
+		SortBy:    q.Get("sort"),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	coupons, err := h.client.ListCoupons(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error listing coupons")
+		return
+	}
+
+	res := ListCouponsRes{
+		Coupons:       make([]CouponRes, 0, len(coupons.GetCoupons())),
+		NextPageToken: coupons.GetNextPageToken(),
+	}
+	for _, c := range coupons.GetCoupons() {
+		res.Coupons = append(res.Coupons, toCouponRes(c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateCoupon(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var c CouponReq
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	req, err := toPBCouponReq(c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Id = i
+
+	updated, err := h.client.UpdateCoupon(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error updating coupon")
+		return
+	}
+
+	res := toCouponRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteCoupon(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteCoupon(h.ctx, &pb.CouponReq{Id: i})
+	if err != nil {
+		writeGRPCError(w, err, "error deleting coupon")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cartTokenHeader carries the token of the cart of a guest.
+const cartTokenHeader = "X-Cart-Token"
+
+// cartOwner returns the user of an authenticated request, or else the cart
+// token of the guest.
+func cartOwner(r *http.Request) (int64, string) {
+	if claims, ok := r.Context().Value(authKey{}).(*token.UserClaims); ok {
+		return claims.ID, ""
+	}
+	return 0, r.Header.Get(cartTokenHeader)
+}
+
+// displayCurrency returns the currency a client asked prices in, from the
+// currency query parameter or else the first one in the Accept-Currency
+// header. It is empty if the client did not ask for one.
+func displayCurrency(r *http.Request) string {
+	if c := r.URL.Query().Get("currency"); c != "" {
+		return c
+	}
+	c, _, _ := strings.Cut(r.Header.Get("Accept-Currency"), ",")
+	c, _, _ = strings.Cut(c, ";")
+	return strings.TrimSpace(c)
+}
+
+func (h *handler) getCart(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
+
+	cart, err := h.client.GetCart(h.ctx, &pb.CartReq{UserId: userID, CartToken: cartToken, DisplayCurrency: displayCurrency(r)})
+	if err != nil {
+		writeGRPCError(w, err, "error getting cart")
+		return
+	}
+
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *handler) addCartItem(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
+
+	var ci CartItemReq
+	if err := json.NewDecoder(r.Body).Decode(&ci); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.AddCartItem(h.ctx, &pb.CartItemReq{
+		UserId:          userID,
+		CartToken:       cartToken,
+		ProductId:       ci.ProductID,
+		VariantId:       ci.VariantID,
+		Quantity:        ci.Quantity,
+		DisplayCurrency: displayCurrency(r),
+	})
+	if err != nil {
+		writeGRPCError(w, err, "error adding cart item")
+		return
+	}
+
+	writeCart(w, http.StatusCreated, cart)
+}
+
+func (h *handler) updateCartItem(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
+
+	id := chi.URLParam(r, "product_id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing product ID", http.StatusBadRequest)
+		return
+	}
+
+	q := queryParams{Values: r.URL.Query()}
+	variantID := q.int64("variant_id")
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ci CartItemReq
+	if err := json.NewDecoder(r.Body).Decode(&ci); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.UpdateCartItem(h.ctx, &pb.CartItemReq{
+		UserId:          userID,
+		CartToken:       cartToken,
+		ProductId:       i,
+		VariantId:       variantID,
+		Quantity:        ci.Quantity,
+		DisplayCurrency: displayCurrency(r),
+	})
+	if err != nil {
+		writeGRPCError(w, err, "error updating cart item")
+		return
+	}
+
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *handler) removeCartItem(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
+
+	id := chi.URLParam(r, "product_id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing product ID", http.StatusBadRequest)
+		return
+	}
+
+	q := queryParams{Values: r.URL.Query()}
+	variantID := q.int64("variant_id")
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cart, err := h.client.RemoveCartItem(h.ctx, &pb.CartItemReq{UserId: userID, CartToken: cartToken, ProductId: i, VariantId: variantID, DisplayCurrency: displayCurrency(r)})
+	if err != nil {
+		writeGRPCError(w, err, "error removing cart item")
+		return
+	}
+
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *handler) clearCart(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
+
+	cart, err := h.client.ClearCart(h.ctx, &pb.CartReq{UserId: userID, CartToken: cartToken, DisplayCurrency: displayCurrency(r)})
 	if err != nil {
-		http.Error(w, "error listing products", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error clearing cart")
+		return
+	}
+
+	writeCart(w, http.StatusOK, cart)
+}
+
+func writeCart(w http.ResponseWriter, code int, cart *pb.CartRes) {
+	res := toCartRes(cart)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) checkout(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	var c CheckoutReq
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
 		return
 	}
 
-	var res []ProductRes
-	for _, p := range lpr.GetProducts() {
-		res = append(res, toProductRes(p))
+	created, err := h.client.Checkout(h.ctx, &pb.CheckoutReq{
+		UserId:         claims.ID,
+		UserEmail:      claims.Email,
+		PaymentMethod:  c.PaymentMethod,
+		CouponCode:     c.CouponCode,
+		Currency:       cmp.Or(c.Currency, displayCurrency(r)),
+		AddressId:      c.AddressID,
+		ShippingMethod: c.ShippingMethod,
+	})
+	if err != nil {
+		writeGRPCError(w, err, "error checking out cart")
+		return
 	}
 
+	res := toOrderRes(created)
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) updateProduct(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	i, err := strconv.ParseInt(id, 10, 64)
-	if err != nil {
-		http.Error(w, "error parsing ID", http.StatusBadRequest)
-		return
-	}
+// quoteShipping returns the shipping options for the cart of the user or
+// guest, priced in the display currency.
+func (h *handler) quoteShipping(w http.ResponseWriter, r *http.Request) {
+	userID, cartToken := cartOwner(r)
 
-	var p ProductReq
-	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+	var q ShippingQuoteReq
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
 		http.Error(w, "error decoding request body", http.StatusBadRequest)
 		return
 	}
-	p.ID = i
 
-	updated, err := h.client.UpdateProduct(h.ctx, toPBProductReq(p))
+	quote, err := h.client.QuoteShipping(h.ctx, &pb.ShippingQuoteReq{
+		UserId:          userID,
+		CartToken:       cartToken,
+		AddressId:       q.AddressID,
+		Country:         q.Country,
+		Region:          q.Region,
+		PostalCode:      q.PostalCode,
+		DisplayCurrency: displayCurrency(r),
+	})
 	if err != nil {
-		http.Error(w, "error updating product", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error quoting shipping")
 		return
 	}
 
-	res := toProductRes(updated)
+	res := toShippingQuoteRes(quote)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) deleteProduct(w http.ResponseWriter, r *http.Request) {
+func (h *handler) getOrder(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
 	id := chi.URLParam(r, "id")
 	i, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
@@ -119,113 +995,288 @@ func (h *handler) deleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.client.DeleteProduct(h.ctx, &pb.ProductReq{Id: i})
+	order, err := h.client.GetOrder(h.ctx, &pb.OrderReq{
+		Id:      i,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
 	if err != nil {
-		http.Error(w, "error deleting product", http.StatusInternalServerError)
+		writeGRPCError(w, err, "internal server error")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	res := toOrderRes(order)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) createOrder(w http.ResponseWriter, r *http.Request) {
+func (h *handler) listMyOrders(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListUserOrdersReq{
+		UserId:        claims.ID,
+		PageSize:      q.int32("page_size"),
+		PageToken:     q.Get("page_token"),
+		SortBy:        q.Get("sort"),
+		Status:        q.orderStatus("status"),
+		CreatedAfter:  q.time("created_after"),
+		CreatedBefore: q.time("created_before"),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, err := h.client.ListUserOrders(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "internal server error")
+		return
+	}
+
+	writeOrders(w, orders)
+}
+
+func (h *handler) listOrders(w http.ResponseWriter, r *http.Request) {
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListOrdersReq{
+		PageSize:      q.int32("page_size"),
+		PageToken:     q.Get("page_token"),
+		SortBy:        q.Get("sort"),
+		UserId:        q.int64("user_id"),
+		Status:        q.orderStatus("status"),
+		CreatedAfter:  q.time("created_after"),
+		CreatedBefore: q.time("created_before"),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, err := h.client.ListOrders(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "internal server error")
+		return
+	}
+
+	writeOrders(w, orders)
+}
+
+func writeOrders(w http.ResponseWriter, orders *pb.ListOrderRes) {
+	res := ListOrdersRes{
+		Orders:        make([]OrderRes, 0, len(orders.GetOrders())),
+		NextPageToken: orders.GetNextPageToken(),
+	}
+	for _, o := range orders.GetOrders() {
+		res.Orders = append(res.Orders, toOrderRes(o))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
 	var o OrderReq
 	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
 		return
 	}
 
-	claims := r.Context().Value(authKey{}).(*token.UserClaims)
-	po := toPBOrderReq(o)
-	po.UserId = claims.ID
-	po.UserEmail=claims.Email
+	status, err := toPBOrderStatus(OrderStatus(o.Status))
+	if err != nil {
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return
+	}
 
-	created, err := h.client.CreateOrder(h.ctx, po)
+	res, err := h.client.UpdateOrderStatus(h.ctx, &pb.OrderReq{
+		Id:        o.ID,
+		UserId:    claims.ID,
+		UserEmail: claims.Email,
+		IsAdmin:   claims.IsAdmin,
+		Status:    status,
+	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeGRPCError(w, err, "failed to update order status")
 		return
 	}
 
-	res := toOrderRes(created)
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) getOrder(w http.ResponseWriter, r *http.Request) {
+func (h *handler) cancelOrder(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(authKey{}).(*token.UserClaims)
 
-	order, err := h.client.GetOrder(h.ctx, &pb.OrderReq{
-		UserId: claims.ID,
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	cancelled, err := h.client.CancelOrder(h.ctx, &pb.OrderReq{
+		Id:        i,
+		UserId:    claims.ID,
+		UserEmail: claims.Email,
+		IsAdmin:   claims.IsAdmin,
 	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeGRPCError(w, err, "failed to cancel order")
 		return
 	}
 
-	res := toOrderRes(order)
+	res := toOrderRes(cancelled)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) listOrders(w http.ResponseWriter, r *http.Request) {
-	orders, err := h.client.ListOrders(h.ctx, &pb.OrderReq{})
+func (h *handler) listOrderStatusHistory(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.client.ListOrderStatusHistory(h.ctx, &pb.OrderReq{
+		Id:      i,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error listing order status history")
 		return
 	}
 
-	var res []OrderRes
-	for _, o := range orders.Orders {
-		res = append(res, toOrderRes(o))
+	res := make([]OrderStatusChangeRes, 0, len(history.GetChanges()))
+	for _, c := range history.GetChanges() {
+		res = append(res, toOrderStatusChangeRes(c))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(res)
 }
 
-func (h *handler) updateOrderStatus(w http.ResponseWriter, r *http.Request) {
+// getInvoice returns the invoice of a paid order as JSON, or as a PDF file
+// when asked for with ?format=pdf or an Accept header of application/pdf.
+func (h *handler) getInvoice(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(authKey{}).(*token.UserClaims)
 
-	var o OrderReq
-	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
-		http.Error(w, "error decoding request body", http.StatusBadRequest)
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
 		return
 	}
 
-	status, err := toPBOrderStatus(OrderStatus(o.Status))
+	res, err := h.client.GetInvoice(h.ctx, &pb.OrderReq{
+		Id:      i,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+	})
 	if err != nil {
-		http.Error(w, "invalid status", http.StatusBadRequest)
+		writeGRPCError(w, err, "error getting invoice")
 		return
 	}
 
-	res, err := h.client.UpdateOrderStatus(h.ctx, &pb.OrderReq{
-		Id:        o.ID,
-		UserId:    claims.ID,
-		UserEmail: claims.Email,
-		Status:    status,
+	inv := toInvoice(res)
+	if r.URL.Query().Get("format") == "pdf" || strings.Contains(r.Header.Get("Accept"), "application/pdf") {
+		var buf bytes.Buffer
+		err = inv.WritePDF(&buf)
+		if err != nil {
+			http.Error(w, "error rendering invoice", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", inv.Filename()))
+		w.Write(buf.Bytes())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(inv)
+}
+
+func (h *handler) createPayment(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	// the payment method is optional, so is the body
+	var pr PaymentReq
+	if err := json.NewDecoder(r.Body).Decode(&pr); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.client.CreatePayment(h.ctx, &pb.PaymentReq{
+		OrderId: i,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
+		Method:  pr.Method,
 	})
 	if err != nil {
-		http.Error(w, "failed to update order status", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error creating payment")
 		return
 	}
 
+	res := toPaymentRes(created)
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(res)
 }
 
+// maxWebhookSize bounds the payload of payment webhooks.
+const maxWebhookSize = 64 << 10
+
+// paymentWebhook receives the webhooks of the payment provider, which are
+// authenticated by their signature rather than a token.
+func (h *handler) paymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.HandlePaymentWebhook(h.ctx, &pb.PaymentWebhookReq{
+		Payload:   payload,
+		Signature: r.Header.Get(payment.SignatureHeader),
+	})
+	if err != nil {
+		writeGRPCError(w, err, "error handling payment webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *handler) deleteOrder(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
 	id := chi.URLParam(r, "id")
 	i, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		panic(err)
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
 	}
 
 	_, err = h.client.DeleteOrder(h.ctx, &pb.OrderReq{
-		Id: i,
+		Id:      i,
+		UserId:  claims.ID,
+		IsAdmin: claims.IsAdmin,
 	})
 	if err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error deleting order")
 		return
 	}
 
@@ -260,13 +1311,30 @@ func (h *handler) createUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *handler) listUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.client.ListUsers(h.ctx, &pb.UserReq{})
+	q := queryParams{Values: r.URL.Query()}
+	req := &pb.ListUsersReq{
+		PageSize:      q.int32("page_size"),
+		PageToken:     q.Get("page_token"),
+		SortBy:        q.Get("sort"),
+		IsAdmin:       q.bool("is_admin"),
+		CreatedAfter:  q.time("created_after"),
+		CreatedBefore: q.time("created_before"),
+	}
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, err := h.client.ListUsers(h.ctx, req)
 	if err != nil {
-		http.Error(w, "error listing users", http.StatusInternalServerError)
+		writeGRPCError(w, err, "error listing users")
 		return
 	}
 
-	var res ListUserRes
+	res := ListUserRes{
+		Users:         make([]UserRes, 0, len(users.GetUsers())),
+		NextPageToken: users.GetNextPageToken(),
+	}
 	for _, u := range users.GetUsers() {
 		res.Users = append(res.Users, toUserRes(u))
 	}
@@ -316,6 +1384,118 @@ func (h handler) deleteUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *handler) createAddress(w http.ResponseWriter, r *http.Request) {
+	var a AddressReq
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+	req := toPBAddressReq(a)
+	req.UserId = claims.ID
+
+	created, err := h.client.CreateAddress(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error creating address")
+		return
+	}
+
+	res := toAddressRes(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) listAddresses(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	addresses, err := h.client.ListAddresses(h.ctx, &pb.AddressReq{UserId: claims.ID})
+	if err != nil {
+		writeGRPCError(w, err, "error listing addresses")
+		return
+	}
+
+	res := ListAddressesRes{Addresses: make([]AddressRes, 0, len(addresses.GetAddresses()))}
+	for _, a := range addresses.GetAddresses() {
+		res.Addresses = append(res.Addresses, toAddressRes(a))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) getAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	address, err := h.client.GetAddress(h.ctx, &pb.AddressReq{Id: i, UserId: claims.ID})
+	if err != nil {
+		writeGRPCError(w, err, "error getting address")
+		return
+	}
+
+	res := toAddressRes(address)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) updateAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	var a AddressReq
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		http.Error(w, "error decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	req := toPBAddressReq(a)
+	req.Id = i
+	req.UserId = claims.ID
+
+	updated, err := h.client.UpdateAddress(h.ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "error updating address")
+		return
+	}
+
+	res := toAddressRes(updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *handler) deleteAddress(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(authKey{}).(*token.UserClaims)
+
+	id := chi.URLParam(r, "id")
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.client.DeleteAddress(h.ctx, &pb.AddressReq{Id: i, UserId: claims.ID})
+	if err != nil {
+		writeGRPCError(w, err, "error deleting address")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *handler) loginUser(w http.ResponseWriter, r *http.Request) {
 	var u LoginUserReq
 	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
@@ -337,6 +1517,15 @@ func (h *handler) loginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// carry the cart built while browsing as a guest over to the user
+	if cartToken := r.Header.Get(cartTokenHeader); cartToken != "" {
+		_, err = h.client.MergeCart(h.ctx, &pb.MergeCartReq{UserId: ur.GetId(), CartToken: cartToken})
+		if err != nil {
+			writeGRPCError(w, err, "error merging cart")
+			return
+		}
+	}
+
 	// create a json web token (JWT) and return it as response
 	accessToken, accessClaims, err := h.TokenMaker.CreateToken(ur.GetId(), ur.GetEmail(), ur.GetIsAdmin(), 15*time.Minute)
 	if err != nil {
@@ -2,45 +2,150 @@ package handler
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/invoice"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func toPBProductReq(p ProductReq) *pb.ProductReq {
 	return &pb.ProductReq{
 		Id:           p.ID,
+		Sku:          p.SKU,
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		CategoryId:   p.CategoryID,
+		TaxCategory:  p.TaxCategory,
 		Description:  p.Description,
-		Rating:       p.Rating,
-		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Price:        int64(p.Price),
+		Currency:     p.Currency,
 		CountInStock: p.CountInStock,
+		Weight:       p.Weight,
+		Length:       p.Length,
+		Width:        p.Width,
+		Height:       p.Height,
 	}
 }
 
 func toProductRes(p *pb.ProductRes) ProductRes {
-	return ProductRes{
+	res := ProductRes{
+		ID:           p.Id,
+		SKU:          p.Sku,
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		CategoryID:   p.CategoryId,
+		TaxCategory:  p.TaxCategory,
 		Description:  p.Description,
 		Rating:       p.Rating,
 		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Price:        money.Amount(p.Price),
+		Currency:     p.Currency,
 		CountInStock: p.CountInStock,
+		Weight:       p.Weight,
+		Length:       p.Length,
+		Width:        p.Width,
+		Height:       p.Height,
+	}
+	for _, v := range p.GetVariants() {
+		res.Variants = append(res.Variants, toVariantRes(v))
+	}
+	for _, img := range p.GetImages() {
+		res.Images = append(res.Images, toImageRes(img))
+	}
+
+	return res
+}
+
+func toPBVariantReq(v VariantReq) *pb.VariantReq {
+	req := &pb.VariantReq{
+		Sku:          v.SKU,
+		Options:      v.Options,
+		CountInStock: v.CountInStock,
+	}
+	if v.Price != nil {
+		price := int64(*v.Price)
+		req.Price = &price
+	}
+
+	return req
+}
+
+func toVariantRes(v *pb.VariantRes) VariantRes {
+	res := VariantRes{
+		ID:           v.GetId(),
+		ProductID:    v.GetProductId(),
+		SKU:          v.GetSku(),
+		Options:      v.GetOptions(),
+		CountInStock: v.GetCountInStock(),
+		CreatedAt:    v.GetCreatedAt().AsTime(),
+	}
+	if v.Price != nil {
+		price := money.Amount(v.GetPrice())
+		res.Price = &price
+	}
+	if v.GetUpdatedAt() != nil {
+		updatedAt := v.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
+func toImageRes(img *pb.ProductImageRes) ImageRes {
+	return ImageRes{
+		ID:           img.GetId(),
+		ProductID:    img.GetProductId(),
+		URL:          img.GetUrl(),
+		ThumbnailURL: img.GetThumbnailUrl(),
+		ContentType:  img.GetContentType(),
+		Size:         img.GetSize(),
+		Width:        img.GetWidth(),
+		Height:       img.GetHeight(),
+		Position:     img.GetPosition(),
+		CreatedAt:    img.GetCreatedAt().AsTime(),
+	}
+}
+
+func toPBCategoryReq(c CategoryReq) *pb.CategoryReq {
+	return &pb.CategoryReq{
+		Name:     c.Name,
+		Slug:     c.Slug,
+		ParentId: c.ParentID,
+	}
+}
+
+func toCategoryRes(c *pb.CategoryRes) CategoryRes {
+	res := CategoryRes{
+		ID:        c.GetId(),
+		ParentID:  c.GetParentId(),
+		Name:      c.GetName(),
+		Slug:      c.GetSlug(),
+		CreatedAt: c.GetCreatedAt().AsTime(),
 	}
+	if c.GetUpdatedAt() != nil {
+		updatedAt := c.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
 }
 
 func toPBOrderReq(o OrderReq) *pb.OrderReq {
 	return &pb.OrderReq{
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Items:         toPBOrderItems(o.Items),
+		PaymentMethod:  o.PaymentMethod,
+		TaxPrice:       int64(o.TaxPrice),
+		ShippingPrice:  int64(o.ShippingPrice),
+		TotalPrice:     int64(o.TotalPrice),
+		Items:          toPBOrderItems(o.Items),
+		CouponCode:     o.CouponCode,
+		Currency:       o.Currency,
+		AddressId:      o.AddressID,
+		ShippingMethod: o.ShippingMethod,
 	}
 }
 
@@ -51,8 +156,9 @@ func toPBOrderItems(oi []*OrderItem) []*pb.OrderItem {
 			Name:      i.Name,
 			Quantity:  i.Quantity,
 			Image:     i.Image,
-			Price:     i.Price,
+			Price:     int64(i.Price),
 			ProductId: i.ProductID,
+			VariantId: i.VariantID,
 		})
 	}
 	return res
@@ -61,34 +167,274 @@ func toPBOrderItems(oi []*OrderItem) []*pb.OrderItem {
 type OrderStatus string
 
 const (
-	Pending   OrderStatus = "pending"
-	Shipped   OrderStatus = "shipped"
-	Delivered OrderStatus = "delivered"
+	Pending    OrderStatus = "pending"
+	Paid       OrderStatus = "paid"
+	Processing OrderStatus = "processing"
+	Shipped    OrderStatus = "shipped"
+	Delivered  OrderStatus = "delivered"
+	Cancelled  OrderStatus = "cancelled"
+	Refunded   OrderStatus = "refunded"
+	Returned   OrderStatus = "returned"
 )
 
 func toPBOrderStatus(s OrderStatus) (pb.OrderStatus, error) {
 	switch s {
 	case Pending:
 		return pb.OrderStatus_PENDING, nil
+	case Paid:
+		return pb.OrderStatus_PAID, nil
+	case Processing:
+		return pb.OrderStatus_PROCESSING, nil
 	case Shipped:
 		return pb.OrderStatus_SHIPPED, nil
 	case Delivered:
 		return pb.OrderStatus_DELIVERED, nil
+	case Cancelled:
+		return pb.OrderStatus_CANCELLED, nil
+	case Refunded:
+		return pb.OrderStatus_REFUNDED, nil
+	case Returned:
+		return pb.OrderStatus_RETURNED, nil
 	default:
 		return 0, fmt.Errorf("unknown order status: %s", s)
 	}
 }
 
+type ReviewStatus string
+
+const (
+	ReviewPublished ReviewStatus = "published"
+	ReviewHidden    ReviewStatus = "hidden"
+)
+
+func toPBReviewStatus(s ReviewStatus) (pb.ReviewStatus, error) {
+	switch s {
+	case ReviewPublished:
+		return pb.ReviewStatus_PUBLISHED, nil
+	case ReviewHidden:
+		return pb.ReviewStatus_HIDDEN, nil
+	default:
+		return 0, fmt.Errorf("unknown review status: %s", s)
+	}
+}
+
+func toReviewRes(r *pb.ReviewRes) ReviewRes {
+	res := ReviewRes{
+		ID:        r.GetId(),
+		ProductID: r.GetProductId(),
+		UserID:    r.GetUserId(),
+		Rating:    r.GetRating(),
+		Comment:   r.GetComment(),
+		Status:    strings.ToLower(r.GetStatus().String()),
+		CreatedAt: r.GetCreatedAt().AsTime(),
+	}
+	if r.GetUpdatedAt() != nil {
+		updatedAt := r.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
 func toOrderRes(o *pb.OrderRes) OrderRes {
-	return OrderRes{
-		ID:            o.Id,
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Items:         toOrderItems(o.Items),
-		Status:        strings.ToLower(o.GetStatus().String()),
+	res := OrderRes{
+		ID:             o.Id,
+		PaymentMethod:  o.PaymentMethod,
+		CouponCode:     o.CouponCode,
+		DiscountPrice:  money.Amount(o.DiscountPrice),
+		TaxPrice:       money.Amount(o.TaxPrice),
+		TaxInclusive:   o.TaxInclusive,
+		ShippingPrice:  money.Amount(o.ShippingPrice),
+		ShippingMethod: o.ShippingMethod,
+		TotalPrice:     money.Amount(o.TotalPrice),
+		Currency:       o.Currency,
+		ExchangeRate:   money.Rate(o.ExchangeRate),
+		Items:          toOrderItems(o.Items),
+		Status:         strings.ToLower(o.GetStatus().String()),
+		CreatedAt:      o.GetCreatedAt().AsTime(),
+	}
+	if sa := o.GetShippingAddress(); sa != nil {
+		res.ShippingAddress = &ShippingAddress{
+			Name:       sa.Name,
+			Line1:      sa.Line1,
+			Line2:      sa.Line2,
+			City:       sa.City,
+			Region:     sa.Region,
+			PostalCode: sa.PostalCode,
+			Country:    sa.Country,
+			Phone:      sa.Phone,
+		}
+	}
+	if o.GetUpdatedAt() != nil {
+		updatedAt := o.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
+func toInvoice(res *pb.InvoiceRes) *invoice.Invoice {
+	inv := &invoice.Invoice{
+		Number:   invoice.FormatNumber(res.GetNumber()),
+		OrderID:  res.GetOrderId(),
+		IssuedAt: res.GetIssuedAt().AsTime(),
+		Seller: invoice.Seller{
+			Name:    res.GetSellerName(),
+			Address: res.GetSellerAddress(),
+			TaxID:   res.GetSellerTaxId(),
+		},
+		Customer: invoice.Customer{
+			Name:  res.GetBillName(),
+			Email: res.GetBillEmail(),
+		},
+		Currency:       res.GetCurrency(),
+		Subtotal:       money.Amount(res.GetSubtotal()),
+		Discount:       money.Amount(res.GetDiscountPrice()),
+		Tax:            money.Amount(res.GetTaxPrice()),
+		TaxInclusive:   res.GetTaxInclusive(),
+		Shipping:       money.Amount(res.GetShippingPrice()),
+		ShippingMethod: res.GetShippingMethod(),
+		Total:          money.Amount(res.GetTotalPrice()),
+	}
+	if sa := res.GetShippingAddress(); sa != nil {
+		inv.Customer.Address = &invoice.Address{
+			Name:       sa.Name,
+			Line1:      sa.Line1,
+			Line2:      sa.Line2,
+			City:       sa.City,
+			Region:     sa.Region,
+			PostalCode: sa.PostalCode,
+			Country:    sa.Country,
+			Phone:      sa.Phone,
+		}
+	}
+	for _, l := range res.GetLines() {
+		price := money.Amount(l.GetPrice())
+		inv.Lines = append(inv.Lines, invoice.Line{
+			ProductID: l.GetProductId(),
+			Name:      l.GetName(),
+			Quantity:  l.GetQuantity(),
+			UnitPrice: price,
+			TaxRate:   l.GetTaxRate(),
+			Tax:       money.Amount(l.GetTaxPrice()),
+			Amount:    price.Mul(l.GetQuantity()),
+		})
+	}
+
+	return inv
+}
+
+func toPBCouponReq(c CouponReq) (*pb.CouponReq, error) {
+	req := &pb.CouponReq{
+		Code:           c.Code,
+		Value:          int64(c.Value),
+		MinOrderValue:  int64(c.MinOrderValue),
+		MaxUses:        c.MaxUses,
+		MaxUsesPerUser: c.MaxUsesPerUser,
+		CategoryId:     c.CategoryID,
+		ProductId:      c.ProductID,
+	}
+	if c.Kind != "" {
+		v, ok := pb.CouponKind_value[strings.ToUpper(c.Kind)]
+		if !ok {
+			return nil, fmt.Errorf("unknown coupon kind: %s", c.Kind)
+		}
+		kind := pb.CouponKind(v)
+		req.Kind = &kind
+	}
+	if c.ExpiresAt != nil {
+		req.ExpiresAt = timestamppb.New(*c.ExpiresAt)
+	}
+
+	return req, nil
+}
+
+func toCouponRes(c *pb.CouponRes) CouponRes {
+	res := CouponRes{
+		ID:             c.GetId(),
+		Code:           c.GetCode(),
+		Kind:           strings.ToLower(c.GetKind().String()),
+		Value:          money.Amount(c.GetValue()),
+		MinOrderValue:  money.Amount(c.GetMinOrderValue()),
+		Currency:       c.GetCurrency(),
+		MaxUses:        c.GetMaxUses(),
+		MaxUsesPerUser: c.GetMaxUsesPerUser(),
+		CategoryID:     c.GetCategoryId(),
+		ProductID:      c.GetProductId(),
+		CreatedAt:      c.GetCreatedAt().AsTime(),
+	}
+	if c.GetExpiresAt() != nil {
+		expiresAt := c.GetExpiresAt().AsTime()
+		res.ExpiresAt = &expiresAt
+	}
+	if c.GetUpdatedAt() != nil {
+		updatedAt := c.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
+func toPaymentRes(p *pb.PaymentRes) PaymentRes {
+	res := PaymentRes{
+		ID:            p.GetId(),
+		OrderID:       p.GetOrderId(),
+		Provider:      p.GetProvider(),
+		Status:        strings.ToLower(strings.TrimPrefix(p.GetStatus().String(), "PAYMENT_")),
+		Amount:        money.Amount(p.GetAmount()),
+		Currency:      p.GetCurrency(),
+		NextActionURL: p.GetNextActionUrl(),
+		DeclineReason: p.GetDeclineReason(),
+		CreatedAt:     p.GetCreatedAt().AsTime(),
+	}
+	if p.GetUpdatedAt() != nil {
+		updatedAt := p.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
+func toCartRes(c *pb.CartRes) CartRes {
+	res := CartRes{
+		Items:          make([]CartItemRes, 0, len(c.GetItems())),
+		Subtotal:       money.Amount(c.GetSubtotal()),
+		TaxPrice:       money.Amount(c.GetTaxPrice()),
+		ShippingPrice:  money.Amount(c.GetShippingPrice()),
+		TotalPrice:     money.Amount(c.GetTotalPrice()),
+		Currency:       c.GetCurrency(),
+		CartToken:      c.GetCartToken(),
+		ChargesPending: c.GetChargesPending(),
+	}
+	for _, ci := range c.GetItems() {
+		res.Items = append(res.Items, CartItemRes{
+			ProductID:    ci.GetProductId(),
+			VariantID:    ci.GetVariantId(),
+			Options:      ci.GetOptions(),
+			Name:         ci.GetName(),
+			Image:        ci.GetImage(),
+			Price:        money.Amount(ci.GetPrice()),
+			Quantity:     ci.GetQuantity(),
+			CountInStock: ci.GetCountInStock(),
+		})
+	}
+
+	return res
+}
+
+func toShippingQuoteRes(q *pb.ShippingQuoteRes) ShippingQuoteRes {
+	res := ShippingQuoteRes{Options: make([]ShippingOptionRes, 0, len(q.GetOptions())), Currency: q.GetCurrency()}
+	for _, o := range q.GetOptions() {
+		res.Options = append(res.Options, ShippingOptionRes{
+			MethodID: o.GetMethodId(),
+			Name:     o.GetName(),
+			MinDays:  o.GetMinDays(),
+			MaxDays:  o.GetMaxDays(),
+			Price:    money.Amount(o.GetPrice()),
+		})
 	}
+
+	return res
 }
 
 func toOrderItems(oi []*pb.OrderItem) []*OrderItem {
@@ -98,13 +444,29 @@ func toOrderItems(oi []*pb.OrderItem) []*OrderItem {
 			Name:      i.Name,
 			Quantity:  i.Quantity,
 			Image:     i.Image,
-			Price:     i.Price,
+			Price:     money.Amount(i.Price),
+			TaxRate:   i.TaxRate,
+			TaxPrice:  money.Amount(i.TaxPrice),
 			ProductID: i.ProductId,
+			VariantID: i.VariantId,
 		})
 	}
 	return res
 }
 
+func toOrderStatusChangeRes(c *pb.OrderStatusChange) OrderStatusChangeRes {
+	res := OrderStatusChangeRes{
+		ToStatus:  strings.ToLower(c.GetToStatus().String()),
+		ChangedBy: c.GetChangedBy(),
+		CreatedAt: c.GetCreatedAt().AsTime(),
+	}
+	if c.FromStatus != nil {
+		res.FromStatus = strings.ToLower(c.GetFromStatus().String())
+	}
+
+	return res
+}
+
 func toPBUserReq(u UserReq) *pb.UserReq {
 	return &pb.UserReq{
 		Name:     u.Name,
@@ -121,3 +483,66 @@ func toUserRes(u *pb.UserRes) UserRes {
 		IsAdmin: u.IsAdmin,
 	}
 }
+
+func toPBAddressReq(a AddressReq) *pb.AddressReq {
+	return &pb.AddressReq{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+		IsDefault:  a.IsDefault,
+	}
+}
+
+func toAddressRes(a *pb.AddressRes) AddressRes {
+	res := AddressRes{
+		ID:         a.Id,
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+		IsDefault:  a.IsDefault,
+		CreatedAt:  a.GetCreatedAt().AsTime(),
+	}
+	if a.GetUpdatedAt() != nil {
+		updatedAt := a.GetUpdatedAt().AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	return res
+}
+
+// writeGRPCError replies with the HTTP status matching the gRPC code of err.
+// Client errors carry the service message, anything else is reported as msg so
+// internal details don't leak.
+func writeGRPCError(w http.ResponseWriter, err error, msg string) {
+	st := status.Convert(err)
+	code := http.StatusInternalServerError
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		code = http.StatusConflict
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.Unimplemented:
+		code = http.StatusNotImplemented
+	default:
+		http.Error(w, msg, code)
+		return
+	}
+
+	http.Error(w, st.Message(), code)
+}
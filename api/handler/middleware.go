@@ -1,12 +1,20 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/niloy104/Conduit/grpc/pb"
 	"github.com/niloy104/Conduit/token"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type authKey struct{}
@@ -29,6 +37,28 @@ func GetAuthMiddlewareFunc(tokenMaker *token.JWTMaker) func(http.Handler) http.H
 	}
 }
 
+// GetOptionalAuthMiddlewareFunc lets requests without an authorization header
+// through as guests, without claims in their context.
+func GetOptionalAuthMiddlewareFunc(tokenMaker *token.JWTMaker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := verifyClaimsFromAuthHeader(r, tokenMaker)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error verifying token: %v", err), http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), authKey{}, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 func GetAdminMiddlewareFunc(tokenMaker *token.JWTMaker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -52,6 +82,140 @@ func GetAdminMiddlewareFunc(tokenMaker *token.JWTMaker) func(http.Handler) http.
 	}
 }
 
+const (
+	// idempotencyKeyHeader carries the key a client retries a request with.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader marks the responses replayed from a key.
+	idempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeySize = 255
+	maxIdempotentBodySize = 1 << 20
+)
+
+// GetIdempotencyMiddlewareFunc makes requests carrying an Idempotency-Key
+// header safe to retry. The first response to a key is stored for ttl and
+// replayed verbatim to the retries of the request. Retries of a request still
+// in progress get 409 Conflict, and reusing a key for another request gets
+// 422. Responses with a 5xx status are not stored, so that the request can be
+// retried. Keys are scoped to the user, so it must run after an auth
+// middleware; guest requests are not deduplicated.
+func GetIdempotencyMiddlewareFunc(client pb.EcommClient, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			claims, ok := r.Context().Value(authKey{}).(*token.UserClaims)
+			if key == "" || !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeySize {
+				http.Error(w, "idempotency key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+			if err != nil {
+				http.Error(w, "error reading request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// the response is stored even if the client gave up waiting
+			ctx := context.WithoutCancel(r.Context())
+			req := &pb.IdempotencyKeyReq{
+				UserId:      claims.ID,
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				ExpiresAt:   timestamppb.New(time.Now().Add(ttl)),
+			}
+			stored, err := client.ReserveIdempotencyKey(ctx, req)
+			if err != nil {
+				writeGRPCError(w, err, "error reserving idempotency key")
+				return
+			}
+			if !stored.GetReserved() {
+				replayResponse(w, stored, req.GetRequestHash())
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				// the handler failed or panicked, let the request be retried
+				if _, err := client.ReleaseIdempotencyKey(ctx, req); err != nil {
+					log.Printf("error releasing idempotency key: %v", err)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+
+			req.StatusCode = int32(rec.status)
+			req.ContentType = rec.Header().Get("Content-Type")
+			req.ResponseBody = rec.body.Bytes()
+			_, err = client.CompleteIdempotencyKey(ctx, req)
+			if err != nil {
+				// keeping the key in progress still keeps retries out
+				log.Printf("error completing idempotency key: %v", err)
+			}
+			completed = true
+		})
+	}
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.Path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayResponse answers a request whose idempotency key was reserved by
+// another request.
+func replayResponse(w http.ResponseWriter, stored *pb.IdempotencyKeyRes, hash string) {
+	switch {
+	case stored.GetRequestHash() != hash:
+		http.Error(w, "idempotency key was used for another request", http.StatusUnprocessableEntity)
+	case stored.GetStatusCode() == 0:
+		http.Error(w, "a request with this idempotency key is in progress", http.StatusConflict)
+	default:
+		if ct := stored.GetContentType(); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		}
+		w.Header().Set(idempotentReplayHeader, "true")
+		w.WriteHeader(int(stored.GetStatusCode()))
+		w.Write(stored.GetResponseBody())
+	}
+}
+
+// responseRecorder keeps a copy of the response it writes through.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if !rr.wroteHeader {
+		rr.status = code
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
 func verifyClaimsFromAuthHeader(r *http.Request, tokenMaker *token.JWTMaker) (*token.UserClaims, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/server"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/token"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// idempotencyClient serves the idempotency key calls of the middleware from
+// an in-process server backed by a memory storer.
+type idempotencyClient struct {
+	pb.EcommClient
+	srv *server.Server
+}
+
+func (c *idempotencyClient) ReserveIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq, _ ...grpc.CallOption) (*pb.IdempotencyKeyRes, error) {
+	return c.srv.ReserveIdempotencyKey(ctx, r)
+}
+
+func (c *idempotencyClient) CompleteIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq, _ ...grpc.CallOption) (*pb.IdempotencyKeyRes, error) {
+	return c.srv.CompleteIdempotencyKey(ctx, r)
+}
+
+func (c *idempotencyClient) ReleaseIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq, _ ...grpc.CallOption) (*pb.IdempotencyKeyRes, error) {
+	return c.srv.ReleaseIdempotencyKey(ctx, r)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	st := storer.NewMemoryStorer()
+	client := &idempotencyClient{srv: server.NewServer(st)}
+	mw := GetIdempotencyMiddlewareFunc(client, time.Hour)
+	alice, err := st.CreateUser(context.Background(), &storer.User{Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := st.CreateUser(context.Background(), &storer.User{Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	var calls atomic.Int64
+	started, unblock := make(chan struct{}), make(chan struct{})
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/fail":
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+		case "/panic":
+			panic("handler failed")
+		case "/slow":
+			close(started)
+			<-unblock
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"call": %d, "body": %q}`, n, body)
+		}
+	}))
+
+	newReq := func(path, key, body string, userID int64) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(idempotencyKeyHeader, key)
+		}
+		if userID != 0 {
+			r = r.WithContext(context.WithValue(r.Context(), authKey{}, &token.UserClaims{ID: userID}))
+		}
+		return r
+	}
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		calls.Store(0)
+		first := serve(newReq("/orders", "replay", `{"qty": 1}`, alice.ID))
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Empty(t, first.Header().Get(idempotentReplayHeader))
+
+		retry := serve(newReq("/orders", "replay", `{"qty": 1}`, alice.ID))
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, "true", retry.Header().Get(idempotentReplayHeader))
+		require.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		require.Equal(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, int64(1), calls.Load(), "retries do not run the handler")
+
+		other := serve(newReq("/orders", "replay", `{"qty": 1}`, bob.ID))
+		require.Empty(t, other.Header().Get(idempotentReplayHeader), "keys are scoped to the user")
+		require.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("key reused for another request", func(t *testing.T) {
+		calls.Store(0)
+		require.Equal(t, http.StatusCreated, serve(newReq("/orders", "reused", `{"qty": 1}`, alice.ID)).Code)
+		require.Equal(t, http.StatusUnprocessableEntity, serve(newReq("/orders", "reused", `{"qty": 2}`, alice.ID)).Code, "other body")
+		require.Equal(t, http.StatusUnprocessableEntity, serve(newReq("/cart/checkout", "reused", `{"qty": 1}`, alice.ID)).Code, "other path")
+		require.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		calls.Store(0)
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- serve(newReq("/slow", "slow", "", alice.ID)) }()
+		select {
+		case <-started:
+		case w := <-done:
+			t.Fatalf("request did not reach the handler: %d %s", w.Code, w.Body)
+		}
+
+		require.Equal(t, http.StatusConflict, serve(newReq("/slow", "slow", "", alice.ID)).Code)
+		close(unblock)
+		require.Equal(t, http.StatusCreated, (<-done).Code)
+		require.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("server errors release the key", func(t *testing.T) {
+		calls.Store(0)
+		require.Equal(t, http.StatusServiceUnavailable, serve(newReq("/fail", "fail", "", alice.ID)).Code)
+		w := serve(newReq("/fail", "fail", "", alice.ID))
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+		require.Empty(t, w.Header().Get(idempotentReplayHeader))
+		require.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("panics release the key", func(t *testing.T) {
+		calls.Store(0)
+		require.Panics(t, func() { serve(newReq("/panic", "panic", "", alice.ID)) })
+		require.Panics(t, func() { serve(newReq("/panic", "panic", "", alice.ID)) }, "the retry runs the handler again")
+		require.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("pass through", func(t *testing.T) {
+		calls.Store(0)
+		for _, r := range []*http.Request{
+			newReq("/orders", "", `{"qty": 1}`, alice.ID),
+			newReq("/orders", "", `{"qty": 1}`, alice.ID),
+			newReq("/orders", "guest", `{"qty": 1}`, 0),
+			newReq("/orders", "guest", `{"qty": 1}`, 0),
+		} {
+			w := serve(r)
+			require.Equal(t, http.StatusCreated, w.Code)
+			require.Empty(t, w.Header().Get(idempotentReplayHeader))
+		}
+		require.Equal(t, int64(4), calls.Load(), "requests without a key and guests are not deduplicated")
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		calls.Store(0)
+		w := serve(newReq("/orders", strings.Repeat("k", maxIdempotencyKeySize+1), "", alice.ID))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Zero(t, calls.Load())
+	})
+}
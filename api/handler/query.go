@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// queryParams reads the paging, sorting and filtering parameters of list
+// endpoints. Absent parameters read as zero values; the first malformed one
+// is kept in err and the following reads are skipped.
+type queryParams struct {
+	url.Values
+	err error
+}
+
+func (q *queryParams) int32(name string) int32 {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		q.err = fmt.Errorf("error parsing %s", name)
+	}
+	return int32(n)
+}
+
+func (q *queryParams) int64(name string) int64 {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		q.err = fmt.Errorf("error parsing %s", name)
+	}
+	return n
+}
+
+// amount parses a decimal amount of money into cents.
+func (q *queryParams) amount(name string) *int64 {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return nil
+	}
+
+	a, err := money.Parse(v)
+	if err != nil {
+		q.err = fmt.Errorf("error parsing %s", name)
+		return nil
+	}
+	cents := int64(a)
+	return &cents
+}
+
+func (q *queryParams) bool(name string) *bool {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		q.err = fmt.Errorf("error parsing %s", name)
+		return nil
+	}
+	return &b
+}
+
+// time accepts either a full RFC 3339 timestamp or a plain date, taken as
+// midnight UTC.
+func (q *queryParams) time(name string) *timestamppb.Timestamp {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			q.err = fmt.Errorf("error parsing %s", name)
+			return nil
+		}
+	}
+	return timestamppb.New(t)
+}
+
+func (q *queryParams) orderStatus(name string) *pb.OrderStatus {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return nil
+	}
+
+	status, err := toPBOrderStatus(OrderStatus(v))
+	if err != nil {
+		q.err = fmt.Errorf("invalid %s", name)
+		return nil
+	}
+	return &status
+}
+
+func (q *queryParams) reviewStatus(name string) *pb.ReviewStatus {
+	v := q.Get(name)
+	if v == "" || q.err != nil {
+		return nil
+	}
+
+	status, err := toPBReviewStatus(ReviewStatus(v))
+	if err != nil {
+		q.err = fmt.Errorf("invalid %s", name)
+		return nil
+	}
+	return &status
+}
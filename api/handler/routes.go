@@ -2,45 +2,124 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 var r *chi.Mux
 
+// idempotencyKeyTTL is how long the responses to idempotent requests are
+// replayed.
+const idempotencyKeyTTL = 24 * time.Hour
+
 func RegisterRoutes(handler *handler) *chi.Mux {
 	r = chi.NewRouter()
 	tokenMaker := handler.TokenMaker
+	idempotent := GetIdempotencyMiddlewareFunc(handler.client, idempotencyKeyTTL)
 
 	r.Route("/products", func(r chi.Router) {
 		r.With(GetAdminMiddlewareFunc(tokenMaker)).Post("/", handler.createProduct)
 		r.Get("/", handler.listProducts)
+		r.Get("/search", handler.searchProducts)
 
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.getProduct)
+			r.Get("/reviews", handler.listProductReviews)
+			r.With(GetAuthMiddlewareFunc(tokenMaker)).Post("/reviews", handler.createReview)
 			r.Group(func(r chi.Router) {
 				r.Use(GetAdminMiddlewareFunc(tokenMaker))
 				r.Patch("/", handler.updateProduct)
 				r.Delete("/", handler.deleteProduct)
+				r.Post("/variants", handler.createVariant)
+				r.Patch("/variants/{variant_id}", handler.updateVariant)
+				r.Delete("/variants/{variant_id}", handler.deleteVariant)
+				r.Post("/images", handler.uploadProductImage)
+				r.Put("/images/order", handler.reorderProductImages)
+				r.Delete("/images/{image_id}", handler.deleteProductImage)
+			})
+		})
+	})
+
+	r.Route("/admin/products", func(r chi.Router) {
+		r.Use(GetAdminMiddlewareFunc(tokenMaker))
+		r.Post("/import", handler.importProducts)
+		r.Get("/export", handler.exportProducts)
+	})
+
+	r.Route("/categories", func(r chi.Router) {
+		r.With(GetAdminMiddlewareFunc(tokenMaker)).Post("/", handler.createCategory)
+		r.Get("/", handler.listCategories)
+
+		r.Route("/{slug}", func(r chi.Router) {
+			r.Get("/", handler.getCategory)
+			r.Get("/products", handler.listCategoryProducts)
+			r.Group(func(r chi.Router) {
+				r.Use(GetAdminMiddlewareFunc(tokenMaker))
+				r.Patch("/", handler.updateCategory)
+				r.Delete("/", handler.deleteCategory)
+			})
+		})
+	})
+
+	r.Route("/reviews", func(r chi.Router) {
+		r.Use(GetAdminMiddlewareFunc(tokenMaker))
+		r.Get("/", handler.listReviews)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Patch("/", handler.moderateReview)
+			r.Delete("/", handler.deleteReview)
+		})
+	})
+
+	r.Route("/coupons", func(r chi.Router) {
+		r.Use(GetAdminMiddlewareFunc(tokenMaker))
+		r.Post("/", handler.createCoupon)
+		r.Get("/", handler.listCoupons)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", handler.getCoupon)
+			r.Patch("/", handler.updateCoupon)
+			r.Delete("/", handler.deleteCoupon)
+		})
+	})
+
+	r.Route("/cart", func(r chi.Router) {
+		r.Route("/items", func(r chi.Router) {
+			r.Use(GetOptionalAuthMiddlewareFunc(tokenMaker))
+			r.Get("/", handler.getCart)
+			r.Post("/", handler.addCartItem)
+			r.Delete("/", handler.clearCart)
+			r.Route("/{product_id}", func(r chi.Router) {
+				r.Patch("/", handler.updateCartItem)
+				r.Delete("/", handler.removeCartItem)
 			})
 		})
+		r.With(GetAuthMiddlewareFunc(tokenMaker), idempotent).Post("/checkout", handler.checkout)
 	})
 
+	r.With(GetOptionalAuthMiddlewareFunc(tokenMaker)).Post("/shipping/quote", handler.quoteShipping)
+
 	r.Group(func(r chi.Router) {
 		r.Use(GetAuthMiddlewareFunc(tokenMaker))
-		r.Get("/myorder", handler.getOrder)
+		r.Get("/myorders", handler.listMyOrders)
 
 		r.Route("/orders", func(r chi.Router) {
-			r.Post("/", handler.createOrder)
+			r.With(idempotent).Post("/", handler.createOrder)
 			r.With(GetAdminMiddlewareFunc(tokenMaker)).Get("/", handler.listOrders)
-			r.With(GetAuthMiddlewareFunc(tokenMaker)).Patch("/status", handler.updateOrderStatus)
+			r.With(GetAdminMiddlewareFunc(tokenMaker)).Patch("/status", handler.updateOrderStatus)
 
 			r.Route("/{id}", func(r chi.Router) {
-				r.Delete("/", handler.deleteOrder)
+				r.Get("/", handler.getOrder)
+				r.With(GetAdminMiddlewareFunc(tokenMaker)).Delete("/", handler.deleteOrder)
+				r.Post("/cancel", handler.cancelOrder)
+				r.Get("/history", handler.listOrderStatusHistory)
+				r.Get("/invoice", handler.getInvoice)
+				r.With(idempotent).Post("/payments", handler.createPayment)
 			})
 		})
 	})
 
+	r.Post("/payments/webhook", handler.paymentWebhook)
+
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/", handler.createUser)
 		r.Post("/login", handler.loginUser)
@@ -57,6 +136,16 @@ func RegisterRoutes(handler *handler) *chi.Mux {
 			r.Use(GetAuthMiddlewareFunc(tokenMaker))
 			r.Patch("/", handler.updateUser)
 			r.Post("/logout", handler.logoutUser)
+
+			r.Route("/me/addresses", func(r chi.Router) {
+				r.Post("/", handler.createAddress)
+				r.Get("/", handler.listAddresses)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", handler.getAddress)
+					r.Patch("/", handler.updateAddress)
+					r.Delete("/", handler.deleteAddress)
+				})
+			})
 		})
 	})
 
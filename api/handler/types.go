@@ -1,63 +1,349 @@
 package handler
 
-import "time"
+import (
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+)
 
 type ProductReq struct {
-	ID           int64   `json:"id"`
-	Name         string  `json:"name"`
-	Image        string  `json:"image"`
-	Category     string  `json:"category"`
-	Description  string  `json:"description"`
-	Rating       int64   `json:"rating"`
-	NumReviews   int64   `json:"num_reviews"`
-	Price        float32 `json:"price"`
-	CountInStock int64   `json:"count_in_stock"`
+	ID           int64        `json:"id"`
+	SKU          string       `json:"sku"`
+	Name         string       `json:"name"`
+	Image        string       `json:"image"`
+	CategoryID   int64        `json:"category_id"`
+	TaxCategory  string       `json:"tax_category"`
+	Description  string       `json:"description"`
+	Price        money.Amount `json:"price"`
+	Currency     string       `json:"currency"`
+	CountInStock int64        `json:"count_in_stock"`
+	Weight       int64        `json:"weight"` // grams
+	Length       int64        `json:"length"` // millimetres, as Width and Height
+	Width        int64        `json:"width"`
+	Height       int64        `json:"height"`
 }
 
 type ProductRes struct {
-	ID           int64      `json:"id"`
-	Name         string     `json:"name"`
-	Image        string     `json:"image"`
-	Category     string     `json:"category"`
-	Description  string     `json:"description"`
-	Rating       int64      `json:"rating"`
-	NumReviews   int64      `json:"num_reviews"`
-	Price        float32    `json:"price"`
-	CountInStock int64      `json:"count_in_stock"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at"`
+	ID           int64        `json:"id"`
+	SKU          string       `json:"sku,omitempty"`
+	Name         string       `json:"name"`
+	Image        string       `json:"image"`
+	CategoryID   int64        `json:"category_id,omitempty"`
+	TaxCategory  string       `json:"tax_category"`
+	Description  string       `json:"description"`
+	Rating       int64        `json:"rating"`
+	NumReviews   int64        `json:"num_reviews"`
+	Price        money.Amount `json:"price"`
+	Currency     string       `json:"currency"`
+	CountInStock int64        `json:"count_in_stock"`
+	Weight       int64        `json:"weight"`
+	Length       int64        `json:"length"`
+	Width        int64        `json:"width"`
+	Height       int64        `json:"height"`
+	Variants     []VariantRes `json:"variants,omitempty"`
+	Images       []ImageRes   `json:"images,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    *time.Time   `json:"updated_at"`
+}
+
+// VariantReq creates or updates a variant of a product. A price of 0 drops
+// the price override, so that the variant costs the price of its product.
+type VariantReq struct {
+	SKU          string            `json:"sku"`
+	Options      map[string]string `json:"options"`
+	Price        *money.Amount     `json:"price"`
+	CountInStock *int64            `json:"count_in_stock"`
+}
+
+type VariantRes struct {
+	ID           int64             `json:"id"`
+	ProductID    int64             `json:"product_id"`
+	SKU          string            `json:"sku"`
+	Options      map[string]string `json:"options"`
+	Price        *money.Amount     `json:"price,omitempty"`
+	CountInStock int64             `json:"count_in_stock"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    *time.Time        `json:"updated_at"`
+}
+
+// ImageRes is an image of the gallery of a product, the first of which is
+// the image of the product. Size is in bytes, Width and Height in pixels.
+type ImageRes struct {
+	ID           int64     `json:"id"`
+	ProductID    int64     `json:"product_id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int64     `json:"width"`
+	Height       int64     `json:"height"`
+	Position     int64     `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ReorderImagesReq lists the IDs of every image of a product, in their new
+// order.
+type ReorderImagesReq struct {
+	ImageIDs []int64 `json:"image_ids"`
+}
+
+type ListProductsRes struct {
+	Products      []ProductRes `json:"products"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+}
+
+// ImportProductsRes reports an import. Created and Updated count the rows
+// saved, or that would be saved by a dry run; Failed counts the rows of
+// Errors.
+type ImportProductsRes struct {
+	DryRun  bool             `json:"dry_run"`
+	Created int64            `json:"created"`
+	Updated int64            `json:"updated"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// ImportRowError is why a row of an import was skipped. Row is the line of
+// the row in the file.
+type ImportRowError struct {
+	Row   int64  `json:"row"`
+	SKU   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+type ProductMatchRes struct {
+	Product ProductRes `json:"product"`
+	Score   float64    `json:"score"`
+	Snippet string     `json:"snippet"`
+}
+
+type SearchProductsRes struct {
+	Results       []ProductMatchRes `json:"results"`
+	NextPageToken string            `json:"next_page_token,omitempty"`
+}
+
+// CategoryReq creates or updates a category. ParentID 0 makes the category a
+// root, and the slug defaults to the name.
+type CategoryReq struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+type CategoryRes struct {
+	ID        int64      `json:"id"`
+	ParentID  int64      `json:"parent_id,omitempty"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListCategoriesRes struct {
+	Categories []CategoryRes `json:"categories"`
+}
+
+type ReviewReq struct {
+	Rating  int64  `json:"rating"`
+	Comment string `json:"comment"`
+	Status  string `json:"status"`
+}
+
+type ReviewRes struct {
+	ID        int64      `json:"id"`
+	ProductID int64      `json:"product_id"`
+	UserID    int64      `json:"user_id"`
+	Rating    int64      `json:"rating"`
+	Comment   string     `json:"comment"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ListReviewsRes struct {
+	Reviews       []ReviewRes `json:"reviews"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
 }
 
 type OrderReq struct {
-	ID            int64        `json:"id"`
-	Items         []*OrderItem `json:"items"`
-	PaymentMethod string       `json:"payment_method"`
-	TaxPrice      float32      `json:"tax_price"`
-	ShippingPrice float32      `json:"shipping_price"`
-	TotalPrice    float32      `json:"total_price"`
-	Status        string       `json:"status"`
+	ID             int64        `json:"id"`
+	Items          []*OrderItem `json:"items"`
+	PaymentMethod  string       `json:"payment_method"`
+	TaxPrice       money.Amount `json:"tax_price"`
+	ShippingPrice  money.Amount `json:"shipping_price"`
+	TotalPrice     money.Amount `json:"total_price"`
+	Status         string       `json:"status"`
+	CouponCode     string       `json:"coupon_code"`
+	Currency       string       `json:"currency"`
+	AddressID      int64        `json:"address_id"`
+	ShippingMethod string       `json:"shipping_method"`
 }
 
 type OrderItem struct {
-	Name      string  `json:"name"`
-	Quantity  int64   `json:"quantity"`
-	Image     string  `json:"image"`
-	Price     float32 `json:"price"`
-	ProductID int64   `json:"product_id"`
+	Name      string       `json:"name"`
+	Quantity  int64        `json:"quantity"`
+	Image     string       `json:"image"`
+	Price     money.Amount `json:"price"`
+	TaxRate   int64        `json:"tax_rate,omitempty"` // basis points
+	TaxPrice  money.Amount `json:"tax_price,omitempty"`
+	ProductID int64        `json:"product_id"`
+	VariantID int64        `json:"variant_id,omitempty"`
 }
 
 type OrderRes struct {
+	ID              int64            `json:"id"`
+	Items           []*OrderItem     `json:"items"`
+	PaymentMethod   string           `json:"payment_method"`
+	CouponCode      string           `json:"coupon_code,omitempty"`
+	DiscountPrice   money.Amount     `json:"discount_price"`
+	TaxPrice        money.Amount     `json:"tax_price"`
+	TaxInclusive    bool             `json:"tax_inclusive"`
+	ShippingPrice   money.Amount     `json:"shipping_price"`
+	ShippingMethod  string           `json:"shipping_method,omitempty"`
+	TotalPrice      money.Amount     `json:"total_price"`
+	Currency        string           `json:"currency"`
+	ExchangeRate    money.Rate       `json:"exchange_rate"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	Status          string           `json:"status"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       *time.Time       `json:"updated_at"`
+}
+
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+type ListOrdersRes struct {
+	Orders        []OrderRes `json:"orders"`
+	NextPageToken string     `json:"next_page_token,omitempty"`
+}
+
+type CartItemReq struct {
+	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id,omitempty"`
+	Quantity  int64 `json:"quantity"`
+}
+
+type CartItemRes struct {
+	ProductID    int64             `json:"product_id"`
+	VariantID    int64             `json:"variant_id,omitempty"`
+	Options      map[string]string `json:"options,omitempty"`
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	Price        money.Amount      `json:"price"`
+	Quantity     int64             `json:"quantity"`
+	CountInStock int64             `json:"count_in_stock"`
+}
+
+type CartRes struct {
+	Items         []CartItemRes `json:"items"`
+	Subtotal      money.Amount  `json:"subtotal"`
+	TaxPrice      money.Amount  `json:"tax_price"`
+	ShippingPrice money.Amount  `json:"shipping_price"`
+	TotalPrice    money.Amount  `json:"total_price"`
+	Currency      string        `json:"currency"`
+	CartToken     string        `json:"cart_token,omitempty"`
+	// ChargesPending is set when tax or shipping depend on a shipping
+	// address the cart does not have yet; they are then left out of
+	// TotalPrice.
+	ChargesPending bool `json:"charges_pending,omitempty"`
+}
+
+type CheckoutReq struct {
+	PaymentMethod  string `json:"payment_method"`
+	CouponCode     string `json:"coupon_code"`
+	Currency       string `json:"currency"`
+	AddressID      int64  `json:"address_id"`
+	ShippingMethod string `json:"shipping_method"`
+}
+
+// ShippingQuoteReq names the destination of a shipping quote: an address of
+// the address book of the user, or the country, region and postal code of a
+// guest. Users without either are quoted to their default address.
+type ShippingQuoteReq struct {
+	AddressID  int64  `json:"address_id"`
+	Country    string `json:"country"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+}
+
+type ShippingOptionRes struct {
+	MethodID string       `json:"method_id"`
+	Name     string       `json:"name"`
+	MinDays  int32        `json:"min_days"`
+	MaxDays  int32        `json:"max_days"`
+	Price    money.Amount `json:"price"`
+}
+
+type ShippingQuoteRes struct {
+	Options  []ShippingOptionRes `json:"options"`
+	Currency string              `json:"currency"`
+}
+
+type PaymentReq struct {
+	Method string `json:"method"`
+}
+
+type PaymentRes struct {
 	ID            int64        `json:"id"`
-	Items         []*OrderItem `json:"items"`
-	PaymentMethod string       `json:"payment_method"`
-	TaxPrice      float32      `json:"tax_price"`
-	ShippingPrice float32      `json:"shipping_price"`
-	TotalPrice    float32      `json:"total_price"`
+	OrderID       int64        `json:"order_id"`
+	Provider      string       `json:"provider"`
 	Status        string       `json:"status"`
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	NextActionURL string       `json:"next_action_url,omitempty"`
+	DeclineReason string       `json:"decline_reason,omitempty"`
 	CreatedAt     time.Time    `json:"created_at"`
 	UpdatedAt     *time.Time   `json:"updated_at"`
 }
 
+type CouponReq struct {
+	Code           string       `json:"code"`
+	Kind           string       `json:"kind"`
+	Value          money.Amount `json:"value"`
+	MinOrderValue  money.Amount `json:"min_order_value"`
+	ExpiresAt      *time.Time   `json:"expires_at"`
+	MaxUses        int64        `json:"max_uses"`
+	MaxUsesPerUser int64        `json:"max_uses_per_user"`
+	CategoryID     int64        `json:"category_id"`
+	ProductID      int64        `json:"product_id"`
+}
+
+type CouponRes struct {
+	ID             int64        `json:"id"`
+	Code           string       `json:"code"`
+	Kind           string       `json:"kind"`
+	Value          money.Amount `json:"value"`
+	MinOrderValue  money.Amount `json:"min_order_value"`
+	Currency       string       `json:"currency"`
+	ExpiresAt      *time.Time   `json:"expires_at"`
+	MaxUses        int64        `json:"max_uses"`
+	MaxUsesPerUser int64        `json:"max_uses_per_user"`
+	CategoryID     int64        `json:"category_id,omitempty"`
+	ProductID      int64        `json:"product_id,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      *time.Time   `json:"updated_at"`
+}
+
+type ListCouponsRes struct {
+	Coupons       []CouponRes `json:"coupons"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+}
+
+type OrderStatusChangeRes struct {
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  int64     `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type UserReq struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
@@ -71,8 +357,40 @@ type UserRes struct {
 	IsAdmin bool   `json:"is_admin"`
 }
 
+type AddressReq struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone"`
+	IsDefault  *bool  `json:"is_default"`
+}
+
+type AddressRes struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Line1      string     `json:"line1"`
+	Line2      string     `json:"line2"`
+	City       string     `json:"city"`
+	Region     string     `json:"region"`
+	PostalCode string     `json:"postal_code"`
+	Country    string     `json:"country"`
+	Phone      string     `json:"phone"`
+	IsDefault  bool       `json:"is_default"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+type ListAddressesRes struct {
+	Addresses []AddressRes `json:"addresses"`
+}
+
 type ListUserRes struct {
-	Users []UserRes `json:"users"`
+	Users         []UserRes `json:"users"`
+	NextPageToken string    `json:"next_page_token,omitempty"`
 }
 
 type LoginUserReq struct {
@@ -0,0 +1,37 @@
+// Package blobstore keeps the files the store serves, such as product images,
+// under slash-separated keys. FS stores them in a local directory, S3 in an
+// S3-compatible object storage.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// ErrInvalidKey is returned for keys that are empty, absolute or escape their
+// root with "..".
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store stores blobs and tells the URL they are served at.
+type Store interface {
+	// Put stores data under key, replacing the blob stored there if any.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete deletes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the URL the blob stored under key is served at.
+	URL(key string) string
+}
+
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,92 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FS stores blobs as files of a local directory, for development and single
+// host deployments. Handler serves them.
+type FS struct {
+	dir     string
+	baseURL string
+}
+
+// NewFS returns a store keeping blobs under dir, served at baseURL, e.g.
+// http://localhost:8080/images.
+func NewFS(dir, baseURL string) *FS {
+	return &FS{dir: dir, baseURL: baseURL}
+}
+
+// Put writes the blob to a temporary file renamed into place, so that readers
+// never see a partial blob.
+func (f *FS) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	name := filepath.Join(f.dir, filepath.FromSlash(key))
+	err := os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return fmt.Errorf("error creating blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("error creating blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing blob %s: %w", key, err)
+	}
+	err = os.Chmod(tmp.Name(), 0o644)
+	if err != nil {
+		return fmt.Errorf("error writing blob %s: %w", key, err)
+	}
+	err = os.Rename(tmp.Name(), name)
+	if err != nil {
+		return fmt.Errorf("error writing blob %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *FS) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(f.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting blob %s: %w", key, err)
+	}
+	return nil
+}
+
+func (f *FS) URL(key string) string {
+	return joinURL(f.baseURL, key)
+}
+
+// Handler serves the blobs of the directory by key, without listing it. Mount
+// it with the prefix of the base URL stripped.
+func (f *FS) Handler() http.Handler {
+	files := http.FileServer(http.Dir(f.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") || checkKey(strings.TrimPrefix(r.URL.Path, "/")) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,56 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"products/1/a.jpg", "a.png", "x/y/z"} {
+		require.NoError(t, checkKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "..", "../a", "a/../../b", "a//b", "a/./b", "a/"} {
+		require.ErrorIs(t, checkKey(key), ErrInvalidKey, key)
+	}
+}
+
+func TestFS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fs := NewFS(dir, "http://localhost:8080/images/")
+
+	require.NoError(t, fs.Put(ctx, "products/1/a.jpg", []byte("jpeg"), "image/jpeg"))
+	data, err := os.ReadFile(filepath.Join(dir, "products", "1", "a.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", string(data))
+	require.Equal(t, "http://localhost:8080/images/products/1/a.jpg", fs.URL("products/1/a.jpg"))
+	require.ErrorIs(t, fs.Put(ctx, "../a.jpg", nil, ""), ErrInvalidKey)
+
+	srv := httptest.NewServer(http.StripPrefix("/images", fs.Handler()))
+	defer srv.Close()
+	get := func(path string) (int, string) {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+	code, body := get("/images/products/1/a.jpg")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "jpeg", body)
+	code, _ = get("/images/products/1/")
+	require.Equal(t, http.StatusNotFound, code, "directories are not listed")
+
+	require.NoError(t, fs.Delete(ctx, "products/1/a.jpg"))
+	require.NoError(t, fs.Delete(ctx, "products/1/a.jpg"), "deleting a missing blob is not an error")
+	code, _ = get("/images/products/1/a.jpg")
+	require.Equal(t, http.StatusNotFound, code)
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"log"
+	"net/http"
 
 	"github.com/ianschenck/envflag"
 	"github.com/niloy104/Conduit/api/handler"
+	"github.com/niloy104/Conduit/blobstore"
 	"github.com/niloy104/Conduit/grpc/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -16,6 +18,7 @@ func main() {
 	var (
 		secretKey = envflag.String("SECRET_kEY", "01234567890123456789012345678901", "secret key for jwt signing")
 		svcAddr   = envflag.String("GRPC_SVC_ADDR", "0.0.0.0:9091", "address where the grpc service is listening on")
+		imagesDir = envflag.String("IMAGES_DIR", "", "directory of the fs blob store of the grpc service to serve at /images, empty serves no images")
 	)
 	envflag.Parse()
 
@@ -36,6 +39,9 @@ func main() {
 	client := pb.NewEcommClient(conn)
 
 	hdl := handler.NewHandler(client, *secretKey)
-	handler.RegisterRoutes(hdl)
+	mux := handler.RegisterRoutes(hdl)
+	if *imagesDir != "" {
+		mux.Handle("/images/*", http.StripPrefix("/images", blobstore.NewFS(*imagesDir, "").Handler()))
+	}
 	handler.Start(":8080")
 }
@@ -3,12 +3,20 @@ package main
 import (
 	"log"
 	"net"
+	"strings"
 
 	"github.com/ianschenck/envflag"
+	"github.com/niloy104/Conduit/blobstore"
 	"github.com/niloy104/Conduit/db"
 	"github.com/niloy104/Conduit/grpc/pb"
 	"github.com/niloy104/Conduit/grpc/server"
 	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/imaging"
+	"github.com/niloy104/Conduit/invoice"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/payment"
+	"github.com/niloy104/Conduit/shipping"
+	"github.com/niloy104/Conduit/tax"
 	"google.golang.org/grpc"
 )
 
@@ -17,24 +25,139 @@ func main() {
 	var (
 		svcAddr = envflag.String("SVC_ADDR", "0.0.0.0:9091", "address where the grpc service is listening on")
 		dbAddr  = envflag.String("DB_ADDR", "127.0.0.1:3306", "address where the database is running on")
+		store   = envflag.String("STORER", "mysql", "storage backend, either mysql or memory")
+
+		taxRate          = envflag.Int64("TAX_RATE", 0, "tax rate applied to the order subtotal in basis points, e.g. 1500 for 15%")
+		taxRatesFile     = envflag.String("TAX_RATES_FILE", "", "JSON file of tax rates by region and tax category, empty taxes orders at TAX_RATE")
+		shippingPrice    = envflag.String("SHIPPING_PRICE", "0", "flat shipping price per order, e.g. 4.99")
+		freeShippingOver = envflag.String("FREE_SHIPPING_OVER", "0", "subtotal from which shipping is free, 0 disables it")
+
+		ratesFile = envflag.String("RATES_FILE", "", "JSON file of exchange rates against a base currency, empty allows only the store currency")
+
+		shippingMethodsFile = envflag.String("SHIPPING_METHODS_FILE", "", "JSON file of shipping methods, empty prices shipping with SHIPPING_PRICE")
+
+		paymentGateway = envflag.String("PAYMENT_GATEWAY", "", "payment provider, only fake for now, empty disables payments")
+		webhookSecret  = envflag.String("PAYMENT_WEBHOOK_SECRET", "", "secret signing the webhooks of the payment provider")
+		fakeWebhookURL = envflag.String("FAKE_GATEWAY_WEBHOOK_URL", "", "URL the fake gateway posts 3-D Secure outcomes to, e.g. http://localhost:8080/payments/webhook")
+
+		sellerName    = envflag.String("INVOICE_SELLER_NAME", "Conduit", "name of the store on its invoices")
+		sellerAddress = envflag.String("INVOICE_SELLER_ADDRESS", "", `address of the store on its invoices, lines separated by \n`)
+		sellerTaxID   = envflag.String("INVOICE_SELLER_TAX_ID", "", "tax identification number of the store on its invoices, e.g. a VAT number")
+
+		blobStore   = envflag.String("BLOB_STORE", "", "where product images are stored, either fs or s3, empty disables image uploads")
+		blobDir     = envflag.String("BLOB_DIR", "images", "directory the fs blob store keeps images in")
+		blobBaseURL = envflag.String("BLOB_BASE_URL", "http://localhost:8080/images", "URL the images of the fs blob store are served at")
+		s3Endpoint  = envflag.String("S3_ENDPOINT", "", "base URL of the S3-compatible storage, e.g. http://localhost:9000")
+		s3Region    = envflag.String("S3_REGION", "us-east-1", "region of the S3 bucket")
+		s3Bucket    = envflag.String("S3_BUCKET", "", "bucket the s3 blob store keeps images in")
+		s3AccessKey = envflag.String("S3_ACCESS_KEY", "", "access key of the S3-compatible storage")
+		s3SecretKey = envflag.String("S3_SECRET_KEY", "", "secret key of the S3-compatible storage")
+		s3PublicURL = envflag.String("S3_PUBLIC_URL", "", "URL the images of the s3 blob store are served at, e.g. a CDN, the bucket if empty")
 	)
 	envflag.Parse()
 
-	//instntiate db
-	db, err := db.NewDatabase(*dbAddr)
+	flatShipping, err := money.Parse(*shippingPrice)
+	if err != nil {
+		log.Fatalf("error parsing SHIPPING_PRICE: %v", err)
+	}
+	freeShipping, err := money.Parse(*freeShippingOver)
 	if err != nil {
-		log.Fatal("error opening database: %w", err)
+		log.Fatalf("error parsing FREE_SHIPPING_OVER: %v", err)
+	}
+
+	opts := []server.Option{
+		server.WithPricingPolicy(&server.FlatPricingPolicy{
+			TaxRate:          *taxRate,
+			ShippingPrice:    flatShipping,
+			FreeShippingOver: freeShipping,
+		}),
+		server.WithInvoiceSeller(invoice.Seller{
+			Name:    *sellerName,
+			Address: strings.ReplaceAll(*sellerAddress, `\n`, "\n"),
+			TaxID:   *sellerTaxID,
+		}),
+	}
+	if *ratesFile != "" {
+		rates, err := money.LoadStaticRates(*ratesFile)
+		if err != nil {
+			log.Fatalf("error loading RATES_FILE: %v", err)
+		}
+		opts = append(opts, server.WithRateProvider(rates))
+	}
+	if *shippingMethodsFile != "" {
+		methods, err := shipping.LoadMethods(*shippingMethodsFile)
+		if err != nil {
+			log.Fatalf("error loading SHIPPING_METHODS_FILE: %v", err)
+		}
+		opts = append(opts, server.WithShippingMethods(methods))
+	}
+	if *taxRatesFile != "" {
+		table, err := tax.LoadTable(*taxRatesFile)
+		if err != nil {
+			log.Fatalf("error loading TAX_RATES_FILE: %v", err)
+		}
+		opts = append(opts, server.WithTaxTable(table))
+	}
+	switch *paymentGateway {
+	case "":
+		log.Println("no payment gateway, orders cannot be paid")
+	case "fake":
+		if *webhookSecret == "" {
+			log.Fatalf("PAYMENT_WEBHOOK_SECRET must be set")
+		}
+		opts = append(opts, server.WithPaymentGateway(payment.NewFake([]byte(*webhookSecret), *fakeWebhookURL)))
+		log.Println("using fake payment gateway, no money is moved")
+	default:
+		log.Fatalf("unknown payment gateway %q", *paymentGateway)
 	}
-	defer db.Close()
-	log.Println("Successfully connected to the database")
 
-	// do something with the database
-	st := storer.NewMySQLStorer(db.GetDB())
-	srv := server.NewServer(st)
+	switch *blobStore {
+	case "":
+		log.Println("no blob store, product images cannot be uploaded")
+	case "fs":
+		opts = append(opts, server.WithBlobStore(blobstore.NewFS(*blobDir, *blobBaseURL)))
+		log.Printf("storing product images in %s", *blobDir)
+	case "s3":
+		if *s3Endpoint == "" || *s3Bucket == "" {
+			log.Fatalf("S3_ENDPOINT and S3_BUCKET must be set")
+		}
+		opts = append(opts, server.WithBlobStore(blobstore.NewS3(blobstore.S3Config{
+			Endpoint:  *s3Endpoint,
+			Region:    *s3Region,
+			Bucket:    *s3Bucket,
+			AccessKey: *s3AccessKey,
+			SecretKey: *s3SecretKey,
+			PublicURL: *s3PublicURL,
+		})))
+		log.Printf("storing product images in bucket %s", *s3Bucket)
+	default:
+		log.Fatalf("unknown blob store %q", *blobStore)
+	}
+
+	var st storer.Storer
+	switch *store {
+	case "memory":
+		st = storer.NewMemoryStorer()
+		log.Println("using in-memory storer, data will not be persisted")
+	case "mysql":
+		//instntiate db
+		db, err := db.NewDatabase(*dbAddr)
+		if err != nil {
+			log.Fatalf("error opening database: %v", err)
+		}
+		defer db.Close()
+		log.Println("Successfully connected to the database")
+
+		st = storer.NewMySQLStorer(db.GetDB())
+	default:
+		log.Fatalf("unknown storer %q", *store)
+	}
+	srv := server.NewServer(st, opts...)
 
 	//register our server with gRPC server
 
-	grpcSrv := grpc.NewServer()
+	// uploaded images are sent whole, along with the rest of their request
+	grpcSrv := grpc.NewServer(grpc.MaxRecvMsgSize(imaging.MaxSize + 1<<20))
 	pb.RegisterEcommServer(grpcSrv, srv)
 
 	listener, err := net.Listen("tcp", *svcAddr)
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `order_status_history`;
+
+UPDATE `orders` SET `status` = 'pending' WHERE `status` IN ('paid', 'processing', 'cancelled');
+UPDATE `orders` SET `status` = 'delivered' WHERE `status` IN ('refunded', 'returned');
+
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'shipped', 'delivered') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,14 @@
+ALTER TABLE `orders`
+    MODIFY COLUMN `status` ENUM('pending', 'paid', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded', 'returned') NOT NULL DEFAULT 'pending';
+
+CREATE TABLE `order_status_history` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL,
+  `from_status` varchar(32),
+  `to_status` varchar(32) NOT NULL,
+  `changed_by` int NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE `order_status_history`
+  ADD CONSTRAINT `order_status_history_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- MySQL may have dropped the implicit index of user_id_fk in favour of
+-- idx_orders_user_created, so keep an index on user_id for the foreign key.
+ALTER TABLE `orders`
+    DROP INDEX `idx_orders_user_created`,
+    ADD INDEX `idx_orders_user_id` (`user_id`);
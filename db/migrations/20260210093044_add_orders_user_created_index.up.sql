@@ -0,0 +1,2 @@
+-- Backs the keyset pagination of a user's orders, newest first.
+CREATE INDEX `idx_orders_user_created` ON `orders` (`user_id`, `created_at`, `id`);
@@ -0,0 +1,6 @@
+DROP INDEX `idx_notification_events_queue_created` ON `notification_events_queue`;
+DROP INDEX `idx_users_created` ON `users`;
+DROP INDEX `idx_orders_created` ON `orders`;
+DROP INDEX `idx_products_category` ON `products`;
+DROP INDEX `idx_products_created` ON `products`;
+DROP INDEX `idx_products_price` ON `products`;
@@ -0,0 +1,7 @@
+-- Back the keyset pagination and filters of the list endpoints.
+CREATE INDEX `idx_products_price` ON `products` (`price`, `id`);
+CREATE INDEX `idx_products_created` ON `products` (`created_at`, `id`);
+CREATE INDEX `idx_products_category` ON `products` (`category`);
+CREATE INDEX `idx_orders_created` ON `orders` (`created_at`, `id`);
+CREATE INDEX `idx_users_created` ON `users` (`created_at`, `id`);
+CREATE INDEX `idx_notification_events_queue_created` ON `notification_events_queue` (`created_at`, `id`);
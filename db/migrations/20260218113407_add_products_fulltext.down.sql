@@ -0,0 +1 @@
+ALTER TABLE `products` DROP INDEX `ft_products_search`;
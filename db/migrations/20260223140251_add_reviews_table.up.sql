@@ -0,0 +1,19 @@
+CREATE TABLE `reviews` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `rating` tinyint NOT NULL,
+  `comment` text NOT NULL,
+  `status` ENUM('published', 'hidden') NOT NULL DEFAULT 'published',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE (`product_id`, `user_id`),
+  CHECK (`rating` BETWEEN 1 AND 5),
+  CONSTRAINT `reviews_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `reviews_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
+
+CREATE INDEX `idx_reviews_product_created` ON `reviews` (`product_id`, `created_at`, `id`);
+
+-- ratings were set by hand until now, recompute them from the (empty) reviews
+UPDATE `products` SET `rating` = 0, `num_reviews` = 0;
@@ -0,0 +1,4 @@
+DELETE FROM `carts` WHERE `user_id` IS NULL;
+ALTER TABLE `carts` DROP INDEX `token`;
+ALTER TABLE `carts` DROP COLUMN `token`;
+ALTER TABLE `carts` MODIFY `user_id` int NOT NULL;
@@ -0,0 +1,4 @@
+-- guest carts have a token instead of a user
+ALTER TABLE `carts` MODIFY `user_id` int NULL;
+ALTER TABLE `carts` ADD COLUMN `token` varchar(64) NULL AFTER `user_id`;
+ALTER TABLE `carts` ADD UNIQUE (`token`);
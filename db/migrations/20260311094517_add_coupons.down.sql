@@ -0,0 +1,7 @@
+ALTER TABLE `orders` DROP FOREIGN KEY `orders_coupon_id_fk`;
+ALTER TABLE `orders`
+  DROP COLUMN `discount_price`,
+  DROP COLUMN `coupon_code`,
+  DROP COLUMN `coupon_id`;
+
+DROP TABLE IF EXISTS `coupons`;
@@ -0,0 +1,24 @@
+CREATE TABLE `coupons` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `code` varchar(64) NOT NULL,
+  `kind` ENUM('percentage', 'fixed') NOT NULL,
+  `value` decimal(10,2) NOT NULL,
+  `min_order_value` decimal(10,2) NOT NULL DEFAULT 0,
+  `expires_at` datetime,
+  `max_uses` int NOT NULL DEFAULT 0,
+  `max_uses_per_user` int NOT NULL DEFAULT 0,
+  `category` varchar(255) NOT NULL DEFAULT '',
+  `product_id` int,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE (`code`),
+  CHECK (`value` > 0),
+  CONSTRAINT `coupons_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+);
+
+-- the code and discount are kept on the order even if its coupon is deleted
+ALTER TABLE `orders`
+  ADD COLUMN `coupon_id` int AFTER `payment_method`,
+  ADD COLUMN `coupon_code` varchar(64) AFTER `coupon_id`,
+  ADD COLUMN `discount_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `coupon_code`,
+  ADD CONSTRAINT `orders_coupon_id_fk` FOREIGN KEY (`coupon_id`) REFERENCES `coupons` (`id`) ON DELETE SET NULL;
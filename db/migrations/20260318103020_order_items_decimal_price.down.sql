@@ -0,0 +1 @@
+ALTER TABLE `order_items` MODIFY `price` int NOT NULL;
@@ -0,0 +1,2 @@
+-- order items were priced in whole units, dropping the cents of every price
+ALTER TABLE `order_items` MODIFY `price` decimal(10,2) NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE `orders`
+  DROP COLUMN `exchange_rate`,
+  DROP COLUMN `currency`;
+ALTER TABLE `products` DROP COLUMN `currency`;
@@ -0,0 +1,5 @@
+-- prices so far were in the store currency, and orders were charged in it
+ALTER TABLE `products` ADD COLUMN `currency` char(3) NOT NULL DEFAULT 'USD' AFTER `price`;
+ALTER TABLE `orders`
+  ADD COLUMN `currency` char(3) NOT NULL DEFAULT 'USD' AFTER `total_price`,
+  ADD COLUMN `exchange_rate` decimal(18,6) NOT NULL DEFAULT 1 AFTER `currency`;
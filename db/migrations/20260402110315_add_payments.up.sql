@@ -0,0 +1,14 @@
+CREATE TABLE `payments` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL,
+  `provider` varchar(32) NOT NULL,
+  `provider_ref` varchar(255) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `currency` char(3) NOT NULL,
+  `status` ENUM('action_required', 'authorized', 'captured', 'declined', 'refunded') NOT NULL,
+  `decline_reason` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE (`provider`, `provider_ref`),
+  CONSTRAINT `payments_order_id_fk` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE
+);
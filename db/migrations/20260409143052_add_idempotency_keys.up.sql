@@ -0,0 +1,13 @@
+-- a zero status_code marks a request still in progress
+CREATE TABLE `idempotency_keys` (
+  `user_id` int NOT NULL,
+  `idem_key` varchar(255) NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `status_code` int NOT NULL DEFAULT 0,
+  `content_type` varchar(255) NOT NULL DEFAULT '',
+  `response_body` mediumblob,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_at` datetime NOT NULL,
+  PRIMARY KEY (`user_id`, `idem_key`),
+  CONSTRAINT `idempotency_keys_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+ALTER TABLE `orders`
+  DROP COLUMN `ship_phone`,
+  DROP COLUMN `ship_country`,
+  DROP COLUMN `ship_postal_code`,
+  DROP COLUMN `ship_region`,
+  DROP COLUMN `ship_city`,
+  DROP COLUMN `ship_line2`,
+  DROP COLUMN `ship_line1`,
+  DROP COLUMN `ship_name`;
+DROP TABLE IF EXISTS `user_addresses`;
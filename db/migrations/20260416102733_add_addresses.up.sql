@@ -0,0 +1,29 @@
+CREATE TABLE `user_addresses` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `line1` varchar(255) NOT NULL,
+  `line2` varchar(255) NOT NULL DEFAULT '',
+  `city` varchar(255) NOT NULL,
+  `region` varchar(255) NOT NULL DEFAULT '',
+  `postal_code` varchar(32) NOT NULL DEFAULT '',
+  `country` char(2) NOT NULL,
+  `phone` varchar(32) NOT NULL DEFAULT '',
+  `is_default` boolean NOT NULL DEFAULT false,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  INDEX `user_addresses_user_id_idx` (`user_id`),
+  CONSTRAINT `user_addresses_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+-- orders keep a copy of the address they ship to, empty for orders placed
+-- without one
+ALTER TABLE `orders`
+  ADD COLUMN `ship_name` varchar(255) NOT NULL DEFAULT '' AFTER `exchange_rate`,
+  ADD COLUMN `ship_line1` varchar(255) NOT NULL DEFAULT '' AFTER `ship_name`,
+  ADD COLUMN `ship_line2` varchar(255) NOT NULL DEFAULT '' AFTER `ship_line1`,
+  ADD COLUMN `ship_city` varchar(255) NOT NULL DEFAULT '' AFTER `ship_line2`,
+  ADD COLUMN `ship_region` varchar(255) NOT NULL DEFAULT '' AFTER `ship_city`,
+  ADD COLUMN `ship_postal_code` varchar(32) NOT NULL DEFAULT '' AFTER `ship_region`,
+  ADD COLUMN `ship_country` varchar(2) NOT NULL DEFAULT '' AFTER `ship_postal_code`,
+  ADD COLUMN `ship_phone` varchar(32) NOT NULL DEFAULT '' AFTER `ship_country`;
@@ -0,0 +1,6 @@
+ALTER TABLE `orders` DROP COLUMN `shipping_method`;
+ALTER TABLE `products`
+  DROP COLUMN `height`,
+  DROP COLUMN `width`,
+  DROP COLUMN `length`,
+  DROP COLUMN `weight`;
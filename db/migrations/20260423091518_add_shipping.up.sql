@@ -0,0 +1,7 @@
+-- weights are in grams and dimensions in millimetres, 0 when unknown
+ALTER TABLE `products`
+  ADD COLUMN `weight` int NOT NULL DEFAULT 0 AFTER `count_in_stock`,
+  ADD COLUMN `length` int NOT NULL DEFAULT 0 AFTER `weight`,
+  ADD COLUMN `width` int NOT NULL DEFAULT 0 AFTER `length`,
+  ADD COLUMN `height` int NOT NULL DEFAULT 0 AFTER `width`;
+ALTER TABLE `orders` ADD COLUMN `shipping_method` varchar(64) NOT NULL DEFAULT '' AFTER `shipping_price`;
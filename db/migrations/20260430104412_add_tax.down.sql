@@ -0,0 +1,5 @@
+ALTER TABLE `orders` DROP COLUMN `tax_inclusive`;
+ALTER TABLE `order_items`
+  DROP COLUMN `tax_price`,
+  DROP COLUMN `tax_rate`;
+ALTER TABLE `products` DROP COLUMN `tax_category`;
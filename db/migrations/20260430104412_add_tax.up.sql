@@ -0,0 +1,7 @@
+-- products without a tax category are taxed at the standard rate
+ALTER TABLE `products` ADD COLUMN `tax_category` varchar(32) NOT NULL DEFAULT '' AFTER `category`;
+-- tax rates are in basis points
+ALTER TABLE `order_items`
+  ADD COLUMN `tax_rate` int NOT NULL DEFAULT 0 AFTER `price`,
+  ADD COLUMN `tax_price` decimal(10,2) NOT NULL DEFAULT 0 AFTER `tax_rate`;
+ALTER TABLE `orders` ADD COLUMN `tax_inclusive` boolean NOT NULL DEFAULT false AFTER `tax_price`;
@@ -0,0 +1,51 @@
+-- the single row of invoice_sequence holds the number of the last invoice;
+-- invoices take the next number in the transaction that inserts them, so that
+-- numbers have no gaps
+CREATE TABLE `invoice_sequence` (
+  `id` int PRIMARY KEY NOT NULL,
+  `last_number` bigint NOT NULL
+);
+INSERT INTO `invoice_sequence` (`id`, `last_number`) VALUES (1, 0);
+
+-- invoices copy everything they show, and outlive their order
+CREATE TABLE `invoices` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `number` bigint NOT NULL UNIQUE,
+  `order_id` int NOT NULL UNIQUE,
+  `user_id` int NOT NULL,
+  `seller_name` varchar(255) NOT NULL DEFAULT '',
+  `seller_address` varchar(1024) NOT NULL DEFAULT '',
+  `seller_tax_id` varchar(64) NOT NULL DEFAULT '',
+  `bill_name` varchar(255) NOT NULL,
+  `bill_email` varchar(255) NOT NULL,
+  `ship_name` varchar(255) NOT NULL DEFAULT '',
+  `ship_line1` varchar(255) NOT NULL DEFAULT '',
+  `ship_line2` varchar(255) NOT NULL DEFAULT '',
+  `ship_city` varchar(255) NOT NULL DEFAULT '',
+  `ship_region` varchar(255) NOT NULL DEFAULT '',
+  `ship_postal_code` varchar(32) NOT NULL DEFAULT '',
+  `ship_country` varchar(2) NOT NULL DEFAULT '',
+  `ship_phone` varchar(32) NOT NULL DEFAULT '',
+  `currency` char(3) NOT NULL,
+  `subtotal` decimal(10,2) NOT NULL,
+  `discount_price` decimal(10,2) NOT NULL,
+  `tax_price` decimal(10,2) NOT NULL,
+  `tax_inclusive` boolean NOT NULL DEFAULT false,
+  `shipping_price` decimal(10,2) NOT NULL,
+  `shipping_method` varchar(64) NOT NULL DEFAULT '',
+  `total_price` decimal(10,2) NOT NULL,
+  `issued_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX `invoices_user_id_idx` (`user_id`)
+);
+
+CREATE TABLE `invoice_lines` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `invoice_id` int NOT NULL,
+  `product_id` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `quantity` int NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `tax_rate` int NOT NULL DEFAULT 0,
+  `tax_price` decimal(10,2) NOT NULL DEFAULT 0,
+  CONSTRAINT `invoice_lines_invoice_id_fk` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE CASCADE
+);
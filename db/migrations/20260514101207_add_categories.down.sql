@@ -0,0 +1,16 @@
+ALTER TABLE `coupons` ADD COLUMN `category` varchar(255) NOT NULL DEFAULT '' AFTER `category_id`;
+UPDATE `coupons` cp JOIN `categories` c ON c.`id` = cp.`category_id` SET cp.`category` = c.`name`;
+ALTER TABLE `coupons`
+  DROP FOREIGN KEY `coupons_category_id_fk`,
+  DROP COLUMN `category_id`;
+
+ALTER TABLE `products` DROP INDEX `ft_products_search`;
+ALTER TABLE `products` ADD COLUMN `category` varchar(255) NOT NULL DEFAULT '' AFTER `category_id`;
+UPDATE `products` p JOIN `categories` c ON c.`id` = p.`category_id` SET p.`category` = c.`name`;
+ALTER TABLE `products`
+  DROP FOREIGN KEY `products_category_id_fk`,
+  DROP COLUMN `category_id`;
+CREATE INDEX `idx_products_category` ON `products` (`category`);
+ALTER TABLE `products` ADD FULLTEXT INDEX `ft_products_search` (`name`, `description`, `category`);
+
+DROP TABLE IF EXISTS `categories`;
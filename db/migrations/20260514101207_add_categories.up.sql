@@ -0,0 +1,43 @@
+-- categories form a tree, top-level categories have no parent
+CREATE TABLE `categories` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `parent_id` int,
+  `name` varchar(255) NOT NULL,
+  `slug` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE (`slug`),
+  CONSTRAINT `categories_parent_id_fk` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`)
+);
+
+-- backfill a top-level category for the free-text categories of products and
+-- coupons; categories spelled with another case or punctuation share a slug
+-- and so a category
+INSERT INTO `categories` (`name`, `slug`)
+SELECT MIN(`name`), `slug` FROM (
+  SELECT TRIM(`category`) AS `name`, TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(`category`)), '[^a-z0-9]+', '-')) AS `slug` FROM `products`
+  UNION ALL
+  SELECT TRIM(`category`), TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(`category`)), '[^a-z0-9]+', '-')) FROM `coupons`
+) AS `c`
+WHERE `slug` <> ''
+GROUP BY `slug`;
+
+ALTER TABLE `products`
+  ADD COLUMN `category_id` int AFTER `category`,
+  ADD CONSTRAINT `products_category_id_fk` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`);
+UPDATE `products` p JOIN `categories` c
+  ON c.`slug` = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(p.`category`)), '[^a-z0-9]+', '-'))
+  SET p.`category_id` = c.`id`;
+
+ALTER TABLE `coupons`
+  ADD COLUMN `category_id` int AFTER `category`,
+  ADD CONSTRAINT `coupons_category_id_fk` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`);
+UPDATE `coupons` cp JOIN `categories` c
+  ON c.`slug` = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(TRIM(cp.`category`)), '[^a-z0-9]+', '-'))
+  SET cp.`category_id` = c.`id`;
+
+ALTER TABLE `products` DROP INDEX `ft_products_search`;
+DROP INDEX `idx_products_category` ON `products`;
+ALTER TABLE `products` DROP COLUMN `category`;
+ALTER TABLE `products` ADD FULLTEXT INDEX `ft_products_search` (`name`, `description`);
+ALTER TABLE `coupons` DROP COLUMN `category`;
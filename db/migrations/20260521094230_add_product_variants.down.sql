@@ -0,0 +1,4 @@
+ALTER TABLE `order_items`
+  DROP FOREIGN KEY `order_items_variant_id_fk`,
+  DROP COLUMN `variant_id`;
+DROP TABLE `product_variants`;
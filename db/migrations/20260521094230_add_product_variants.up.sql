@@ -0,0 +1,22 @@
+-- variants are the versions of a product that differ in their options, e.g.
+-- {"size": "M", "color": "red"}; their price overrides the price of the
+-- product unless NULL, and the stock of a product with variants is the total
+-- stock of its variants
+CREATE TABLE `product_variants` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `sku` varchar(64) NOT NULL,
+  `options` json NOT NULL,
+  `price` decimal(10,2),
+  `count_in_stock` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime,
+  UNIQUE (`sku`),
+  CHECK (`count_in_stock` >= 0),
+  CONSTRAINT `product_variants_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+);
+
+-- like products, ordered variants cannot be deleted
+ALTER TABLE `order_items`
+  ADD COLUMN `variant_id` int AFTER `product_id`,
+  ADD CONSTRAINT `order_items_variant_id_fk` FOREIGN KEY (`variant_id`) REFERENCES `product_variants` (`id`);
@@ -0,0 +1,19 @@
+-- the image gallery of a product, in the order of position; products.image
+-- mirrors the url of the first image, so that listings need no join. Images
+-- are stored in a blob store under image_key, their thumbnail under thumbnail_key.
+CREATE TABLE `product_images` (
+  `id` int PRIMARY KEY NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL,
+  `position` int NOT NULL,
+  `image_key` varchar(255) NOT NULL,
+  `url` varchar(1024) NOT NULL,
+  `thumbnail_key` varchar(255) NOT NULL,
+  `thumbnail_url` varchar(1024) NOT NULL,
+  `content_type` varchar(64) NOT NULL,
+  `size` int NOT NULL,
+  `width` int NOT NULL,
+  `height` int NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX (`product_id`, `position`),
+  CONSTRAINT `product_images_product_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+-- the SKU identifies a product in catalog imports and exports; products
+-- without one are NULL, which the unique key allows any number of
+ALTER TABLE `products`
+  ADD COLUMN `sku` varchar(64) AFTER `id`,
+  ADD CONSTRAINT `products_sku_key` UNIQUE (`sku`);
@@ -0,0 +1,2 @@
+-- products are searched by the name of their category too, through a join
+ALTER TABLE `categories` ADD FULLTEXT INDEX `ft_categories_name` (`name`);
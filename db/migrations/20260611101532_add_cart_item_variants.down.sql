@@ -0,0 +1,6 @@
+DELETE FROM `cart_items` WHERE `variant_id` IS NOT NULL;
+ALTER TABLE `cart_items`
+  DROP FOREIGN KEY `cart_items_variant_id_fk`,
+  ADD UNIQUE (`cart_id`, `product_id`),
+  DROP INDEX `cart_items_item_key`,
+  DROP COLUMN `variant_id`;
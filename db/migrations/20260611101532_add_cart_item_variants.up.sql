@@ -0,0 +1,8 @@
+-- carts hold a product once per variant; MySQL unique keys let NULL variants
+-- repeat, so the carts row lock keeps the items of products without variants
+-- unique
+ALTER TABLE `cart_items`
+  ADD COLUMN `variant_id` int AFTER `product_id`,
+  ADD UNIQUE `cart_items_item_key` (`cart_id`, `product_id`, `variant_id`),
+  DROP INDEX `cart_id`,
+  ADD CONSTRAINT `cart_items_variant_id_fk` FOREIGN KEY (`variant_id`) REFERENCES `product_variants` (`id`) ON DELETE CASCADE;
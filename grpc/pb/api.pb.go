@@ -22,12 +22,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ReviewStatus int32
+
+const (
+	ReviewStatus_PUBLISHED ReviewStatus = 0
+	ReviewStatus_HIDDEN    ReviewStatus = 1
+)
+
+// Enum value maps for ReviewStatus.
+var (
+	ReviewStatus_name = map[int32]string{
+		0: "PUBLISHED",
+		1: "HIDDEN",
+	}
+	ReviewStatus_value = map[string]int32{
+		"PUBLISHED": 0,
+		"HIDDEN":    1,
+	}
+)
+
+func (x ReviewStatus) Enum() *ReviewStatus {
+	p := new(ReviewStatus)
+	*p = x
+	return p
+}
+
+func (x ReviewStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[0].Descriptor()
+}
+
+func (ReviewStatus) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[0]
+}
+
+func (x ReviewStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewStatus.Descriptor instead.
+func (ReviewStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{0}
+}
+
 type OrderStatus int32
 
 const (
-	OrderStatus_PENDING   OrderStatus = 0
-	OrderStatus_SHIPPED   OrderStatus = 1
-	OrderStatus_DELIVERED OrderStatus = 2
+	OrderStatus_PENDING    OrderStatus = 0
+	OrderStatus_SHIPPED    OrderStatus = 1
+	OrderStatus_DELIVERED  OrderStatus = 2
+	OrderStatus_PAID       OrderStatus = 3
+	OrderStatus_PROCESSING OrderStatus = 4
+	OrderStatus_CANCELLED  OrderStatus = 5
+	OrderStatus_REFUNDED   OrderStatus = 6
+	OrderStatus_RETURNED   OrderStatus = 7
 )
 
 // Enum value maps for OrderStatus.
@@ -36,11 +87,21 @@ var (
 		0: "PENDING",
 		1: "SHIPPED",
 		2: "DELIVERED",
+		3: "PAID",
+		4: "PROCESSING",
+		5: "CANCELLED",
+		6: "REFUNDED",
+		7: "RETURNED",
 	}
 	OrderStatus_value = map[string]int32{
-		"PENDING":   0,
-		"SHIPPED":   1,
-		"DELIVERED": 2,
+		"PENDING":    0,
+		"SHIPPED":    1,
+		"DELIVERED":  2,
+		"PAID":       3,
+		"PROCESSING": 4,
+		"CANCELLED":  5,
+		"REFUNDED":   6,
+		"RETURNED":   7,
 	}
 )
 
@@ -55,11 +116,11 @@ func (x OrderStatus) String() string {
 }
 
 func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_enumTypes[0].Descriptor()
+	return file_api_proto_enumTypes[1].Descriptor()
 }
 
 func (OrderStatus) Type() protoreflect.EnumType {
-	return &file_api_proto_enumTypes[0]
+	return &file_api_proto_enumTypes[1]
 }
 
 func (x OrderStatus) Number() protoreflect.EnumNumber {
@@ -68,7 +129,110 @@ func (x OrderStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use OrderStatus.Descriptor instead.
 func (OrderStatus) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_rawDescGZIP(), []int{1}
+}
+
+// Payment status values are prefixed since enum values share the package
+// scope with OrderStatus.
+type PaymentStatus int32
+
+const (
+	PaymentStatus_PAYMENT_ACTION_REQUIRED PaymentStatus = 0
+	PaymentStatus_PAYMENT_AUTHORIZED      PaymentStatus = 1
+	PaymentStatus_PAYMENT_CAPTURED        PaymentStatus = 2
+	PaymentStatus_PAYMENT_DECLINED        PaymentStatus = 3
+	PaymentStatus_PAYMENT_REFUNDED        PaymentStatus = 4
+)
+
+// Enum value maps for PaymentStatus.
+var (
+	PaymentStatus_name = map[int32]string{
+		0: "PAYMENT_ACTION_REQUIRED",
+		1: "PAYMENT_AUTHORIZED",
+		2: "PAYMENT_CAPTURED",
+		3: "PAYMENT_DECLINED",
+		4: "PAYMENT_REFUNDED",
+	}
+	PaymentStatus_value = map[string]int32{
+		"PAYMENT_ACTION_REQUIRED": 0,
+		"PAYMENT_AUTHORIZED":      1,
+		"PAYMENT_CAPTURED":        2,
+		"PAYMENT_DECLINED":        3,
+		"PAYMENT_REFUNDED":        4,
+	}
+)
+
+func (x PaymentStatus) Enum() *PaymentStatus {
+	p := new(PaymentStatus)
+	*p = x
+	return p
+}
+
+func (x PaymentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[2].Descriptor()
+}
+
+func (PaymentStatus) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[2]
+}
+
+func (x PaymentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentStatus.Descriptor instead.
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{2}
+}
+
+type CouponKind int32
+
+const (
+	CouponKind_PERCENTAGE CouponKind = 0
+	CouponKind_FIXED      CouponKind = 1
+)
+
+// Enum value maps for CouponKind.
+var (
+	CouponKind_name = map[int32]string{
+		0: "PERCENTAGE",
+		1: "FIXED",
+	}
+	CouponKind_value = map[string]int32{
+		"PERCENTAGE": 0,
+		"FIXED":      1,
+	}
+)
+
+func (x CouponKind) Enum() *CouponKind {
+	p := new(CouponKind)
+	*p = x
+	return p
+}
+
+func (x CouponKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CouponKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_enumTypes[3].Descriptor()
+}
+
+func (CouponKind) Type() protoreflect.EnumType {
+	return &file_api_proto_enumTypes[3]
+}
+
+func (x CouponKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CouponKind.Descriptor instead.
+func (CouponKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{3}
 }
 
 type NotificationResponseType int32
@@ -101,11 +265,11 @@ func (x NotificationResponseType) String() string {
 }
 
 func (NotificationResponseType) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_proto_enumTypes[1].Descriptor()
+	return file_api_proto_enumTypes[4].Descriptor()
 }
 
 func (NotificationResponseType) Type() protoreflect.EnumType {
-	return &file_api_proto_enumTypes[1]
+	return &file_api_proto_enumTypes[4]
 }
 
 func (x NotificationResponseType) Number() protoreflect.EnumNumber {
@@ -114,20 +278,30 @@ func (x NotificationResponseType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NotificationResponseType.Descriptor instead.
 func (NotificationResponseType) EnumDescriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_rawDescGZIP(), []int{4}
 }
 
 type ProductReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	Rating        int64                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
-	NumReviews    int64                  `protobuf:"varint,7,opt,name=num_reviews,json=numReviews,proto3" json:"num_reviews,omitempty"`
-	Price         float32                `protobuf:"fixed32,8,opt,name=price,proto3" json:"price,omitempty"`
-	CountInStock  int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image        string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Description  string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CountInStock int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Price        int64                  `protobuf:"varint,10,opt,name=price,proto3" json:"price,omitempty"`
+	// currency of price, the store currency if empty
+	Currency        string `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	DisplayCurrency string `protobuf:"bytes,12,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	// weight in grams, dimensions in millimetres
+	Weight int64 `protobuf:"varint,13,opt,name=weight,proto3" json:"weight,omitempty"`
+	Length int64 `protobuf:"varint,14,opt,name=length,proto3" json:"length,omitempty"`
+	Width  int64 `protobuf:"varint,15,opt,name=width,proto3" json:"width,omitempty"`
+	Height int64 `protobuf:"varint,16,opt,name=height,proto3" json:"height,omitempty"`
+	// tax category of the product, the standard one if empty
+	TaxCategory string `protobuf:"bytes,17,opt,name=tax_category,json=taxCategory,proto3" json:"tax_category,omitempty"`
+	CategoryId  int64  `protobuf:"varint,18,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// stock keeping unit, unique across products, which imports match on
+	Sku           string `protobuf:"bytes,19,opt,name=sku,proto3" json:"sku,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -183,61 +357,112 @@ func (x *ProductReq) GetImage() string {
 	return ""
 }
 
-func (x *ProductReq) GetCategory() string {
+func (x *ProductReq) GetDescription() string {
 	if x != nil {
-		return x.Category
+		return x.Description
 	}
 	return ""
 }
 
-func (x *ProductReq) GetDescription() string {
+func (x *ProductReq) GetCountInStock() int64 {
 	if x != nil {
-		return x.Description
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *ProductReq) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductReq) GetCurrency() string {
+	if x != nil {
+		return x.Currency
 	}
 	return ""
 }
 
-func (x *ProductReq) GetRating() int64 {
+func (x *ProductReq) GetDisplayCurrency() string {
 	if x != nil {
-		return x.Rating
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *ProductReq) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
 	}
 	return 0
 }
 
-func (x *ProductReq) GetNumReviews() int64 {
+func (x *ProductReq) GetLength() int64 {
 	if x != nil {
-		return x.NumReviews
+		return x.Length
 	}
 	return 0
 }
 
-func (x *ProductReq) GetPrice() float32 {
+func (x *ProductReq) GetWidth() int64 {
 	if x != nil {
-		return x.Price
+		return x.Width
 	}
 	return 0
 }
 
-func (x *ProductReq) GetCountInStock() int64 {
+func (x *ProductReq) GetHeight() int64 {
 	if x != nil {
-		return x.CountInStock
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ProductReq) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *ProductReq) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
 	}
 	return 0
 }
 
+func (x *ProductReq) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
 type ProductRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
 	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
 	Rating        int64                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
 	NumReviews    int64                  `protobuf:"varint,7,opt,name=num_reviews,json=numReviews,proto3" json:"num_reviews,omitempty"`
-	Price         float32                `protobuf:"fixed32,8,opt,name=price,proto3" json:"price,omitempty"`
 	CountInStock  int64                  `protobuf:"varint,9,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Price         int64                  `protobuf:"varint,12,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string                 `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	Weight        int64                  `protobuf:"varint,14,opt,name=weight,proto3" json:"weight,omitempty"`
+	Length        int64                  `protobuf:"varint,15,opt,name=length,proto3" json:"length,omitempty"`
+	Width         int64                  `protobuf:"varint,16,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int64                  `protobuf:"varint,17,opt,name=height,proto3" json:"height,omitempty"`
+	TaxCategory   string                 `protobuf:"bytes,18,opt,name=tax_category,json=taxCategory,proto3" json:"tax_category,omitempty"`
+	CategoryId    int64                  `protobuf:"varint,19,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Variants      []*VariantRes          `protobuf:"bytes,20,rep,name=variants,proto3" json:"variants,omitempty"`
+	Images        []*ProductImageRes     `protobuf:"bytes,21,rep,name=images,proto3" json:"images,omitempty"`
+	Sku           string                 `protobuf:"bytes,22,opt,name=sku,proto3" json:"sku,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -293,13 +518,6 @@ func (x *ProductRes) GetImage() string {
 	return ""
 }
 
-func (x *ProductRes) GetCategory() string {
-	if x != nil {
-		return x.Category
-	}
-	return ""
-}
-
 func (x *ProductRes) GetDescription() string {
 	if x != nil {
 		return x.Description
@@ -321,13 +539,6 @@ func (x *ProductRes) GetNumReviews() int64 {
 	return 0
 }
 
-func (x *ProductRes) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
 func (x *ProductRes) GetCountInStock() int64 {
 	if x != nil {
 		return x.CountInStock
@@ -349,76 +560,116 @@ func (x *ProductRes) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type ListProductRes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductRes          `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProductRes) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
 }
 
-func (x *ListProductRes) Reset() {
-	*x = ListProductRes{}
-	mi := &file_api_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ProductRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
 }
 
-func (x *ListProductRes) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ProductRes) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
 }
 
-func (*ListProductRes) ProtoMessage() {}
+func (x *ProductRes) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
 
-func (x *ListProductRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[2]
+func (x *ProductRes) GetWidth() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Width
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use ListProductRes.ProtoReflect.Descriptor instead.
-func (*ListProductRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{2}
+func (x *ProductRes) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
 }
 
-func (x *ListProductRes) GetProducts() []*ProductRes {
+func (x *ProductRes) GetTaxCategory() string {
 	if x != nil {
-		return x.Products
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *ProductRes) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *ProductRes) GetVariants() []*VariantRes {
+	if x != nil {
+		return x.Variants
 	}
 	return nil
 }
 
-type OrderItem struct {
+func (x *ProductRes) GetImages() []*ProductImageRes {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *ProductRes) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+// Variants are the versions of a product a customer picks from, such as a
+// size or a color, each with its own SKU and stock. The stock of a product
+// with variants is the total stock of its variants, and orders for it must
+// name a variant. A variant costs the price of its product unless it has a
+// non-zero price of its own, in the currency of its product; updating the
+// price to 0 drops the override.
+type VariantReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Price         float32                `protobuf:"fixed32,4,opt,name=price,proto3" json:"price,omitempty"`
-	ProductId     int64                  `protobuf:"varint,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sku           string                 `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Options       map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price         *int64                 `protobuf:"varint,5,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	CountInStock  *int64                 `protobuf:"varint,6,opt,name=count_in_stock,json=countInStock,proto3,oneof" json:"count_in_stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderItem) Reset() {
-	*x = OrderItem{}
-	mi := &file_api_proto_msgTypes[3]
+func (x *VariantReq) Reset() {
+	*x = VariantReq{}
+	mi := &file_api_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderItem) String() string {
+func (x *VariantReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderItem) ProtoMessage() {}
+func (*VariantReq) ProtoMessage() {}
 
-func (x *OrderItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[3]
+func (x *VariantReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -429,76 +680,83 @@ func (x *OrderItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
-func (*OrderItem) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use VariantReq.ProtoReflect.Descriptor instead.
+func (*VariantReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *OrderItem) GetName() string {
+func (x *VariantReq) GetId() int64 {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *OrderItem) GetQuantity() int64 {
+func (x *VariantReq) GetProductId() int64 {
 	if x != nil {
-		return x.Quantity
+		return x.ProductId
 	}
 	return 0
 }
 
-func (x *OrderItem) GetImage() string {
+func (x *VariantReq) GetSku() string {
 	if x != nil {
-		return x.Image
+		return x.Sku
 	}
 	return ""
 }
 
-func (x *OrderItem) GetPrice() float32 {
+func (x *VariantReq) GetOptions() map[string]string {
 	if x != nil {
-		return x.Price
+		return x.Options
+	}
+	return nil
+}
+
+func (x *VariantReq) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
 	}
 	return 0
 }
 
-func (x *OrderItem) GetProductId() int64 {
-	if x != nil {
-		return x.ProductId
+func (x *VariantReq) GetCountInStock() int64 {
+	if x != nil && x.CountInStock != nil {
+		return *x.CountInStock
 	}
 	return 0
 }
 
-type OrderReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
-	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
-	TaxPrice      float32                `protobuf:"fixed32,4,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
-	ShippingPrice float32                `protobuf:"fixed32,5,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
-	TotalPrice    float32                `protobuf:"fixed32,6,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	UserEmail     string                 `protobuf:"bytes,8,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,9,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+type VariantRes struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sku       string                 `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Options   map[string]string      `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// price of the variant if it overrides the price of its product
+	Price         *int64                 `protobuf:"varint,5,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	CountInStock  int64                  `protobuf:"varint,6,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderReq) Reset() {
-	*x = OrderReq{}
-	mi := &file_api_proto_msgTypes[4]
+func (x *VariantRes) Reset() {
+	*x = VariantRes{}
+	mi := &file_api_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderReq) String() string {
+func (x *VariantRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderReq) ProtoMessage() {}
+func (*VariantRes) ProtoMessage() {}
 
-func (x *OrderReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[4]
+func (x *VariantRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,104 +767,162 @@ func (x *OrderReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderReq.ProtoReflect.Descriptor instead.
-func (*OrderReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use VariantRes.ProtoReflect.Descriptor instead.
+func (*VariantRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *OrderReq) GetId() int64 {
+func (x *VariantRes) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *OrderReq) GetItems() []*OrderItem {
+func (x *VariantRes) GetProductId() int64 {
 	if x != nil {
-		return x.Items
+		return x.ProductId
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderReq) GetPaymentMethod() string {
+func (x *VariantRes) GetSku() string {
 	if x != nil {
-		return x.PaymentMethod
+		return x.Sku
 	}
 	return ""
 }
 
-func (x *OrderReq) GetTaxPrice() float32 {
+func (x *VariantRes) GetOptions() map[string]string {
 	if x != nil {
-		return x.TaxPrice
+		return x.Options
+	}
+	return nil
+}
+
+func (x *VariantRes) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
 	}
 	return 0
 }
 
-func (x *OrderReq) GetShippingPrice() float32 {
+func (x *VariantRes) GetCountInStock() int64 {
 	if x != nil {
-		return x.ShippingPrice
+		return x.CountInStock
 	}
 	return 0
 }
 
-func (x *OrderReq) GetTotalPrice() float32 {
+func (x *VariantRes) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TotalPrice
+		return x.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *OrderReq) GetUserId() int64 {
+func (x *VariantRes) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.UserId
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// The gallery of a product is an ordered list of JPEG, PNG or GIF images of
+// at most 5 MiB, each with a thumbnail. The image of a product is the first
+// image of its gallery.
+type ProductImageReq struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// content of the uploaded image
+	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductImageReq) Reset() {
+	*x = ProductImageReq{}
+	mi := &file_api_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductImageReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductImageReq) ProtoMessage() {}
+
+func (x *ProductImageReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductImageReq.ProtoReflect.Descriptor instead.
+func (*ProductImageReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProductImageReq) GetId() int64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *OrderReq) GetUserEmail() string {
+func (x *ProductImageReq) GetProductId() int64 {
 	if x != nil {
-		return x.UserEmail
+		return x.ProductId
 	}
-	return ""
+	return 0
 }
 
-func (x *OrderReq) GetStatus() OrderStatus {
+func (x *ProductImageReq) GetData() []byte {
 	if x != nil {
-		return x.Status
+		return x.Data
 	}
-	return OrderStatus_PENDING
+	return nil
 }
 
-type OrderRes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
-	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
-	TaxPrice      float32                `protobuf:"fixed32,4,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
-	ShippingPrice float32                `protobuf:"fixed32,5,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
-	TotalPrice    float32                `protobuf:"fixed32,6,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,10,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+type ProductImageRes struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId    int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Url          string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	ThumbnailUrl string                 `protobuf:"bytes,4,opt,name=thumbnail_url,json=thumbnailUrl,proto3" json:"thumbnail_url,omitempty"`
+	ContentType  string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// size in bytes, width and height in pixels
+	Size          int64                  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	Width         int64                  `protobuf:"varint,7,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int64                  `protobuf:"varint,8,opt,name=height,proto3" json:"height,omitempty"`
+	Position      int64                  `protobuf:"varint,9,opt,name=position,proto3" json:"position,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderRes) Reset() {
-	*x = OrderRes{}
+func (x *ProductImageRes) Reset() {
+	*x = ProductImageRes{}
 	mi := &file_api_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderRes) String() string {
+func (x *ProductImageRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderRes) ProtoMessage() {}
+func (*ProductImageRes) ProtoMessage() {}
 
-func (x *OrderRes) ProtoReflect() protoreflect.Message {
+func (x *ProductImageRes) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -618,102 +934,104 @@ func (x *OrderRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderRes.ProtoReflect.Descriptor instead.
-func (*OrderRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProductImageRes.ProtoReflect.Descriptor instead.
+func (*ProductImageRes) Descriptor() ([]byte, []int) {
 	return file_api_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *OrderRes) GetId() int64 {
+func (x *ProductImageRes) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *OrderRes) GetItems() []*OrderItem {
+func (x *ProductImageRes) GetProductId() int64 {
 	if x != nil {
-		return x.Items
+		return x.ProductId
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderRes) GetPaymentMethod() string {
+func (x *ProductImageRes) GetUrl() string {
 	if x != nil {
-		return x.PaymentMethod
+		return x.Url
 	}
 	return ""
 }
 
-func (x *OrderRes) GetTaxPrice() float32 {
+func (x *ProductImageRes) GetThumbnailUrl() string {
 	if x != nil {
-		return x.TaxPrice
+		return x.ThumbnailUrl
 	}
-	return 0
+	return ""
 }
 
-func (x *OrderRes) GetShippingPrice() float32 {
+func (x *ProductImageRes) GetContentType() string {
 	if x != nil {
-		return x.ShippingPrice
+		return x.ContentType
 	}
-	return 0
+	return ""
 }
 
-func (x *OrderRes) GetTotalPrice() float32 {
+func (x *ProductImageRes) GetSize() int64 {
 	if x != nil {
-		return x.TotalPrice
+		return x.Size
 	}
 	return 0
 }
 
-func (x *OrderRes) GetUserId() int64 {
+func (x *ProductImageRes) GetWidth() int64 {
 	if x != nil {
-		return x.UserId
+		return x.Width
 	}
 	return 0
 }
 
-func (x *OrderRes) GetCreatedAt() *timestamppb.Timestamp {
+func (x *ProductImageRes) GetHeight() int64 {
 	if x != nil {
-		return x.CreatedAt
+		return x.Height
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderRes) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *ProductImageRes) GetPosition() int64 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Position
 	}
-	return nil
+	return 0
 }
 
-func (x *OrderRes) GetStatus() OrderStatus {
+func (x *ProductImageRes) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Status
+		return x.CreatedAt
 	}
-	return OrderStatus_PENDING
+	return nil
 }
 
-type ListOrderRes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*OrderRes            `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+type ReorderProductImagesReq struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// ids of every image of the product, in their new order
+	ImageIds      []int64 `protobuf:"varint,2,rep,packed,name=image_ids,json=imageIds,proto3" json:"image_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrderRes) Reset() {
-	*x = ListOrderRes{}
+func (x *ReorderProductImagesReq) Reset() {
+	*x = ReorderProductImagesReq{}
 	mi := &file_api_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrderRes) String() string {
+func (x *ReorderProductImagesReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrderRes) ProtoMessage() {}
+func (*ReorderProductImagesReq) ProtoMessage() {}
 
-func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
+func (x *ReorderProductImagesReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -725,43 +1043,54 @@ func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrderRes.ProtoReflect.Descriptor instead.
-func (*ListOrderRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReorderProductImagesReq.ProtoReflect.Descriptor instead.
+func (*ReorderProductImagesReq) Descriptor() ([]byte, []int) {
 	return file_api_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListOrderRes) GetOrders() []*OrderRes {
+func (x *ReorderProductImagesReq) GetProductId() int64 {
 	if x != nil {
-		return x.Orders
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReorderProductImagesReq) GetImageIds() []int64 {
+	if x != nil {
+		return x.ImageIds
 	}
 	return nil
 }
 
-type UserReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
-	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+// ImportProducts upserts products by sku from a stream of rows, saving them
+// in batches of one transaction each. Rows are patches, like UpdateProduct,
+// for products that exist: their empty fields keep the current values.
+type ImportProductsReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// line of the row in the imported file, reported along with its error
+	Row     int64       `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Product *ProductReq `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	// validates the rows without saving them; set on the first row, it applies
+	// to the whole import
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserReq) Reset() {
-	*x = UserReq{}
+func (x *ImportProductsReq) Reset() {
+	*x = ImportProductsReq{}
 	mi := &file_api_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserReq) String() string {
+func (x *ImportProductsReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserReq) ProtoMessage() {}
+func (*ImportProductsReq) ProtoMessage() {}
 
-func (x *UserReq) ProtoReflect() protoreflect.Message {
+func (x *ImportProductsReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -773,73 +1102,125 @@ func (x *UserReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserReq.ProtoReflect.Descriptor instead.
-func (*UserReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImportProductsReq.ProtoReflect.Descriptor instead.
+func (*ImportProductsReq) Descriptor() ([]byte, []int) {
 	return file_api_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *UserReq) GetId() int64 {
+func (x *ImportProductsReq) GetRow() int64 {
 	if x != nil {
-		return x.Id
+		return x.Row
 	}
 	return 0
 }
 
-func (x *UserReq) GetName() string {
+func (x *ImportProductsReq) GetProduct() *ProductReq {
 	if x != nil {
-		return x.Name
+		return x.Product
 	}
-	return ""
+	return nil
 }
 
-func (x *UserReq) GetEmail() string {
+func (x *ImportProductsReq) GetDryRun() bool {
 	if x != nil {
-		return x.Email
+		return x.DryRun
 	}
-	return ""
+	return false
 }
 
-func (x *UserReq) GetPassword() string {
+type ImportProductsRes struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Created int64                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Updated int64                  `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	// rows that were not imported, in the order of the stream
+	Errors        []*ImportRowError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	DryRun        bool              `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportProductsRes) Reset() {
+	*x = ImportProductsRes{}
+	mi := &file_api_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportProductsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportProductsRes) ProtoMessage() {}
+
+func (x *ImportProductsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[8]
 	if x != nil {
-		return x.Password
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *UserReq) GetIsAdmin() bool {
+// Deprecated: Use ImportProductsRes.ProtoReflect.Descriptor instead.
+func (*ImportProductsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ImportProductsRes) GetCreated() int64 {
 	if x != nil {
-		return x.IsAdmin
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportProductsRes) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportProductsRes) GetErrors() []*ImportRowError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportProductsRes) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
 	}
 	return false
 }
 
-type UserRes struct {
+type ImportRowError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
-	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Row           int64                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserRes) Reset() {
-	*x = UserRes{}
-	mi := &file_api_proto_msgTypes[8]
+func (x *ImportRowError) Reset() {
+	*x = ImportRowError{}
+	mi := &file_api_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserRes) String() string {
+func (x *ImportRowError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserRes) ProtoMessage() {}
+func (*ImportRowError) ProtoMessage() {}
 
-func (x *UserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[8]
+func (x *ImportRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -850,75 +1231,4000 @@ func (x *UserRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserRes.ProtoReflect.Descriptor instead.
-func (*UserRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ImportRowError.ProtoReflect.Descriptor instead.
+func (*ImportRowError) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UserRes) GetId() int64 {
+func (x *ImportRowError) GetRow() int64 {
 	if x != nil {
-		return x.Id
+		return x.Row
 	}
 	return 0
 }
 
-func (x *UserRes) GetName() string {
+func (x *ImportRowError) GetSku() string {
 	if x != nil {
-		return x.Name
+		return x.Sku
 	}
 	return ""
 }
 
-func (x *UserRes) GetEmail() string {
+func (x *ImportRowError) GetError() string {
 	if x != nil {
-		return x.Email
+		return x.Error
 	}
 	return ""
 }
 
-func (x *UserRes) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
+type ListProductsReq struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy    string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// slug of a category, listing the products of its subcategories too
+	Category string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	InStock  bool   `protobuf:"varint,7,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	// price bounds in display_currency, or else in the currency of the catalog
+	MinPrice        *int64 `protobuf:"varint,8,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice        *int64 `protobuf:"varint,9,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	DisplayCurrency string `protobuf:"bytes,10,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListProductsReq) Reset() {
+	*x = ListProductsReq{}
+	mi := &file_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserRes) GetIsAdmin() bool {
+func (x *ListProductsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsReq) ProtoMessage() {}
+
+func (x *ListProductsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[10]
 	if x != nil {
-		return x.IsAdmin
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsReq.ProtoReflect.Descriptor instead.
+func (*ListProductsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListProductsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProductsReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListProductsReq) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListProductsReq) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *ListProductsReq) GetMinPrice() int64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListProductsReq) GetMaxPrice() int64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListProductsReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type ListProductRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductRes          `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductRes) Reset() {
+	*x = ListProductRes{}
+	mi := &file_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductRes) ProtoMessage() {}
+
+func (x *ListProductRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductRes.ProtoReflect.Descriptor instead.
+func (*ListProductRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListProductRes) GetProducts() []*ProductRes {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ListProductRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SearchProductsReq struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Query           string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize        int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken       string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,4,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchProductsReq) Reset() {
+	*x = SearchProductsReq{}
+	mi := &file_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsReq) ProtoMessage() {}
+
+func (x *SearchProductsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsReq.ProtoReflect.Descriptor instead.
+func (*SearchProductsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchProductsReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchProductsReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchProductsReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type ProductMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *ProductRes            `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Snippet       string                 `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductMatch) Reset() {
+	*x = ProductMatch{}
+	mi := &file_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductMatch) ProtoMessage() {}
+
+func (x *ProductMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductMatch.ProtoReflect.Descriptor instead.
+func (*ProductMatch) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProductMatch) GetProduct() *ProductRes {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductMatch) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProductMatch) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchProductsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*ProductMatch        `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRes) Reset() {
+	*x = SearchProductsRes{}
+	mi := &file_api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRes) ProtoMessage() {}
+
+func (x *SearchProductsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRes.ProtoReflect.Descriptor instead.
+func (*SearchProductsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchProductsRes) GetMatches() []*ProductMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *SearchProductsRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Categories form a tree: a category without parent_id is a root. Slugs are
+// the lowercase words of the name joined by hyphens unless set, and unique.
+type CategoryReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	ParentId      *int64                 `protobuf:"varint,4,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryReq) Reset() {
+	*x = CategoryReq{}
+	mi := &file_api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryReq) ProtoMessage() {}
+
+func (x *CategoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryReq.ProtoReflect.Descriptor instead.
+func (*CategoryReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CategoryReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CategoryReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryReq) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CategoryReq) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+type CategoryRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId      int64                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryRes) Reset() {
+	*x = CategoryRes{}
+	mi := &file_api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryRes) ProtoMessage() {}
+
+func (x *CategoryRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryRes.ProtoReflect.Descriptor instead.
+func (*CategoryRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CategoryRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CategoryRes) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CategoryRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryRes) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CategoryRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CategoryRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListCategoriesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesReq) Reset() {
+	*x = ListCategoriesReq{}
+	mi := &file_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesReq) ProtoMessage() {}
+
+func (x *ListCategoriesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesReq.ProtoReflect.Descriptor instead.
+func (*ListCategoriesReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{17}
+}
+
+type ListCategoriesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*CategoryRes         `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRes) Reset() {
+	*x = ListCategoriesRes{}
+	mi := &file_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRes) ProtoMessage() {}
+
+func (x *ListCategoriesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRes.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListCategoriesRes) GetCategories() []*CategoryRes {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ReviewReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int64                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Comment       string                 `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	Status        ReviewStatus           `protobuf:"varint,6,opt,name=status,proto3,enum=pb.ReviewStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewReq) Reset() {
+	*x = ReviewReq{}
+	mi := &file_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewReq) ProtoMessage() {}
+
+func (x *ReviewReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewReq.ProtoReflect.Descriptor instead.
+func (*ReviewReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReviewReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetRating() int64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ReviewReq) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *ReviewReq) GetStatus() ReviewStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReviewStatus_PUBLISHED
+}
+
+type ReviewRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int64                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Comment       string                 `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	Status        ReviewStatus           `protobuf:"varint,6,opt,name=status,proto3,enum=pb.ReviewStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewRes) Reset() {
+	*x = ReviewRes{}
+	mi := &file_api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewRes) ProtoMessage() {}
+
+func (x *ReviewRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewRes.ProtoReflect.Descriptor instead.
+func (*ReviewRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReviewRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetRating() int64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ReviewRes) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *ReviewRes) GetStatus() ReviewStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReviewStatus_PUBLISHED
+}
+
+func (x *ReviewRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReviewRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListReviewsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy        string                 `protobuf:"bytes,4,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Status        *ReviewStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=pb.ReviewStatus,oneof" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsReq) Reset() {
+	*x = ListReviewsReq{}
+	mi := &file_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsReq) ProtoMessage() {}
+
+func (x *ListReviewsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsReq.ProtoReflect.Descriptor instead.
+func (*ListReviewsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListReviewsReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ListReviewsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReviewsReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListReviewsReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListReviewsReq) GetStatus() ReviewStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ReviewStatus_PUBLISHED
+}
+
+type ListReviewsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*ReviewRes           `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRes) Reset() {
+	*x = ListReviewsRes{}
+	mi := &file_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRes) ProtoMessage() {}
+
+func (x *ListReviewsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRes.ProtoReflect.Descriptor instead.
+func (*ListReviewsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListReviewsRes) GetReviews() []*ReviewRes {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type OrderItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity  int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Image     string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	ProductId int64                  `protobuf:"varint,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Price     int64                  `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
+	// tax of the item, computed by the server, at tax_rate basis points
+	TaxRate  int64 `protobuf:"varint,7,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	TaxPrice int64 `protobuf:"varint,8,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	// variant ordered, required for products with variants
+	VariantId     int64 `protobuf:"varint,9,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *OrderItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *OrderItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderItem) GetTaxRate() int64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *OrderItem) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+func (x *OrderItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type OrderReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserEmail     string                 `protobuf:"bytes,8,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,9,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,10,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	CouponCode    string                 `protobuf:"bytes,11,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	TaxPrice      int64                  `protobuf:"varint,12,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	ShippingPrice int64                  `protobuf:"varint,13,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TotalPrice    int64                  `protobuf:"varint,14,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	// currency the order is charged in, the store currency if empty
+	Currency string `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	// address book entry the order ships to, the default address if zero
+	AddressId int64 `protobuf:"varint,16,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	// shipping method the order ships with, the cheapest one if empty
+	ShippingMethod string `protobuf:"bytes,17,opt,name=shipping_method,json=shippingMethod,proto3" json:"shipping_method,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OrderReq) Reset() {
+	*x = OrderReq{}
+	mi := &file_api_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReq) ProtoMessage() {}
+
+func (x *OrderReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReq.ProtoReflect.Descriptor instead.
+func (*OrderReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *OrderReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderReq) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderReq) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *OrderReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetUserEmail() string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return ""
+}
+
+func (x *OrderReq) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *OrderReq) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *OrderReq) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+func (x *OrderReq) GetShippingPrice() int64 {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return 0
+}
+
+func (x *OrderReq) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *OrderReq) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OrderReq) GetAddressId() int64 {
+	if x != nil {
+		return x.AddressId
+	}
+	return 0
+}
+
+func (x *OrderReq) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+type OrderRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	UserId        int64                  `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,10,opt,name=status,proto3,enum=pb.OrderStatus" json:"status,omitempty"`
+	CouponCode    string                 `protobuf:"bytes,11,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	DiscountPrice int64                  `protobuf:"varint,13,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	TaxPrice      int64                  `protobuf:"varint,14,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	ShippingPrice int64                  `protobuf:"varint,15,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TotalPrice    int64                  `protobuf:"varint,16,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Currency      string                 `protobuf:"bytes,17,opt,name=currency,proto3" json:"currency,omitempty"`
+	// rate the amounts were converted from the store currency at
+	ExchangeRate int64 `protobuf:"varint,18,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`
+	// copy of the address the order ships to, unset if it has none
+	ShippingAddress *ShippingAddress `protobuf:"bytes,19,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	ShippingMethod  string           `protobuf:"bytes,20,opt,name=shipping_method,json=shippingMethod,proto3" json:"shipping_method,omitempty"`
+	// whether the item prices include tax_price, rather than tax_price being
+	// added to them
+	TaxInclusive  bool `protobuf:"varint,21,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRes) Reset() {
+	*x = OrderRes{}
+	mi := &file_api_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRes) ProtoMessage() {}
+
+func (x *OrderRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRes.ProtoReflect.Descriptor instead.
+func (*OrderRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *OrderRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderRes) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderRes) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *OrderRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *OrderRes) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderRes) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *OrderRes) GetDiscountPrice() int64 {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return 0
+}
+
+func (x *OrderRes) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+func (x *OrderRes) GetShippingPrice() int64 {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return 0
+}
+
+func (x *OrderRes) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *OrderRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OrderRes) GetExchangeRate() int64 {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return 0
+}
+
+func (x *OrderRes) GetShippingAddress() *ShippingAddress {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *OrderRes) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+func (x *OrderRes) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+type ShippingAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Phone         string                 `protobuf:"bytes,8,opt,name=phone,proto3" json:"phone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingAddress) Reset() {
+	*x = ShippingAddress{}
+	mi := &file_api_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingAddress) ProtoMessage() {}
+
+func (x *ShippingAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingAddress.ProtoReflect.Descriptor instead.
+func (*ShippingAddress) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ShippingAddress) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ShippingAddress) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type ListOrderRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*OrderRes            `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderRes) Reset() {
+	*x = ListOrderRes{}
+	mi := &file_api_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderRes) ProtoMessage() {}
+
+func (x *ListOrderRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderRes.ProtoReflect.Descriptor instead.
+func (*ListOrderRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListOrderRes) GetOrders() []*OrderRes {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrderRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListOrdersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	UserId        int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        *OrderStatus           `protobuf:"varint,5,opt,name=status,proto3,enum=pb.OrderStatus,oneof" json:"status,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersReq) Reset() {
+	*x = ListOrdersReq{}
+	mi := &file_api_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersReq) ProtoMessage() {}
+
+func (x *ListOrdersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersReq.ProtoReflect.Descriptor instead.
+func (*ListOrdersReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListOrdersReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListOrdersReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListOrdersReq) GetStatus() OrderStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *ListOrdersReq) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListOrdersReq) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+type ListUserOrdersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Status        *OrderStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=pb.OrderStatus,oneof" json:"status,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	SortBy        string                 `protobuf:"bytes,7,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserOrdersReq) Reset() {
+	*x = ListUserOrdersReq{}
+	mi := &file_api_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserOrdersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserOrdersReq) ProtoMessage() {}
+
+func (x *ListUserOrdersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserOrdersReq.ProtoReflect.Descriptor instead.
+func (*ListUserOrdersReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListUserOrdersReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListUserOrdersReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUserOrdersReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUserOrdersReq) GetStatus() OrderStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *ListUserOrdersReq) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListUserOrdersReq) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListUserOrdersReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+type OrderStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	FromStatus    *OrderStatus           `protobuf:"varint,3,opt,name=from_status,json=fromStatus,proto3,enum=pb.OrderStatus,oneof" json:"from_status,omitempty"`
+	ToStatus      OrderStatus            `protobuf:"varint,4,opt,name=to_status,json=toStatus,proto3,enum=pb.OrderStatus" json:"to_status,omitempty"`
+	ChangedBy     int64                  `protobuf:"varint,5,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_api_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChange) ProtoMessage() {}
+
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *OrderStatusChange) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetFromStatus() OrderStatus {
+	if x != nil && x.FromStatus != nil {
+		return *x.FromStatus
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderStatusChange) GetToStatus() OrderStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return OrderStatus_PENDING
+}
+
+func (x *OrderStatusChange) GetChangedBy() int64 {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return 0
+}
+
+func (x *OrderStatusChange) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListOrderStatusHistoryRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*OrderStatusChange   `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrderStatusHistoryRes) Reset() {
+	*x = ListOrderStatusHistoryRes{}
+	mi := &file_api_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrderStatusHistoryRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrderStatusHistoryRes) ProtoMessage() {}
+
+func (x *ListOrderStatusHistoryRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrderStatusHistoryRes.ProtoReflect.Descriptor instead.
+func (*ListOrderStatusHistoryRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListOrderStatusHistoryRes) GetChanges() []*OrderStatusChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type InvoiceLine struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity  int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// unit price
+	Price         int64 `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	TaxRate       int64 `protobuf:"varint,5,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	TaxPrice      int64 `protobuf:"varint,6,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvoiceLine) Reset() {
+	*x = InvoiceLine{}
+	mi := &file_api_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceLine) ProtoMessage() {}
+
+func (x *InvoiceLine) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceLine.ProtoReflect.Descriptor instead.
+func (*InvoiceLine) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *InvoiceLine) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InvoiceLine) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetTaxRate() int64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *InvoiceLine) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+// Invoice issued when an order is paid, numbered without gaps. Amounts are in
+// currency.
+type InvoiceRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int64                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	SellerName    string                 `protobuf:"bytes,5,opt,name=seller_name,json=sellerName,proto3" json:"seller_name,omitempty"`
+	SellerAddress string                 `protobuf:"bytes,6,opt,name=seller_address,json=sellerAddress,proto3" json:"seller_address,omitempty"`
+	SellerTaxId   string                 `protobuf:"bytes,7,opt,name=seller_tax_id,json=sellerTaxId,proto3" json:"seller_tax_id,omitempty"`
+	BillName      string                 `protobuf:"bytes,8,opt,name=bill_name,json=billName,proto3" json:"bill_name,omitempty"`
+	BillEmail     string                 `protobuf:"bytes,9,opt,name=bill_email,json=billEmail,proto3" json:"bill_email,omitempty"`
+	// unset if the order has no shipping address
+	ShippingAddress *ShippingAddress `protobuf:"bytes,10,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	Lines           []*InvoiceLine   `protobuf:"bytes,11,rep,name=lines,proto3" json:"lines,omitempty"`
+	Currency        string           `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`
+	Subtotal        int64            `protobuf:"varint,13,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	DiscountPrice   int64            `protobuf:"varint,14,opt,name=discount_price,json=discountPrice,proto3" json:"discount_price,omitempty"`
+	TaxPrice        int64            `protobuf:"varint,15,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	TaxInclusive    bool             `protobuf:"varint,16,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`
+	ShippingPrice   int64            `protobuf:"varint,17,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	ShippingMethod  string           `protobuf:"bytes,18,opt,name=shipping_method,json=shippingMethod,proto3" json:"shipping_method,omitempty"`
+	TotalPrice      int64            `protobuf:"varint,19,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InvoiceRes) Reset() {
+	*x = InvoiceRes{}
+	mi := &file_api_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceRes) ProtoMessage() {}
+
+func (x *InvoiceRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceRes.ProtoReflect.Descriptor instead.
+func (*InvoiceRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *InvoiceRes) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetSellerName() string {
+	if x != nil {
+		return x.SellerName
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetSellerAddress() string {
+	if x != nil {
+		return x.SellerAddress
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetSellerTaxId() string {
+	if x != nil {
+		return x.SellerTaxId
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetBillName() string {
+	if x != nil {
+		return x.BillName
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetBillEmail() string {
+	if x != nil {
+		return x.BillEmail
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetShippingAddress() *ShippingAddress {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetLines() []*InvoiceLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *InvoiceRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetSubtotal() int64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetDiscountPrice() int64 {
+	if x != nil {
+		return x.DiscountPrice
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *InvoiceRes) GetShippingPrice() int64 {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return 0
+}
+
+func (x *InvoiceRes) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+func (x *InvoiceRes) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+type CartItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image     string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Quantity  int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// stock and price of the variant for variants
+	CountInStock int64 `protobuf:"varint,6,opt,name=count_in_stock,json=countInStock,proto3" json:"count_in_stock,omitempty"`
+	Price        int64 `protobuf:"varint,7,opt,name=price,proto3" json:"price,omitempty"`
+	// variant in the cart, zero for products without variants
+	VariantId     int64             `protobuf:"varint,8,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Options       map[string]string `protobuf:"bytes,9,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_api_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CartItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItem) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *CartItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartItem) GetCountInStock() int64 {
+	if x != nil {
+		return x.CountInStock
+	}
+	return 0
+}
+
+func (x *CartItem) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CartItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *CartItem) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// Carts belong to user_id, or to the guest holding cart_token when user_id
+// is zero.
+type CartReq struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CartToken       string                 `protobuf:"bytes,2,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,3,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CartReq) Reset() {
+	*x = CartReq{}
+	mi := &file_api_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartReq) ProtoMessage() {}
+
+func (x *CartReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartReq.ProtoReflect.Descriptor instead.
+func (*CartReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CartReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CartReq) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+func (x *CartReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type CartItemReq struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity        int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CartToken       string                 `protobuf:"bytes,4,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,5,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	// variant of the product, required for products with variants
+	VariantId     int64 `protobuf:"varint,6,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartItemReq) Reset() {
+	*x = CartItemReq{}
+	mi := &file_api_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItemReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItemReq) ProtoMessage() {}
+
+func (x *CartItemReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItemReq.ProtoReflect.Descriptor instead.
+func (*CartItemReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CartItemReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CartItemReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartItemReq) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartItemReq) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+func (x *CartItemReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *CartItemReq) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type CartRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*CartItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	CartToken     string                 `protobuf:"bytes,6,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	Subtotal      int64                  `protobuf:"varint,7,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	TaxPrice      int64                  `protobuf:"varint,8,opt,name=tax_price,json=taxPrice,proto3" json:"tax_price,omitempty"`
+	ShippingPrice int64                  `protobuf:"varint,9,opt,name=shipping_price,json=shippingPrice,proto3" json:"shipping_price,omitempty"`
+	TotalPrice    int64                  `protobuf:"varint,10,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Currency      string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	// charges_pending is set when shipping or tax depend on a shipping address
+	// the cart does not have yet. They are then zero and left out of
+	// total_price until checkout.
+	ChargesPending bool `protobuf:"varint,12,opt,name=charges_pending,json=chargesPending,proto3" json:"charges_pending,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CartRes) Reset() {
+	*x = CartRes{}
+	mi := &file_api_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartRes) ProtoMessage() {}
+
+func (x *CartRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartRes.ProtoReflect.Descriptor instead.
+func (*CartRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CartRes) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CartRes) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+func (x *CartRes) GetSubtotal() int64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *CartRes) GetTaxPrice() int64 {
+	if x != nil {
+		return x.TaxPrice
+	}
+	return 0
+}
+
+func (x *CartRes) GetShippingPrice() int64 {
+	if x != nil {
+		return x.ShippingPrice
+	}
+	return 0
+}
+
+func (x *CartRes) GetTotalPrice() int64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *CartRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CartRes) GetChargesPending() bool {
+	if x != nil {
+		return x.ChargesPending
+	}
+	return false
+}
+
+type MergeCartReq struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CartToken       string                 `protobuf:"bytes,2,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,3,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeCartReq) Reset() {
+	*x = MergeCartReq{}
+	mi := &file_api_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartReq) ProtoMessage() {}
+
+func (x *MergeCartReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartReq.ProtoReflect.Descriptor instead.
+func (*MergeCartReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *MergeCartReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MergeCartReq) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+func (x *MergeCartReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type CheckoutReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserEmail      string                 `protobuf:"bytes,2,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	PaymentMethod  string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	CouponCode     string                 `protobuf:"bytes,4,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	Currency       string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	AddressId      int64                  `protobuf:"varint,6,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	ShippingMethod string                 `protobuf:"bytes,7,opt,name=shipping_method,json=shippingMethod,proto3" json:"shipping_method,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CheckoutReq) Reset() {
+	*x = CheckoutReq{}
+	mi := &file_api_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutReq) ProtoMessage() {}
+
+func (x *CheckoutReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutReq.ProtoReflect.Descriptor instead.
+func (*CheckoutReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CheckoutReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CheckoutReq) GetUserEmail() string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return ""
+}
+
+func (x *CheckoutReq) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *CheckoutReq) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *CheckoutReq) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CheckoutReq) GetAddressId() int64 {
+	if x != nil {
+		return x.AddressId
+	}
+	return 0
+}
+
+func (x *CheckoutReq) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+// Users quote shipping to an address of their address book, their default
+// address if address_id is zero; guests send the destination.
+type ShippingQuoteReq struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CartToken       string                 `protobuf:"bytes,2,opt,name=cart_token,json=cartToken,proto3" json:"cart_token,omitempty"`
+	AddressId       int64                  `protobuf:"varint,3,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	Country         string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Region          string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode      string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,7,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ShippingQuoteReq) Reset() {
+	*x = ShippingQuoteReq{}
+	mi := &file_api_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingQuoteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingQuoteReq) ProtoMessage() {}
+
+func (x *ShippingQuoteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingQuoteReq.ProtoReflect.Descriptor instead.
+func (*ShippingQuoteReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ShippingQuoteReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ShippingQuoteReq) GetCartToken() string {
+	if x != nil {
+		return x.CartToken
+	}
+	return ""
+}
+
+func (x *ShippingQuoteReq) GetAddressId() int64 {
+	if x != nil {
+		return x.AddressId
+	}
+	return 0
+}
+
+func (x *ShippingQuoteReq) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ShippingQuoteReq) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ShippingQuoteReq) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *ShippingQuoteReq) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type ShippingOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MethodId      string                 `protobuf:"bytes,1,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MinDays       int32                  `protobuf:"varint,3,opt,name=min_days,json=minDays,proto3" json:"min_days,omitempty"`
+	MaxDays       int32                  `protobuf:"varint,4,opt,name=max_days,json=maxDays,proto3" json:"max_days,omitempty"`
+	Price         int64                  `protobuf:"varint,5,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingOption) Reset() {
+	*x = ShippingOption{}
+	mi := &file_api_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingOption) ProtoMessage() {}
+
+func (x *ShippingOption) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingOption.ProtoReflect.Descriptor instead.
+func (*ShippingOption) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ShippingOption) GetMethodId() string {
+	if x != nil {
+		return x.MethodId
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetMinDays() int32 {
+	if x != nil {
+		return x.MinDays
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetMaxDays() int32 {
+	if x != nil {
+		return x.MaxDays
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type ShippingQuoteRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       []*ShippingOption      `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingQuoteRes) Reset() {
+	*x = ShippingQuoteRes{}
+	mi := &file_api_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingQuoteRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingQuoteRes) ProtoMessage() {}
+
+func (x *ShippingQuoteRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingQuoteRes.ProtoReflect.Descriptor instead.
+func (*ShippingQuoteRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ShippingQuoteRes) GetOptions() []*ShippingOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ShippingQuoteRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// method is the payment method handed to the gateway, the payment method of
+// the order if empty.
+type PaymentReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,3,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	Method        string                 `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentReq) Reset() {
+	*x = PaymentReq{}
+	mi := &file_api_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentReq) ProtoMessage() {}
+
+func (x *PaymentReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentReq.ProtoReflect.Descriptor instead.
+func (*PaymentReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PaymentReq) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PaymentReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *PaymentReq) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type PaymentRes struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId  int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Provider string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Status   PaymentStatus          `protobuf:"varint,4,opt,name=status,proto3,enum=pb.PaymentStatus" json:"status,omitempty"`
+	Amount   int64                  `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	// where the customer completes a payment requiring action, only set when
+	// the payment is created
+	NextActionUrl string                 `protobuf:"bytes,7,opt,name=next_action_url,json=nextActionUrl,proto3" json:"next_action_url,omitempty"`
+	DeclineReason string                 `protobuf:"bytes,8,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentRes) Reset() {
+	*x = PaymentRes{}
+	mi := &file_api_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRes) ProtoMessage() {}
+
+func (x *PaymentRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRes.ProtoReflect.Descriptor instead.
+func (*PaymentRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PaymentRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PaymentRes) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PaymentRes) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetStatus() PaymentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PaymentStatus_PAYMENT_ACTION_REQUIRED
+}
+
+func (x *PaymentRes) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetNextActionUrl() string {
+	if x != nil {
+		return x.NextActionUrl
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetDeclineReason() string {
+	if x != nil {
+		return x.DeclineReason
+	}
+	return ""
+}
+
+func (x *PaymentRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PaymentRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type PaymentWebhookReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Signature     string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentWebhookReq) Reset() {
+	*x = PaymentWebhookReq{}
+	mi := &file_api_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentWebhookReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentWebhookReq) ProtoMessage() {}
+
+func (x *PaymentWebhookReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentWebhookReq.ProtoReflect.Descriptor instead.
+func (*PaymentWebhookReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PaymentWebhookReq) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PaymentWebhookReq) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// Coupons scoped to a category or a product only discount the matching items,
+// the items of the subcategories of the category included. Zero limits, a zero
+// category_id and a zero product_id do not restrict the coupon. The value of percentage coupons is in basis points, 1000 for 10%.
+type CouponReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Kind           *CouponKind            `protobuf:"varint,3,opt,name=kind,proto3,enum=pb.CouponKind,oneof" json:"kind,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	MaxUses        int64                  `protobuf:"varint,7,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	MaxUsesPerUser int64                  `protobuf:"varint,8,opt,name=max_uses_per_user,json=maxUsesPerUser,proto3" json:"max_uses_per_user,omitempty"`
+	ProductId      int64                  `protobuf:"varint,10,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Value          int64                  `protobuf:"varint,11,opt,name=value,proto3" json:"value,omitempty"`
+	MinOrderValue  int64                  `protobuf:"varint,12,opt,name=min_order_value,json=minOrderValue,proto3" json:"min_order_value,omitempty"`
+	CategoryId     int64                  `protobuf:"varint,13,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CouponReq) Reset() {
+	*x = CouponReq{}
+	mi := &file_api_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponReq) ProtoMessage() {}
+
+func (x *CouponReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponReq.ProtoReflect.Descriptor instead.
+func (*CouponReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CouponReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CouponReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CouponReq) GetKind() CouponKind {
+	if x != nil && x.Kind != nil {
+		return *x.Kind
+	}
+	return CouponKind_PERCENTAGE
+}
+
+func (x *CouponReq) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CouponReq) GetMaxUses() int64 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CouponReq) GetMaxUsesPerUser() int64 {
+	if x != nil {
+		return x.MaxUsesPerUser
+	}
+	return 0
+}
+
+func (x *CouponReq) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CouponReq) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CouponReq) GetMinOrderValue() int64 {
+	if x != nil {
+		return x.MinOrderValue
+	}
+	return 0
+}
+
+func (x *CouponReq) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+type CouponRes struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Kind           CouponKind             `protobuf:"varint,3,opt,name=kind,proto3,enum=pb.CouponKind" json:"kind,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	MaxUses        int64                  `protobuf:"varint,7,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	MaxUsesPerUser int64                  `protobuf:"varint,8,opt,name=max_uses_per_user,json=maxUsesPerUser,proto3" json:"max_uses_per_user,omitempty"`
+	ProductId      int64                  `protobuf:"varint,10,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Value          int64                  `protobuf:"varint,13,opt,name=value,proto3" json:"value,omitempty"`
+	MinOrderValue  int64                  `protobuf:"varint,14,opt,name=min_order_value,json=minOrderValue,proto3" json:"min_order_value,omitempty"`
+	Currency       string                 `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	CategoryId     int64                  `protobuf:"varint,16,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CouponRes) Reset() {
+	*x = CouponRes{}
+	mi := &file_api_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponRes) ProtoMessage() {}
+
+func (x *CouponRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponRes.ProtoReflect.Descriptor instead.
+func (*CouponRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CouponRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CouponRes) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CouponRes) GetKind() CouponKind {
+	if x != nil {
+		return x.Kind
+	}
+	return CouponKind_PERCENTAGE
+}
+
+func (x *CouponRes) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CouponRes) GetMaxUses() int64 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CouponRes) GetMaxUsesPerUser() int64 {
+	if x != nil {
+		return x.MaxUsesPerUser
+	}
+	return 0
+}
+
+func (x *CouponRes) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CouponRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CouponRes) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *CouponRes) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CouponRes) GetMinOrderValue() int64 {
+	if x != nil {
+		return x.MinOrderValue
+	}
+	return 0
+}
+
+func (x *CouponRes) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CouponRes) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+type ListCouponsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsReq) Reset() {
+	*x = ListCouponsReq{}
+	mi := &file_api_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsReq) ProtoMessage() {}
+
+func (x *ListCouponsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsReq.ProtoReflect.Descriptor instead.
+func (*ListCouponsReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListCouponsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCouponsReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCouponsReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+type ListCouponsRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupons       []*CouponRes           `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsRes) Reset() {
+	*x = ListCouponsRes{}
+	mi := &file_api_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsRes) ProtoMessage() {}
+
+func (x *ListCouponsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsRes.ProtoReflect.Descriptor instead.
+func (*ListCouponsRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListCouponsRes) GetCoupons() []*CouponRes {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+func (x *ListCouponsRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UserReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserReq) Reset() {
+	*x = UserReq{}
+	mi := &file_api_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserReq) ProtoMessage() {}
+
+func (x *UserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserReq.ProtoReflect.Descriptor instead.
+func (*UserReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *UserReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UserReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserReq) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserReq) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UserReq) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+type UserRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	IsAdmin       bool                   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRes) Reset() {
+	*x = UserRes{}
+	mi := &file_api_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRes) ProtoMessage() {}
+
+func (x *UserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRes.ProtoReflect.Descriptor instead.
+func (*UserRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *UserRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UserRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserRes) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserRes) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UserRes) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *UserRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListUsersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	IsAdmin       *bool                  `protobuf:"varint,4,opt,name=is_admin,json=isAdmin,proto3,oneof" json:"is_admin,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersReq) Reset() {
+	*x = ListUsersReq{}
+	mi := &file_api_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersReq) ProtoMessage() {}
+
+func (x *ListUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersReq.ProtoReflect.Descriptor instead.
+func (*ListUsersReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListUsersReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersReq) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListUsersReq) GetIsAdmin() bool {
+	if x != nil && x.IsAdmin != nil {
+		return *x.IsAdmin
+	}
+	return false
+}
+
+func (x *ListUsersReq) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListUsersReq) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+type ListUserRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserRes             `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserRes) Reset() {
+	*x = ListUserRes{}
+	mi := &file_api_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserRes) ProtoMessage() {}
+
+func (x *ListUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserRes.ProtoReflect.Descriptor instead.
+func (*ListUserRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListUserRes) GetUsers() []*UserRes {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUserRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type AddressReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,4,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,5,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,8,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,9,opt,name=country,proto3" json:"country,omitempty"`
+	Phone         string                 `protobuf:"bytes,10,opt,name=phone,proto3" json:"phone,omitempty"`
+	IsDefault     *bool                  `protobuf:"varint,11,opt,name=is_default,json=isDefault,proto3,oneof" json:"is_default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressReq) Reset() {
+	*x = AddressReq{}
+	mi := &file_api_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressReq) ProtoMessage() {}
+
+func (x *AddressReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressReq.ProtoReflect.Descriptor instead.
+func (*AddressReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *AddressReq) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddressReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddressReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddressReq) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *AddressReq) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *AddressReq) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *AddressReq) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *AddressReq) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *AddressReq) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *AddressReq) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *AddressReq) GetIsDefault() bool {
+	if x != nil && x.IsDefault != nil {
+		return *x.IsDefault
+	}
+	return false
+}
+
+type AddressRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,4,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,5,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,8,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,9,opt,name=country,proto3" json:"country,omitempty"`
+	Phone         string                 `protobuf:"bytes,10,opt,name=phone,proto3" json:"phone,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,11,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressRes) Reset() {
+	*x = AddressRes{}
+	mi := &file_api_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRes) ProtoMessage() {}
+
+func (x *AddressRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRes.ProtoReflect.Descriptor instead.
+func (*AddressRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AddressRes) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddressRes) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddressRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddressRes) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *AddressRes) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *AddressRes) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *AddressRes) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *AddressRes) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *AddressRes) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *AddressRes) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *AddressRes) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *AddressRes) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
-	return false
+	return nil
 }
 
-func (x *UserRes) GetCreatedAt() *timestamppb.Timestamp {
+func (x *AddressRes) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CreatedAt
+		return x.UpdatedAt
 	}
 	return nil
 }
 
-type ListUserRes struct {
+type ListAddressesRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*UserRes             `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Addresses     []*AddressRes          `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserRes) Reset() {
-	*x = ListUserRes{}
-	mi := &file_api_proto_msgTypes[9]
+func (x *ListAddressesRes) Reset() {
+	*x = ListAddressesRes{}
+	mi := &file_api_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserRes) String() string {
+func (x *ListAddressesRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserRes) ProtoMessage() {}
+func (*ListAddressesRes) ProtoMessage() {}
 
-func (x *ListUserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[9]
+func (x *ListAddressesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -929,14 +5235,14 @@ func (x *ListUserRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserRes.ProtoReflect.Descriptor instead.
-func (*ListUserRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ListAddressesRes.ProtoReflect.Descriptor instead.
+func (*ListAddressesRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *ListUserRes) GetUsers() []*UserRes {
+func (x *ListAddressesRes) GetAddresses() []*AddressRes {
 	if x != nil {
-		return x.Users
+		return x.Addresses
 	}
 	return nil
 }
@@ -954,7 +5260,7 @@ type SessionReq struct {
 
 func (x *SessionReq) Reset() {
 	*x = SessionReq{}
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -966,7 +5272,7 @@ func (x *SessionReq) String() string {
 func (*SessionReq) ProtoMessage() {}
 
 func (x *SessionReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -979,7 +5285,7 @@ func (x *SessionReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionReq.ProtoReflect.Descriptor instead.
 func (*SessionReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *SessionReq) GetId() string {
@@ -1030,7 +5336,7 @@ type SessionRes struct {
 
 func (x *SessionRes) Reset() {
 	*x = SessionRes{}
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1042,7 +5348,7 @@ func (x *SessionRes) String() string {
 func (*SessionRes) ProtoMessage() {}
 
 func (x *SessionRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1055,7 +5361,7 @@ func (x *SessionRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionRes.ProtoReflect.Descriptor instead.
 func (*SessionRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{11}
+	return file_api_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *SessionRes) GetId() string {
@@ -1093,6 +5399,179 @@ func (x *SessionRes) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+// Idempotency keys are reserved by the first request sending them and
+// completed with its response. status_code is zero while the request is in
+// progress.
+type IdempotencyKeyReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	RequestHash   string                 `protobuf:"bytes,3,opt,name=request_hash,json=requestHash,proto3" json:"request_hash,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ContentType   string                 `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ResponseBody  []byte                 `protobuf:"bytes,7,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdempotencyKeyReq) Reset() {
+	*x = IdempotencyKeyReq{}
+	mi := &file_api_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdempotencyKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdempotencyKeyReq) ProtoMessage() {}
+
+func (x *IdempotencyKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdempotencyKeyReq.ProtoReflect.Descriptor instead.
+func (*IdempotencyKeyReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *IdempotencyKeyReq) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *IdempotencyKeyReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IdempotencyKeyReq) GetRequestHash() string {
+	if x != nil {
+		return x.RequestHash
+	}
+	return ""
+}
+
+func (x *IdempotencyKeyReq) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *IdempotencyKeyReq) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *IdempotencyKeyReq) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *IdempotencyKeyReq) GetResponseBody() []byte {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return nil
+}
+
+type IdempotencyKeyRes struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// whether the key was reserved for this request, otherwise the fields
+	// below are those of the request that did
+	Reserved      bool   `protobuf:"varint,1,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	RequestHash   string `protobuf:"bytes,2,opt,name=request_hash,json=requestHash,proto3" json:"request_hash,omitempty"`
+	StatusCode    int32  `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ContentType   string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ResponseBody  []byte `protobuf:"bytes,5,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdempotencyKeyRes) Reset() {
+	*x = IdempotencyKeyRes{}
+	mi := &file_api_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdempotencyKeyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdempotencyKeyRes) ProtoMessage() {}
+
+func (x *IdempotencyKeyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdempotencyKeyRes.ProtoReflect.Descriptor instead.
+func (*IdempotencyKeyRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *IdempotencyKeyRes) GetReserved() bool {
+	if x != nil {
+		return x.Reserved
+	}
+	return false
+}
+
+func (x *IdempotencyKeyRes) GetRequestHash() string {
+	if x != nil {
+		return x.RequestHash
+	}
+	return ""
+}
+
+func (x *IdempotencyKeyRes) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *IdempotencyKeyRes) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *IdempotencyKeyRes) GetResponseBody() []byte {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return nil
+}
+
 type NotificationEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1107,7 +5586,7 @@ type NotificationEvent struct {
 
 func (x *NotificationEvent) Reset() {
 	*x = NotificationEvent{}
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1119,7 +5598,7 @@ func (x *NotificationEvent) String() string {
 func (*NotificationEvent) ProtoMessage() {}
 
 func (x *NotificationEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1132,7 +5611,7 @@ func (x *NotificationEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationEvent.ProtoReflect.Descriptor instead.
 func (*NotificationEvent) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{12}
+	return file_api_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *NotificationEvent) GetId() int64 {
@@ -1179,13 +5658,15 @@ func (x *NotificationEvent) GetAttempts() int64 {
 
 type ListNotificationEventsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListNotificationEventsReq) Reset() {
 	*x = ListNotificationEventsReq{}
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1197,7 +5678,7 @@ func (x *ListNotificationEventsReq) String() string {
 func (*ListNotificationEventsReq) ProtoMessage() {}
 
 func (x *ListNotificationEventsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,19 +5691,34 @@ func (x *ListNotificationEventsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotificationEventsReq.ProtoReflect.Descriptor instead.
 func (*ListNotificationEventsReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{13}
+	return file_api_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListNotificationEventsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListNotificationEventsReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
 type ListNotificationEventsRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Events        []*NotificationEvent   `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListNotificationEventsRes) Reset() {
 	*x = ListNotificationEventsRes{}
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1234,7 +5730,7 @@ func (x *ListNotificationEventsRes) String() string {
 func (*ListNotificationEventsRes) ProtoMessage() {}
 
 func (x *ListNotificationEventsRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1247,7 +5743,7 @@ func (x *ListNotificationEventsRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotificationEventsRes.ProtoReflect.Descriptor instead.
 func (*ListNotificationEventsRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{14}
+	return file_api_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *ListNotificationEventsRes) GetEvents() []*NotificationEvent {
@@ -1257,6 +5753,13 @@ func (x *ListNotificationEventsRes) GetEvents() []*NotificationEvent {
 	return nil
 }
 
+func (x *ListNotificationEventsRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type UpdateNotificationEventReq struct {
 	state         protoimpl.MessageState   `protogen:"open.v1"`
 	Id            int64                    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1270,7 +5773,7 @@ type UpdateNotificationEventReq struct {
 
 func (x *UpdateNotificationEventReq) Reset() {
 	*x = UpdateNotificationEventReq{}
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1282,7 +5785,7 @@ func (x *UpdateNotificationEventReq) String() string {
 func (*UpdateNotificationEventReq) ProtoMessage() {}
 
 func (x *UpdateNotificationEventReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1295,7 +5798,7 @@ func (x *UpdateNotificationEventReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNotificationEventReq.ProtoReflect.Descriptor instead.
 func (*UpdateNotificationEventReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{15}
+	return file_api_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *UpdateNotificationEventReq) GetId() int64 {
@@ -1342,7 +5845,7 @@ type UpdateNotificationEventRes struct {
 
 func (x *UpdateNotificationEventRes) Reset() {
 	*x = UpdateNotificationEventRes{}
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1354,7 +5857,7 @@ func (x *UpdateNotificationEventRes) String() string {
 func (*UpdateNotificationEventRes) ProtoMessage() {}
 
 func (x *UpdateNotificationEventRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1367,7 +5870,7 @@ func (x *UpdateNotificationEventRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNotificationEventRes.ProtoReflect.Descriptor instead.
 func (*UpdateNotificationEventRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{16}
+	return file_api_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *UpdateNotificationEventRes) GetSucceeded() bool {
@@ -1381,74 +5884,484 @@ var File_api_proto protoreflect.FileDescriptor
 
 const file_api_proto_rawDesc = "" +
 	"\n" +
-	"\tapi.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x01\n" +
+	"\tapi.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd6\x03\n" +
 	"\n" +
 	"ProductReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12 \n" +
-	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06rating\x18\x06 \x01(\x03R\x06rating\x12\x1f\n" +
-	"\vnum_reviews\x18\a \x01(\x03R\n" +
-	"numReviews\x12\x14\n" +
-	"\x05price\x18\b \x01(\x02R\x05price\x12$\n" +
-	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\"\xef\x02\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12$\n" +
+	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\x12\x14\n" +
+	"\x05price\x18\n" +
+	" \x01(\x03R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12)\n" +
+	"\x10display_currency\x18\f \x01(\tR\x0fdisplayCurrency\x12\x16\n" +
+	"\x06weight\x18\r \x01(\x03R\x06weight\x12\x16\n" +
+	"\x06length\x18\x0e \x01(\x03R\x06length\x12\x14\n" +
+	"\x05width\x18\x0f \x01(\x03R\x05width\x12\x16\n" +
+	"\x06height\x18\x10 \x01(\x03R\x06height\x12!\n" +
+	"\ftax_category\x18\x11 \x01(\tR\vtaxCategory\x12\x1f\n" +
+	"\vcategory_id\x18\x12 \x01(\x03R\n" +
+	"categoryId\x12\x10\n" +
+	"\x03sku\x18\x13 \x01(\tR\x03skuJ\x04\b\x06\x10\aJ\x04\b\a\x10\bJ\x04\b\b\x10\tJ\x04\b\x04\x10\x05R\x06ratingR\vnum_reviewsR\bcategory\"\x92\x05\n" +
 	"\n" +
 	"ProductRes\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12 \n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12 \n" +
 	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06rating\x18\x06 \x01(\x03R\x06rating\x12\x1f\n" +
 	"\vnum_reviews\x18\a \x01(\x03R\n" +
-	"numReviews\x12\x14\n" +
-	"\x05price\x18\b \x01(\x02R\x05price\x12$\n" +
+	"numReviews\x12$\n" +
 	"\x0ecount_in_stock\x18\t \x01(\x03R\fcountInStock\x129\n" +
 	"\n" +
 	"created_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"<\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05price\x18\f \x01(\x03R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06weight\x18\x0e \x01(\x03R\x06weight\x12\x16\n" +
+	"\x06length\x18\x0f \x01(\x03R\x06length\x12\x14\n" +
+	"\x05width\x18\x10 \x01(\x03R\x05width\x12\x16\n" +
+	"\x06height\x18\x11 \x01(\x03R\x06height\x12!\n" +
+	"\ftax_category\x18\x12 \x01(\tR\vtaxCategory\x12\x1f\n" +
+	"\vcategory_id\x18\x13 \x01(\x03R\n" +
+	"categoryId\x12*\n" +
+	"\bvariants\x18\x14 \x03(\v2\x0e.pb.VariantResR\bvariants\x12+\n" +
+	"\x06images\x18\x15 \x03(\v2\x13.pb.ProductImageResR\x06images\x12\x10\n" +
+	"\x03sku\x18\x16 \x01(\tR\x03skuJ\x04\b\x04\x10\x05J\x04\b\b\x10\tR\bcategory\"\xa3\x02\n" +
+	"\n" +
+	"VariantReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x125\n" +
+	"\aoptions\x18\x04 \x03(\v2\x1b.pb.VariantReq.OptionsEntryR\aoptions\x12\x19\n" +
+	"\x05price\x18\x05 \x01(\x03H\x00R\x05price\x88\x01\x01\x12)\n" +
+	"\x0ecount_in_stock\x18\x06 \x01(\x03H\x01R\fcountInStock\x88\x01\x01\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\b\n" +
+	"\x06_priceB\x11\n" +
+	"\x0f_count_in_stock\"\x81\x03\n" +
+	"\n" +
+	"VariantRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x125\n" +
+	"\aoptions\x18\x04 \x03(\v2\x1b.pb.VariantRes.OptionsEntryR\aoptions\x12\x19\n" +
+	"\x05price\x18\x05 \x01(\x03H\x00R\x05price\x88\x01\x01\x12$\n" +
+	"\x0ecount_in_stock\x18\x06 \x01(\x03R\fcountInStock\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\b\n" +
+	"\x06_price\"T\n" +
+	"\x0fProductImageReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"\xb3\x02\n" +
+	"\x0fProductImageRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12#\n" +
+	"\rthumbnail_url\x18\x04 \x01(\tR\fthumbnailUrl\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\x12\x14\n" +
+	"\x05width\x18\a \x01(\x03R\x05width\x12\x16\n" +
+	"\x06height\x18\b \x01(\x03R\x06height\x12\x1a\n" +
+	"\bposition\x18\t \x01(\x03R\bposition\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"U\n" +
+	"\x17ReorderProductImagesReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1b\n" +
+	"\timage_ids\x18\x02 \x03(\x03R\bimageIds\"h\n" +
+	"\x11ImportProductsReq\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12(\n" +
+	"\aproduct\x18\x02 \x01(\v2\x0e.pb.ProductReqR\aproduct\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\x8c\x01\n" +
+	"\x11ImportProductsRes\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\x12\x18\n" +
+	"\aupdated\x18\x02 \x01(\x03R\aupdated\x12*\n" +
+	"\x06errors\x18\x03 \x03(\v2\x12.pb.ImportRowErrorR\x06errors\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"J\n" +
+	"\x0eImportRowError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xb4\x02\n" +
+	"\x0fListProductsReq\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x19\n" +
+	"\bin_stock\x18\a \x01(\bR\ainStock\x12 \n" +
+	"\tmin_price\x18\b \x01(\x03H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\t \x01(\x03H\x01R\bmaxPrice\x88\x01\x01\x12)\n" +
+	"\x10display_currency\x18\n" +
+	" \x01(\tR\x0fdisplayCurrencyB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_priceJ\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"d\n" +
 	"\x0eListProductRes\x12*\n" +
-	"\bproducts\x18\x01 \x03(\v2\x0e.pb.ProductResR\bproducts\"\x86\x01\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0e.pb.ProductResR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x90\x01\n" +
+	"\x11SearchProductsReq\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12)\n" +
+	"\x10display_currency\x18\x04 \x01(\tR\x0fdisplayCurrency\"h\n" +
+	"\fProductMatch\x12(\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0e.pb.ProductResR\aproduct\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"g\n" +
+	"\x11SearchProductsRes\x12*\n" +
+	"\amatches\x18\x01 \x03(\v2\x10.pb.ProductMatchR\amatches\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"u\n" +
+	"\vCategoryReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\x12 \n" +
+	"\tparent_id\x18\x04 \x01(\x03H\x00R\bparentId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_id\"\xd8\x01\n" +
+	"\vCategoryRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x13\n" +
+	"\x11ListCategoriesReq\"D\n" +
+	"\x11ListCategoriesRes\x12/\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x0f.pb.CategoryResR\n" +
+	"categories\"\xaf\x01\n" +
+	"\tReviewReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x03R\x06rating\x12\x18\n" +
+	"\acomment\x18\x05 \x01(\tR\acomment\x12(\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x10.pb.ReviewStatusR\x06status\"\xa5\x02\n" +
+	"\tReviewRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x03R\x06rating\x12\x18\n" +
+	"\acomment\x18\x05 \x01(\tR\acomment\x12(\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x10.pb.ReviewStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbe\x01\n" +
+	"\x0eListReviewsReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x04 \x01(\tR\x06sortBy\x12-\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x10.pb.ReviewStatusH\x00R\x06status\x88\x01\x01B\t\n" +
+	"\a_status\"a\n" +
+	"\x0eListReviewsRes\x12'\n" +
+	"\areviews\x18\x01 \x03(\v2\r.pb.ReviewResR\areviews\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xe3\x01\n" +
 	"\tOrderItem\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
 	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x14\n" +
-	"\x05image\x18\x03 \x01(\tR\x05image\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x02R\x05price\x12\x1d\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x05 \x01(\x03R\tproductId\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x19\n" +
+	"\btax_rate\x18\a \x01(\x03R\ataxRate\x12\x1b\n" +
+	"\ttax_price\x18\b \x01(\x03R\btaxPrice\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x05 \x01(\x03R\tproductId\"\xac\x02\n" +
+	"variant_id\x18\t \x01(\x03R\tvariantIdJ\x04\b\x04\x10\x05\"\xde\x03\n" +
 	"\bOrderReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
 	"\x05items\x18\x02 \x03(\v2\r.pb.OrderItemR\x05items\x12%\n" +
-	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x1b\n" +
-	"\ttax_price\x18\x04 \x01(\x02R\btaxPrice\x12%\n" +
-	"\x0eshipping_price\x18\x05 \x01(\x02R\rshippingPrice\x12\x1f\n" +
-	"\vtotal_price\x18\x06 \x01(\x02R\n" +
-	"totalPrice\x12\x17\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x17\n" +
 	"\auser_id\x18\a \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"user_email\x18\b \x01(\tR\tuserEmail\x12'\n" +
-	"\x06status\x18\t \x01(\x0e2\x0f.pb.OrderStatusR\x06status\"\x83\x03\n" +
+	"\x06status\x18\t \x01(\x0e2\x0f.pb.OrderStatusR\x06status\x12\x19\n" +
+	"\bis_admin\x18\n" +
+	" \x01(\bR\aisAdmin\x12\x1f\n" +
+	"\vcoupon_code\x18\v \x01(\tR\n" +
+	"couponCode\x12\x1b\n" +
+	"\ttax_price\x18\f \x01(\x03R\btaxPrice\x12%\n" +
+	"\x0eshipping_price\x18\r \x01(\x03R\rshippingPrice\x12\x1f\n" +
+	"\vtotal_price\x18\x0e \x01(\x03R\n" +
+	"totalPrice\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x10 \x01(\x03R\taddressId\x12'\n" +
+	"\x0fshipping_method\x18\x11 \x01(\tR\x0eshippingMethodJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"\xb2\x05\n" +
 	"\bOrderRes\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
 	"\x05items\x18\x02 \x03(\v2\r.pb.OrderItemR\x05items\x12%\n" +
-	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x1b\n" +
-	"\ttax_price\x18\x04 \x01(\x02R\btaxPrice\x12%\n" +
-	"\x0eshipping_price\x18\x05 \x01(\x02R\rshippingPrice\x12\x1f\n" +
-	"\vtotal_price\x18\x06 \x01(\x02R\n" +
-	"totalPrice\x12\x17\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x17\n" +
 	"\auser_id\x18\a \x01(\x03R\x06userId\x129\n" +
 	"\n" +
 	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12'\n" +
 	"\x06status\x18\n" +
-	" \x01(\x0e2\x0f.pb.OrderStatusR\x06status\"4\n" +
+	" \x01(\x0e2\x0f.pb.OrderStatusR\x06status\x12\x1f\n" +
+	"\vcoupon_code\x18\v \x01(\tR\n" +
+	"couponCode\x12%\n" +
+	"\x0ediscount_price\x18\r \x01(\x03R\rdiscountPrice\x12\x1b\n" +
+	"\ttax_price\x18\x0e \x01(\x03R\btaxPrice\x12%\n" +
+	"\x0eshipping_price\x18\x0f \x01(\x03R\rshippingPrice\x12\x1f\n" +
+	"\vtotal_price\x18\x10 \x01(\x03R\n" +
+	"totalPrice\x12\x1a\n" +
+	"\bcurrency\x18\x11 \x01(\tR\bcurrency\x12#\n" +
+	"\rexchange_rate\x18\x12 \x01(\x03R\fexchangeRate\x12>\n" +
+	"\x10shipping_address\x18\x13 \x01(\v2\x13.pb.ShippingAddressR\x0fshippingAddress\x12'\n" +
+	"\x0fshipping_method\x18\x14 \x01(\tR\x0eshippingMethod\x12#\n" +
+	"\rtax_inclusive\x18\x15 \x01(\bR\ftaxInclusiveJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\aJ\x04\b\f\x10\r\"\xce\x01\n" +
+	"\x0fShippingAddress\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x14\n" +
+	"\x05phone\x18\b \x01(\tR\x05phone\"\\\n" +
 	"\fListOrderRes\x12$\n" +
-	"\x06orders\x18\x01 \x03(\v2\f.pb.OrderResR\x06orders\"z\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.pb.OrderResR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xba\x02\n" +
+	"\rListOrdersReq\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x03R\x06userId\x12,\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x0f.pb.OrderStatusH\x00R\x06status\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBeforeB\t\n" +
+	"\a_status\"\xbe\x02\n" +
+	"\x11ListUserOrdersReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12,\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.pb.OrderStatusH\x00R\x06status\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x17\n" +
+	"\asort_by\x18\a \x01(\tR\x06sortByB\t\n" +
+	"\a_status\"\x8d\x02\n" +
+	"\x11OrderStatusChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x125\n" +
+	"\vfrom_status\x18\x03 \x01(\x0e2\x0f.pb.OrderStatusH\x00R\n" +
+	"fromStatus\x88\x01\x01\x12,\n" +
+	"\tto_status\x18\x04 \x01(\x0e2\x0f.pb.OrderStatusR\btoStatus\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x05 \x01(\x03R\tchangedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\x0e\n" +
+	"\f_from_status\"L\n" +
+	"\x19ListOrderStatusHistoryRes\x12/\n" +
+	"\achanges\x18\x01 \x03(\v2\x15.pb.OrderStatusChangeR\achanges\"\xaa\x01\n" +
+	"\vInvoiceLine\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x03R\x05price\x12\x19\n" +
+	"\btax_rate\x18\x05 \x01(\x03R\ataxRate\x12\x1b\n" +
+	"\ttax_price\x18\x06 \x01(\x03R\btaxPrice\"\xb2\x05\n" +
+	"\n" +
+	"InvoiceRes\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x03R\x06number\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x127\n" +
+	"\tissued_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12\x1f\n" +
+	"\vseller_name\x18\x05 \x01(\tR\n" +
+	"sellerName\x12%\n" +
+	"\x0eseller_address\x18\x06 \x01(\tR\rsellerAddress\x12\"\n" +
+	"\rseller_tax_id\x18\a \x01(\tR\vsellerTaxId\x12\x1b\n" +
+	"\tbill_name\x18\b \x01(\tR\bbillName\x12\x1d\n" +
+	"\n" +
+	"bill_email\x18\t \x01(\tR\tbillEmail\x12>\n" +
+	"\x10shipping_address\x18\n" +
+	" \x01(\v2\x13.pb.ShippingAddressR\x0fshippingAddress\x12%\n" +
+	"\x05lines\x18\v \x03(\v2\x0f.pb.InvoiceLineR\x05lines\x12\x1a\n" +
+	"\bcurrency\x18\f \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bsubtotal\x18\r \x01(\x03R\bsubtotal\x12%\n" +
+	"\x0ediscount_price\x18\x0e \x01(\x03R\rdiscountPrice\x12\x1b\n" +
+	"\ttax_price\x18\x0f \x01(\x03R\btaxPrice\x12#\n" +
+	"\rtax_inclusive\x18\x10 \x01(\bR\ftaxInclusive\x12%\n" +
+	"\x0eshipping_price\x18\x11 \x01(\x03R\rshippingPrice\x12'\n" +
+	"\x0fshipping_method\x18\x12 \x01(\tR\x0eshippingMethod\x12\x1f\n" +
+	"\vtotal_price\x18\x13 \x01(\x03R\n" +
+	"totalPrice\"\xc1\x02\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x12$\n" +
+	"\x0ecount_in_stock\x18\x06 \x01(\x03R\fcountInStock\x12\x14\n" +
+	"\x05price\x18\a \x01(\x03R\x05price\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\b \x01(\x03R\tvariantId\x123\n" +
+	"\aoptions\x18\t \x03(\v2\x19.pb.CartItem.OptionsEntryR\aoptions\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\x04\x10\x05\"l\n" +
+	"\aCartReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x02 \x01(\tR\tcartToken\x12)\n" +
+	"\x10display_currency\x18\x03 \x01(\tR\x0fdisplayCurrency\"\xca\x01\n" +
+	"\vCartItemReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x04 \x01(\tR\tcartToken\x12)\n" +
+	"\x10display_currency\x18\x05 \x01(\tR\x0fdisplayCurrency\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x06 \x01(\x03R\tvariantId\"\xaa\x02\n" +
+	"\aCartRes\x12\"\n" +
+	"\x05items\x18\x01 \x03(\v2\f.pb.CartItemR\x05items\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x06 \x01(\tR\tcartToken\x12\x1a\n" +
+	"\bsubtotal\x18\a \x01(\x03R\bsubtotal\x12\x1b\n" +
+	"\ttax_price\x18\b \x01(\x03R\btaxPrice\x12%\n" +
+	"\x0eshipping_price\x18\t \x01(\x03R\rshippingPrice\x12\x1f\n" +
+	"\vtotal_price\x18\n" +
+	" \x01(\x03R\n" +
+	"totalPrice\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12'\n" +
+	"\x0fcharges_pending\x18\f \x01(\bR\x0echargesPendingJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04J\x04\b\x04\x10\x05J\x04\b\x05\x10\x06\"q\n" +
+	"\fMergeCartReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x02 \x01(\tR\tcartToken\x12)\n" +
+	"\x10display_currency\x18\x03 \x01(\tR\x0fdisplayCurrency\"\xf1\x01\n" +
+	"\vCheckoutReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"user_email\x18\x02 \x01(\tR\tuserEmail\x12%\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12\x1f\n" +
+	"\vcoupon_code\x18\x04 \x01(\tR\n" +
+	"couponCode\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x06 \x01(\x03R\taddressId\x12'\n" +
+	"\x0fshipping_method\x18\a \x01(\tR\x0eshippingMethod\"\xe7\x01\n" +
+	"\x10ShippingQuoteReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"cart_token\x18\x02 \x01(\tR\tcartToken\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x03 \x01(\x03R\taddressId\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12)\n" +
+	"\x10display_currency\x18\a \x01(\tR\x0fdisplayCurrency\"\x8d\x01\n" +
+	"\x0eShippingOption\x12\x1b\n" +
+	"\tmethod_id\x18\x01 \x01(\tR\bmethodId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bmin_days\x18\x03 \x01(\x05R\aminDays\x12\x19\n" +
+	"\bmax_days\x18\x04 \x01(\x05R\amaxDays\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x03R\x05price\"\\\n" +
+	"\x10ShippingQuoteRes\x12,\n" +
+	"\aoptions\x18\x01 \x03(\v2\x12.pb.ShippingOptionR\aoptions\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"s\n" +
+	"\n" +
+	"PaymentReq\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bis_admin\x18\x03 \x01(\bR\aisAdmin\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\"\xf7\x02\n" +
+	"\n" +
+	"PaymentRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12)\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x11.pb.PaymentStatusR\x06status\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12&\n" +
+	"\x0fnext_action_url\x18\a \x01(\tR\rnextActionUrl\x12%\n" +
+	"\x0edecline_reason\x18\b \x01(\tR\rdeclineReason\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"K\n" +
+	"\x11PaymentWebhookReq\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\"\xfc\x02\n" +
+	"\tCouponReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12'\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x0e.pb.CouponKindH\x00R\x04kind\x88\x01\x01\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x19\n" +
+	"\bmax_uses\x18\a \x01(\x03R\amaxUses\x12)\n" +
+	"\x11max_uses_per_user\x18\b \x01(\x03R\x0emaxUsesPerUser\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\n" +
+	" \x01(\x03R\tproductId\x12\x14\n" +
+	"\x05value\x18\v \x01(\x03R\x05value\x12&\n" +
+	"\x0fmin_order_value\x18\f \x01(\x03R\rminOrderValue\x12\x1f\n" +
+	"\vcategory_id\x18\r \x01(\x03R\n" +
+	"categoryIdB\a\n" +
+	"\x05_kindJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\t\x10\n" +
+	"R\bcategory\"\x80\x04\n" +
+	"\tCouponRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\"\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x0e.pb.CouponKindR\x04kind\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x19\n" +
+	"\bmax_uses\x18\a \x01(\x03R\amaxUses\x12)\n" +
+	"\x11max_uses_per_user\x18\b \x01(\x03R\x0emaxUsesPerUser\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\n" +
+	" \x01(\x03R\tproductId\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05value\x18\r \x01(\x03R\x05value\x12&\n" +
+	"\x0fmin_order_value\x18\x0e \x01(\x03R\rminOrderValue\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vcategory_id\x18\x10 \x01(\x03R\n" +
+	"categoryIdJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\t\x10\n" +
+	"R\bcategory\"e\n" +
+	"\x0eListCouponsReq\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\"a\n" +
+	"\x0eListCouponsRes\x12'\n" +
+	"\acoupons\x18\x01 \x03(\v2\r.pb.CouponResR\acoupons\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"z\n" +
 	"\aUserReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -1462,9 +6375,58 @@ const file_api_proto_rawDesc = "" +
 	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x19\n" +
 	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\x129\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"0\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x94\x02\n" +
+	"\fListUsersReq\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x1e\n" +
+	"\bis_admin\x18\x04 \x01(\bH\x00R\aisAdmin\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBeforeB\v\n" +
+	"\t_is_admin\"X\n" +
 	"\vListUserRes\x12!\n" +
-	"\x05users\x18\x01 \x03(\v2\v.pb.UserResR\x05users\"\xba\x01\n" +
+	"\x05users\x18\x01 \x03(\v2\v.pb.UserResR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa5\x02\n" +
+	"\n" +
+	"AddressReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x04 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x05 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\a \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\b \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\t \x01(\tR\acountry\x12\x14\n" +
+	"\x05phone\x18\n" +
+	" \x01(\tR\x05phone\x12\"\n" +
+	"\n" +
+	"is_default\x18\v \x01(\bH\x00R\tisDefault\x88\x01\x01B\r\n" +
+	"\v_is_default\"\x87\x03\n" +
+	"\n" +
+	"AddressRes\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x04 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x05 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\a \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\b \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\t \x01(\tR\acountry\x12\x14\n" +
+	"\x05phone\x18\n" +
+	" \x01(\tR\x05phone\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\v \x01(\bR\tisDefault\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"@\n" +
+	"\x10ListAddressesRes\x12,\n" +
+	"\taddresses\x18\x01 \x03(\v2\x0e.pb.AddressResR\taddresses\"\xba\x01\n" +
 	"\n" +
 	"SessionReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
@@ -1484,7 +6446,24 @@ const file_api_proto_rawDesc = "" +
 	"\n" +
 	"is_revoked\x18\x04 \x01(\bR\tisRevoked\x129\n" +
 	"\n" +
-	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xc8\x01\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x85\x02\n" +
+	"\x11IdempotencyKeyReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12!\n" +
+	"\frequest_hash\x18\x03 \x01(\tR\vrequestHash\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1f\n" +
+	"\vstatus_code\x18\x05 \x01(\x05R\n" +
+	"statusCode\x12!\n" +
+	"\fcontent_type\x18\x06 \x01(\tR\vcontentType\x12#\n" +
+	"\rresponse_body\x18\a \x01(\fR\fresponseBody\"\xbb\x01\n" +
+	"\x11IdempotencyKeyRes\x12\x1a\n" +
+	"\breserved\x18\x01 \x01(\bR\breserved\x12!\n" +
+	"\frequest_hash\x18\x02 \x01(\tR\vrequestHash\x12\x1f\n" +
+	"\vstatus_code\x18\x03 \x01(\x05R\n" +
+	"statusCode\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12#\n" +
+	"\rresponse_body\x18\x05 \x01(\fR\fresponseBody\"\xc8\x01\n" +
 	"\x11NotificationEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
 	"\n" +
@@ -1492,10 +6471,14 @@ const file_api_proto_rawDesc = "" +
 	"\forder_status\x18\x03 \x01(\x0e2\x0f.pb.OrderStatusR\vorderStatus\x12\x19\n" +
 	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x19\n" +
 	"\bstate_id\x18\x05 \x01(\x03R\astateId\x12\x1a\n" +
-	"\battempts\x18\x06 \x01(\x03R\battempts\"\x1b\n" +
-	"\x19ListNotificationEventsReq\"J\n" +
+	"\battempts\x18\x06 \x01(\x03R\battempts\"W\n" +
+	"\x19ListNotificationEventsReq\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"r\n" +
 	"\x19ListNotificationEventsRes\x12-\n" +
-	"\x06events\x18\x01 \x03(\v2\x15.pb.NotificationEventR\x06events\"\xbf\x01\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.pb.NotificationEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xbf\x01\n" +
 	"\x1aUpdateNotificationEventReq\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
 	"\bstate_id\x18\x02 \x01(\x03R\astateId\x12\x19\n" +
@@ -1503,40 +6486,107 @@ const file_api_proto_rawDesc = "" +
 	"\rresponse_type\x18\x04 \x01(\x0e2\x1c.pb.NotificationResponseTypeR\fresponseType\x12\x18\n" +
 	"\amessage\x18\x05 \x01(\tR\amessage\":\n" +
 	"\x1aUpdateNotificationEventRes\x12\x1c\n" +
-	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded*6\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded*)\n" +
+	"\fReviewStatus\x12\r\n" +
+	"\tPUBLISHED\x10\x00\x12\n" +
+	"\n" +
+	"\x06HIDDEN\x10\x01*{\n" +
 	"\vOrderStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\v\n" +
 	"\aSHIPPED\x10\x01\x12\r\n" +
-	"\tDELIVERED\x10\x02*4\n" +
+	"\tDELIVERED\x10\x02\x12\b\n" +
+	"\x04PAID\x10\x03\x12\x0e\n" +
+	"\n" +
+	"PROCESSING\x10\x04\x12\r\n" +
+	"\tCANCELLED\x10\x05\x12\f\n" +
+	"\bREFUNDED\x10\x06\x12\f\n" +
+	"\bRETURNED\x10\a*\x86\x01\n" +
+	"\rPaymentStatus\x12\x1b\n" +
+	"\x17PAYMENT_ACTION_REQUIRED\x10\x00\x12\x16\n" +
+	"\x12PAYMENT_AUTHORIZED\x10\x01\x12\x14\n" +
+	"\x10PAYMENT_CAPTURED\x10\x02\x12\x14\n" +
+	"\x10PAYMENT_DECLINED\x10\x03\x12\x14\n" +
+	"\x10PAYMENT_REFUNDED\x10\x04*'\n" +
+	"\n" +
+	"CouponKind\x12\x0e\n" +
+	"\n" +
+	"PERCENTAGE\x10\x00\x12\t\n" +
+	"\x05FIXED\x10\x01*4\n" +
 	"\x18NotificationResponseType\x12\v\n" +
 	"\aSUCCESS\x10\x00\x12\v\n" +
-	"\aFAILURE\x10\x012\xbf\b\n" +
+	"\aFAILURE\x10\x012\xbd\x1b\n" +
 	"\x05ecomm\x121\n" +
 	"\rCreateProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x12.\n" +
 	"\n" +
-	"GetProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x124\n" +
-	"\fListProducts\x12\x0e.pb.ProductReq\x1a\x12.pb.ListProductRes\"\x00\x121\n" +
+	"GetProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x129\n" +
+	"\fListProducts\x12\x13.pb.ListProductsReq\x1a\x12.pb.ListProductRes\"\x00\x12@\n" +
+	"\x0eSearchProducts\x12\x15.pb.SearchProductsReq\x1a\x15.pb.SearchProductsRes\"\x00\x121\n" +
 	"\rUpdateProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x121\n" +
-	"\rDeleteProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x12+\n" +
+	"\rDeleteProduct\x12\x0e.pb.ProductReq\x1a\x0e.pb.ProductRes\"\x00\x12B\n" +
+	"\x0eImportProducts\x12\x15.pb.ImportProductsReq\x1a\x15.pb.ImportProductsRes\"\x00(\x01\x121\n" +
+	"\rCreateVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x121\n" +
+	"\rUpdateVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x121\n" +
+	"\rDeleteVariant\x12\x0e.pb.VariantReq\x1a\x0e.pb.VariantRes\"\x00\x12=\n" +
+	"\x0fAddProductImage\x12\x13.pb.ProductImageReq\x1a\x13.pb.ProductImageRes\"\x00\x12@\n" +
+	"\x12DeleteProductImage\x12\x13.pb.ProductImageReq\x1a\x13.pb.ProductImageRes\"\x00\x12E\n" +
+	"\x14ReorderProductImages\x12\x1b.pb.ReorderProductImagesReq\x1a\x0e.pb.ProductRes\"\x00\x124\n" +
+	"\x0eCreateCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x121\n" +
+	"\vGetCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x12@\n" +
+	"\x0eListCategories\x12\x15.pb.ListCategoriesReq\x1a\x15.pb.ListCategoriesRes\"\x00\x124\n" +
+	"\x0eUpdateCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x124\n" +
+	"\x0eDeleteCategory\x12\x0f.pb.CategoryReq\x1a\x0f.pb.CategoryRes\"\x00\x12.\n" +
+	"\fCreateReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x127\n" +
+	"\vListReviews\x12\x12.pb.ListReviewsReq\x1a\x12.pb.ListReviewsRes\"\x00\x120\n" +
+	"\x0eModerateReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x12.\n" +
+	"\fDeleteReview\x12\r.pb.ReviewReq\x1a\r.pb.ReviewRes\"\x00\x12+\n" +
 	"\vCreateOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12(\n" +
-	"\bGetOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12.\n" +
+	"\bGetOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x123\n" +
 	"\n" +
-	"ListOrders\x12\f.pb.OrderReq\x1a\x10.pb.ListOrderRes\"\x00\x121\n" +
+	"ListOrders\x12\x11.pb.ListOrdersReq\x1a\x10.pb.ListOrderRes\"\x00\x12;\n" +
+	"\x0eListUserOrders\x12\x15.pb.ListUserOrdersReq\x1a\x10.pb.ListOrderRes\"\x00\x121\n" +
 	"\x11UpdateOrderStatus\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12+\n" +
-	"\vDeleteOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12(\n" +
+	"\vCancelOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12+\n" +
+	"\vDeleteOrder\x12\f.pb.OrderReq\x1a\f.pb.OrderRes\"\x00\x12G\n" +
+	"\x16ListOrderStatusHistory\x12\f.pb.OrderReq\x1a\x1d.pb.ListOrderStatusHistoryRes\"\x00\x12,\n" +
+	"\n" +
+	"GetInvoice\x12\f.pb.OrderReq\x1a\x0e.pb.InvoiceRes\"\x00\x121\n" +
+	"\rCreatePayment\x12\x0e.pb.PaymentReq\x1a\x0e.pb.PaymentRes\"\x00\x12?\n" +
+	"\x14HandlePaymentWebhook\x12\x15.pb.PaymentWebhookReq\x1a\x0e.pb.PaymentRes\"\x00\x12%\n" +
+	"\aGetCart\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12-\n" +
+	"\vAddCartItem\x12\x0f.pb.CartItemReq\x1a\v.pb.CartRes\"\x00\x120\n" +
+	"\x0eUpdateCartItem\x12\x0f.pb.CartItemReq\x1a\v.pb.CartRes\"\x00\x120\n" +
+	"\x0eRemoveCartItem\x12\x0f.pb.CartItemReq\x1a\v.pb.CartRes\"\x00\x12'\n" +
+	"\tClearCart\x12\v.pb.CartReq\x1a\v.pb.CartRes\"\x00\x12,\n" +
+	"\tMergeCart\x12\x10.pb.MergeCartReq\x1a\v.pb.CartRes\"\x00\x12+\n" +
+	"\bCheckout\x12\x0f.pb.CheckoutReq\x1a\f.pb.OrderRes\"\x00\x12=\n" +
+	"\rQuoteShipping\x12\x14.pb.ShippingQuoteReq\x1a\x14.pb.ShippingQuoteRes\"\x00\x12.\n" +
+	"\fCreateCoupon\x12\r.pb.CouponReq\x1a\r.pb.CouponRes\"\x00\x12+\n" +
+	"\tGetCoupon\x12\r.pb.CouponReq\x1a\r.pb.CouponRes\"\x00\x127\n" +
+	"\vListCoupons\x12\x12.pb.ListCouponsReq\x1a\x12.pb.ListCouponsRes\"\x00\x12.\n" +
+	"\fUpdateCoupon\x12\r.pb.CouponReq\x1a\r.pb.CouponRes\"\x00\x12.\n" +
+	"\fDeleteCoupon\x12\r.pb.CouponReq\x1a\r.pb.CouponRes\"\x00\x12(\n" +
 	"\n" +
 	"CreateUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12%\n" +
-	"\aGetUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12+\n" +
-	"\tListUsers\x12\v.pb.UserReq\x1a\x0f.pb.ListUserRes\"\x00\x12(\n" +
+	"\aGetUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x120\n" +
+	"\tListUsers\x12\x10.pb.ListUsersReq\x1a\x0f.pb.ListUserRes\"\x00\x12(\n" +
 	"\n" +
 	"UpdateUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x12(\n" +
 	"\n" +
 	"DeleteUser\x12\v.pb.UserReq\x1a\v.pb.UserRes\"\x00\x121\n" +
+	"\rCreateAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x12.\n" +
+	"\n" +
+	"GetAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x127\n" +
+	"\rListAddresses\x12\x0e.pb.AddressReq\x1a\x14.pb.ListAddressesRes\"\x00\x121\n" +
+	"\rUpdateAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x121\n" +
+	"\rDeleteAddress\x12\x0e.pb.AddressReq\x1a\x0e.pb.AddressRes\"\x00\x121\n" +
 	"\rCreateSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x12.\n" +
 	"\n" +
 	"GetSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x121\n" +
 	"\rRevokeSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x121\n" +
-	"\rDeleteSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x12X\n" +
+	"\rDeleteSession\x12\x0e.pb.SessionReq\x1a\x0e.pb.SessionRes\"\x00\x12G\n" +
+	"\x15ReserveIdempotencyKey\x12\x15.pb.IdempotencyKeyReq\x1a\x15.pb.IdempotencyKeyRes\"\x00\x12H\n" +
+	"\x16CompleteIdempotencyKey\x12\x15.pb.IdempotencyKeyReq\x1a\x15.pb.IdempotencyKeyRes\"\x00\x12G\n" +
+	"\x15ReleaseIdempotencyKey\x12\x15.pb.IdempotencyKeyReq\x1a\x15.pb.IdempotencyKeyRes\"\x00\x12X\n" +
 	"\x16ListNotificationEvents\x12\x1d.pb.ListNotificationEventsReq\x1a\x1d.pb.ListNotificationEventsRes\"\x00\x12[\n" +
 	"\x17UpdateNotificationEvent\x12\x1e.pb.UpdateNotificationEventReq\x1a\x1e.pb.UpdateNotificationEventRes\"\x00B%Z#github.com/niloy104/Conduit/grpc/pbb\x06proto3"
 
@@ -1552,95 +6602,291 @@ func file_api_proto_rawDescGZIP() []byte {
 	return file_api_proto_rawDescData
 }
 
-var file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 69)
 var file_api_proto_goTypes = []any{
-	(OrderStatus)(0),                   // 0: pb.OrderStatus
-	(NotificationResponseType)(0),      // 1: pb.NotificationResponseType
-	(*ProductReq)(nil),                 // 2: pb.ProductReq
-	(*ProductRes)(nil),                 // 3: pb.ProductRes
-	(*ListProductRes)(nil),             // 4: pb.ListProductRes
-	(*OrderItem)(nil),                  // 5: pb.OrderItem
-	(*OrderReq)(nil),                   // 6: pb.OrderReq
-	(*OrderRes)(nil),                   // 7: pb.OrderRes
-	(*ListOrderRes)(nil),               // 8: pb.ListOrderRes
-	(*UserReq)(nil),                    // 9: pb.UserReq
-	(*UserRes)(nil),                    // 10: pb.UserRes
-	(*ListUserRes)(nil),                // 11: pb.ListUserRes
-	(*SessionReq)(nil),                 // 12: pb.SessionReq
-	(*SessionRes)(nil),                 // 13: pb.SessionRes
-	(*NotificationEvent)(nil),          // 14: pb.NotificationEvent
-	(*ListNotificationEventsReq)(nil),  // 15: pb.ListNotificationEventsReq
-	(*ListNotificationEventsRes)(nil),  // 16: pb.ListNotificationEventsRes
-	(*UpdateNotificationEventReq)(nil), // 17: pb.UpdateNotificationEventReq
-	(*UpdateNotificationEventRes)(nil), // 18: pb.UpdateNotificationEventRes
-	(*timestamppb.Timestamp)(nil),      // 19: google.protobuf.Timestamp
+	(ReviewStatus)(0),                  // 0: pb.ReviewStatus
+	(OrderStatus)(0),                   // 1: pb.OrderStatus
+	(PaymentStatus)(0),                 // 2: pb.PaymentStatus
+	(CouponKind)(0),                    // 3: pb.CouponKind
+	(NotificationResponseType)(0),      // 4: pb.NotificationResponseType
+	(*ProductReq)(nil),                 // 5: pb.ProductReq
+	(*ProductRes)(nil),                 // 6: pb.ProductRes
+	(*VariantReq)(nil),                 // 7: pb.VariantReq
+	(*VariantRes)(nil),                 // 8: pb.VariantRes
+	(*ProductImageReq)(nil),            // 9: pb.ProductImageReq
+	(*ProductImageRes)(nil),            // 10: pb.ProductImageRes
+	(*ReorderProductImagesReq)(nil),    // 11: pb.ReorderProductImagesReq
+	(*ImportProductsReq)(nil),          // 12: pb.ImportProductsReq
+	(*ImportProductsRes)(nil),          // 13: pb.ImportProductsRes
+	(*ImportRowError)(nil),             // 14: pb.ImportRowError
+	(*ListProductsReq)(nil),            // 15: pb.ListProductsReq
+	(*ListProductRes)(nil),             // 16: pb.ListProductRes
+	(*SearchProductsReq)(nil),          // 17: pb.SearchProductsReq
+	(*ProductMatch)(nil),               // 18: pb.ProductMatch
+	(*SearchProductsRes)(nil),          // 19: pb.SearchProductsRes
+	(*CategoryReq)(nil),                // 20: pb.CategoryReq
+	(*CategoryRes)(nil),                // 21: pb.CategoryRes
+	(*ListCategoriesReq)(nil),          // 22: pb.ListCategoriesReq
+	(*ListCategoriesRes)(nil),          // 23: pb.ListCategoriesRes
+	(*ReviewReq)(nil),                  // 24: pb.ReviewReq
+	(*ReviewRes)(nil),                  // 25: pb.ReviewRes
+	(*ListReviewsReq)(nil),             // 26: pb.ListReviewsReq
+	(*ListReviewsRes)(nil),             // 27: pb.ListReviewsRes
+	(*OrderItem)(nil),                  // 28: pb.OrderItem
+	(*OrderReq)(nil),                   // 29: pb.OrderReq
+	(*OrderRes)(nil),                   // 30: pb.OrderRes
+	(*ShippingAddress)(nil),            // 31: pb.ShippingAddress
+	(*ListOrderRes)(nil),               // 32: pb.ListOrderRes
+	(*ListOrdersReq)(nil),              // 33: pb.ListOrdersReq
+	(*ListUserOrdersReq)(nil),          // 34: pb.ListUserOrdersReq
+	(*OrderStatusChange)(nil),          // 35: pb.OrderStatusChange
+	(*ListOrderStatusHistoryRes)(nil),  // 36: pb.ListOrderStatusHistoryRes
+	(*InvoiceLine)(nil),                // 37: pb.InvoiceLine
+	(*InvoiceRes)(nil),                 // 38: pb.InvoiceRes
+	(*CartItem)(nil),                   // 39: pb.CartItem
+	(*CartReq)(nil),                    // 40: pb.CartReq
+	(*CartItemReq)(nil),                // 41: pb.CartItemReq
+	(*CartRes)(nil),                    // 42: pb.CartRes
+	(*MergeCartReq)(nil),               // 43: pb.MergeCartReq
+	(*CheckoutReq)(nil),                // 44: pb.CheckoutReq
+	(*ShippingQuoteReq)(nil),           // 45: pb.ShippingQuoteReq
+	(*ShippingOption)(nil),             // 46: pb.ShippingOption
+	(*ShippingQuoteRes)(nil),           // 47: pb.ShippingQuoteRes
+	(*PaymentReq)(nil),                 // 48: pb.PaymentReq
+	(*PaymentRes)(nil),                 // 49: pb.PaymentRes
+	(*PaymentWebhookReq)(nil),          // 50: pb.PaymentWebhookReq
+	(*CouponReq)(nil),                  // 51: pb.CouponReq
+	(*CouponRes)(nil),                  // 52: pb.CouponRes
+	(*ListCouponsReq)(nil),             // 53: pb.ListCouponsReq
+	(*ListCouponsRes)(nil),             // 54: pb.ListCouponsRes
+	(*UserReq)(nil),                    // 55: pb.UserReq
+	(*UserRes)(nil),                    // 56: pb.UserRes
+	(*ListUsersReq)(nil),               // 57: pb.ListUsersReq
+	(*ListUserRes)(nil),                // 58: pb.ListUserRes
+	(*AddressReq)(nil),                 // 59: pb.AddressReq
+	(*AddressRes)(nil),                 // 60: pb.AddressRes
+	(*ListAddressesRes)(nil),           // 61: pb.ListAddressesRes
+	(*SessionReq)(nil),                 // 62: pb.SessionReq
+	(*SessionRes)(nil),                 // 63: pb.SessionRes
+	(*IdempotencyKeyReq)(nil),          // 64: pb.IdempotencyKeyReq
+	(*IdempotencyKeyRes)(nil),          // 65: pb.IdempotencyKeyRes
+	(*NotificationEvent)(nil),          // 66: pb.NotificationEvent
+	(*ListNotificationEventsReq)(nil),  // 67: pb.ListNotificationEventsReq
+	(*ListNotificationEventsRes)(nil),  // 68: pb.ListNotificationEventsRes
+	(*UpdateNotificationEventReq)(nil), // 69: pb.UpdateNotificationEventReq
+	(*UpdateNotificationEventRes)(nil), // 70: pb.UpdateNotificationEventRes
+	nil,                                // 71: pb.VariantReq.OptionsEntry
+	nil,                                // 72: pb.VariantRes.OptionsEntry
+	nil,                                // 73: pb.CartItem.OptionsEntry
+	(*timestamppb.Timestamp)(nil),      // 74: google.protobuf.Timestamp
 }
 var file_api_proto_depIdxs = []int32{
-	19, // 0: pb.ProductRes.created_at:type_name -> google.protobuf.Timestamp
-	19, // 1: pb.ProductRes.updated_at:type_name -> google.protobuf.Timestamp
-	3,  // 2: pb.ListProductRes.products:type_name -> pb.ProductRes
-	5,  // 3: pb.OrderReq.items:type_name -> pb.OrderItem
-	0,  // 4: pb.OrderReq.status:type_name -> pb.OrderStatus
-	5,  // 5: pb.OrderRes.items:type_name -> pb.OrderItem
-	19, // 6: pb.OrderRes.created_at:type_name -> google.protobuf.Timestamp
-	19, // 7: pb.OrderRes.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 8: pb.OrderRes.status:type_name -> pb.OrderStatus
-	7,  // 9: pb.ListOrderRes.orders:type_name -> pb.OrderRes
-	19, // 10: pb.UserRes.created_at:type_name -> google.protobuf.Timestamp
-	10, // 11: pb.ListUserRes.users:type_name -> pb.UserRes
-	19, // 12: pb.SessionReq.expires_at:type_name -> google.protobuf.Timestamp
-	19, // 13: pb.SessionRes.expires_at:type_name -> google.protobuf.Timestamp
-	0,  // 14: pb.NotificationEvent.order_status:type_name -> pb.OrderStatus
-	14, // 15: pb.ListNotificationEventsRes.events:type_name -> pb.NotificationEvent
-	1,  // 16: pb.UpdateNotificationEventReq.response_type:type_name -> pb.NotificationResponseType
-	2,  // 17: pb.ecomm.CreateProduct:input_type -> pb.ProductReq
-	2,  // 18: pb.ecomm.GetProduct:input_type -> pb.ProductReq
-	2,  // 19: pb.ecomm.ListProducts:input_type -> pb.ProductReq
-	2,  // 20: pb.ecomm.UpdateProduct:input_type -> pb.ProductReq
-	2,  // 21: pb.ecomm.DeleteProduct:input_type -> pb.ProductReq
-	6,  // 22: pb.ecomm.CreateOrder:input_type -> pb.OrderReq
-	6,  // 23: pb.ecomm.GetOrder:input_type -> pb.OrderReq
-	6,  // 24: pb.ecomm.ListOrders:input_type -> pb.OrderReq
-	6,  // 25: pb.ecomm.UpdateOrderStatus:input_type -> pb.OrderReq
-	6,  // 26: pb.ecomm.DeleteOrder:input_type -> pb.OrderReq
-	9,  // 27: pb.ecomm.CreateUser:input_type -> pb.UserReq
-	9,  // 28: pb.ecomm.GetUser:input_type -> pb.UserReq
-	9,  // 29: pb.ecomm.ListUsers:input_type -> pb.UserReq
-	9,  // 30: pb.ecomm.UpdateUser:input_type -> pb.UserReq
-	9,  // 31: pb.ecomm.DeleteUser:input_type -> pb.UserReq
-	12, // 32: pb.ecomm.CreateSession:input_type -> pb.SessionReq
-	12, // 33: pb.ecomm.GetSession:input_type -> pb.SessionReq
-	12, // 34: pb.ecomm.RevokeSession:input_type -> pb.SessionReq
-	12, // 35: pb.ecomm.DeleteSession:input_type -> pb.SessionReq
-	15, // 36: pb.ecomm.ListNotificationEvents:input_type -> pb.ListNotificationEventsReq
-	17, // 37: pb.ecomm.UpdateNotificationEvent:input_type -> pb.UpdateNotificationEventReq
-	3,  // 38: pb.ecomm.CreateProduct:output_type -> pb.ProductRes
-	3,  // 39: pb.ecomm.GetProduct:output_type -> pb.ProductRes
-	4,  // 40: pb.ecomm.ListProducts:output_type -> pb.ListProductRes
-	3,  // 41: pb.ecomm.UpdateProduct:output_type -> pb.ProductRes
-	3,  // 42: pb.ecomm.DeleteProduct:output_type -> pb.ProductRes
-	7,  // 43: pb.ecomm.CreateOrder:output_type -> pb.OrderRes
-	7,  // 44: pb.ecomm.GetOrder:output_type -> pb.OrderRes
-	8,  // 45: pb.ecomm.ListOrders:output_type -> pb.ListOrderRes
-	7,  // 46: pb.ecomm.UpdateOrderStatus:output_type -> pb.OrderRes
-	7,  // 47: pb.ecomm.DeleteOrder:output_type -> pb.OrderRes
-	10, // 48: pb.ecomm.CreateUser:output_type -> pb.UserRes
-	10, // 49: pb.ecomm.GetUser:output_type -> pb.UserRes
-	11, // 50: pb.ecomm.ListUsers:output_type -> pb.ListUserRes
-	10, // 51: pb.ecomm.UpdateUser:output_type -> pb.UserRes
-	10, // 52: pb.ecomm.DeleteUser:output_type -> pb.UserRes
-	13, // 53: pb.ecomm.CreateSession:output_type -> pb.SessionRes
-	13, // 54: pb.ecomm.GetSession:output_type -> pb.SessionRes
-	13, // 55: pb.ecomm.RevokeSession:output_type -> pb.SessionRes
-	13, // 56: pb.ecomm.DeleteSession:output_type -> pb.SessionRes
-	16, // 57: pb.ecomm.ListNotificationEvents:output_type -> pb.ListNotificationEventsRes
-	18, // 58: pb.ecomm.UpdateNotificationEvent:output_type -> pb.UpdateNotificationEventRes
-	38, // [38:59] is the sub-list for method output_type
-	17, // [17:38] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	74,  // 0: pb.ProductRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 1: pb.ProductRes.updated_at:type_name -> google.protobuf.Timestamp
+	8,   // 2: pb.ProductRes.variants:type_name -> pb.VariantRes
+	10,  // 3: pb.ProductRes.images:type_name -> pb.ProductImageRes
+	71,  // 4: pb.VariantReq.options:type_name -> pb.VariantReq.OptionsEntry
+	72,  // 5: pb.VariantRes.options:type_name -> pb.VariantRes.OptionsEntry
+	74,  // 6: pb.VariantRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 7: pb.VariantRes.updated_at:type_name -> google.protobuf.Timestamp
+	74,  // 8: pb.ProductImageRes.created_at:type_name -> google.protobuf.Timestamp
+	5,   // 9: pb.ImportProductsReq.product:type_name -> pb.ProductReq
+	14,  // 10: pb.ImportProductsRes.errors:type_name -> pb.ImportRowError
+	6,   // 11: pb.ListProductRes.products:type_name -> pb.ProductRes
+	6,   // 12: pb.ProductMatch.product:type_name -> pb.ProductRes
+	18,  // 13: pb.SearchProductsRes.matches:type_name -> pb.ProductMatch
+	74,  // 14: pb.CategoryRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 15: pb.CategoryRes.updated_at:type_name -> google.protobuf.Timestamp
+	21,  // 16: pb.ListCategoriesRes.categories:type_name -> pb.CategoryRes
+	0,   // 17: pb.ReviewReq.status:type_name -> pb.ReviewStatus
+	0,   // 18: pb.ReviewRes.status:type_name -> pb.ReviewStatus
+	74,  // 19: pb.ReviewRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 20: pb.ReviewRes.updated_at:type_name -> google.protobuf.Timestamp
+	0,   // 21: pb.ListReviewsReq.status:type_name -> pb.ReviewStatus
+	25,  // 22: pb.ListReviewsRes.reviews:type_name -> pb.ReviewRes
+	28,  // 23: pb.OrderReq.items:type_name -> pb.OrderItem
+	1,   // 24: pb.OrderReq.status:type_name -> pb.OrderStatus
+	28,  // 25: pb.OrderRes.items:type_name -> pb.OrderItem
+	74,  // 26: pb.OrderRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 27: pb.OrderRes.updated_at:type_name -> google.protobuf.Timestamp
+	1,   // 28: pb.OrderRes.status:type_name -> pb.OrderStatus
+	31,  // 29: pb.OrderRes.shipping_address:type_name -> pb.ShippingAddress
+	30,  // 30: pb.ListOrderRes.orders:type_name -> pb.OrderRes
+	1,   // 31: pb.ListOrdersReq.status:type_name -> pb.OrderStatus
+	74,  // 32: pb.ListOrdersReq.created_after:type_name -> google.protobuf.Timestamp
+	74,  // 33: pb.ListOrdersReq.created_before:type_name -> google.protobuf.Timestamp
+	1,   // 34: pb.ListUserOrdersReq.status:type_name -> pb.OrderStatus
+	74,  // 35: pb.ListUserOrdersReq.created_after:type_name -> google.protobuf.Timestamp
+	74,  // 36: pb.ListUserOrdersReq.created_before:type_name -> google.protobuf.Timestamp
+	1,   // 37: pb.OrderStatusChange.from_status:type_name -> pb.OrderStatus
+	1,   // 38: pb.OrderStatusChange.to_status:type_name -> pb.OrderStatus
+	74,  // 39: pb.OrderStatusChange.created_at:type_name -> google.protobuf.Timestamp
+	35,  // 40: pb.ListOrderStatusHistoryRes.changes:type_name -> pb.OrderStatusChange
+	74,  // 41: pb.InvoiceRes.issued_at:type_name -> google.protobuf.Timestamp
+	31,  // 42: pb.InvoiceRes.shipping_address:type_name -> pb.ShippingAddress
+	37,  // 43: pb.InvoiceRes.lines:type_name -> pb.InvoiceLine
+	73,  // 44: pb.CartItem.options:type_name -> pb.CartItem.OptionsEntry
+	39,  // 45: pb.CartRes.items:type_name -> pb.CartItem
+	46,  // 46: pb.ShippingQuoteRes.options:type_name -> pb.ShippingOption
+	2,   // 47: pb.PaymentRes.status:type_name -> pb.PaymentStatus
+	74,  // 48: pb.PaymentRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 49: pb.PaymentRes.updated_at:type_name -> google.protobuf.Timestamp
+	3,   // 50: pb.CouponReq.kind:type_name -> pb.CouponKind
+	74,  // 51: pb.CouponReq.expires_at:type_name -> google.protobuf.Timestamp
+	3,   // 52: pb.CouponRes.kind:type_name -> pb.CouponKind
+	74,  // 53: pb.CouponRes.expires_at:type_name -> google.protobuf.Timestamp
+	74,  // 54: pb.CouponRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 55: pb.CouponRes.updated_at:type_name -> google.protobuf.Timestamp
+	52,  // 56: pb.ListCouponsRes.coupons:type_name -> pb.CouponRes
+	74,  // 57: pb.UserRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 58: pb.ListUsersReq.created_after:type_name -> google.protobuf.Timestamp
+	74,  // 59: pb.ListUsersReq.created_before:type_name -> google.protobuf.Timestamp
+	56,  // 60: pb.ListUserRes.users:type_name -> pb.UserRes
+	74,  // 61: pb.AddressRes.created_at:type_name -> google.protobuf.Timestamp
+	74,  // 62: pb.AddressRes.updated_at:type_name -> google.protobuf.Timestamp
+	60,  // 63: pb.ListAddressesRes.addresses:type_name -> pb.AddressRes
+	74,  // 64: pb.SessionReq.expires_at:type_name -> google.protobuf.Timestamp
+	74,  // 65: pb.SessionRes.expires_at:type_name -> google.protobuf.Timestamp
+	74,  // 66: pb.IdempotencyKeyReq.expires_at:type_name -> google.protobuf.Timestamp
+	1,   // 67: pb.NotificationEvent.order_status:type_name -> pb.OrderStatus
+	66,  // 68: pb.ListNotificationEventsRes.events:type_name -> pb.NotificationEvent
+	4,   // 69: pb.UpdateNotificationEventReq.response_type:type_name -> pb.NotificationResponseType
+	5,   // 70: pb.ecomm.CreateProduct:input_type -> pb.ProductReq
+	5,   // 71: pb.ecomm.GetProduct:input_type -> pb.ProductReq
+	15,  // 72: pb.ecomm.ListProducts:input_type -> pb.ListProductsReq
+	17,  // 73: pb.ecomm.SearchProducts:input_type -> pb.SearchProductsReq
+	5,   // 74: pb.ecomm.UpdateProduct:input_type -> pb.ProductReq
+	5,   // 75: pb.ecomm.DeleteProduct:input_type -> pb.ProductReq
+	12,  // 76: pb.ecomm.ImportProducts:input_type -> pb.ImportProductsReq
+	7,   // 77: pb.ecomm.CreateVariant:input_type -> pb.VariantReq
+	7,   // 78: pb.ecomm.UpdateVariant:input_type -> pb.VariantReq
+	7,   // 79: pb.ecomm.DeleteVariant:input_type -> pb.VariantReq
+	9,   // 80: pb.ecomm.AddProductImage:input_type -> pb.ProductImageReq
+	9,   // 81: pb.ecomm.DeleteProductImage:input_type -> pb.ProductImageReq
+	11,  // 82: pb.ecomm.ReorderProductImages:input_type -> pb.ReorderProductImagesReq
+	20,  // 83: pb.ecomm.CreateCategory:input_type -> pb.CategoryReq
+	20,  // 84: pb.ecomm.GetCategory:input_type -> pb.CategoryReq
+	22,  // 85: pb.ecomm.ListCategories:input_type -> pb.ListCategoriesReq
+	20,  // 86: pb.ecomm.UpdateCategory:input_type -> pb.CategoryReq
+	20,  // 87: pb.ecomm.DeleteCategory:input_type -> pb.CategoryReq
+	24,  // 88: pb.ecomm.CreateReview:input_type -> pb.ReviewReq
+	26,  // 89: pb.ecomm.ListReviews:input_type -> pb.ListReviewsReq
+	24,  // 90: pb.ecomm.ModerateReview:input_type -> pb.ReviewReq
+	24,  // 91: pb.ecomm.DeleteReview:input_type -> pb.ReviewReq
+	29,  // 92: pb.ecomm.CreateOrder:input_type -> pb.OrderReq
+	29,  // 93: pb.ecomm.GetOrder:input_type -> pb.OrderReq
+	33,  // 94: pb.ecomm.ListOrders:input_type -> pb.ListOrdersReq
+	34,  // 95: pb.ecomm.ListUserOrders:input_type -> pb.ListUserOrdersReq
+	29,  // 96: pb.ecomm.UpdateOrderStatus:input_type -> pb.OrderReq
+	29,  // 97: pb.ecomm.CancelOrder:input_type -> pb.OrderReq
+	29,  // 98: pb.ecomm.DeleteOrder:input_type -> pb.OrderReq
+	29,  // 99: pb.ecomm.ListOrderStatusHistory:input_type -> pb.OrderReq
+	29,  // 100: pb.ecomm.GetInvoice:input_type -> pb.OrderReq
+	48,  // 101: pb.ecomm.CreatePayment:input_type -> pb.PaymentReq
+	50,  // 102: pb.ecomm.HandlePaymentWebhook:input_type -> pb.PaymentWebhookReq
+	40,  // 103: pb.ecomm.GetCart:input_type -> pb.CartReq
+	41,  // 104: pb.ecomm.AddCartItem:input_type -> pb.CartItemReq
+	41,  // 105: pb.ecomm.UpdateCartItem:input_type -> pb.CartItemReq
+	41,  // 106: pb.ecomm.RemoveCartItem:input_type -> pb.CartItemReq
+	40,  // 107: pb.ecomm.ClearCart:input_type -> pb.CartReq
+	43,  // 108: pb.ecomm.MergeCart:input_type -> pb.MergeCartReq
+	44,  // 109: pb.ecomm.Checkout:input_type -> pb.CheckoutReq
+	45,  // 110: pb.ecomm.QuoteShipping:input_type -> pb.ShippingQuoteReq
+	51,  // 111: pb.ecomm.CreateCoupon:input_type -> pb.CouponReq
+	51,  // 112: pb.ecomm.GetCoupon:input_type -> pb.CouponReq
+	53,  // 113: pb.ecomm.ListCoupons:input_type -> pb.ListCouponsReq
+	51,  // 114: pb.ecomm.UpdateCoupon:input_type -> pb.CouponReq
+	51,  // 115: pb.ecomm.DeleteCoupon:input_type -> pb.CouponReq
+	55,  // 116: pb.ecomm.CreateUser:input_type -> pb.UserReq
+	55,  // 117: pb.ecomm.GetUser:input_type -> pb.UserReq
+	57,  // 118: pb.ecomm.ListUsers:input_type -> pb.ListUsersReq
+	55,  // 119: pb.ecomm.UpdateUser:input_type -> pb.UserReq
+	55,  // 120: pb.ecomm.DeleteUser:input_type -> pb.UserReq
+	59,  // 121: pb.ecomm.CreateAddress:input_type -> pb.AddressReq
+	59,  // 122: pb.ecomm.GetAddress:input_type -> pb.AddressReq
+	59,  // 123: pb.ecomm.ListAddresses:input_type -> pb.AddressReq
+	59,  // 124: pb.ecomm.UpdateAddress:input_type -> pb.AddressReq
+	59,  // 125: pb.ecomm.DeleteAddress:input_type -> pb.AddressReq
+	62,  // 126: pb.ecomm.CreateSession:input_type -> pb.SessionReq
+	62,  // 127: pb.ecomm.GetSession:input_type -> pb.SessionReq
+	62,  // 128: pb.ecomm.RevokeSession:input_type -> pb.SessionReq
+	62,  // 129: pb.ecomm.DeleteSession:input_type -> pb.SessionReq
+	64,  // 130: pb.ecomm.ReserveIdempotencyKey:input_type -> pb.IdempotencyKeyReq
+	64,  // 131: pb.ecomm.CompleteIdempotencyKey:input_type -> pb.IdempotencyKeyReq
+	64,  // 132: pb.ecomm.ReleaseIdempotencyKey:input_type -> pb.IdempotencyKeyReq
+	67,  // 133: pb.ecomm.ListNotificationEvents:input_type -> pb.ListNotificationEventsReq
+	69,  // 134: pb.ecomm.UpdateNotificationEvent:input_type -> pb.UpdateNotificationEventReq
+	6,   // 135: pb.ecomm.CreateProduct:output_type -> pb.ProductRes
+	6,   // 136: pb.ecomm.GetProduct:output_type -> pb.ProductRes
+	16,  // 137: pb.ecomm.ListProducts:output_type -> pb.ListProductRes
+	19,  // 138: pb.ecomm.SearchProducts:output_type -> pb.SearchProductsRes
+	6,   // 139: pb.ecomm.UpdateProduct:output_type -> pb.ProductRes
+	6,   // 140: pb.ecomm.DeleteProduct:output_type -> pb.ProductRes
+	13,  // 141: pb.ecomm.ImportProducts:output_type -> pb.ImportProductsRes
+	8,   // 142: pb.ecomm.CreateVariant:output_type -> pb.VariantRes
+	8,   // 143: pb.ecomm.UpdateVariant:output_type -> pb.VariantRes
+	8,   // 144: pb.ecomm.DeleteVariant:output_type -> pb.VariantRes
+	10,  // 145: pb.ecomm.AddProductImage:output_type -> pb.ProductImageRes
+	10,  // 146: pb.ecomm.DeleteProductImage:output_type -> pb.ProductImageRes
+	6,   // 147: pb.ecomm.ReorderProductImages:output_type -> pb.ProductRes
+	21,  // 148: pb.ecomm.CreateCategory:output_type -> pb.CategoryRes
+	21,  // 149: pb.ecomm.GetCategory:output_type -> pb.CategoryRes
+	23,  // 150: pb.ecomm.ListCategories:output_type -> pb.ListCategoriesRes
+	21,  // 151: pb.ecomm.UpdateCategory:output_type -> pb.CategoryRes
+	21,  // 152: pb.ecomm.DeleteCategory:output_type -> pb.CategoryRes
+	25,  // 153: pb.ecomm.CreateReview:output_type -> pb.ReviewRes
+	27,  // 154: pb.ecomm.ListReviews:output_type -> pb.ListReviewsRes
+	25,  // 155: pb.ecomm.ModerateReview:output_type -> pb.ReviewRes
+	25,  // 156: pb.ecomm.DeleteReview:output_type -> pb.ReviewRes
+	30,  // 157: pb.ecomm.CreateOrder:output_type -> pb.OrderRes
+	30,  // 158: pb.ecomm.GetOrder:output_type -> pb.OrderRes
+	32,  // 159: pb.ecomm.ListOrders:output_type -> pb.ListOrderRes
+	32,  // 160: pb.ecomm.ListUserOrders:output_type -> pb.ListOrderRes
+	30,  // 161: pb.ecomm.UpdateOrderStatus:output_type -> pb.OrderRes
+	30,  // 162: pb.ecomm.CancelOrder:output_type -> pb.OrderRes
+	30,  // 163: pb.ecomm.DeleteOrder:output_type -> pb.OrderRes
+	36,  // 164: pb.ecomm.ListOrderStatusHistory:output_type -> pb.ListOrderStatusHistoryRes
+	38,  // 165: pb.ecomm.GetInvoice:output_type -> pb.InvoiceRes
+	49,  // 166: pb.ecomm.CreatePayment:output_type -> pb.PaymentRes
+	49,  // 167: pb.ecomm.HandlePaymentWebhook:output_type -> pb.PaymentRes
+	42,  // 168: pb.ecomm.GetCart:output_type -> pb.CartRes
+	42,  // 169: pb.ecomm.AddCartItem:output_type -> pb.CartRes
+	42,  // 170: pb.ecomm.UpdateCartItem:output_type -> pb.CartRes
+	42,  // 171: pb.ecomm.RemoveCartItem:output_type -> pb.CartRes
+	42,  // 172: pb.ecomm.ClearCart:output_type -> pb.CartRes
+	42,  // 173: pb.ecomm.MergeCart:output_type -> pb.CartRes
+	30,  // 174: pb.ecomm.Checkout:output_type -> pb.OrderRes
+	47,  // 175: pb.ecomm.QuoteShipping:output_type -> pb.ShippingQuoteRes
+	52,  // 176: pb.ecomm.CreateCoupon:output_type -> pb.CouponRes
+	52,  // 177: pb.ecomm.GetCoupon:output_type -> pb.CouponRes
+	54,  // 178: pb.ecomm.ListCoupons:output_type -> pb.ListCouponsRes
+	52,  // 179: pb.ecomm.UpdateCoupon:output_type -> pb.CouponRes
+	52,  // 180: pb.ecomm.DeleteCoupon:output_type -> pb.CouponRes
+	56,  // 181: pb.ecomm.CreateUser:output_type -> pb.UserRes
+	56,  // 182: pb.ecomm.GetUser:output_type -> pb.UserRes
+	58,  // 183: pb.ecomm.ListUsers:output_type -> pb.ListUserRes
+	56,  // 184: pb.ecomm.UpdateUser:output_type -> pb.UserRes
+	56,  // 185: pb.ecomm.DeleteUser:output_type -> pb.UserRes
+	60,  // 186: pb.ecomm.CreateAddress:output_type -> pb.AddressRes
+	60,  // 187: pb.ecomm.GetAddress:output_type -> pb.AddressRes
+	61,  // 188: pb.ecomm.ListAddresses:output_type -> pb.ListAddressesRes
+	60,  // 189: pb.ecomm.UpdateAddress:output_type -> pb.AddressRes
+	60,  // 190: pb.ecomm.DeleteAddress:output_type -> pb.AddressRes
+	63,  // 191: pb.ecomm.CreateSession:output_type -> pb.SessionRes
+	63,  // 192: pb.ecomm.GetSession:output_type -> pb.SessionRes
+	63,  // 193: pb.ecomm.RevokeSession:output_type -> pb.SessionRes
+	63,  // 194: pb.ecomm.DeleteSession:output_type -> pb.SessionRes
+	65,  // 195: pb.ecomm.ReserveIdempotencyKey:output_type -> pb.IdempotencyKeyRes
+	65,  // 196: pb.ecomm.CompleteIdempotencyKey:output_type -> pb.IdempotencyKeyRes
+	65,  // 197: pb.ecomm.ReleaseIdempotencyKey:output_type -> pb.IdempotencyKeyRes
+	68,  // 198: pb.ecomm.ListNotificationEvents:output_type -> pb.ListNotificationEventsRes
+	70,  // 199: pb.ecomm.UpdateNotificationEvent:output_type -> pb.UpdateNotificationEventRes
+	135, // [135:200] is the sub-list for method output_type
+	70,  // [70:135] is the sub-list for method input_type
+	70,  // [70:70] is the sub-list for extension type_name
+	70,  // [70:70] is the sub-list for extension extendee
+	0,   // [0:70] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_init() }
@@ -1648,13 +6894,24 @@ func file_api_proto_init() {
 	if File_api_proto != nil {
 		return
 	}
+	file_api_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_proto_msgTypes[15].OneofWrappers = []any{}
+	file_api_proto_msgTypes[21].OneofWrappers = []any{}
+	file_api_proto_msgTypes[28].OneofWrappers = []any{}
+	file_api_proto_msgTypes[29].OneofWrappers = []any{}
+	file_api_proto_msgTypes[30].OneofWrappers = []any{}
+	file_api_proto_msgTypes[46].OneofWrappers = []any{}
+	file_api_proto_msgTypes[52].OneofWrappers = []any{}
+	file_api_proto_msgTypes[54].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_rawDesc), len(file_api_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   17,
+			NumEnums:      5,
+			NumMessages:   69,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
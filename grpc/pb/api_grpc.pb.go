@@ -22,22 +22,66 @@ const (
 	Ecomm_CreateProduct_FullMethodName           = "/pb.ecomm/CreateProduct"
 	Ecomm_GetProduct_FullMethodName              = "/pb.ecomm/GetProduct"
 	Ecomm_ListProducts_FullMethodName            = "/pb.ecomm/ListProducts"
+	Ecomm_SearchProducts_FullMethodName          = "/pb.ecomm/SearchProducts"
 	Ecomm_UpdateProduct_FullMethodName           = "/pb.ecomm/UpdateProduct"
 	Ecomm_DeleteProduct_FullMethodName           = "/pb.ecomm/DeleteProduct"
+	Ecomm_ImportProducts_FullMethodName          = "/pb.ecomm/ImportProducts"
+	Ecomm_CreateVariant_FullMethodName           = "/pb.ecomm/CreateVariant"
+	Ecomm_UpdateVariant_FullMethodName           = "/pb.ecomm/UpdateVariant"
+	Ecomm_DeleteVariant_FullMethodName           = "/pb.ecomm/DeleteVariant"
+	Ecomm_AddProductImage_FullMethodName         = "/pb.ecomm/AddProductImage"
+	Ecomm_DeleteProductImage_FullMethodName      = "/pb.ecomm/DeleteProductImage"
+	Ecomm_ReorderProductImages_FullMethodName    = "/pb.ecomm/ReorderProductImages"
+	Ecomm_CreateCategory_FullMethodName          = "/pb.ecomm/CreateCategory"
+	Ecomm_GetCategory_FullMethodName             = "/pb.ecomm/GetCategory"
+	Ecomm_ListCategories_FullMethodName          = "/pb.ecomm/ListCategories"
+	Ecomm_UpdateCategory_FullMethodName          = "/pb.ecomm/UpdateCategory"
+	Ecomm_DeleteCategory_FullMethodName          = "/pb.ecomm/DeleteCategory"
+	Ecomm_CreateReview_FullMethodName            = "/pb.ecomm/CreateReview"
+	Ecomm_ListReviews_FullMethodName             = "/pb.ecomm/ListReviews"
+	Ecomm_ModerateReview_FullMethodName          = "/pb.ecomm/ModerateReview"
+	Ecomm_DeleteReview_FullMethodName            = "/pb.ecomm/DeleteReview"
 	Ecomm_CreateOrder_FullMethodName             = "/pb.ecomm/CreateOrder"
 	Ecomm_GetOrder_FullMethodName                = "/pb.ecomm/GetOrder"
 	Ecomm_ListOrders_FullMethodName              = "/pb.ecomm/ListOrders"
+	Ecomm_ListUserOrders_FullMethodName          = "/pb.ecomm/ListUserOrders"
 	Ecomm_UpdateOrderStatus_FullMethodName       = "/pb.ecomm/UpdateOrderStatus"
+	Ecomm_CancelOrder_FullMethodName             = "/pb.ecomm/CancelOrder"
 	Ecomm_DeleteOrder_FullMethodName             = "/pb.ecomm/DeleteOrder"
+	Ecomm_ListOrderStatusHistory_FullMethodName  = "/pb.ecomm/ListOrderStatusHistory"
+	Ecomm_GetInvoice_FullMethodName              = "/pb.ecomm/GetInvoice"
+	Ecomm_CreatePayment_FullMethodName           = "/pb.ecomm/CreatePayment"
+	Ecomm_HandlePaymentWebhook_FullMethodName    = "/pb.ecomm/HandlePaymentWebhook"
+	Ecomm_GetCart_FullMethodName                 = "/pb.ecomm/GetCart"
+	Ecomm_AddCartItem_FullMethodName             = "/pb.ecomm/AddCartItem"
+	Ecomm_UpdateCartItem_FullMethodName          = "/pb.ecomm/UpdateCartItem"
+	Ecomm_RemoveCartItem_FullMethodName          = "/pb.ecomm/RemoveCartItem"
+	Ecomm_ClearCart_FullMethodName               = "/pb.ecomm/ClearCart"
+	Ecomm_MergeCart_FullMethodName               = "/pb.ecomm/MergeCart"
+	Ecomm_Checkout_FullMethodName                = "/pb.ecomm/Checkout"
+	Ecomm_QuoteShipping_FullMethodName           = "/pb.ecomm/QuoteShipping"
+	Ecomm_CreateCoupon_FullMethodName            = "/pb.ecomm/CreateCoupon"
+	Ecomm_GetCoupon_FullMethodName               = "/pb.ecomm/GetCoupon"
+	Ecomm_ListCoupons_FullMethodName             = "/pb.ecomm/ListCoupons"
+	Ecomm_UpdateCoupon_FullMethodName            = "/pb.ecomm/UpdateCoupon"
+	Ecomm_DeleteCoupon_FullMethodName            = "/pb.ecomm/DeleteCoupon"
 	Ecomm_CreateUser_FullMethodName              = "/pb.ecomm/CreateUser"
 	Ecomm_GetUser_FullMethodName                 = "/pb.ecomm/GetUser"
 	Ecomm_ListUsers_FullMethodName               = "/pb.ecomm/ListUsers"
 	Ecomm_UpdateUser_FullMethodName              = "/pb.ecomm/UpdateUser"
 	Ecomm_DeleteUser_FullMethodName              = "/pb.ecomm/DeleteUser"
+	Ecomm_CreateAddress_FullMethodName           = "/pb.ecomm/CreateAddress"
+	Ecomm_GetAddress_FullMethodName              = "/pb.ecomm/GetAddress"
+	Ecomm_ListAddresses_FullMethodName           = "/pb.ecomm/ListAddresses"
+	Ecomm_UpdateAddress_FullMethodName           = "/pb.ecomm/UpdateAddress"
+	Ecomm_DeleteAddress_FullMethodName           = "/pb.ecomm/DeleteAddress"
 	Ecomm_CreateSession_FullMethodName           = "/pb.ecomm/CreateSession"
 	Ecomm_GetSession_FullMethodName              = "/pb.ecomm/GetSession"
 	Ecomm_RevokeSession_FullMethodName           = "/pb.ecomm/RevokeSession"
 	Ecomm_DeleteSession_FullMethodName           = "/pb.ecomm/DeleteSession"
+	Ecomm_ReserveIdempotencyKey_FullMethodName   = "/pb.ecomm/ReserveIdempotencyKey"
+	Ecomm_CompleteIdempotencyKey_FullMethodName  = "/pb.ecomm/CompleteIdempotencyKey"
+	Ecomm_ReleaseIdempotencyKey_FullMethodName   = "/pb.ecomm/ReleaseIdempotencyKey"
 	Ecomm_ListNotificationEvents_FullMethodName  = "/pb.ecomm/ListNotificationEvents"
 	Ecomm_UpdateNotificationEvent_FullMethodName = "/pb.ecomm/UpdateNotificationEvent"
 )
@@ -48,23 +92,67 @@ const (
 type EcommClient interface {
 	CreateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
 	GetProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
-	ListProducts(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ListProductRes, error)
+	ListProducts(ctx context.Context, in *ListProductsReq, opts ...grpc.CallOption) (*ListProductRes, error)
+	SearchProducts(ctx context.Context, in *SearchProductsReq, opts ...grpc.CallOption) (*SearchProductsRes, error)
 	UpdateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
 	DeleteProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error)
+	ImportProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportProductsReq, ImportProductsRes], error)
+	CreateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	UpdateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	DeleteVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error)
+	AddProductImage(ctx context.Context, in *ProductImageReq, opts ...grpc.CallOption) (*ProductImageRes, error)
+	DeleteProductImage(ctx context.Context, in *ProductImageReq, opts ...grpc.CallOption) (*ProductImageRes, error)
+	ReorderProductImages(ctx context.Context, in *ReorderProductImagesReq, opts ...grpc.CallOption) (*ProductRes, error)
+	CreateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	GetCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	ListCategories(ctx context.Context, in *ListCategoriesReq, opts ...grpc.CallOption) (*ListCategoriesRes, error)
+	UpdateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	DeleteCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error)
+	CreateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	ListReviews(ctx context.Context, in *ListReviewsReq, opts ...grpc.CallOption) (*ListReviewsRes, error)
+	ModerateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
+	DeleteReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error)
 	CreateOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
 	GetOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
-	ListOrders(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderRes, error)
+	ListOrders(ctx context.Context, in *ListOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error)
+	ListUserOrders(ctx context.Context, in *ListUserOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error)
 	UpdateOrderStatus(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
+	CancelOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
 	DeleteOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error)
+	ListOrderStatusHistory(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderStatusHistoryRes, error)
+	GetInvoice(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*InvoiceRes, error)
+	CreatePayment(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*PaymentRes, error)
+	HandlePaymentWebhook(ctx context.Context, in *PaymentWebhookReq, opts ...grpc.CallOption) (*PaymentRes, error)
+	GetCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	AddCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error)
+	UpdateCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error)
+	RemoveCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error)
+	ClearCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error)
+	MergeCart(ctx context.Context, in *MergeCartReq, opts ...grpc.CallOption) (*CartRes, error)
+	Checkout(ctx context.Context, in *CheckoutReq, opts ...grpc.CallOption) (*OrderRes, error)
+	QuoteShipping(ctx context.Context, in *ShippingQuoteReq, opts ...grpc.CallOption) (*ShippingQuoteRes, error)
+	CreateCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error)
+	GetCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error)
+	ListCoupons(ctx context.Context, in *ListCouponsReq, opts ...grpc.CallOption) (*ListCouponsRes, error)
+	UpdateCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error)
+	DeleteCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error)
 	CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
-	ListUsers(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*ListUserRes, error)
+	ListUsers(ctx context.Context, in *ListUsersReq, opts ...grpc.CallOption) (*ListUserRes, error)
 	UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
 	DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error)
+	CreateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	GetAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	ListAddresses(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*ListAddressesRes, error)
+	UpdateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
+	DeleteAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error)
 	CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
 	DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error)
+	ReserveIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error)
+	CompleteIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error)
+	ReleaseIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error)
 	ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error)
 	UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error)
 }
@@ -97,7 +185,7 @@ func (c *ecommClient) GetProduct(ctx context.Context, in *ProductReq, opts ...gr
 	return out, nil
 }
 
-func (c *ecommClient) ListProducts(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ListProductRes, error) {
+func (c *ecommClient) ListProducts(ctx context.Context, in *ListProductsReq, opts ...grpc.CallOption) (*ListProductRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListProductRes)
 	err := c.cc.Invoke(ctx, Ecomm_ListProducts_FullMethodName, in, out, cOpts...)
@@ -107,6 +195,16 @@ func (c *ecommClient) ListProducts(ctx context.Context, in *ProductReq, opts ...
 	return out, nil
 }
 
+func (c *ecommClient) SearchProducts(ctx context.Context, in *SearchProductsReq, opts ...grpc.CallOption) (*SearchProductsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchProductsRes)
+	err := c.cc.Invoke(ctx, Ecomm_SearchProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ecommClient) UpdateProduct(ctx context.Context, in *ProductReq, opts ...grpc.CallOption) (*ProductRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ProductRes)
@@ -127,6 +225,169 @@ func (c *ecommClient) DeleteProduct(ctx context.Context, in *ProductReq, opts ..
 	return out, nil
 }
 
+func (c *ecommClient) ImportProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportProductsReq, ImportProductsRes], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Ecomm_ServiceDesc.Streams[0], Ecomm_ImportProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportProductsReq, ImportProductsRes]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ecomm_ImportProductsClient = grpc.ClientStreamingClient[ImportProductsReq, ImportProductsRes]
+
+func (c *ecommClient) CreateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) UpdateVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteVariant(ctx context.Context, in *VariantReq, opts ...grpc.CallOption) (*VariantRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VariantRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) AddProductImage(ctx context.Context, in *ProductImageReq, opts ...grpc.CallOption) (*ProductImageRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductImageRes)
+	err := c.cc.Invoke(ctx, Ecomm_AddProductImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteProductImage(ctx context.Context, in *ProductImageReq, opts ...grpc.CallOption) (*ProductImageRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductImageRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteProductImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ReorderProductImages(ctx context.Context, in *ReorderProductImagesReq, opts ...grpc.CallOption) (*ProductRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductRes)
+	err := c.cc.Invoke(ctx, Ecomm_ReorderProductImages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) CreateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) GetCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ListCategories(ctx context.Context, in *ListCategoriesReq, opts ...grpc.CallOption) (*ListCategoriesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCategoriesRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) UpdateCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteCategory(ctx context.Context, in *CategoryReq, opts ...grpc.CallOption) (*CategoryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) CreateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ListReviews(ctx context.Context, in *ListReviewsReq, opts ...grpc.CallOption) (*ListReviewsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewsRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ModerateReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecomm_ModerateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteReview(ctx context.Context, in *ReviewReq, opts ...grpc.CallOption) (*ReviewRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ecommClient) CreateOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OrderRes)
@@ -147,7 +408,7 @@ func (c *ecommClient) GetOrder(ctx context.Context, in *OrderReq, opts ...grpc.C
 	return out, nil
 }
 
-func (c *ecommClient) ListOrders(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
+func (c *ecommClient) ListOrders(ctx context.Context, in *ListOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListOrderRes)
 	err := c.cc.Invoke(ctx, Ecomm_ListOrders_FullMethodName, in, out, cOpts...)
@@ -157,6 +418,16 @@ func (c *ecommClient) ListOrders(ctx context.Context, in *OrderReq, opts ...grpc
 	return out, nil
 }
 
+func (c *ecommClient) ListUserOrders(ctx context.Context, in *ListUserOrdersReq, opts ...grpc.CallOption) (*ListOrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrderRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListUserOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ecommClient) UpdateOrderStatus(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OrderRes)
@@ -167,6 +438,16 @@ func (c *ecommClient) UpdateOrderStatus(ctx context.Context, in *OrderReq, opts
 	return out, nil
 }
 
+func (c *ecommClient) CancelOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecomm_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ecommClient) DeleteOrder(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*OrderRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OrderRes)
@@ -177,188 +458,590 @@ func (c *ecommClient) DeleteOrder(ctx context.Context, in *OrderReq, opts ...grp
 	return out, nil
 }
 
-func (c *ecommClient) CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecommClient) ListOrderStatusHistory(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*ListOrderStatusHistoryRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecomm_CreateUser_FullMethodName, in, out, cOpts...)
+	out := new(ListOrderStatusHistoryRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListOrderStatusHistory_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecommClient) GetInvoice(ctx context.Context, in *OrderReq, opts ...grpc.CallOption) (*InvoiceRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecomm_GetUser_FullMethodName, in, out, cOpts...)
+	out := new(InvoiceRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetInvoice_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) ListUsers(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*ListUserRes, error) {
+func (c *ecommClient) CreatePayment(ctx context.Context, in *PaymentReq, opts ...grpc.CallOption) (*PaymentRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListUserRes)
-	err := c.cc.Invoke(ctx, Ecomm_ListUsers_FullMethodName, in, out, cOpts...)
+	out := new(PaymentRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreatePayment_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecommClient) HandlePaymentWebhook(ctx context.Context, in *PaymentWebhookReq, opts ...grpc.CallOption) (*PaymentRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecomm_UpdateUser_FullMethodName, in, out, cOpts...)
+	out := new(PaymentRes)
+	err := c.cc.Invoke(ctx, Ecomm_HandlePaymentWebhook_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+func (c *ecommClient) GetCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UserRes)
-	err := c.cc.Invoke(ctx, Ecomm_DeleteUser_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetCart_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecommClient) AddCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecomm_CreateSession_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_AddCartItem_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecommClient) UpdateCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecomm_GetSession_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateCartItem_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecommClient) RemoveCartItem(ctx context.Context, in *CartItemReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecomm_RevokeSession_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_RemoveCartItem_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+func (c *ecommClient) ClearCart(ctx context.Context, in *CartReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SessionRes)
-	err := c.cc.Invoke(ctx, Ecomm_DeleteSession_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_ClearCart_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error) {
+func (c *ecommClient) MergeCart(ctx context.Context, in *MergeCartReq, opts ...grpc.CallOption) (*CartRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListNotificationEventsRes)
-	err := c.cc.Invoke(ctx, Ecomm_ListNotificationEvents_FullMethodName, in, out, cOpts...)
+	out := new(CartRes)
+	err := c.cc.Invoke(ctx, Ecomm_MergeCart_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ecommClient) UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error) {
+func (c *ecommClient) Checkout(ctx context.Context, in *CheckoutReq, opts ...grpc.CallOption) (*OrderRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(UpdateNotificationEventRes)
-	err := c.cc.Invoke(ctx, Ecomm_UpdateNotificationEvent_FullMethodName, in, out, cOpts...)
+	out := new(OrderRes)
+	err := c.cc.Invoke(ctx, Ecomm_Checkout_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// EcommServer is the server API for Ecomm service.
-// All implementations must embed UnimplementedEcommServer
-// for forward compatibility.
-type EcommServer interface {
-	CreateProduct(context.Context, *ProductReq) (*ProductRes, error)
-	GetProduct(context.Context, *ProductReq) (*ProductRes, error)
-	ListProducts(context.Context, *ProductReq) (*ListProductRes, error)
-	UpdateProduct(context.Context, *ProductReq) (*ProductRes, error)
-	DeleteProduct(context.Context, *ProductReq) (*ProductRes, error)
-	CreateOrder(context.Context, *OrderReq) (*OrderRes, error)
-	GetOrder(context.Context, *OrderReq) (*OrderRes, error)
-	ListOrders(context.Context, *OrderReq) (*ListOrderRes, error)
-	UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error)
-	DeleteOrder(context.Context, *OrderReq) (*OrderRes, error)
-	CreateUser(context.Context, *UserReq) (*UserRes, error)
-	GetUser(context.Context, *UserReq) (*UserRes, error)
-	ListUsers(context.Context, *UserReq) (*ListUserRes, error)
-	UpdateUser(context.Context, *UserReq) (*UserRes, error)
-	DeleteUser(context.Context, *UserReq) (*UserRes, error)
-	CreateSession(context.Context, *SessionReq) (*SessionRes, error)
-	GetSession(context.Context, *SessionReq) (*SessionRes, error)
-	RevokeSession(context.Context, *SessionReq) (*SessionRes, error)
-	DeleteSession(context.Context, *SessionReq) (*SessionRes, error)
-	ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error)
-	UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error)
-	mustEmbedUnimplementedEcommServer()
+func (c *ecommClient) QuoteShipping(ctx context.Context, in *ShippingQuoteReq, opts ...grpc.CallOption) (*ShippingQuoteRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShippingQuoteRes)
+	err := c.cc.Invoke(ctx, Ecomm_QuoteShipping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedEcommServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedEcommServer struct{}
-
-func (UnimplementedEcommServer) CreateProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method CreateProduct not implemented")
-}
-func (UnimplementedEcommServer) GetProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetProduct not implemented")
+func (c *ecommClient) CreateCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CouponRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) ListProducts(context.Context, *ProductReq) (*ListProductRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method ListProducts not implemented")
+
+func (c *ecommClient) GetCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CouponRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) UpdateProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method UpdateProduct not implemented")
+
+func (c *ecommClient) ListCoupons(ctx context.Context, in *ListCouponsReq, opts ...grpc.CallOption) (*ListCouponsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCouponsRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListCoupons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) DeleteProduct(context.Context, *ProductReq) (*ProductRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method DeleteProduct not implemented")
+
+func (c *ecommClient) UpdateCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CouponRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) CreateOrder(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method CreateOrder not implemented")
+
+func (c *ecommClient) DeleteCoupon(ctx context.Context, in *CouponReq, opts ...grpc.CallOption) (*CouponRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CouponRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) GetOrder(context.Context, *OrderReq) (*OrderRes, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
+
+func (c *ecommClient) CreateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedEcommServer) ListOrders(context.Context, *OrderReq) (*ListOrderRes, error) {
+
+func (c *ecommClient) GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ListUsers(ctx context.Context, in *ListUsersReq, opts ...grpc.CallOption) (*ListUserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) UpdateUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) CreateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) GetAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ListAddresses(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*ListAddressesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAddressesRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) UpdateAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteAddress(ctx context.Context, in *AddressReq, opts ...grpc.CallOption) (*AddressRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) CreateSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecomm_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) GetSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecomm_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) RevokeSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecomm_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) DeleteSession(ctx context.Context, in *SessionReq, opts ...grpc.CallOption) (*SessionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionRes)
+	err := c.cc.Invoke(ctx, Ecomm_DeleteSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ReserveIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IdempotencyKeyRes)
+	err := c.cc.Invoke(ctx, Ecomm_ReserveIdempotencyKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) CompleteIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IdempotencyKeyRes)
+	err := c.cc.Invoke(ctx, Ecomm_CompleteIdempotencyKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ReleaseIdempotencyKey(ctx context.Context, in *IdempotencyKeyReq, opts ...grpc.CallOption) (*IdempotencyKeyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IdempotencyKeyRes)
+	err := c.cc.Invoke(ctx, Ecomm_ReleaseIdempotencyKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) ListNotificationEvents(ctx context.Context, in *ListNotificationEventsReq, opts ...grpc.CallOption) (*ListNotificationEventsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationEventsRes)
+	err := c.cc.Invoke(ctx, Ecomm_ListNotificationEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ecommClient) UpdateNotificationEvent(ctx context.Context, in *UpdateNotificationEventReq, opts ...grpc.CallOption) (*UpdateNotificationEventRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateNotificationEventRes)
+	err := c.cc.Invoke(ctx, Ecomm_UpdateNotificationEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EcommServer is the server API for Ecomm service.
+// All implementations must embed UnimplementedEcommServer
+// for forward compatibility.
+type EcommServer interface {
+	CreateProduct(context.Context, *ProductReq) (*ProductRes, error)
+	GetProduct(context.Context, *ProductReq) (*ProductRes, error)
+	ListProducts(context.Context, *ListProductsReq) (*ListProductRes, error)
+	SearchProducts(context.Context, *SearchProductsReq) (*SearchProductsRes, error)
+	UpdateProduct(context.Context, *ProductReq) (*ProductRes, error)
+	DeleteProduct(context.Context, *ProductReq) (*ProductRes, error)
+	ImportProducts(grpc.ClientStreamingServer[ImportProductsReq, ImportProductsRes]) error
+	CreateVariant(context.Context, *VariantReq) (*VariantRes, error)
+	UpdateVariant(context.Context, *VariantReq) (*VariantRes, error)
+	DeleteVariant(context.Context, *VariantReq) (*VariantRes, error)
+	AddProductImage(context.Context, *ProductImageReq) (*ProductImageRes, error)
+	DeleteProductImage(context.Context, *ProductImageReq) (*ProductImageRes, error)
+	ReorderProductImages(context.Context, *ReorderProductImagesReq) (*ProductRes, error)
+	CreateCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	GetCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	ListCategories(context.Context, *ListCategoriesReq) (*ListCategoriesRes, error)
+	UpdateCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	DeleteCategory(context.Context, *CategoryReq) (*CategoryRes, error)
+	CreateReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	ListReviews(context.Context, *ListReviewsReq) (*ListReviewsRes, error)
+	ModerateReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	DeleteReview(context.Context, *ReviewReq) (*ReviewRes, error)
+	CreateOrder(context.Context, *OrderReq) (*OrderRes, error)
+	GetOrder(context.Context, *OrderReq) (*OrderRes, error)
+	ListOrders(context.Context, *ListOrdersReq) (*ListOrderRes, error)
+	ListUserOrders(context.Context, *ListUserOrdersReq) (*ListOrderRes, error)
+	UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error)
+	CancelOrder(context.Context, *OrderReq) (*OrderRes, error)
+	DeleteOrder(context.Context, *OrderReq) (*OrderRes, error)
+	ListOrderStatusHistory(context.Context, *OrderReq) (*ListOrderStatusHistoryRes, error)
+	GetInvoice(context.Context, *OrderReq) (*InvoiceRes, error)
+	CreatePayment(context.Context, *PaymentReq) (*PaymentRes, error)
+	HandlePaymentWebhook(context.Context, *PaymentWebhookReq) (*PaymentRes, error)
+	GetCart(context.Context, *CartReq) (*CartRes, error)
+	AddCartItem(context.Context, *CartItemReq) (*CartRes, error)
+	UpdateCartItem(context.Context, *CartItemReq) (*CartRes, error)
+	RemoveCartItem(context.Context, *CartItemReq) (*CartRes, error)
+	ClearCart(context.Context, *CartReq) (*CartRes, error)
+	MergeCart(context.Context, *MergeCartReq) (*CartRes, error)
+	Checkout(context.Context, *CheckoutReq) (*OrderRes, error)
+	QuoteShipping(context.Context, *ShippingQuoteReq) (*ShippingQuoteRes, error)
+	CreateCoupon(context.Context, *CouponReq) (*CouponRes, error)
+	GetCoupon(context.Context, *CouponReq) (*CouponRes, error)
+	ListCoupons(context.Context, *ListCouponsReq) (*ListCouponsRes, error)
+	UpdateCoupon(context.Context, *CouponReq) (*CouponRes, error)
+	DeleteCoupon(context.Context, *CouponReq) (*CouponRes, error)
+	CreateUser(context.Context, *UserReq) (*UserRes, error)
+	GetUser(context.Context, *UserReq) (*UserRes, error)
+	ListUsers(context.Context, *ListUsersReq) (*ListUserRes, error)
+	UpdateUser(context.Context, *UserReq) (*UserRes, error)
+	DeleteUser(context.Context, *UserReq) (*UserRes, error)
+	CreateAddress(context.Context, *AddressReq) (*AddressRes, error)
+	GetAddress(context.Context, *AddressReq) (*AddressRes, error)
+	ListAddresses(context.Context, *AddressReq) (*ListAddressesRes, error)
+	UpdateAddress(context.Context, *AddressReq) (*AddressRes, error)
+	DeleteAddress(context.Context, *AddressReq) (*AddressRes, error)
+	CreateSession(context.Context, *SessionReq) (*SessionRes, error)
+	GetSession(context.Context, *SessionReq) (*SessionRes, error)
+	RevokeSession(context.Context, *SessionReq) (*SessionRes, error)
+	DeleteSession(context.Context, *SessionReq) (*SessionRes, error)
+	ReserveIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error)
+	CompleteIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error)
+	ReleaseIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error)
+	ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error)
+	UpdateNotificationEvent(context.Context, *UpdateNotificationEventReq) (*UpdateNotificationEventRes, error)
+	mustEmbedUnimplementedEcommServer()
+}
+
+// UnimplementedEcommServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEcommServer struct{}
+
+func (UnimplementedEcommServer) CreateProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (UnimplementedEcommServer) GetProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedEcommServer) ListProducts(context.Context, *ListProductsReq) (*ListProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedEcommServer) SearchProducts(context.Context, *SearchProductsReq) (*SearchProductsRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedEcommServer) UpdateProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedEcommServer) DeleteProduct(context.Context, *ProductReq) (*ProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteProduct not implemented")
+}
+func (UnimplementedEcommServer) ImportProducts(grpc.ClientStreamingServer[ImportProductsReq, ImportProductsRes]) error {
+	return status.Error(codes.Unimplemented, "method ImportProducts not implemented")
+}
+func (UnimplementedEcommServer) CreateVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateVariant not implemented")
+}
+func (UnimplementedEcommServer) UpdateVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateVariant not implemented")
+}
+func (UnimplementedEcommServer) DeleteVariant(context.Context, *VariantReq) (*VariantRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteVariant not implemented")
+}
+func (UnimplementedEcommServer) AddProductImage(context.Context, *ProductImageReq) (*ProductImageRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddProductImage not implemented")
+}
+func (UnimplementedEcommServer) DeleteProductImage(context.Context, *ProductImageReq) (*ProductImageRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteProductImage not implemented")
+}
+func (UnimplementedEcommServer) ReorderProductImages(context.Context, *ReorderProductImagesReq) (*ProductRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReorderProductImages not implemented")
+}
+func (UnimplementedEcommServer) CreateCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedEcommServer) GetCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCategory not implemented")
+}
+func (UnimplementedEcommServer) ListCategories(context.Context, *ListCategoriesReq) (*ListCategoriesRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedEcommServer) UpdateCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+func (UnimplementedEcommServer) DeleteCategory(context.Context, *CategoryReq) (*CategoryRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+func (UnimplementedEcommServer) CreateReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedEcommServer) ListReviews(context.Context, *ListReviewsReq) (*ListReviewsRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedEcommServer) ModerateReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedEcommServer) DeleteReview(context.Context, *ReviewReq) (*ReviewRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedEcommServer) CreateOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateOrder not implemented")
+}
+func (UnimplementedEcommServer) GetOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedEcommServer) ListOrders(context.Context, *ListOrdersReq) (*ListOrderRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListOrders not implemented")
 }
+func (UnimplementedEcommServer) ListUserOrders(context.Context, *ListUserOrdersReq) (*ListOrderRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUserOrders not implemented")
+}
 func (UnimplementedEcommServer) UpdateOrderStatus(context.Context, *OrderReq) (*OrderRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateOrderStatus not implemented")
 }
+func (UnimplementedEcommServer) CancelOrder(context.Context, *OrderReq) (*OrderRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelOrder not implemented")
+}
 func (UnimplementedEcommServer) DeleteOrder(context.Context, *OrderReq) (*OrderRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteOrder not implemented")
 }
+func (UnimplementedEcommServer) ListOrderStatusHistory(context.Context, *OrderReq) (*ListOrderStatusHistoryRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOrderStatusHistory not implemented")
+}
+func (UnimplementedEcommServer) GetInvoice(context.Context, *OrderReq) (*InvoiceRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedEcommServer) CreatePayment(context.Context, *PaymentReq) (*PaymentRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreatePayment not implemented")
+}
+func (UnimplementedEcommServer) HandlePaymentWebhook(context.Context, *PaymentWebhookReq) (*PaymentRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method HandlePaymentWebhook not implemented")
+}
+func (UnimplementedEcommServer) GetCart(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCart not implemented")
+}
+func (UnimplementedEcommServer) AddCartItem(context.Context, *CartItemReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddCartItem not implemented")
+}
+func (UnimplementedEcommServer) UpdateCartItem(context.Context, *CartItemReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCartItem not implemented")
+}
+func (UnimplementedEcommServer) RemoveCartItem(context.Context, *CartItemReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveCartItem not implemented")
+}
+func (UnimplementedEcommServer) ClearCart(context.Context, *CartReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearCart not implemented")
+}
+func (UnimplementedEcommServer) MergeCart(context.Context, *MergeCartReq) (*CartRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeCart not implemented")
+}
+func (UnimplementedEcommServer) Checkout(context.Context, *CheckoutReq) (*OrderRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method Checkout not implemented")
+}
+func (UnimplementedEcommServer) QuoteShipping(context.Context, *ShippingQuoteReq) (*ShippingQuoteRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method QuoteShipping not implemented")
+}
+func (UnimplementedEcommServer) CreateCoupon(context.Context, *CouponReq) (*CouponRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCoupon not implemented")
+}
+func (UnimplementedEcommServer) GetCoupon(context.Context, *CouponReq) (*CouponRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCoupon not implemented")
+}
+func (UnimplementedEcommServer) ListCoupons(context.Context, *ListCouponsReq) (*ListCouponsRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCoupons not implemented")
+}
+func (UnimplementedEcommServer) UpdateCoupon(context.Context, *CouponReq) (*CouponRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCoupon not implemented")
+}
+func (UnimplementedEcommServer) DeleteCoupon(context.Context, *CouponReq) (*CouponRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteCoupon not implemented")
+}
 func (UnimplementedEcommServer) CreateUser(context.Context, *UserReq) (*UserRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
 }
 func (UnimplementedEcommServer) GetUser(context.Context, *UserReq) (*UserRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
 }
-func (UnimplementedEcommServer) ListUsers(context.Context, *UserReq) (*ListUserRes, error) {
+func (UnimplementedEcommServer) ListUsers(context.Context, *ListUsersReq) (*ListUserRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListUsers not implemented")
 }
 func (UnimplementedEcommServer) UpdateUser(context.Context, *UserReq) (*UserRes, error) {
@@ -367,6 +1050,21 @@ func (UnimplementedEcommServer) UpdateUser(context.Context, *UserReq) (*UserRes,
 func (UnimplementedEcommServer) DeleteUser(context.Context, *UserReq) (*UserRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteUser not implemented")
 }
+func (UnimplementedEcommServer) CreateAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAddress not implemented")
+}
+func (UnimplementedEcommServer) GetAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAddress not implemented")
+}
+func (UnimplementedEcommServer) ListAddresses(context.Context, *AddressReq) (*ListAddressesRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAddresses not implemented")
+}
+func (UnimplementedEcommServer) UpdateAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAddress not implemented")
+}
+func (UnimplementedEcommServer) DeleteAddress(context.Context, *AddressReq) (*AddressRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAddress not implemented")
+}
 func (UnimplementedEcommServer) CreateSession(context.Context, *SessionReq) (*SessionRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateSession not implemented")
 }
@@ -379,6 +1077,15 @@ func (UnimplementedEcommServer) RevokeSession(context.Context, *SessionReq) (*Se
 func (UnimplementedEcommServer) DeleteSession(context.Context, *SessionReq) (*SessionRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteSession not implemented")
 }
+func (UnimplementedEcommServer) ReserveIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReserveIdempotencyKey not implemented")
+}
+func (UnimplementedEcommServer) CompleteIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompleteIdempotencyKey not implemented")
+}
+func (UnimplementedEcommServer) ReleaseIdempotencyKey(context.Context, *IdempotencyKeyReq) (*IdempotencyKeyRes, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReleaseIdempotencyKey not implemented")
+}
 func (UnimplementedEcommServer) ListNotificationEvents(context.Context, *ListNotificationEventsReq) (*ListNotificationEventsRes, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListNotificationEvents not implemented")
 }
@@ -403,185 +1110,822 @@ func RegisterEcommServer(s grpc.ServiceRegistrar, srv EcommServer) {
 	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
 		t.testEmbeddedByValue()
 	}
-	s.RegisterService(&Ecomm_ServiceDesc, srv)
+	s.RegisterService(&Ecomm_ServiceDesc, srv)
+}
+
+func _Ecomm_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListProducts(ctx, req.(*ListProductsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_SearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).SearchProducts(ctx, req.(*SearchProductsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteProduct(ctx, req.(*ProductReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ImportProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EcommServer).ImportProducts(&grpc.GenericServerStream[ImportProductsReq, ImportProductsRes]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ecomm_ImportProductsServer = grpc.ClientStreamingServer[ImportProductsReq, ImportProductsRes]
+
+func _Ecomm_CreateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteVariant(ctx, req.(*VariantReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_AddProductImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductImageReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).AddProductImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_AddProductImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).AddProductImage(ctx, req.(*ProductImageReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteProductImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductImageReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteProductImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteProductImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteProductImage(ctx, req.(*ProductImageReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ReorderProductImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderProductImagesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ReorderProductImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ReorderProductImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ReorderProductImages(ctx, req.(*ReorderProductImagesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListCategories(ctx, req.(*ListCategoriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteCategory(ctx, req.(*CategoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListReviews(ctx, req.(*ListReviewsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ModerateReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteReview(ctx, req.(*ReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListOrders(ctx, req.(*ListOrdersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListUserOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserOrdersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListUserOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListUserOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListUserOrders(ctx, req.(*ListUserOrdersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateOrderStatus(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CancelOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteOrder(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListOrderStatusHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListOrderStatusHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListOrderStatusHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListOrderStatusHistory(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetInvoice(ctx, req.(*OrderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CreatePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreatePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreatePayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreatePayment(ctx, req.(*PaymentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_HandlePaymentWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentWebhookReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).HandlePaymentWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_HandlePaymentWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).HandlePaymentWebhook(ctx, req.(*PaymentWebhookReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetCart(ctx, req.(*CartReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_AddCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartItemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).AddCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_AddCartItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).AddCartItem(ctx, req.(*CartItemReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartItemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateCartItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateCartItem(ctx, req.(*CartItemReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecomm_RemoveCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartItemReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).CreateProduct(ctx, in)
+		return srv.(EcommServer).RemoveCartItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_CreateProduct_FullMethodName,
+		FullMethod: Ecomm_RemoveCartItem_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).CreateProduct(ctx, req.(*ProductReq))
+		return srv.(EcommServer).RemoveCartItem(ctx, req.(*CartItemReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecomm_ClearCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).GetProduct(ctx, in)
+		return srv.(EcommServer).ClearCart(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_GetProduct_FullMethodName,
+		FullMethod: Ecomm_ClearCart_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).GetProduct(ctx, req.(*ProductReq))
+		return srv.(EcommServer).ClearCart(ctx, req.(*CartReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecomm_MergeCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).ListProducts(ctx, in)
+		return srv.(EcommServer).MergeCart(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_ListProducts_FullMethodName,
+		FullMethod: Ecomm_MergeCart_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).ListProducts(ctx, req.(*ProductReq))
+		return srv.(EcommServer).MergeCart(ctx, req.(*MergeCartReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecomm_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).UpdateProduct(ctx, in)
+		return srv.(EcommServer).Checkout(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_UpdateProduct_FullMethodName,
+		FullMethod: Ecomm_Checkout_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).UpdateProduct(ctx, req.(*ProductReq))
+		return srv.(EcommServer).Checkout(ctx, req.(*CheckoutReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProductReq)
+func _Ecomm_QuoteShipping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShippingQuoteReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).DeleteProduct(ctx, in)
+		return srv.(EcommServer).QuoteShipping(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_DeleteProduct_FullMethodName,
+		FullMethod: Ecomm_QuoteShipping_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).DeleteProduct(ctx, req.(*ProductReq))
+		return srv.(EcommServer).QuoteShipping(ctx, req.(*ShippingQuoteReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecomm_CreateCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).CreateOrder(ctx, in)
+		return srv.(EcommServer).CreateCoupon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_CreateOrder_FullMethodName,
+		FullMethod: Ecomm_CreateCoupon_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).CreateOrder(ctx, req.(*OrderReq))
+		return srv.(EcommServer).CreateCoupon(ctx, req.(*CouponReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecomm_GetCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).GetOrder(ctx, in)
+		return srv.(EcommServer).GetCoupon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_GetOrder_FullMethodName,
+		FullMethod: Ecomm_GetCoupon_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).GetOrder(ctx, req.(*OrderReq))
+		return srv.(EcommServer).GetCoupon(ctx, req.(*CouponReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecomm_ListCoupons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCouponsReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).ListOrders(ctx, in)
+		return srv.(EcommServer).ListCoupons(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_ListOrders_FullMethodName,
+		FullMethod: Ecomm_ListCoupons_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).ListOrders(ctx, req.(*OrderReq))
+		return srv.(EcommServer).ListCoupons(ctx, req.(*ListCouponsReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecomm_UpdateCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).UpdateOrderStatus(ctx, in)
+		return srv.(EcommServer).UpdateCoupon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_UpdateOrderStatus_FullMethodName,
+		FullMethod: Ecomm_UpdateCoupon_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).UpdateOrderStatus(ctx, req.(*OrderReq))
+		return srv.(EcommServer).UpdateCoupon(ctx, req.(*CouponReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Ecomm_DeleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderReq)
+func _Ecomm_DeleteCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(EcommServer).DeleteOrder(ctx, in)
+		return srv.(EcommServer).DeleteCoupon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Ecomm_DeleteOrder_FullMethodName,
+		FullMethod: Ecomm_DeleteCoupon_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).DeleteOrder(ctx, req.(*OrderReq))
+		return srv.(EcommServer).DeleteCoupon(ctx, req.(*CouponReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -623,7 +1967,7 @@ func _Ecomm_GetUser_Handler(srv interface{}, ctx context.Context, dec func(inter
 }
 
 func _Ecomm_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserReq)
+	in := new(ListUsersReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -635,7 +1979,7 @@ func _Ecomm_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(int
 		FullMethod: Ecomm_ListUsers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EcommServer).ListUsers(ctx, req.(*UserReq))
+		return srv.(EcommServer).ListUsers(ctx, req.(*ListUsersReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -676,6 +2020,96 @@ func _Ecomm_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ecomm_CreateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CreateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CreateAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CreateAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_GetAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).GetAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_GetAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).GetAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ListAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ListAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ListAddresses(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_UpdateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).UpdateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_UpdateAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).UpdateAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_DeleteAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).DeleteAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_DeleteAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).DeleteAddress(ctx, req.(*AddressReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ecomm_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SessionReq)
 	if err := dec(in); err != nil {
@@ -748,6 +2182,60 @@ func _Ecomm_DeleteSession_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ecomm_ReserveIdempotencyKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdempotencyKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ReserveIdempotencyKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ReserveIdempotencyKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ReserveIdempotencyKey(ctx, req.(*IdempotencyKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_CompleteIdempotencyKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdempotencyKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).CompleteIdempotencyKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_CompleteIdempotencyKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).CompleteIdempotencyKey(ctx, req.(*IdempotencyKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ecomm_ReleaseIdempotencyKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdempotencyKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EcommServer).ReleaseIdempotencyKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ecomm_ReleaseIdempotencyKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EcommServer).ReleaseIdempotencyKey(ctx, req.(*IdempotencyKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ecomm_ListNotificationEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListNotificationEventsReq)
 	if err := dec(in); err != nil {
@@ -803,6 +2291,10 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListProducts",
 			Handler:    _Ecomm_ListProducts_Handler,
 		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _Ecomm_SearchProducts_Handler,
+		},
 		{
 			MethodName: "UpdateProduct",
 			Handler:    _Ecomm_UpdateProduct_Handler,
@@ -811,6 +2303,66 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProduct",
 			Handler:    _Ecomm_DeleteProduct_Handler,
 		},
+		{
+			MethodName: "CreateVariant",
+			Handler:    _Ecomm_CreateVariant_Handler,
+		},
+		{
+			MethodName: "UpdateVariant",
+			Handler:    _Ecomm_UpdateVariant_Handler,
+		},
+		{
+			MethodName: "DeleteVariant",
+			Handler:    _Ecomm_DeleteVariant_Handler,
+		},
+		{
+			MethodName: "AddProductImage",
+			Handler:    _Ecomm_AddProductImage_Handler,
+		},
+		{
+			MethodName: "DeleteProductImage",
+			Handler:    _Ecomm_DeleteProductImage_Handler,
+		},
+		{
+			MethodName: "ReorderProductImages",
+			Handler:    _Ecomm_ReorderProductImages_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _Ecomm_CreateCategory_Handler,
+		},
+		{
+			MethodName: "GetCategory",
+			Handler:    _Ecomm_GetCategory_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _Ecomm_ListCategories_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _Ecomm_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _Ecomm_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _Ecomm_CreateReview_Handler,
+		},
+		{
+			MethodName: "ListReviews",
+			Handler:    _Ecomm_ListReviews_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _Ecomm_ModerateReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _Ecomm_DeleteReview_Handler,
+		},
 		{
 			MethodName: "CreateOrder",
 			Handler:    _Ecomm_CreateOrder_Handler,
@@ -823,14 +2375,90 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListOrders",
 			Handler:    _Ecomm_ListOrders_Handler,
 		},
+		{
+			MethodName: "ListUserOrders",
+			Handler:    _Ecomm_ListUserOrders_Handler,
+		},
 		{
 			MethodName: "UpdateOrderStatus",
 			Handler:    _Ecomm_UpdateOrderStatus_Handler,
 		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _Ecomm_CancelOrder_Handler,
+		},
 		{
 			MethodName: "DeleteOrder",
 			Handler:    _Ecomm_DeleteOrder_Handler,
 		},
+		{
+			MethodName: "ListOrderStatusHistory",
+			Handler:    _Ecomm_ListOrderStatusHistory_Handler,
+		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _Ecomm_GetInvoice_Handler,
+		},
+		{
+			MethodName: "CreatePayment",
+			Handler:    _Ecomm_CreatePayment_Handler,
+		},
+		{
+			MethodName: "HandlePaymentWebhook",
+			Handler:    _Ecomm_HandlePaymentWebhook_Handler,
+		},
+		{
+			MethodName: "GetCart",
+			Handler:    _Ecomm_GetCart_Handler,
+		},
+		{
+			MethodName: "AddCartItem",
+			Handler:    _Ecomm_AddCartItem_Handler,
+		},
+		{
+			MethodName: "UpdateCartItem",
+			Handler:    _Ecomm_UpdateCartItem_Handler,
+		},
+		{
+			MethodName: "RemoveCartItem",
+			Handler:    _Ecomm_RemoveCartItem_Handler,
+		},
+		{
+			MethodName: "ClearCart",
+			Handler:    _Ecomm_ClearCart_Handler,
+		},
+		{
+			MethodName: "MergeCart",
+			Handler:    _Ecomm_MergeCart_Handler,
+		},
+		{
+			MethodName: "Checkout",
+			Handler:    _Ecomm_Checkout_Handler,
+		},
+		{
+			MethodName: "QuoteShipping",
+			Handler:    _Ecomm_QuoteShipping_Handler,
+		},
+		{
+			MethodName: "CreateCoupon",
+			Handler:    _Ecomm_CreateCoupon_Handler,
+		},
+		{
+			MethodName: "GetCoupon",
+			Handler:    _Ecomm_GetCoupon_Handler,
+		},
+		{
+			MethodName: "ListCoupons",
+			Handler:    _Ecomm_ListCoupons_Handler,
+		},
+		{
+			MethodName: "UpdateCoupon",
+			Handler:    _Ecomm_UpdateCoupon_Handler,
+		},
+		{
+			MethodName: "DeleteCoupon",
+			Handler:    _Ecomm_DeleteCoupon_Handler,
+		},
 		{
 			MethodName: "CreateUser",
 			Handler:    _Ecomm_CreateUser_Handler,
@@ -851,6 +2479,26 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _Ecomm_DeleteUser_Handler,
 		},
+		{
+			MethodName: "CreateAddress",
+			Handler:    _Ecomm_CreateAddress_Handler,
+		},
+		{
+			MethodName: "GetAddress",
+			Handler:    _Ecomm_GetAddress_Handler,
+		},
+		{
+			MethodName: "ListAddresses",
+			Handler:    _Ecomm_ListAddresses_Handler,
+		},
+		{
+			MethodName: "UpdateAddress",
+			Handler:    _Ecomm_UpdateAddress_Handler,
+		},
+		{
+			MethodName: "DeleteAddress",
+			Handler:    _Ecomm_DeleteAddress_Handler,
+		},
 		{
 			MethodName: "CreateSession",
 			Handler:    _Ecomm_CreateSession_Handler,
@@ -867,6 +2515,18 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteSession",
 			Handler:    _Ecomm_DeleteSession_Handler,
 		},
+		{
+			MethodName: "ReserveIdempotencyKey",
+			Handler:    _Ecomm_ReserveIdempotencyKey_Handler,
+		},
+		{
+			MethodName: "CompleteIdempotencyKey",
+			Handler:    _Ecomm_CompleteIdempotencyKey_Handler,
+		},
+		{
+			MethodName: "ReleaseIdempotencyKey",
+			Handler:    _Ecomm_ReleaseIdempotencyKey_Handler,
+		},
 		{
 			MethodName: "ListNotificationEvents",
 			Handler:    _Ecomm_ListNotificationEvents_Handler,
@@ -876,6 +2536,12 @@ var Ecomm_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Ecomm_UpdateNotificationEvent_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportProducts",
+			Handler:       _Ecomm_ImportProducts_Handler,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "api.proto",
 }
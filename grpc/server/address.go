@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateAddress adds an address to the address book of a user. The first
+// address of a user becomes the default one.
+func (s *Server) CreateAddress(ctx context.Context, r *pb.AddressReq) (*pb.AddressRes, error) {
+	a := toStorerAddress(r)
+	err := validateAddress(a)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.IsDefault {
+		addresses, err := s.storer.ListUserAddresses(ctx, a.UserID)
+		if err != nil {
+			return nil, err
+		}
+		a.IsDefault = len(addresses) == 0
+	}
+
+	created, err := s.storer.CreateAddress(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBAddressRes(created), nil
+}
+
+func (s *Server) GetAddress(ctx context.Context, r *pb.AddressReq) (*pb.AddressRes, error) {
+	a, err := s.userAddress(ctx, r.GetId(), r.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBAddressRes(a), nil
+}
+
+// ListAddresses returns the address book of a user, the default address
+// first.
+func (s *Server) ListAddresses(ctx context.Context, r *pb.AddressReq) (*pb.ListAddressesRes, error) {
+	addresses, err := s.storer.ListUserAddresses(ctx, r.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	lar := make([]*pb.AddressRes, 0, len(addresses))
+	for _, a := range addresses {
+		lar = append(lar, toPBAddressRes(a))
+	}
+
+	return &pb.ListAddressesRes{Addresses: lar}, nil
+}
+
+// UpdateAddress edits an address. Orders keep the address they were placed
+// with.
+func (s *Server) UpdateAddress(ctx context.Context, r *pb.AddressReq) (*pb.AddressRes, error) {
+	a, err := s.userAddress(ctx, r.GetId(), r.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	patchAddressReq(a, r)
+	err = validateAddress(a)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storer.UpdateAddress(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBAddressRes(updated), nil
+}
+
+func (s *Server) DeleteAddress(ctx context.Context, r *pb.AddressReq) (*pb.AddressRes, error) {
+	a, err := s.userAddress(ctx, r.GetId(), r.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.DeleteAddress(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AddressRes{}, nil
+}
+
+// userAddress returns the address with the ID from the address book of the
+// user.
+func (s *Server) userAddress(ctx context.Context, id, userID int64) (*storer.Address, error) {
+	a, err := s.storer.GetAddress(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "address %d does not exist", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "address %d does not belong to user %d", id, userID)
+	}
+
+	return a, nil
+}
+
+// shippingAddress returns the copy of the address an order of the user ships
+// to: the address with the ID, or the default address of the user if the ID
+// is zero. It is zero if the user has no default address.
+func (s *Server) shippingAddress(ctx context.Context, userID, addressID int64) (storer.OrderAddress, error) {
+	if addressID != 0 {
+		a, err := s.userAddress(ctx, addressID, userID)
+		if err != nil {
+			return storer.OrderAddress{}, err
+		}
+		return a.OrderAddress(), nil
+	}
+
+	addresses, err := s.storer.ListUserAddresses(ctx, userID)
+	if err != nil {
+		return storer.OrderAddress{}, err
+	}
+	if len(addresses) == 0 || !addresses[0].IsDefault {
+		return storer.OrderAddress{}, nil
+	}
+	return addresses[0].OrderAddress(), nil
+}
+
+// normalizeCountry makes ISO 3166-1 alpha-2 country codes case insensitive.
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// validateAddress checks the address a user adds or edits.
+func validateAddress(a *storer.Address) error {
+	switch {
+	case a.Name == "":
+		return status.Error(codes.InvalidArgument, "address name is required")
+	case a.Line1 == "":
+		return status.Error(codes.InvalidArgument, "address line 1 is required")
+	case a.City == "":
+		return status.Error(codes.InvalidArgument, "address city is required")
+	case len(a.Country) != 2:
+		return status.Errorf(codes.InvalidArgument, "invalid country code %q", a.Country)
+	}
+	return nil
+}
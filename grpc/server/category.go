@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slugify turns a category name into its slug: the lowercase letters and
+// digits of the name, other runs of characters replaced by a hyphen. The
+// migration backfilling categories computes the same slugs.
+func slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// categoryTree indexes every category by ID.
+type categoryTree map[int64]*storer.Category
+
+func (s *Server) categoryTree(ctx context.Context) (categoryTree, error) {
+	categories, err := s.storer.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t := make(categoryTree, len(categories))
+	for _, c := range categories {
+		t[c.ID] = c
+	}
+	return t, nil
+}
+
+// path returns the category and its ancestors, from the category up.
+func (t categoryTree) path(id int64) []int64 {
+	var path []int64
+	c := t[id]
+	for c != nil && len(path) <= len(t) {
+		path = append(path, c.ID)
+		if c.ParentID == nil {
+			break
+		}
+		c = t[*c.ParentID]
+	}
+	return path
+}
+
+// descendants returns the category and every category below it.
+func (t categoryTree) descendants(id int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, c := range t {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []int64{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// productCategories maps products to the path of their category, given the
+// category of every product.
+func (s *Server) productCategories(ctx context.Context, categoryIDs map[int64]*int64) (map[int64][]int64, error) {
+	paths := make(map[int64][]int64, len(categoryIDs))
+	var t categoryTree
+	for productID, categoryID := range categoryIDs {
+		if categoryID == nil {
+			continue
+		}
+		if t == nil {
+			var err error
+			t, err = s.categoryTree(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		paths[productID] = t.path(*categoryID)
+	}
+	return paths, nil
+}
+
+// checkCategory fails with InvalidArgument if the category a product or a
+// coupon references does not exist.
+func (s *Server) checkCategory(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	_, err := s.storer.GetCategory(ctx, *id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.InvalidArgument, "category %d does not exist", *id)
+	}
+	return err
+}
+
+// validateCategory checks the category an admin creates or updates. Parents
+// must exist and a category cannot be moved below itself.
+func (s *Server) validateCategory(ctx context.Context, c *storer.Category) error {
+	switch {
+	case c.Name == "":
+		return status.Error(codes.InvalidArgument, "category name is required")
+	case c.Slug == "" || c.Slug != slugify(c.Slug):
+		return status.Errorf(codes.InvalidArgument, "invalid category slug %q", c.Slug)
+	case c.ParentID == nil:
+		return nil
+	}
+
+	t, err := s.categoryTree(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := t[*c.ParentID]; !ok {
+		return status.Errorf(codes.InvalidArgument, "parent category %d does not exist", *c.ParentID)
+	}
+	for _, id := range t.path(*c.ParentID) {
+		if id == c.ID {
+			return status.Errorf(codes.InvalidArgument, "category %d cannot be moved below itself", c.ID)
+		}
+	}
+	return nil
+}
+
+func categoryError(c *storer.Category, err error) error {
+	if errors.Is(err, storer.ErrDuplicateCategory) {
+		return status.Errorf(codes.AlreadyExists, "category %s already exists", c.Slug)
+	}
+	return err
+}
+
+func (s *Server) CreateCategory(ctx context.Context, c *pb.CategoryReq) (*pb.CategoryRes, error) {
+	category := toStorerCategory(c)
+	err := s.validateCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateCategory(ctx, category)
+	if err != nil {
+		return nil, categoryError(category, err)
+	}
+
+	return toPBCategoryRes(created), nil
+}
+
+// GetCategory returns the category with the ID of the request, or with its
+// slug if it has no ID.
+func (s *Server) GetCategory(ctx context.Context, c *pb.CategoryReq) (*pb.CategoryRes, error) {
+	category, err := s.getCategory(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBCategoryRes(category), nil
+}
+
+func (s *Server) getCategory(ctx context.Context, c *pb.CategoryReq) (*storer.Category, error) {
+	if c.GetId() == 0 {
+		category, err := s.storer.GetCategoryBySlug(ctx, c.GetSlug())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "category %s does not exist", c.GetSlug())
+		}
+		return category, err
+	}
+
+	category, err := s.storer.GetCategory(ctx, c.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "category %d does not exist", c.GetId())
+	}
+	return category, err
+}
+
+func (s *Server) ListCategories(ctx context.Context, _ *pb.ListCategoriesReq) (*pb.ListCategoriesRes, error) {
+	categories, err := s.storer.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lcr := make([]*pb.CategoryRes, 0, len(categories))
+	for _, c := range categories {
+		lcr = append(lcr, toPBCategoryRes(c))
+	}
+
+	return &pb.ListCategoriesRes{Categories: lcr}, nil
+}
+
+func (s *Server) UpdateCategory(ctx context.Context, c *pb.CategoryReq) (*pb.CategoryRes, error) {
+	category, err := s.getCategory(ctx, &pb.CategoryReq{Id: c.GetId()})
+	if err != nil {
+		return nil, err
+	}
+
+	patchCategoryReq(category, c)
+	err = s.validateCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storer.UpdateCategory(ctx, category)
+	if err != nil {
+		return nil, categoryError(category, err)
+	}
+
+	return toPBCategoryRes(updated), nil
+}
+
+// DeleteCategory deletes a category without subcategories, products or
+// coupons.
+func (s *Server) DeleteCategory(ctx context.Context, c *pb.CategoryReq) (*pb.CategoryRes, error) {
+	err := s.storer.DeleteCategory(ctx, c.GetId())
+	if errors.Is(err, storer.ErrCategoryInUse) {
+		return nil, status.Errorf(codes.FailedPrecondition, "category %d has subcategories, products or coupons", c.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CategoryRes{}, nil
+}
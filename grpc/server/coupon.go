@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// normalizeCouponCode makes coupon codes case insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// redeemableCoupon returns the coupon with the code if the order may redeem
+// it. The usage limits are checked again when the order is placed.
+func (s *Server) redeemableCoupon(ctx context.Context, code string, order *storer.Order, categories map[int64][]int64) (*storer.Coupon, error) {
+	c, err := s.storer.GetCouponByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "coupon %s does not exist", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ExpiresAt != nil && !time.Now().Before(*c.ExpiresAt) {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon %s expired", code)
+	}
+	if subtotal := itemsSubtotal(order.Items); subtotal < c.MinOrderValue {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon %s needs an order of at least %s, got %s", code, c.MinOrderValue, subtotal)
+	}
+	if couponDiscount(c, order.Items, categories) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon %s does not apply to any item", code)
+	}
+
+	uses, userUses, err := s.storer.CountCouponUses(ctx, c.ID, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Exhausted(uses, userUses) {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon %s reached its usage limit", code)
+	}
+
+	return c, nil
+}
+
+// couponDiscount is the discount the coupon gives on the items it applies
+// to, categories mapping products to their category and its ancestors.
+func couponDiscount(c *storer.Coupon, items []storer.OrderItem, categories map[int64][]int64) money.Amount {
+	var eligible money.Amount
+	for i, applies := range couponItems(c, items, categories) {
+		if applies {
+			eligible += items[i].Price.Mul(items[i].Quantity)
+		}
+	}
+
+	switch c.Kind {
+	case storer.PercentageCoupon:
+		return eligible.MulBasisPoints(int64(c.Value))
+	case storer.FixedCoupon:
+		return min(c.Value, eligible)
+	default:
+		return 0
+	}
+}
+
+// couponItems reports for every item whether the coupon applies to it.
+func couponItems(c *storer.Coupon, items []storer.OrderItem, categories map[int64][]int64) []bool {
+	applies := make([]bool, len(items))
+	for i, oi := range items {
+		applies[i] = (c.ProductID == nil || oi.ProductID == *c.ProductID) &&
+			(c.CategoryID == nil || slices.Contains(categories[oi.ProductID], *c.CategoryID))
+	}
+	return applies
+}
+
+// validateCoupon checks the coupon an admin creates or updates.
+func validateCoupon(c *storer.Coupon) error {
+	switch {
+	case c.Code == "":
+		return status.Error(codes.InvalidArgument, "coupon code is required")
+	case c.Value <= 0:
+		return status.Errorf(codes.InvalidArgument, "invalid coupon value %s", c.Value)
+	case c.Kind == storer.PercentageCoupon && c.Value > 10000:
+		return status.Errorf(codes.InvalidArgument, "invalid coupon percentage %s", c.Value)
+	case c.MinOrderValue < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid minimum order value %s", c.MinOrderValue)
+	case c.MaxUses < 0 || c.MaxUsesPerUser < 0:
+		return status.Error(codes.InvalidArgument, "usage limits must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// currencyCode normalizes an ISO 4217 code, def if code is empty.
+func currencyCode(code, def string) string {
+	if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+		return code
+	}
+	return def
+}
+
+// exchangeRate returns the rate converting amounts from one currency to
+// another. Currencies the rate provider does not know are invalid arguments.
+func (s *Server) exchangeRate(ctx context.Context, from, to string) (money.Rate, error) {
+	if from == to {
+		return money.RateOne, nil
+	}
+
+	r, err := s.rates.Rate(ctx, from, to)
+	if errors.Is(err, money.ErrUnsupportedCurrency) {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return 0, err
+	}
+	return r, nil
+}
+
+func (s *Server) convert(ctx context.Context, a money.Amount, from, to string) (money.Amount, error) {
+	r, err := s.exchangeRate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return a.Convert(r), nil
+}
+
+// productRes returns the product with its price and the prices of its
+// variants in the display currency, or in the currency of the product if
+// display is empty.
+func (s *Server) productRes(ctx context.Context, p *storer.Product, display string) (*pb.ProductRes, error) {
+	res := toPBProductRes(p)
+	res.Currency = currencyCode(display, p.Currency)
+
+	rate, err := s.exchangeRate(ctx, p.Currency, res.Currency)
+	if err != nil {
+		return nil, err
+	}
+	res.Price = int64(p.Price.Convert(rate))
+	for _, v := range res.Variants {
+		if v.Price != nil {
+			price := int64(money.Amount(v.GetPrice()).Convert(rate))
+			v.Price = &price
+		}
+	}
+
+	return res, nil
+}
+
+// chargeIn converts an order priced in the store currency to the currency
+// its customer pays in and records the exchange rate. The total is summed
+// from the converted amounts, so that the order adds up in either currency,
+// and so is the tax of orders taxed item by item.
+func (s *Server) chargeIn(ctx context.Context, order *storer.Order, currency string) error {
+	rate, err := s.exchangeRate(ctx, money.StoreCurrency, currency)
+	if err != nil {
+		return err
+	}
+
+	var itemsTax money.Amount
+	for i := range order.Items {
+		oi := &order.Items[i]
+		oi.Price = oi.Price.Convert(rate)
+		oi.TaxPrice = oi.TaxPrice.Convert(rate)
+		itemsTax += oi.TaxPrice
+	}
+	subtotal := itemsSubtotal(order.Items)
+	order.DiscountPrice = min(order.DiscountPrice.Convert(rate), subtotal)
+	order.TaxPrice = order.TaxPrice.Convert(rate)
+	if itemsTax != 0 {
+		order.TaxPrice = itemsTax
+	}
+	order.ShippingPrice = order.ShippingPrice.Convert(rate)
+	order.TotalPrice = subtotal - order.DiscountPrice + order.ShippingPrice
+	if !order.TaxInclusive {
+		order.TotalPrice += order.TaxPrice
+	}
+	order.Currency = currency
+	order.ExchangeRate = rate
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/imaging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddProductImage checks an uploaded image, stores it and its thumbnail in
+// the blob store and appends it to the gallery of its product.
+func (s *Server) AddProductImage(ctx context.Context, r *pb.ProductImageReq) (*pb.ProductImageRes, error) {
+	if s.blobs == nil {
+		return nil, status.Error(codes.Unimplemented, "image uploads are not enabled")
+	}
+
+	_, err := s.storer.GetProduct(ctx, r.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "product %d does not exist", r.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := r.GetData()
+	info, err := imaging.Inspect(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image: %v", err)
+	}
+	thumb, thumbType, err := imaging.Thumbnail(data, imaging.ThumbnailSize)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image: %v", err)
+	}
+
+	// random keys never collide with the blobs of deleted images, which
+	// caches may still hold
+	name := fmt.Sprintf("products/%d/%s", r.GetProductId(), strings.ToLower(rand.Text()))
+	img := &storer.ProductImage{
+		ProductID:    r.GetProductId(),
+		ImageKey:     name + imaging.Extension(info.ContentType),
+		ThumbnailKey: name + "_thumb" + imaging.Extension(thumbType),
+		ContentType:  info.ContentType,
+		Size:         int64(len(data)),
+		Width:        int64(info.Width),
+		Height:       int64(info.Height),
+	}
+	img.URL = s.blobs.URL(img.ImageKey)
+	img.ThumbnailURL = s.blobs.URL(img.ThumbnailKey)
+
+	err = s.blobs.Put(ctx, img.ImageKey, data, info.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	err = s.blobs.Put(ctx, img.ThumbnailKey, thumb, thumbType)
+	if err != nil {
+		s.deleteBlobs(ctx, img.ImageKey)
+		return nil, err
+	}
+
+	created, err := s.storer.CreateProductImage(ctx, img)
+	if err != nil {
+		s.deleteBlobs(ctx, img.ImageKey, img.ThumbnailKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "product %d does not exist", r.GetProductId())
+		}
+		return nil, err
+	}
+
+	return toPBProductImageRes(created), nil
+}
+
+// DeleteProductImage removes an image from the gallery of its product, then
+// deletes it and its thumbnail from the blob store.
+func (s *Server) DeleteProductImage(ctx context.Context, r *pb.ProductImageReq) (*pb.ProductImageRes, error) {
+	img, err := s.storer.GetProductImage(ctx, r.GetId())
+	if errors.Is(err, sql.ErrNoRows) || err == nil && img.ProductID != r.GetProductId() {
+		return nil, status.Errorf(codes.NotFound, "product %d has no image %d", r.GetProductId(), r.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.DeleteProductImage(ctx, img.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteBlobs(ctx, img.ImageKey, img.ThumbnailKey)
+
+	return &pb.ProductImageRes{}, nil
+}
+
+// ReorderProductImages orders the gallery of a product. The request lists
+// every image of the product, in its new order.
+func (s *Server) ReorderProductImages(ctx context.Context, r *pb.ReorderProductImagesReq) (*pb.ProductRes, error) {
+	err := s.storer.ReorderProductImages(ctx, r.GetProductId(), r.GetImageIds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "product %d does not exist", r.GetProductId())
+	}
+	if errors.Is(err, storer.ErrImagesMismatch) {
+		return nil, status.Errorf(codes.InvalidArgument, "image_ids must list every image of product %d once", r.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.storer.GetProduct(ctx, r.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.productRes(ctx, p, "")
+}
+
+// deleteBlobs deletes the blobs of images removed from the catalog. The
+// images are gone whether or not it succeeds, so failures are only logged and
+// leave orphaned blobs behind.
+func (s *Server) deleteBlobs(ctx context.Context, keys ...string) {
+	if s.blobs == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		err := s.blobs.Delete(ctx, key)
+		if err != nil {
+			log.Printf("error deleting blob %s: %v", key, err)
+		}
+	}
+}
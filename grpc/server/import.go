@@ -0,0 +1,206 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// importBatchSize is the number of rows ImportProducts saves per
+	// transaction.
+	importBatchSize = 100
+	// maxSKULength is the size of the sku columns.
+	maxSKULength = 64
+)
+
+// productImport is the state of an import across its batches.
+type productImport struct {
+	res *pb.ImportProductsRes
+	// seen maps the skus of the rows imported so far to their row, so that
+	// a file cannot import a product twice
+	seen map[string]int64
+}
+
+func (imp *productImport) fail(r *pb.ImportProductsReq, err error) {
+	imp.res.Errors = append(imp.res.Errors, &pb.ImportRowError{
+		Row:   r.GetRow(),
+		Sku:   strings.TrimSpace(r.GetProduct().GetSku()),
+		Error: status.Convert(err).Message(),
+	})
+}
+
+// ImportProducts creates the products of the rows whose sku no product has
+// and updates the others, in transactions of importBatchSize rows. Invalid
+// rows are reported and skipped, the other rows of their batch are saved.
+func (s *Server) ImportProducts(stream pb.Ecomm_ImportProductsServer) error {
+	ctx := stream.Context()
+	imp := &productImport{res: &pb.ImportProductsRes{}, seen: make(map[string]int64)}
+
+	batch := make([]*pb.ImportProductsReq, 0, importBatchSize)
+	for i := int64(1); ; i++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if i == 1 {
+			imp.res.DryRun = req.GetDryRun()
+		}
+		if req.GetRow() == 0 {
+			req.Row = i
+		}
+
+		batch = append(batch, req)
+		if len(batch) == importBatchSize {
+			err = s.importBatch(ctx, imp, batch)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	err := s.importBatch(ctx, imp, batch)
+	if err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(imp.res.Errors, func(a, b *pb.ImportRowError) int { return cmp.Compare(a.GetRow(), b.GetRow()) })
+	return stream.SendAndClose(imp.res)
+}
+
+// importBatch validates a batch of rows and saves the valid ones in a single
+// transaction, unless the import is a dry run.
+func (s *Server) importBatch(ctx context.Context, imp *productImport, rows []*pb.ImportProductsReq) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	skus := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if sku := strings.TrimSpace(r.GetProduct().GetSku()); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	existing, err := s.storer.GetProductsBySKU(ctx, skus)
+	if err != nil {
+		return err
+	}
+	bySKU := make(map[string]*storer.Product, len(existing))
+	for _, p := range existing {
+		bySKU[*p.SKU] = p
+	}
+
+	var (
+		products         []*storer.Product
+		valid            []*pb.ImportProductsReq
+		created, updated int64
+	)
+	for _, r := range rows {
+		p, err := s.importRow(ctx, imp, bySKU, r)
+		if _, ok := status.FromError(err); !ok {
+			return err
+		}
+		if err != nil {
+			imp.fail(r, err)
+			continue
+		}
+
+		products = append(products, p)
+		valid = append(valid, r)
+		if p.ID == 0 {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	if !imp.res.DryRun && len(products) > 0 {
+		err = s.storer.SaveProducts(ctx, products)
+		if errors.Is(err, storer.ErrDuplicateSKU) {
+			// another import or admin took one of the skus since they were
+			// looked up
+			for _, r := range valid {
+				imp.fail(r, status.Error(codes.Aborted, "a sku of the batch of the row was taken concurrently, the batch was not saved"))
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	imp.res.Created += created
+	imp.res.Updated += updated
+
+	return nil
+}
+
+// importRow returns the product a row creates, or the product with its sku
+// patched by the row.
+func (s *Server) importRow(ctx context.Context, imp *productImport, existing map[string]*storer.Product, r *pb.ImportProductsReq) (*storer.Product, error) {
+	req := r.GetProduct()
+	sku := strings.TrimSpace(req.GetSku())
+	switch {
+	case sku == "":
+		return nil, status.Error(codes.InvalidArgument, "sku is required")
+	case len(sku) > maxSKULength:
+		return nil, status.Errorf(codes.InvalidArgument, "sku is longer than %d characters", maxSKULength)
+	}
+	if row, ok := imp.seen[sku]; ok {
+		return nil, status.Errorf(codes.InvalidArgument, "sku %s is already imported by row %d", sku, row)
+	}
+
+	product, ok := existing[sku]
+	switch {
+	case ok && len(product.Variants) > 0 && req.GetCountInStock() != 0:
+		return nil, status.Errorf(codes.InvalidArgument, "the stock of product %s is the stock of its variants", sku)
+	case ok && len(product.Images) > 0 && req.GetImage() != "":
+		return nil, status.Errorf(codes.InvalidArgument, "the image of product %s is the first image of its gallery", sku)
+	case ok:
+		patchProductReq(product, req)
+	case req.GetName() == "":
+		return nil, status.Error(codes.InvalidArgument, "name is required for new products")
+	default:
+		product = toStorerProduct(req)
+	}
+
+	err := validateProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.exchangeRate(ctx, product.Currency, money.StoreCurrency)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkCategory(ctx, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	imp.seen[sku] = r.GetRow()
+	return product, nil
+}
+
+// validateProduct checks the amounts of an imported product.
+func validateProduct(p *storer.Product) error {
+	switch {
+	case p.Price < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid price %s", p.Price)
+	case p.CountInStock < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid stock %d", p.CountInStock)
+	case p.Weight < 0 || p.Length < 0 || p.Width < 0 || p.Height < 0:
+		return status.Error(codes.InvalidArgument, "weight and dimensions cannot be negative")
+	}
+	return nil
+}
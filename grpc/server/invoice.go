@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newInvoice builds the invoice of an order about to be paid, copying its
+// lines, charges and address, the seller and the owner of the order.
+func (s *Server) newInvoice(ctx context.Context, orderID int64, owner *storer.User) (*storer.Invoice, error) {
+	order, err := s.storer.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &storer.Invoice{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		SellerName:     s.seller.Name,
+		SellerAddress:  s.seller.Address,
+		SellerTaxID:    s.seller.TaxID,
+		BillName:       owner.Name,
+		BillEmail:      owner.Email,
+		OrderAddress:   order.OrderAddress,
+		Currency:       order.Currency,
+		DiscountPrice:  order.DiscountPrice,
+		TaxPrice:       order.TaxPrice,
+		TaxInclusive:   order.TaxInclusive,
+		ShippingPrice:  order.ShippingPrice,
+		ShippingMethod: order.ShippingMethod,
+		TotalPrice:     order.TotalPrice,
+	}
+	if inv.Currency == "" {
+		inv.Currency = money.StoreCurrency
+	}
+	for _, oi := range order.Items {
+		inv.Lines = append(inv.Lines, storer.InvoiceLine{
+			ProductID: oi.ProductID,
+			Name:      oi.Name,
+			Quantity:  oi.Quantity,
+			Price:     oi.Price,
+			TaxRate:   oi.TaxRate,
+			TaxPrice:  oi.TaxPrice,
+		})
+		inv.Subtotal += oi.Price.Mul(oi.Quantity)
+	}
+
+	return inv, nil
+}
+
+// GetInvoice returns the invoice of a paid order to its owner or an admin.
+func (s *Server) GetInvoice(ctx context.Context, o *pb.OrderReq) (*pb.InvoiceRes, error) {
+	inv, err := s.storer.GetOrderInvoice(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d has no invoice", o.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.GetIsAdmin() && o.GetUserId() != inv.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", o.GetId(), o.GetUserId())
+	}
+
+	return toPBInvoiceRes(inv), nil
+}
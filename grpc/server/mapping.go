@@ -1,84 +1,240 @@
 package server
 
 import (
+	"strings"
 	"time"
 
 	"github.com/niloy104/Conduit/grpc/pb"
 	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/shipping"
 	"github.com/niloy104/Conduit/util"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func toStorerProduct(p *pb.ProductReq) *storer.Product {
-	return &storer.Product{
+	product := &storer.Product{
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		TaxCategory:  p.TaxCategory,
 		Description:  p.Description,
-		Rating:       p.Rating,
-		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Price:        money.Amount(p.Price),
+		Currency:     currencyCode(p.Currency, money.StoreCurrency),
 		CountInStock: p.CountInStock,
+		Weight:       p.Weight,
+		Length:       p.Length,
+		Width:        p.Width,
+		Height:       p.Height,
+	}
+	if p.CategoryId != 0 {
+		categoryID := p.CategoryId
+		product.CategoryID = &categoryID
 	}
+	if sku := strings.TrimSpace(p.Sku); sku != "" {
+		product.SKU = &sku
+	}
+
+	return product
 }
 
 func toPBProductRes(p *storer.Product) *pb.ProductRes {
 	res := &pb.ProductRes{
+		Id:           p.ID,
 		Name:         p.Name,
 		Image:        p.Image,
-		Category:     p.Category,
+		TaxCategory:  p.TaxCategory,
 		Description:  p.Description,
 		Rating:       p.Rating,
 		NumReviews:   p.NumReviews,
-		Price:        p.Price,
+		Price:        int64(p.Price),
+		Currency:     p.Currency,
 		CountInStock: p.CountInStock,
+		Weight:       p.Weight,
+		Length:       p.Length,
+		Width:        p.Width,
+		Height:       p.Height,
 		CreatedAt:    timestamppb.New(p.CreatedAt),
 	}
+	if p.CategoryID != nil {
+		res.CategoryId = *p.CategoryID
+	}
+	if p.SKU != nil {
+		res.Sku = *p.SKU
+	}
 	if p.UpdatedAt != nil {
 		res.UpdatedAt = timestamppb.New(*p.UpdatedAt)
 	}
+	for i := range p.Variants {
+		res.Variants = append(res.Variants, toPBVariantRes(&p.Variants[i]))
+	}
+	for i := range p.Images {
+		res.Images = append(res.Images, toPBProductImageRes(&p.Images[i]))
+	}
 
 	return res
 }
 
+// toStorerVariant reads a price of 0 as no price override.
+func toStorerVariant(v *pb.VariantReq) *storer.Variant {
+	variant := &storer.Variant{
+		ProductID:    v.GetProductId(),
+		SKU:          strings.TrimSpace(v.GetSku()),
+		Options:      v.GetOptions(),
+		CountInStock: v.GetCountInStock(),
+	}
+	if v.GetPrice() != 0 {
+		variant.Price = toAmountPtr(v.Price)
+	}
+
+	return variant
+}
+
+func toPBVariantRes(v *storer.Variant) *pb.VariantRes {
+	res := &pb.VariantRes{
+		Id:           v.ID,
+		ProductId:    v.ProductID,
+		Sku:          v.SKU,
+		Options:      v.Options,
+		CountInStock: v.CountInStock,
+		CreatedAt:    timestamppb.New(v.CreatedAt),
+	}
+	if v.Price != nil {
+		price := int64(*v.Price)
+		res.Price = &price
+	}
+	if v.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*v.UpdatedAt)
+	}
+
+	return res
+}
+
+func patchVariantReq(variant *storer.Variant, v *pb.VariantReq) {
+	if sku := strings.TrimSpace(v.GetSku()); sku != "" {
+		variant.SKU = sku
+	}
+	if len(v.GetOptions()) > 0 {
+		variant.Options = v.GetOptions()
+	}
+	if v.Price != nil {
+		variant.Price = nil
+		if v.GetPrice() != 0 {
+			variant.Price = toAmountPtr(v.Price)
+		}
+	}
+	if v.CountInStock != nil {
+		variant.CountInStock = v.GetCountInStock()
+	}
+	variant.UpdatedAt = toTimePtr(time.Now())
+}
+
+func toPBProductImageRes(img *storer.ProductImage) *pb.ProductImageRes {
+	return &pb.ProductImageRes{
+		Id:           img.ID,
+		ProductId:    img.ProductID,
+		Url:          img.URL,
+		ThumbnailUrl: img.ThumbnailURL,
+		ContentType:  img.ContentType,
+		Size:         img.Size,
+		Width:        img.Width,
+		Height:       img.Height,
+		Position:     img.Position,
+		CreatedAt:    timestamppb.New(img.CreatedAt),
+	}
+}
+
 func patchProductReq(product *storer.Product, p *pb.ProductReq) {
 	if p.Name != "" {
 		product.Name = p.Name
 	}
+	if sku := strings.TrimSpace(p.Sku); sku != "" {
+		product.SKU = &sku
+	}
 	if p.Image != "" {
 		product.Image = p.Image
 	}
-	if p.Category != "" {
-		product.Category = p.Category
+	if p.CategoryId != 0 {
+		categoryID := p.CategoryId
+		product.CategoryID = &categoryID
+	}
+	if p.TaxCategory != "" {
+		product.TaxCategory = p.TaxCategory
 	}
 	if p.Description != "" {
 		product.Description = p.Description
 	}
-	if p.Rating != 0 {
-		product.Rating = p.Rating
-	}
-	if p.NumReviews != 0 {
-		product.NumReviews = p.NumReviews
-	}
 	if p.Price != 0 {
-		product.Price = p.Price
+		product.Price = money.Amount(p.Price)
+	}
+	if p.Currency != "" {
+		product.Currency = currencyCode(p.Currency, money.StoreCurrency)
 	}
 	if p.CountInStock != 0 {
 		product.CountInStock = p.CountInStock
 	}
+	if p.Weight != 0 {
+		product.Weight = p.Weight
+	}
+	if p.Length != 0 {
+		product.Length = p.Length
+	}
+	if p.Width != 0 {
+		product.Width = p.Width
+	}
+	if p.Height != 0 {
+		product.Height = p.Height
+	}
 	product.UpdatedAt = toTimePtr(time.Now())
 }
 
+func toStorerReviewStatus(s pb.ReviewStatus) storer.ReviewStatus {
+	return storer.ReviewStatus(strings.ToLower(s.String()))
+}
+
+func toPBReviewStatus(rs storer.ReviewStatus) pb.ReviewStatus {
+	switch rs {
+	case storer.ReviewHidden:
+		return pb.ReviewStatus_HIDDEN
+	default:
+		return pb.ReviewStatus_PUBLISHED
+	}
+}
+
+func toPBReviewRes(r *storer.Review) *pb.ReviewRes {
+	res := &pb.ReviewRes{
+		Id:        r.ID,
+		ProductId: r.ProductID,
+		UserId:    r.UserID,
+		Rating:    r.Rating,
+		Comment:   r.Comment,
+		Status:    toPBReviewStatus(r.Status),
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+	if r.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+
+	return res
+}
+
 func toTimePtr(t time.Time) *time.Time {
 	return &t
 }
 
+func toAmountPtr(v *int64) *money.Amount {
+	if v == nil {
+		return nil
+	}
+	a := money.Amount(*v)
+	return &a
+}
+
 func toStorerOrder(o *pb.OrderReq) *storer.Order {
 	return &storer.Order{
 		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
+		TaxPrice:      money.Amount(o.TaxPrice),
+		ShippingPrice: money.Amount(o.ShippingPrice),
+		TotalPrice:    money.Amount(o.TotalPrice),
 		UserID:        o.UserId,
 		Items:         toStorerOrderItems(o.Items),
 	}
@@ -87,13 +243,18 @@ func toStorerOrder(o *pb.OrderReq) *storer.Order {
 func toStorerOrderItems(items []*pb.OrderItem) []storer.OrderItem {
 	var res []storer.OrderItem
 	for _, i := range items {
-		res = append(res, storer.OrderItem{
+		oi := storer.OrderItem{
 			Name:      i.Name,
 			Quantity:  i.Quantity,
 			Image:     i.Image,
-			Price:     i.Price,
+			Price:     money.Amount(i.Price),
 			ProductID: i.ProductId,
-		})
+		}
+		if i.VariantId != 0 {
+			variantID := i.VariantId
+			oi.VariantID = &variantID
+		}
+		res = append(res, oi)
 	}
 	return res
 }
@@ -102,25 +263,67 @@ func toPBOrderStatus(os storer.OrderStatus) pb.OrderStatus {
 	switch os {
 	case storer.Pending:
 		return pb.OrderStatus_PENDING
+	case storer.Paid:
+		return pb.OrderStatus_PAID
+	case storer.Processing:
+		return pb.OrderStatus_PROCESSING
 	case storer.Shipped:
 		return pb.OrderStatus_SHIPPED
 	case storer.Delivered:
 		return pb.OrderStatus_DELIVERED
+	case storer.Cancelled:
+		return pb.OrderStatus_CANCELLED
+	case storer.Refunded:
+		return pb.OrderStatus_REFUNDED
+	case storer.Returned:
+		return pb.OrderStatus_RETURNED
 	default:
 		return 0
 	}
 }
 
+func toStorerOrderStatus(s pb.OrderStatus) storer.OrderStatus {
+	return storer.OrderStatus(strings.ToLower(s.String()))
+}
+
+func toPBOrderStatusChange(c *storer.OrderStatusChange) *pb.OrderStatusChange {
+	res := &pb.OrderStatusChange{
+		Id:        c.ID,
+		OrderId:   c.OrderID,
+		ToStatus:  toPBOrderStatus(c.ToStatus),
+		ChangedBy: c.ChangedBy,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+	}
+	if c.FromStatus != nil {
+		from := toPBOrderStatus(*c.FromStatus)
+		res.FromStatus = &from
+	}
+
+	return res
+}
+
 func toPBOrderRes(o *storer.Order) *pb.OrderRes {
 	res := &pb.OrderRes{
-		Id:            o.ID,
-		Items:         toPBOrderItems(o.Items),
-		PaymentMethod: o.PaymentMethod,
-		TaxPrice:      o.TaxPrice,
-		ShippingPrice: o.ShippingPrice,
-		TotalPrice:    o.TotalPrice,
-		Status:        toPBOrderStatus(o.Status),
-		CreatedAt:     timestamppb.New(o.CreatedAt),
+		Id:             o.ID,
+		Items:          toPBOrderItems(o.Items),
+		PaymentMethod:  o.PaymentMethod,
+		DiscountPrice:  int64(o.DiscountPrice),
+		TaxPrice:       int64(o.TaxPrice),
+		TaxInclusive:   o.TaxInclusive,
+		ShippingPrice:  int64(o.ShippingPrice),
+		ShippingMethod: o.ShippingMethod,
+		TotalPrice:     int64(o.TotalPrice),
+		Currency:       o.Currency,
+		ExchangeRate:   int64(o.ExchangeRate),
+		UserId:         o.UserID,
+		Status:         toPBOrderStatus(o.Status),
+		CreatedAt:      timestamppb.New(o.CreatedAt),
+	}
+	if o.CouponCode != nil {
+		res.CouponCode = *o.CouponCode
+	}
+	if !o.OrderAddress.IsZero() {
+		res.ShippingAddress = toPBShippingAddress(o.OrderAddress)
 	}
 	if o.UpdatedAt != nil {
 		res.UpdatedAt = timestamppb.New(*o.UpdatedAt)
@@ -129,15 +332,378 @@ func toPBOrderRes(o *storer.Order) *pb.OrderRes {
 	return res
 }
 
+func toPBShippingAddress(oa storer.OrderAddress) *pb.ShippingAddress {
+	return &pb.ShippingAddress{
+		Name:       oa.ShipName,
+		Line1:      oa.ShipLine1,
+		Line2:      oa.ShipLine2,
+		City:       oa.ShipCity,
+		Region:     oa.ShipRegion,
+		PostalCode: oa.ShipPostalCode,
+		Country:    oa.ShipCountry,
+		Phone:      oa.ShipPhone,
+	}
+}
+
 func toPBOrderItems(items []storer.OrderItem) []*pb.OrderItem {
 	var res []*pb.OrderItem
 	for _, i := range items {
-		res = append(res, &pb.OrderItem{
+		oi := &pb.OrderItem{
 			Name:      i.Name,
 			Quantity:  i.Quantity,
 			Image:     i.Image,
-			Price:     i.Price,
+			Price:     int64(i.Price),
+			TaxRate:   i.TaxRate,
+			TaxPrice:  int64(i.TaxPrice),
 			ProductId: i.ProductID,
+		}
+		if i.VariantID != nil {
+			oi.VariantId = *i.VariantID
+		}
+		res = append(res, oi)
+	}
+	return res
+}
+
+func toPBPaymentStatus(ps storer.PaymentStatus) pb.PaymentStatus {
+	switch ps {
+	case storer.PaymentAuthorized:
+		return pb.PaymentStatus_PAYMENT_AUTHORIZED
+	case storer.PaymentCaptured:
+		return pb.PaymentStatus_PAYMENT_CAPTURED
+	case storer.PaymentDeclined:
+		return pb.PaymentStatus_PAYMENT_DECLINED
+	case storer.PaymentRefunded:
+		return pb.PaymentStatus_PAYMENT_REFUNDED
+	default:
+		return pb.PaymentStatus_PAYMENT_ACTION_REQUIRED
+	}
+}
+
+func toPBPaymentRes(p *storer.Payment) *pb.PaymentRes {
+	res := &pb.PaymentRes{
+		Id:            p.ID,
+		OrderId:       p.OrderID,
+		Provider:      p.Provider,
+		Status:        toPBPaymentStatus(p.Status),
+		Amount:        int64(p.Amount),
+		Currency:      p.Currency,
+		DeclineReason: p.DeclineReason,
+		CreatedAt:     timestamppb.New(p.CreatedAt),
+	}
+	if p.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*p.UpdatedAt)
+	}
+
+	return res
+}
+
+func toPBInvoiceRes(inv *storer.Invoice) *pb.InvoiceRes {
+	res := &pb.InvoiceRes{
+		Number:         inv.Number,
+		OrderId:        inv.OrderID,
+		UserId:         inv.UserID,
+		IssuedAt:       timestamppb.New(inv.IssuedAt),
+		SellerName:     inv.SellerName,
+		SellerAddress:  inv.SellerAddress,
+		SellerTaxId:    inv.SellerTaxID,
+		BillName:       inv.BillName,
+		BillEmail:      inv.BillEmail,
+		Currency:       inv.Currency,
+		Subtotal:       int64(inv.Subtotal),
+		DiscountPrice:  int64(inv.DiscountPrice),
+		TaxPrice:       int64(inv.TaxPrice),
+		TaxInclusive:   inv.TaxInclusive,
+		ShippingPrice:  int64(inv.ShippingPrice),
+		ShippingMethod: inv.ShippingMethod,
+		TotalPrice:     int64(inv.TotalPrice),
+	}
+	if !inv.OrderAddress.IsZero() {
+		res.ShippingAddress = toPBShippingAddress(inv.OrderAddress)
+	}
+	for _, l := range inv.Lines {
+		res.Lines = append(res.Lines, &pb.InvoiceLine{
+			ProductId: l.ProductID,
+			Name:      l.Name,
+			Quantity:  l.Quantity,
+			Price:     int64(l.Price),
+			TaxRate:   l.TaxRate,
+			TaxPrice:  int64(l.TaxPrice),
+		})
+	}
+
+	return res
+}
+
+func toStorerCategory(c *pb.CategoryReq) *storer.Category {
+	name := strings.TrimSpace(c.GetName())
+	category := &storer.Category{
+		Name: name,
+		Slug: c.GetSlug(),
+	}
+	if category.Slug == "" {
+		category.Slug = slugify(name)
+	}
+	if c.GetParentId() != 0 {
+		parentID := c.GetParentId()
+		category.ParentID = &parentID
+	}
+
+	return category
+}
+
+func toPBCategoryRes(c *storer.Category) *pb.CategoryRes {
+	res := &pb.CategoryRes{
+		Id:        c.ID,
+		Name:      c.Name,
+		Slug:      c.Slug,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+	}
+	if c.ParentID != nil {
+		res.ParentId = *c.ParentID
+	}
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*c.UpdatedAt)
+	}
+
+	return res
+}
+
+// patchCategoryReq applies the fields set in c. A parent_id of zero makes the
+// category a root.
+func patchCategoryReq(category *storer.Category, c *pb.CategoryReq) {
+	if name := strings.TrimSpace(c.GetName()); name != "" {
+		category.Name = name
+	}
+	if c.GetSlug() != "" {
+		category.Slug = c.GetSlug()
+	}
+	if c.ParentId != nil {
+		category.ParentID = nil
+		if c.GetParentId() != 0 {
+			parentID := c.GetParentId()
+			category.ParentID = &parentID
+		}
+	}
+	category.UpdatedAt = toTimePtr(time.Now())
+}
+
+func toStorerCoupon(c *pb.CouponReq) *storer.Coupon {
+	coupon := &storer.Coupon{
+		Code:           normalizeCouponCode(c.GetCode()),
+		Kind:           toStorerCouponKind(c.GetKind()),
+		Value:          money.Amount(c.GetValue()),
+		MinOrderValue:  money.Amount(c.GetMinOrderValue()),
+		MaxUses:        c.GetMaxUses(),
+		MaxUsesPerUser: c.GetMaxUsesPerUser(),
+	}
+	if c.GetExpiresAt() != nil {
+		coupon.ExpiresAt = toTimePtr(c.GetExpiresAt().AsTime())
+	}
+	if c.GetCategoryId() != 0 {
+		categoryID := c.GetCategoryId()
+		coupon.CategoryID = &categoryID
+	}
+	if c.GetProductId() != 0 {
+		productID := c.GetProductId()
+		coupon.ProductID = &productID
+	}
+
+	return coupon
+}
+
+func toPBCouponRes(c *storer.Coupon) *pb.CouponRes {
+	res := &pb.CouponRes{
+		Id:             c.ID,
+		Code:           c.Code,
+		Kind:           toPBCouponKind(c.Kind),
+		Value:          int64(c.Value),
+		MinOrderValue:  int64(c.MinOrderValue),
+		Currency:       money.StoreCurrency,
+		MaxUses:        c.MaxUses,
+		MaxUsesPerUser: c.MaxUsesPerUser,
+		CreatedAt:      timestamppb.New(c.CreatedAt),
+	}
+	if c.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*c.ExpiresAt)
+	}
+	if c.CategoryID != nil {
+		res.CategoryId = *c.CategoryID
+	}
+	if c.ProductID != nil {
+		res.ProductId = *c.ProductID
+	}
+	if c.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*c.UpdatedAt)
+	}
+
+	return res
+}
+
+func patchCouponReq(coupon *storer.Coupon, c *pb.CouponReq) {
+	if code := normalizeCouponCode(c.GetCode()); code != "" {
+		coupon.Code = code
+	}
+	if c.Kind != nil {
+		coupon.Kind = toStorerCouponKind(c.GetKind())
+	}
+	if c.GetValue() != 0 {
+		coupon.Value = money.Amount(c.GetValue())
+	}
+	if c.GetMinOrderValue() != 0 {
+		coupon.MinOrderValue = money.Amount(c.GetMinOrderValue())
+	}
+	if c.GetExpiresAt() != nil {
+		coupon.ExpiresAt = toTimePtr(c.GetExpiresAt().AsTime())
+	}
+	if c.GetMaxUses() != 0 {
+		coupon.MaxUses = c.GetMaxUses()
+	}
+	if c.GetMaxUsesPerUser() != 0 {
+		coupon.MaxUsesPerUser = c.GetMaxUsesPerUser()
+	}
+	if c.GetCategoryId() != 0 {
+		categoryID := c.GetCategoryId()
+		coupon.CategoryID = &categoryID
+	}
+	if c.GetProductId() != 0 {
+		productID := c.GetProductId()
+		coupon.ProductID = &productID
+	}
+	coupon.UpdatedAt = toTimePtr(time.Now())
+}
+
+func toPBShippingOption(o *shipping.Option) *pb.ShippingOption {
+	return &pb.ShippingOption{
+		MethodId: o.MethodID,
+		Name:     o.Name,
+		MinDays:  int32(o.MinDays),
+		MaxDays:  int32(o.MaxDays),
+		Price:    int64(o.Price),
+	}
+}
+
+func toStorerAddress(a *pb.AddressReq) *storer.Address {
+	return &storer.Address{
+		UserID:     a.GetUserId(),
+		Name:       a.GetName(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+		City:       a.GetCity(),
+		Region:     a.GetRegion(),
+		PostalCode: a.GetPostalCode(),
+		Country:    normalizeCountry(a.GetCountry()),
+		Phone:      a.GetPhone(),
+		IsDefault:  a.GetIsDefault(),
+	}
+}
+
+func toPBAddressRes(a *storer.Address) *pb.AddressRes {
+	res := &pb.AddressRes{
+		Id:         a.ID,
+		UserId:     a.UserID,
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+		IsDefault:  a.IsDefault,
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+	}
+	if a.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*a.UpdatedAt)
+	}
+
+	return res
+}
+
+func patchAddressReq(address *storer.Address, a *pb.AddressReq) {
+	if a.GetName() != "" {
+		address.Name = a.GetName()
+	}
+	if a.GetLine1() != "" {
+		address.Line1 = a.GetLine1()
+	}
+	if a.GetLine2() != "" {
+		address.Line2 = a.GetLine2()
+	}
+	if a.GetCity() != "" {
+		address.City = a.GetCity()
+	}
+	if a.GetRegion() != "" {
+		address.Region = a.GetRegion()
+	}
+	if a.GetPostalCode() != "" {
+		address.PostalCode = a.GetPostalCode()
+	}
+	if country := normalizeCountry(a.GetCountry()); country != "" {
+		address.Country = country
+	}
+	if a.GetPhone() != "" {
+		address.Phone = a.GetPhone()
+	}
+	if a.IsDefault != nil {
+		address.IsDefault = a.GetIsDefault()
+	}
+	address.UpdatedAt = toTimePtr(time.Now())
+}
+
+func toStorerCouponKind(k pb.CouponKind) storer.CouponKind {
+	return storer.CouponKind(strings.ToLower(k.String()))
+}
+
+func toPBCouponKind(k storer.CouponKind) pb.CouponKind {
+	switch k {
+	case storer.FixedCoupon:
+		return pb.CouponKind_FIXED
+	default:
+		return pb.CouponKind_PERCENTAGE
+	}
+}
+
+func toPBCartItems(items []storer.CartItem) []*pb.CartItem {
+	res := make([]*pb.CartItem, 0, len(items))
+	for _, ci := range items {
+		item := &pb.CartItem{
+			ProductId:    ci.ProductID,
+			Name:         ci.Name,
+			Image:        ci.Image,
+			Price:        int64(ci.Price),
+			Quantity:     ci.Quantity,
+			CountInStock: ci.CountInStock,
+			Options:      ci.Options,
+		}
+		if ci.VariantID != nil {
+			item.VariantId = *ci.VariantID
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
+// cartItemVariantID returns the variant a cart item request names, nil for
+// products without variants.
+func cartItemVariantID(ci *pb.CartItemReq) *int64 {
+	if ci.GetVariantId() == 0 {
+		return nil
+	}
+	variantID := ci.GetVariantId()
+	return &variantID
+}
+
+func toStorerCartOrderItems(items []storer.CartItem) []storer.OrderItem {
+	res := make([]storer.OrderItem, 0, len(items))
+	for _, ci := range items {
+		res = append(res, storer.OrderItem{
+			Name:      ci.Name,
+			Quantity:  ci.Quantity,
+			Image:     ci.Image,
+			Price:     ci.Price,
+			ProductID: ci.ProductID,
+			VariantID: ci.VariantID,
 		})
 	}
 	return res
@@ -0,0 +1,26 @@
+package server
+
+import "github.com/niloy104/Conduit/grpc/storer"
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Cancelled and returned orders put their items back in stock, and so do
+// orders refunded before they ship, see storer.OrderStatusChange.
+var orderTransitions = map[storer.OrderStatus][]storer.OrderStatus{
+	storer.Pending:    {storer.Paid, storer.Processing, storer.Cancelled},
+	storer.Paid:       {storer.Processing, storer.Cancelled, storer.Refunded},
+	storer.Processing: {storer.Shipped, storer.Cancelled},
+	storer.Shipped:    {storer.Delivered, storer.Returned},
+	storer.Delivered:  {storer.Returned},
+	storer.Returned:   {storer.Refunded},
+	storer.Cancelled:  {storer.Refunded},
+	storer.Refunded:   {},
+}
+
+func canTransition(from, to storer.OrderStatus) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
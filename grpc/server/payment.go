@@ -0,0 +1,259 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/payment"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// systemUserID is recorded as the author of the status changes the store
+// makes on its own, such as a captured payment marking its order paid.
+const systemUserID = 0
+
+// paymentTransitions lists the statuses a payment may move to from each
+// status, as reported by the payment gateway.
+var paymentTransitions = map[storer.PaymentStatus][]storer.PaymentStatus{
+	storer.PaymentActionRequired: {storer.PaymentAuthorized, storer.PaymentCaptured, storer.PaymentDeclined},
+	storer.PaymentAuthorized:     {storer.PaymentCaptured, storer.PaymentDeclined},
+	storer.PaymentCaptured:       {storer.PaymentRefunded},
+	storer.PaymentDeclined:       {},
+	storer.PaymentRefunded:       {},
+}
+
+// paymentLeadsTo reports whether a payment in status from may end up in
+// status to, or already is in it.
+func paymentLeadsTo(from, to storer.PaymentStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range paymentTransitions[from] {
+		if paymentLeadsTo(next, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePayment starts paying a pending order through the payment gateway.
+// Authorized payments are captured at once, which marks the order paid.
+// Payments requiring action from the customer are completed by a webhook,
+// see HandlePaymentWebhook.
+func (s *Server) CreatePayment(ctx context.Context, r *pb.PaymentReq) (*pb.PaymentRes, error) {
+	if s.gateway == nil {
+		return nil, status.Error(codes.Unimplemented, "payments are not enabled")
+	}
+
+	order, err := s.storer.GetOrder(ctx, r.GetOrderId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d does not exist", r.GetOrderId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !r.GetIsAdmin() && r.GetUserId() != order.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", order.ID, r.GetUserId())
+	}
+	if order.Status != storer.Pending {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d is %s and cannot be paid", order.ID, order.Status)
+	}
+
+	payments, err := s.storer.ListOrderPayments(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if p.Status == storer.PaymentAuthorized || p.Status == storer.PaymentCaptured {
+			return nil, status.Errorf(codes.FailedPrecondition, "order %d already has an %s payment", order.ID, p.Status)
+		}
+	}
+
+	res, err := s.gateway.Authorize(ctx, &payment.AuthorizeReq{
+		OrderID:  order.ID,
+		Amount:   order.TotalPrice,
+		Currency: order.Currency,
+		Method:   cmp.Or(r.GetMethod(), order.PaymentMethod),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error authorizing payment: %v", err)
+	}
+
+	p, err := s.storer.CreatePayment(ctx, &storer.Payment{
+		OrderID:       order.ID,
+		Provider:      s.gateway.Name(),
+		ProviderRef:   res.Ref,
+		Amount:        order.TotalPrice,
+		Currency:      order.Currency,
+		Status:        storer.PaymentStatus(res.Status),
+		DeclineReason: res.DeclineReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p, err = s.advancePayment(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := toPBPaymentRes(p)
+	pr.NextActionUrl = res.NextActionURL
+	return pr, nil
+}
+
+// HandlePaymentWebhook applies a change of status notified by the payment
+// gateway. Redelivered webhooks, and webhooks the payment has moved past, are
+// acknowledged without effect.
+func (s *Server) HandlePaymentWebhook(ctx context.Context, r *pb.PaymentWebhookReq) (*pb.PaymentRes, error) {
+	if s.gateway == nil {
+		return nil, status.Error(codes.Unimplemented, "payments are not enabled")
+	}
+
+	ev, err := s.gateway.VerifyWebhook(r.GetPayload(), r.GetSignature())
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	p, err := s.storer.GetPaymentByRef(ctx, s.gateway.Name(), ev.Ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "payment %s does not exist", ev.Ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if paymentLeadsTo(storer.PaymentStatus(ev.Status), p.Status) {
+		return toPBPaymentRes(p), nil
+	}
+
+	err = s.setPaymentStatus(ctx, p, storer.PaymentStatus(ev.Status), ev.DeclineReason)
+	if err != nil {
+		return nil, err
+	}
+	p, err = s.advancePayment(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBPaymentRes(p), nil
+}
+
+// setPaymentStatus validates the move of p to status against the payment
+// lifecycle and persists it.
+func (s *Server) setPaymentStatus(ctx context.Context, p *storer.Payment, to storer.PaymentStatus, declineReason string) error {
+	if !slices.Contains(paymentTransitions[p.Status], to) {
+		return status.Errorf(codes.FailedPrecondition, "payment status cannot change from %s to %s", p.Status, to)
+	}
+
+	from := p.Status
+	p.Status = to
+	p.DeclineReason = declineReason
+	_, err := s.storer.UpdatePaymentStatus(ctx, p, from)
+	if errors.Is(err, storer.ErrPaymentStatusConflict) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return err
+}
+
+// advancePayment acts on a payment that just reached its status: authorized
+// payments are captured, captured payments pay their order and refunded
+// payments refund it.
+func (s *Server) advancePayment(ctx context.Context, p *storer.Payment) (*storer.Payment, error) {
+	switch p.Status {
+	case storer.PaymentAuthorized:
+		res, err := s.gateway.Capture(ctx, p.ProviderRef, p.Amount)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "error capturing payment: %v", err)
+		}
+		err = s.setPaymentStatus(ctx, p, storer.PaymentStatus(res.Status), res.DeclineReason)
+		if err != nil {
+			return nil, err
+		}
+		return s.advancePayment(ctx, p)
+	case storer.PaymentCaptured:
+		return p, s.settlePayment(ctx, p)
+	case storer.PaymentRefunded:
+		order, err := s.storer.GetOrderStatusByID(ctx, p.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if canTransition(order.Status, storer.Refunded) {
+			_, err = s.transitionOrder(ctx, order, storer.Refunded, systemUserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// settlePayment marks the order of a captured payment paid. The payment is
+// refunded instead if its order was cancelled while the payment was pending,
+// or was already paid by another payment.
+func (s *Server) settlePayment(ctx context.Context, p *storer.Payment) error {
+	order, err := s.storer.GetOrderStatusByID(ctx, p.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status == storer.Pending {
+		_, err = s.transitionOrder(ctx, order, storer.Paid, systemUserID)
+		return err
+	}
+
+	if order.Status != storer.Cancelled {
+		payments, err := s.storer.ListOrderPayments(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		paidByOther := slices.ContainsFunc(payments, func(op *storer.Payment) bool {
+			return op.ID != p.ID && op.Status == storer.PaymentCaptured
+		})
+		if !paidByOther {
+			return nil
+		}
+	}
+
+	return s.refundPayment(ctx, p)
+}
+
+func (s *Server) refundPayment(ctx context.Context, p *storer.Payment) error {
+	res, err := s.gateway.Refund(ctx, p.ProviderRef, p.Amount)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "error refunding payment: %v", err)
+	}
+	return s.setPaymentStatus(ctx, p, storer.PaymentStatus(res.Status), res.DeclineReason)
+}
+
+// refundOrder refunds the captured payments of an order through the payment
+// gateway.
+func (s *Server) refundOrder(ctx context.Context, orderID int64) error {
+	if s.gateway == nil {
+		return nil
+	}
+
+	payments, err := s.storer.ListOrderPayments(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, p := range payments {
+		if p.Status != storer.PaymentCaptured || p.Provider != s.gateway.Name() {
+			continue
+		}
+		err = s.refundPayment(ctx, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
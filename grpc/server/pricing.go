@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+)
+
+// PricingPolicy computes the charges of an order whose items already carry
+// catalog prices, once discount is taken off the items.
+type PricingPolicy interface {
+	Price(ctx context.Context, items []storer.OrderItem, discount money.Amount) (*Quote, error)
+}
+
+type Quote struct {
+	Subtotal money.Amount
+	Discount money.Amount
+	Tax      money.Amount
+	Shipping money.Amount
+	Total    money.Amount
+}
+
+// FlatPricingPolicy charges a fixed tax rate, in basis points, on the
+// discounted subtotal and a flat shipping price, waived once the discounted
+// subtotal reaches FreeShippingOver.
+type FlatPricingPolicy struct {
+	TaxRate          int64
+	ShippingPrice    money.Amount
+	FreeShippingOver money.Amount
+}
+
+func (fp *FlatPricingPolicy) Price(ctx context.Context, items []storer.OrderItem, discount money.Amount) (*Quote, error) {
+	subtotal := itemsSubtotal(items)
+	off := min(discount, subtotal)
+	discounted := subtotal - off
+
+	tax := discounted.MulBasisPoints(fp.TaxRate)
+	shipping := fp.ShippingPrice
+	if fp.FreeShippingOver > 0 && discounted >= fp.FreeShippingOver {
+		shipping = 0
+	}
+
+	return &Quote{
+		Subtotal: subtotal,
+		Discount: off,
+		Tax:      tax,
+		Shipping: shipping,
+		Total:    discounted + tax + shipping,
+	}, nil
+}
+
+func itemsSubtotal(items []storer.OrderItem) money.Amount {
+	var subtotal money.Amount
+	for _, oi := range items {
+		subtotal += oi.Price.Mul(oi.Quantity)
+	}
+	return subtotal
+}
@@ -2,87 +2,458 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/niloy104/Conduit/blobstore"
 	"github.com/niloy104/Conduit/grpc/pb"
 	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/invoice"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/payment"
+	"github.com/niloy104/Conduit/shipping"
+	"github.com/niloy104/Conduit/tax"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type Server struct {
-	storer *storer.MySQLStorer
+	storer   storer.Storer
+	pricing  PricingPolicy
+	rates    money.RateProvider
+	gateway  payment.Gateway
+	shipping shipping.Methods
+	tax      *tax.Table
+	seller   invoice.Seller
+	blobs    blobstore.Store
 	pb.UnimplementedEcommServer
 }
 
-func NewServer(storer *storer.MySQLStorer) *Server {
-	return &Server{
-		storer: storer,
+type Option func(*Server)
+
+func WithPricingPolicy(p PricingPolicy) Option {
+	return func(s *Server) {
+		s.pricing = p
+	}
+}
+
+// WithRateProvider sets the source of the exchange rates prices are
+// converted at. Without one, only the store currency is supported.
+func WithRateProvider(rp money.RateProvider) Option {
+	return func(s *Server) {
+		s.rates = rp
+	}
+}
+
+// WithPaymentGateway sets the payment service provider orders are paid
+// through. Without one, payments are disabled.
+func WithPaymentGateway(g payment.Gateway) Option {
+	return func(s *Server) {
+		s.gateway = g
+	}
+}
+
+// WithShippingMethods sets the shipping methods customers choose from, which
+// price shipping instead of the pricing policy. Without them, the pricing
+// policy prices shipping and orders need no shipping address.
+func WithShippingMethods(ms shipping.Methods) Option {
+	return func(s *Server) {
+		s.shipping = ms
 	}
 }
 
+// WithTaxTable sets the tax rates orders are taxed at by the region they ship
+// to, instead of the tax of the pricing policy.
+func WithTaxTable(t *tax.Table) Option {
+	return func(s *Server) {
+		s.tax = t
+	}
+}
+
+// WithInvoiceSeller sets the seller named on the invoices issued for paid
+// orders.
+func WithInvoiceSeller(seller invoice.Seller) Option {
+	return func(s *Server) {
+		s.seller = seller
+	}
+}
+
+// WithBlobStore sets the store the product images are uploaded to. Without
+// one, image uploads are disabled.
+func WithBlobStore(bs blobstore.Store) Option {
+	return func(s *Server) {
+		s.blobs = bs
+	}
+}
+
+func NewServer(storer storer.Storer, opts ...Option) *Server {
+	s := &Server{
+		storer:  storer,
+		pricing: &FlatPricingPolicy{},
+		rates:   money.NewStaticRates(money.StoreCurrency, nil),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
 // /-----///
 func (s *Server) CreateProduct(ctx context.Context, req *pb.ProductReq) (*pb.ProductRes, error) {
-	pr, err := s.storer.CreateProduct(ctx, toStorerProduct(req))
+	product := toStorerProduct(req)
+	// prices must be convertible to the store currency to be ordered
+	_, err := s.exchangeRate(ctx, product.Currency, money.StoreCurrency)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkCategory(ctx, product.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
+	pr, err := s.storer.CreateProduct(ctx, product)
+	if err != nil {
+		return nil, productError(product, err)
+	}
+
 	return toPBProductRes(pr), nil
 }
 
+// GetProduct returns a product, its price converted to the display currency
+// of the request if any.
 func (s *Server) GetProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
 	pr, err := s.storer.GetProduct(ctx, p.GetId())
 	if err != nil {
 		return nil, err
 	}
 
-	return toPBProductRes(pr), nil
+	return s.productRes(ctx, pr, p.GetDisplayCurrency())
 }
 
-func (s *Server) ListProducts(ctx context.Context, p *pb.ProductReq) (*pb.ListProductRes, error) {
-	lps, err := s.storer.ListProducts(ctx)
+func (s *Server) ListProducts(ctx context.Context, p *pb.ListProductsReq) (*pb.ListProductRes, error) {
+	size, err := pageSize(p.GetPageSize())
 	if err != nil {
 		return nil, err
 	}
 
+	f := &storer.ProductFilter{
+		InStock:   p.GetInStock(),
+		Sort:      p.GetSortBy(),
+		PageSize:  size,
+		PageToken: p.GetPageToken(),
+	}
+	f.Prices, err = s.priceRanges(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.GetCategory() != "" {
+		c, err := s.getCategory(ctx, &pb.CategoryReq{Slug: p.GetCategory()})
+		if err != nil {
+			return nil, err
+		}
+		t, err := s.categoryTree(ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.CategoryIDs = t.descendants(c.ID)
+	}
+	lps, next, err := s.storer.ListProducts(ctx, f)
+	if err != nil {
+		return nil, listError(err)
+	}
+
 	lpr := make([]*pb.ProductRes, 0, len(lps))
 	for _, lp := range lps {
-		lpr = append(lpr, toPBProductRes(lp))
+		pr, err := s.productRes(ctx, lp, p.GetDisplayCurrency())
+		if err != nil {
+			return nil, err
+		}
+		lpr = append(lpr, pr)
 	}
 
 	return &pb.ListProductRes{
-		Products: lpr,
+		Products:      lpr,
+		NextPageToken: next,
 	}, nil
 }
 
+// priceRanges converts the price bounds of a product listing into a range for
+// every currency of the catalog, so that products are filtered by the prices
+// they are shown with. The bounds are in the display currency, or else in the
+// currency of the catalog, the store currency if it has several. Prices in
+// different currencies cannot be compared as stored, so the price sort needs
+// a catalog in a single currency.
+func (s *Server) priceRanges(ctx context.Context, p *pb.ListProductsReq) ([]storer.PriceRange, error) {
+	sortByPrice := strings.TrimPrefix(p.GetSortBy(), "-") == "price"
+	if p.MinPrice == nil && p.MaxPrice == nil && !sortByPrice {
+		return nil, nil
+	}
+
+	currencies, err := s.storer.ListProductCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sortByPrice && len(currencies) > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot sort by price: products are priced in %s", strings.Join(currencies, ", "))
+	}
+	if p.MinPrice == nil && p.MaxPrice == nil {
+		return nil, nil
+	}
+
+	from := money.StoreCurrency
+	if len(currencies) == 1 {
+		from = currencies[0]
+	}
+	from = currencyCode(p.GetDisplayCurrency(), from)
+	ranges := make([]storer.PriceRange, 0, len(currencies))
+	for _, c := range currencies {
+		rate, err := s.exchangeRate(ctx, from, c)
+		if err != nil {
+			return nil, err
+		}
+		convert := func(bound *int64) *money.Amount {
+			if bound == nil {
+				return nil
+			}
+			a := money.Amount(*bound).Convert(rate)
+			return &a
+		}
+		ranges = append(ranges, storer.PriceRange{Currency: c, Min: convert(p.MinPrice), Max: convert(p.MaxPrice)})
+	}
+
+	return ranges, nil
+}
+
+// SearchProducts returns the products matching a full-text query, most
+// relevant first.
+func (s *Server) SearchProducts(ctx context.Context, p *pb.SearchProductsReq) (*pb.SearchProductsRes, error) {
+	query := strings.TrimSpace(p.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "search query must not be empty")
+	}
+
+	size, err := pageSize(p.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	matches, next, err := s.storer.SearchProducts(ctx, &storer.ProductSearch{
+		Query:     query,
+		PageSize:  size,
+		PageToken: p.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	res := &pb.SearchProductsRes{
+		Matches:       make([]*pb.ProductMatch, 0, len(matches)),
+		NextPageToken: next,
+	}
+	for _, m := range matches {
+		pr, err := s.productRes(ctx, &m.Product, p.GetDisplayCurrency())
+		if err != nil {
+			return nil, err
+		}
+		res.Matches = append(res.Matches, &pb.ProductMatch{
+			Product: pr,
+			Score:   m.Score,
+			Snippet: m.Snippet,
+		})
+	}
+
+	return res, nil
+}
+
 func (s *Server) UpdateProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
 	product, err := s.storer.GetProduct(ctx, p.GetId())
 	if err != nil {
 		return nil, err
 	}
 
+	if len(product.Variants) > 0 && p.GetCountInStock() != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "the stock of product %d is the stock of its variants", p.GetId())
+	}
+	if len(product.Images) > 0 && p.GetImage() != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "the image of product %d is the first image of its gallery", p.GetId())
+	}
+
 	patchProductReq(product, p)
-	pr, err := s.storer.UpdateProduct(ctx, product)
+	_, err = s.exchangeRate(ctx, product.Currency, money.StoreCurrency)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkCategory(ctx, product.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
+	pr, err := s.storer.UpdateProduct(ctx, product)
+	if err != nil {
+		return nil, productError(product, err)
+	}
+
 	return toPBProductRes(pr), nil
 }
 
+func productError(p *storer.Product, err error) error {
+	if errors.Is(err, storer.ErrDuplicateSKU) {
+		return status.Errorf(codes.AlreadyExists, "sku %s already exists", *p.SKU)
+	}
+	return err
+}
+
+// DeleteProduct deletes a product, then the blobs of its images.
 func (s *Server) DeleteProduct(ctx context.Context, p *pb.ProductReq) (*pb.ProductRes, error) {
-	err := s.storer.DeleteProduct(ctx, p.GetId())
+	product, err := s.storer.GetProduct(ctx, p.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return &pb.ProductRes{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.storer.DeleteProduct(ctx, p.GetId())
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range product.Images {
+		s.deleteBlobs(ctx, img.ImageKey, img.ThumbnailKey)
+	}
+
 	return &pb.ProductRes{}, nil
 }
 
+// CreateReview lets a customer rate a product they received, once.
+func (s *Server) CreateReview(ctx context.Context, r *pb.ReviewReq) (*pb.ReviewRes, error) {
+	if r.GetRating() < 1 || r.GetRating() > 5 {
+		return nil, status.Errorf(codes.InvalidArgument, "rating must be between 1 and 5, got %d", r.GetRating())
+	}
+
+	_, err := s.storer.GetProduct(ctx, r.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "product %d does not exist", r.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	delivered, err := s.storer.HasDeliveredProduct(ctx, r.GetUserId(), r.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, status.Errorf(codes.PermissionDenied, "user %d has no delivered order of product %d", r.GetUserId(), r.GetProductId())
+	}
+
+	review, err := s.storer.CreateReview(ctx, &storer.Review{
+		ProductID: r.GetProductId(),
+		UserID:    r.GetUserId(),
+		Rating:    r.GetRating(),
+		Comment:   strings.TrimSpace(r.GetComment()),
+		Status:    storer.ReviewPublished,
+	})
+	if errors.Is(err, storer.ErrDuplicateReview) {
+		return nil, status.Errorf(codes.AlreadyExists, "user %d already reviewed product %d", r.GetUserId(), r.GetProductId())
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "product %d does not exist", r.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBReviewRes(review), nil
+}
+
+func (s *Server) ListReviews(ctx context.Context, r *pb.ListReviewsReq) (*pb.ListReviewsRes, error) {
+	size, err := pageSize(r.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	f := &storer.ReviewFilter{
+		ProductID: r.GetProductId(),
+		Sort:      r.GetSortBy(),
+		PageSize:  size,
+		PageToken: r.GetPageToken(),
+	}
+	if r.Status != nil {
+		rs := toStorerReviewStatus(r.GetStatus())
+		f.Status = &rs
+	}
+
+	reviews, next, err := s.storer.ListReviews(ctx, f)
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	lrr := make([]*pb.ReviewRes, 0, len(reviews))
+	for _, review := range reviews {
+		lrr = append(lrr, toPBReviewRes(review))
+	}
+
+	return &pb.ListReviewsRes{
+		Reviews:       lrr,
+		NextPageToken: next,
+	}, nil
+}
+
+// ModerateReview publishes or hides a review. Hidden reviews no longer count
+// towards the rating of the product.
+func (s *Server) ModerateReview(ctx context.Context, r *pb.ReviewReq) (*pb.ReviewRes, error) {
+	review, err := s.storer.UpdateReviewStatus(ctx, r.GetId(), toStorerReviewStatus(r.GetStatus()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "review %d does not exist", r.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBReviewRes(review), nil
+}
+
+func (s *Server) DeleteReview(ctx context.Context, r *pb.ReviewReq) (*pb.ReviewRes, error) {
+	err := s.storer.DeleteReview(ctx, r.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "review %d does not exist", r.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReviewRes{}, nil
+}
+
 func (s *Server) CreateOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	order, err := s.storer.CreateOrder(ctx, toStorerOrder(o))
+	po, err := s.priceOrder(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.placeOrder(ctx, po, o.GetUserEmail(), s.storer.CreateOrder)
+}
+
+// placeOrder stores a priced order with create and notifies its user.
+func (s *Server) placeOrder(ctx context.Context, po *storer.Order, userEmail string, create func(context.Context, *storer.Order) (*storer.Order, error)) (*pb.OrderRes, error) {
+	order, err := create(ctx, po)
+	if errors.Is(err, storer.ErrInsufficientStock) || errors.Is(err, storer.ErrCouponExhausted) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, storer.ErrCartChanged) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +461,7 @@ func (s *Server) CreateOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes,
 	order.Status = storer.Pending
 	//enqueue notification event
 	_, err = s.storer.EnqueueNotificationEvent(ctx, &storer.NotificationEvent{
-		UserEmail:   o.GetUserEmail(),
+		UserEmail:   userEmail,
 		OrderStatus: order.Status,
 		OrderID:     order.ID,
 		Attempts:    0,
@@ -102,57 +473,367 @@ func (s *Server) CreateOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes,
 	return toPBOrderRes(order), nil
 }
 
+// priceOrder builds the order from the catalog: the name, image and price of
+// every item come from the product or the variant it names. The order ships
+// to a copy of the address it names from the address book of its user, or of
+// the default address, with the shipping method it names or the cheapest one.
+// Its charges come from the pricing policy, with shipping priced by that
+// method and tax by the region the order ships to. The order is priced in the
+// store currency, then converted to the currency it is charged in, and the
+// prices sent by the client are only checked against the converted ones.
+func (s *Server) priceOrder(ctx context.Context, o *pb.OrderReq) (*storer.Order, error) {
+	if len(o.GetItems()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order has no items")
+	}
+	currency := currencyCode(o.GetCurrency(), money.StoreCurrency)
+
+	order := toStorerOrder(o)
+	quantities := make(map[int64]int64)
+	variantQuantities := make(map[int64]int64)
+	categoryIDs := make(map[int64]*int64)
+	taxCategories := make(map[int64]string)
+	parcel := &shipping.Parcel{}
+	for i := range order.Items {
+		oi := &order.Items[i]
+		if oi.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid quantity %d for product %d", oi.Quantity, oi.ProductID)
+		}
+
+		p, err := s.storer.GetProduct(ctx, oi.ProductID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "product %d does not exist", oi.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := orderedVariant(p, oi.VariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantities[p.ID] += oi.Quantity
+		if quantities[p.ID] > p.CountInStock {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %d has only %d items in stock", p.ID, p.CountInStock)
+		}
+
+		oi.Name = p.Name
+		oi.Image = p.Image
+		price := p.Price
+		if v != nil {
+			variantQuantities[v.ID] += oi.Quantity
+			if variantQuantities[v.ID] > v.CountInStock {
+				return nil, status.Errorf(codes.FailedPrecondition, "variant %d has only %d items in stock", v.ID, v.CountInStock)
+			}
+			oi.Name = variantName(p, v)
+			if v.Price != nil {
+				price = *v.Price
+			}
+		}
+		oi.Price, err = s.convert(ctx, price, p.Currency, money.StoreCurrency)
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs[p.ID] = p.CategoryID
+		taxCategories[p.ID] = p.TaxCategory
+		parcel.Add(p.Weight, p.Length, p.Width, p.Height, oi.Quantity)
+	}
+
+	oa, err := s.shippingAddress(ctx, order.UserID, o.GetAddressId())
+	if err != nil {
+		return nil, err
+	}
+	order.OrderAddress = oa
+
+	var discount money.Amount
+	discounted := make([]bool, len(order.Items))
+	if code := normalizeCouponCode(o.GetCouponCode()); code != "" {
+		categories, err := s.productCategories(ctx, categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		c, err := s.redeemableCoupon(ctx, code, order, categories)
+		if err != nil {
+			return nil, err
+		}
+		discount = couponDiscount(c, order.Items, categories)
+		discounted = couponItems(c, order.Items, categories)
+		order.CouponID = &c.ID
+		order.CouponCode = &c.Code
+	}
+
+	q, err := s.pricing.Price(ctx, order.Items, discount)
+	if err != nil {
+		return nil, err
+	}
+	err = s.chargeShipping(ctx, order, q, parcel, o.GetShippingMethod())
+	if err != nil {
+		return nil, err
+	}
+	err = s.chargeTax(order, q, discounted, taxCategories)
+	if err != nil {
+		return nil, err
+	}
+	order.DiscountPrice = q.Discount
+	order.TaxPrice = q.Tax
+	order.ShippingPrice = q.Shipping
+	order.TotalPrice = q.Total
+
+	err = s.chargeIn(ctx, order, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, oi := range order.Items {
+		if sent := money.Amount(o.GetItems()[i].GetPrice()); sent != 0 && sent != oi.Price {
+			return nil, status.Errorf(codes.InvalidArgument, "price mismatch for product %d: got %s, want %s", oi.ProductID, sent, oi.Price)
+		}
+	}
+	for _, c := range []struct {
+		name          string
+		got, computed money.Amount
+	}{
+		{"tax price", money.Amount(o.GetTaxPrice()), order.TaxPrice},
+		{"shipping price", money.Amount(o.GetShippingPrice()), order.ShippingPrice},
+		{"total price", money.Amount(o.GetTotalPrice()), order.TotalPrice},
+	} {
+		if c.got != 0 && c.got != c.computed {
+			return nil, status.Errorf(codes.InvalidArgument, "%s mismatch: got %s, want %s", c.name, c.got, c.computed)
+		}
+	}
+
+	return order, nil
+}
+
+// GetOrder returns an order with its items. Customers can only read their own
+// orders.
 func (s *Server) GetOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	order, err := s.storer.GetOrder(ctx, o.GetUserId())
+	order, err := s.storer.GetOrder(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d does not exist", o.GetId())
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if !o.GetIsAdmin() && o.GetUserId() != order.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", o.GetId(), o.GetUserId())
+	}
+
 	return toPBOrderRes(order), nil
 }
 
-func (s *Server) ListOrders(ctx context.Context, o *pb.OrderReq) (*pb.ListOrderRes, error) {
-	orders, err := s.storer.ListOrders(ctx)
+// ListOrders returns the orders of every user, newest first unless sorted
+// otherwise, one page at a time.
+func (s *Server) ListOrders(ctx context.Context, o *pb.ListOrdersReq) (*pb.ListOrderRes, error) {
+	size, err := pageSize(o.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	f := &storer.OrderFilter{
+		UserID:    o.GetUserId(),
+		Sort:      o.GetSortBy(),
+		PageSize:  size,
+		PageToken: o.GetPageToken(),
+	}
+	if o.Status != nil {
+		st := toStorerOrderStatus(o.GetStatus())
+		f.Status = &st
+	}
+	if o.GetCreatedAfter() != nil {
+		f.CreatedAfter = o.GetCreatedAfter().AsTime()
+	}
+	if o.GetCreatedBefore() != nil {
+		f.CreatedBefore = o.GetCreatedBefore().AsTime()
+	}
+
+	return s.listOrders(ctx, f)
+}
+
+// ListUserOrders returns the orders of a user, newest first, one page at a
+// time.
+func (s *Server) ListUserOrders(ctx context.Context, r *pb.ListUserOrdersReq) (*pb.ListOrderRes, error) {
+	size, err := pageSize(r.GetPageSize())
 	if err != nil {
 		return nil, err
 	}
 
+	f := &storer.OrderFilter{
+		UserID:    r.GetUserId(),
+		Sort:      r.GetSortBy(),
+		PageSize:  size,
+		PageToken: r.GetPageToken(),
+	}
+	if r.Status != nil {
+		st := toStorerOrderStatus(r.GetStatus())
+		f.Status = &st
+	}
+	if r.GetCreatedAfter() != nil {
+		f.CreatedAfter = r.GetCreatedAfter().AsTime()
+	}
+	if r.GetCreatedBefore() != nil {
+		f.CreatedBefore = r.GetCreatedBefore().AsTime()
+	}
+
+	return s.listOrders(ctx, f)
+}
+
+func (s *Server) listOrders(ctx context.Context, f *storer.OrderFilter) (*pb.ListOrderRes, error) {
+	orders, next, err := s.storer.ListOrders(ctx, f)
+	if err != nil {
+		return nil, listError(err)
+	}
+
 	lor := make([]*pb.OrderRes, 0, len(orders))
 	for _, order := range orders {
 		lor = append(lor, toPBOrderRes(order))
 	}
 
 	return &pb.ListOrderRes{
-		Orders: lor,
+		Orders:        lor,
+		NextPageToken: next,
 	}, nil
 }
 
+// UpdateOrderStatus moves any order along its lifecycle and is reserved to
+// admins. Customers can only cancel their own orders, see CancelOrder.
+// Refunding an order refunds its captured payments once the order is
+// refunded, so that orders moved concurrently keep their payments. Refunding
+// it again retries the refunds that failed.
 func (s *Server) UpdateOrderStatus(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
-	// vadliate the order req
+	if !o.GetIsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only admins can change the status of an order")
+	}
+
+	order, err := s.getOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	sOrderStatus := toStorerOrderStatus(o.GetStatus())
+	if sOrderStatus != storer.Refunded || order.Status != storer.Refunded {
+		order, err = s.transitionOrder(ctx, order, sOrderStatus, o.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if sOrderStatus == storer.Refunded {
+		err = s.refundOrder(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return toPBOrderRes(order), nil
+}
+
+// CancelOrder lets customers cancel their own orders as long as they are
+// still pending.
+func (s *Server) CancelOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
+	order, err := s.getOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != storer.Pending {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d is %s and can no longer be cancelled", order.ID, order.Status)
+	}
+
+	or, err := s.transitionOrder(ctx, order, storer.Cancelled, o.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBOrderRes(or), nil
+}
+
+// pageSize applies the default and maximum page sizes of list calls.
+func pageSize(n int32) (int, error) {
+	switch {
+	case n < 0:
+		return 0, status.Error(codes.InvalidArgument, "page size must not be negative")
+	case n == 0:
+		return defaultPageSize, nil
+	case n > maxPageSize:
+		return maxPageSize, nil
+	default:
+		return int(n), nil
+	}
+}
+
+// listError turns the errors of bad list parameters into InvalidArgument.
+func listError(err error) error {
+	if errors.Is(err, storer.ErrInvalidPageToken) || errors.Is(err, storer.ErrInvalidSort) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+// getOrderStatus loads the order of the request and checks that the caller
+// owns it, unless they are an admin.
+func (s *Server) getOrderStatus(ctx context.Context, o *pb.OrderReq) (*storer.Order, error) {
 	order, err := s.storer.GetOrderStatusByID(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d does not exist", o.GetId())
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if o.GetUserId() != order.UserID {
-		return nil, fmt.Errorf("order %d does not belong to user %d", o.GetId(), o.GetUserId())
+	if !o.GetIsAdmin() && o.GetUserId() != order.UserID {
+		return nil, status.Errorf(codes.PermissionDenied, "order %d does not belong to user %d", o.GetId(), o.GetUserId())
 	}
 
-	sOrderStatus := storer.OrderStatus(strings.ToLower(o.GetStatus().String()))
-	if sOrderStatus == order.Status {
-		return nil, fmt.Errorf("order status is already %s", order.Status)
+	return order, nil
+}
+
+// transitionOrder validates the move of order to status against the
+// lifecycle, persists it and notifies the owner of the order. Orders moving
+// to paid are invoiced in the same write, so a failed invoice leaves them
+// unpaid.
+func (s *Server) transitionOrder(ctx context.Context, order *storer.Order, to storer.OrderStatus, changedBy int64) (*storer.Order, error) {
+	if to == order.Status {
+		return nil, status.Errorf(codes.FailedPrecondition, "order status is already %s", order.Status)
+	}
+	if !canTransition(order.Status, to) {
+		return nil, status.Errorf(codes.FailedPrecondition, "order status cannot change from %s to %s", order.Status, to)
 	}
 
-	order.Status = sOrderStatus
-	order.UpdatedAt = toTimePtr(time.Now())
-	or, err := s.storer.UpdateOrderStatus(ctx, order)
+	owner, err := s.storer.GetUserByID(ctx, order.UserID)
 	if err != nil {
 		return nil, err
 	}
 
+	from := order.Status
+	c := &storer.OrderStatusChange{
+		OrderID:    order.ID,
+		FromStatus: &from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+	}
+	if to == storer.Paid {
+		c.Invoice, err = s.newInvoice(ctx, order.ID, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.storer.UpdateOrderStatus(ctx, c)
+	if errors.Is(err, storer.ErrOrderStatusConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = to
+	order.UpdatedAt = toTimePtr(time.Now())
+
 	//enqueue notification event
 	_, err = s.storer.EnqueueNotificationEvent(ctx, &storer.NotificationEvent{
-		UserEmail:   o.GetUserEmail(),
+		UserEmail:   owner.Email,
 		OrderStatus: order.Status,
 		OrderID:     order.ID,
 		Attempts:    0,
@@ -161,11 +842,42 @@ func (s *Server) UpdateOrderStatus(ctx context.Context, o *pb.OrderReq) (*pb.Ord
 		return nil, err
 	}
 
-	return toPBOrderRes(or), nil
+	return order, nil
+}
+
+func (s *Server) ListOrderStatusHistory(ctx context.Context, o *pb.OrderReq) (*pb.ListOrderStatusHistoryRes, error) {
+	order, err := s.getOrderStatus(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.storer.ListOrderStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.OrderStatusChange, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, toPBOrderStatusChange(c))
+	}
+
+	return &pb.ListOrderStatusHistoryRes{
+		Changes: res,
+	}, nil
 }
 
+// DeleteOrder removes an order, putting its items back in stock if it still
+// holds them, and is reserved to admins. Customers cancel their orders
+// instead, see CancelOrder.
 func (s *Server) DeleteOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes, error) {
+	if !o.GetIsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only admins can delete orders")
+	}
+
 	err := s.storer.DeleteOrder(ctx, o.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d does not exist", o.GetId())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +885,386 @@ func (s *Server) DeleteOrder(ctx context.Context, o *pb.OrderReq) (*pb.OrderRes,
 	return &pb.OrderRes{}, nil
 }
 
+func (s *Server) CreateCoupon(ctx context.Context, c *pb.CouponReq) (*pb.CouponRes, error) {
+	coupon := toStorerCoupon(c)
+	err := validateCoupon(coupon)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkCategory(ctx, coupon.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateCoupon(ctx, coupon)
+	if errors.Is(err, storer.ErrDuplicateCoupon) {
+		return nil, status.Errorf(codes.AlreadyExists, "coupon %s already exists", coupon.Code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBCouponRes(created), nil
+}
+
+func (s *Server) GetCoupon(ctx context.Context, c *pb.CouponReq) (*pb.CouponRes, error) {
+	coupon, err := s.storer.GetCoupon(ctx, c.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "coupon %d does not exist", c.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBCouponRes(coupon), nil
+}
+
+func (s *Server) ListCoupons(ctx context.Context, c *pb.ListCouponsReq) (*pb.ListCouponsRes, error) {
+	size, err := pageSize(c.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, next, err := s.storer.ListCoupons(ctx, &storer.CouponFilter{
+		Sort:      c.GetSortBy(),
+		PageSize:  size,
+		PageToken: c.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	lcr := make([]*pb.CouponRes, 0, len(coupons))
+	for _, coupon := range coupons {
+		lcr = append(lcr, toPBCouponRes(coupon))
+	}
+
+	return &pb.ListCouponsRes{
+		Coupons:       lcr,
+		NextPageToken: next,
+	}, nil
+}
+
+func (s *Server) UpdateCoupon(ctx context.Context, c *pb.CouponReq) (*pb.CouponRes, error) {
+	coupon, err := s.storer.GetCoupon(ctx, c.GetId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "coupon %d does not exist", c.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	patchCouponReq(coupon, c)
+	err = validateCoupon(coupon)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkCategory(ctx, coupon.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storer.UpdateCoupon(ctx, coupon)
+	if errors.Is(err, storer.ErrDuplicateCoupon) {
+		return nil, status.Errorf(codes.AlreadyExists, "coupon %s already exists", coupon.Code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBCouponRes(updated), nil
+}
+
+func (s *Server) DeleteCoupon(ctx context.Context, c *pb.CouponReq) (*pb.CouponRes, error) {
+	err := s.storer.DeleteCoupon(ctx, c.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CouponRes{}, nil
+}
+
+func (s *Server) GetCart(ctx context.Context, c *pb.CartReq) (*pb.CartRes, error) {
+	return s.cartRes(ctx, cartOwner(c.GetUserId(), c.GetCartToken()), c.GetDisplayCurrency())
+}
+
+// AddCartItem puts items in a cart. Guests without a cart token get a new
+// one in the response.
+func (s *Server) AddCartItem(ctx context.Context, ci *pb.CartItemReq) (*pb.CartRes, error) {
+	owner := cartOwner(ci.GetUserId(), ci.GetCartToken())
+	if owner.UserID == 0 && owner.Token == "" {
+		owner.Token = rand.Text()
+	}
+
+	cart, err := s.storer.GetCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := ci.GetQuantity()
+	for _, item := range toPBCartItems(cart.Items) {
+		if item.GetProductId() == ci.GetProductId() && item.GetVariantId() == ci.GetVariantId() {
+			quantity += item.GetQuantity()
+		}
+	}
+	err = s.checkCartQuantity(ctx, ci, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.AddCartItem(ctx, owner, ci.GetProductId(), cartItemVariantID(ci), ci.GetQuantity())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartRes(ctx, owner, ci.GetDisplayCurrency())
+}
+
+func (s *Server) UpdateCartItem(ctx context.Context, ci *pb.CartItemReq) (*pb.CartRes, error) {
+	err := s.checkCartQuantity(ctx, ci, ci.GetQuantity())
+	if err != nil {
+		return nil, err
+	}
+
+	owner := cartOwner(ci.GetUserId(), ci.GetCartToken())
+	err = s.storer.SetCartItemQuantity(ctx, owner, ci.GetProductId(), cartItemVariantID(ci), ci.GetQuantity())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cartItemNotFound(ci)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartRes(ctx, owner, ci.GetDisplayCurrency())
+}
+
+// checkCartQuantity checks that the product and variant of a cart item exist,
+// that products with variants are added as one of them, and that total, the
+// quantity the cart will hold after adding or setting the quantity of the
+// item, is in stock.
+func (s *Server) checkCartQuantity(ctx context.Context, ci *pb.CartItemReq, total int64) error {
+	if ci.GetQuantity() <= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid quantity %d for product %d", ci.GetQuantity(), ci.GetProductId())
+	}
+
+	p, err := s.storer.GetProduct(ctx, ci.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "product %d does not exist", ci.GetProductId())
+	}
+	if err != nil {
+		return err
+	}
+
+	v, err := orderedVariant(p, cartItemVariantID(ci))
+	if err != nil {
+		return err
+	}
+	if v != nil && total > v.CountInStock {
+		return status.Errorf(codes.FailedPrecondition, "variant %d has only %d items in stock", v.ID, v.CountInStock)
+	}
+	if total > p.CountInStock {
+		return status.Errorf(codes.FailedPrecondition, "product %d has only %d items in stock", p.ID, p.CountInStock)
+	}
+	return nil
+}
+
+// cartItemNotFound is the error of a cart item the cart does not hold.
+func cartItemNotFound(ci *pb.CartItemReq) error {
+	if ci.GetVariantId() != 0 {
+		return status.Errorf(codes.NotFound, "variant %d of product %d is not in the cart", ci.GetVariantId(), ci.GetProductId())
+	}
+	return status.Errorf(codes.NotFound, "product %d is not in the cart", ci.GetProductId())
+}
+
+func (s *Server) RemoveCartItem(ctx context.Context, ci *pb.CartItemReq) (*pb.CartRes, error) {
+	owner := cartOwner(ci.GetUserId(), ci.GetCartToken())
+	err := s.storer.RemoveCartItem(ctx, owner, ci.GetProductId(), cartItemVariantID(ci))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cartItemNotFound(ci)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartRes(ctx, owner, ci.GetDisplayCurrency())
+}
+
+func (s *Server) ClearCart(ctx context.Context, c *pb.CartReq) (*pb.CartRes, error) {
+	owner := cartOwner(c.GetUserId(), c.GetCartToken())
+	err := s.storer.ClearCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartRes(ctx, owner, c.GetDisplayCurrency())
+}
+
+// MergeCart moves the guest cart with the token into the cart of the user, as
+// happens when a guest logs in.
+func (s *Server) MergeCart(ctx context.Context, m *pb.MergeCartReq) (*pb.CartRes, error) {
+	if m.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+
+	if m.GetCartToken() != "" {
+		err := s.storer.MergeCart(ctx, m.GetCartToken(), m.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.cartRes(ctx, storer.CartOwner{UserID: m.GetUserId()}, m.GetDisplayCurrency())
+}
+
+// Checkout places an order for the items of the cart, priced like any other
+// order, and empties the cart.
+func (s *Server) Checkout(ctx context.Context, c *pb.CheckoutReq) (*pb.OrderRes, error) {
+	cart, err := s.storer.GetCart(ctx, storer.CartOwner{UserID: c.GetUserId()})
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+
+	o := &pb.OrderReq{
+		UserId:         c.GetUserId(),
+		UserEmail:      c.GetUserEmail(),
+		PaymentMethod:  c.GetPaymentMethod(),
+		CouponCode:     c.GetCouponCode(),
+		Currency:       c.GetCurrency(),
+		AddressId:      c.GetAddressId(),
+		ShippingMethod: c.GetShippingMethod(),
+	}
+	for _, ci := range toPBCartItems(cart.Items) {
+		o.Items = append(o.Items, &pb.OrderItem{ProductId: ci.GetProductId(), VariantId: ci.GetVariantId(), Quantity: ci.GetQuantity()})
+	}
+
+	po, err := s.priceOrder(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.placeOrder(ctx, po, o.GetUserEmail(), s.storer.CheckoutCart)
+}
+
+// cartOwner returns the owner of the cart of the user, or of the guest with
+// the token if userID is zero.
+func cartOwner(userID int64, token string) storer.CartOwner {
+	if userID != 0 {
+		return storer.CartOwner{UserID: userID}
+	}
+	return storer.CartOwner{Token: token}
+}
+
+// cartRes returns the cart of the owner with the charges its checkout would
+// have at current prices, see chargeCart, converted to the display currency,
+// the store currency if empty.
+func (s *Server) cartRes(ctx context.Context, owner storer.CartOwner, display string) (*pb.CartRes, error) {
+	cart, err := s.storer.GetCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	display = currencyCode(display, money.StoreCurrency)
+	res := &pb.CartRes{Items: toPBCartItems(cart.Items), CartToken: owner.Token, Currency: display}
+	if len(cart.Items) == 0 {
+		return res, nil
+	}
+
+	items := toStorerCartOrderItems(cart.Items)
+	for i, ci := range cart.Items {
+		items[i].Price, err = s.convert(ctx, ci.Price, ci.Currency, money.StoreCurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	order := &storer.Order{Items: items}
+	res.ChargesPending, err = s.chargeCart(ctx, owner, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chargeIn(ctx, order, display)
+	if err != nil {
+		return nil, err
+	}
+	for i, oi := range order.Items {
+		res.Items[i].Price = int64(oi.Price)
+	}
+	res.Subtotal = int64(itemsSubtotal(order.Items))
+	res.TaxPrice = int64(order.TaxPrice)
+	res.ShippingPrice = int64(order.ShippingPrice)
+	res.TotalPrice = int64(order.TotalPrice)
+
+	return res, nil
+}
+
+// chargeCart charges the order a cart would check out as, its items priced in
+// the store currency, like priceOrder does for an order shipping to the
+// default address of the owner with the cheapest shipping method. Guests and
+// users without a default address have no address to ship to: shipping and
+// tax that depend on it are then left out and chargeCart reports them
+// pending, as it does when no shipping method ships the cart to the address.
+func (s *Server) chargeCart(ctx context.Context, owner storer.CartOwner, order *storer.Order) (bool, error) {
+	q, err := s.pricing.Price(ctx, order.Items, 0)
+	if err != nil {
+		return false, err
+	}
+	if s.shipping == nil && s.tax == nil {
+		order.TaxPrice = q.Tax
+		order.ShippingPrice = q.Shipping
+		return false, nil
+	}
+
+	if owner.UserID != 0 {
+		order.OrderAddress, err = s.shippingAddress(ctx, owner.UserID, 0)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if order.OrderAddress.IsZero() {
+		if s.shipping != nil {
+			q.Shipping = 0
+		}
+		if s.tax != nil {
+			q.Tax = 0
+		}
+		order.TaxPrice = q.Tax
+		order.ShippingPrice = q.Shipping
+		return true, nil
+	}
+
+	taxCategories := make(map[int64]string)
+	parcel := &shipping.Parcel{}
+	for _, oi := range order.Items {
+		p, err := s.storer.GetProduct(ctx, oi.ProductID)
+		if err != nil {
+			return false, err
+		}
+		taxCategories[p.ID] = p.TaxCategory
+		parcel.Add(p.Weight, p.Length, p.Width, p.Height, oi.Quantity)
+	}
+
+	pending := false
+	err = s.chargeShipping(ctx, order, q, parcel, "")
+	if status.Code(err) == codes.FailedPrecondition {
+		q.Shipping = 0
+		pending = true
+	} else if err != nil {
+		return false, err
+	}
+	err = s.chargeTax(order, q, make([]bool, len(order.Items)), taxCategories)
+	if err != nil {
+		return false, err
+	}
+
+	order.TaxPrice = q.Tax
+	order.ShippingPrice = q.Shipping
+	return pending, nil
+}
+
 func (s *Server) CreateUser(ctx context.Context, u *pb.UserReq) (*pb.UserRes, error) {
 	user, err := s.storer.CreateUser(ctx, toStorerUser(u))
 	if err != nil {
@@ -191,19 +1283,38 @@ func (s *Server) GetUser(ctx context.Context, u *pb.UserReq) (*pb.UserRes, error
 	return toPBUserRes(user), nil
 }
 
-func (s *Server) ListUsers(ctx context.Context, u *pb.UserReq) (*pb.ListUserRes, error) {
-	users, err := s.storer.ListUsers(ctx)
+func (s *Server) ListUsers(ctx context.Context, u *pb.ListUsersReq) (*pb.ListUserRes, error) {
+	size, err := pageSize(u.GetPageSize())
 	if err != nil {
 		return nil, err
 	}
 
+	f := &storer.UserFilter{
+		IsAdmin:   u.IsAdmin,
+		Sort:      u.GetSortBy(),
+		PageSize:  size,
+		PageToken: u.GetPageToken(),
+	}
+	if u.GetCreatedAfter() != nil {
+		f.CreatedAfter = u.GetCreatedAfter().AsTime()
+	}
+	if u.GetCreatedBefore() != nil {
+		f.CreatedBefore = u.GetCreatedBefore().AsTime()
+	}
+
+	users, next, err := s.storer.ListUsers(ctx, f)
+	if err != nil {
+		return nil, listError(err)
+	}
+
 	lur := make([]*pb.UserRes, 0, len(users))
 	for _, user := range users {
 		lur = append(lur, toPBUserRes(user))
 	}
 
 	return &pb.ListUserRes{
-		Users: lur,
+		Users:         lur,
+		NextPageToken: next,
 	}, nil
 }
 
@@ -285,12 +1396,78 @@ func (s *Server) DeleteSession(ctx context.Context, sr *pb.SessionReq) (*pb.Sess
 	return &pb.SessionRes{}, nil
 }
 
+// ReserveIdempotencyKey reserves a key for the request of a user, or returns
+// the request that reserved it first.
+func (s *Server) ReserveIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq) (*pb.IdempotencyKeyRes, error) {
+	if r.GetKey() == "" || r.GetRequestHash() == "" || r.GetExpiresAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "key, request hash and expiry are required")
+	}
+
+	k, reserved, err := s.storer.ReserveIdempotencyKey(ctx, &storer.IdempotencyKey{
+		UserID:      r.GetUserId(),
+		Key:         r.GetKey(),
+		RequestHash: r.GetRequestHash(),
+		ExpiresAt:   r.GetExpiresAt().AsTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.IdempotencyKeyRes{
+		Reserved:     reserved,
+		RequestHash:  k.RequestHash,
+		StatusCode:   k.StatusCode,
+		ContentType:  k.ContentType,
+		ResponseBody: k.ResponseBody,
+	}, nil
+}
+
+// CompleteIdempotencyKey stores the response to the request holding a key,
+// which is replayed to its retries until the key expires.
+func (s *Server) CompleteIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq) (*pb.IdempotencyKeyRes, error) {
+	if r.GetStatusCode() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "status code is required")
+	}
+
+	err := s.storer.CompleteIdempotencyKey(ctx, &storer.IdempotencyKey{
+		UserID:       r.GetUserId(),
+		Key:          r.GetKey(),
+		StatusCode:   r.GetStatusCode(),
+		ContentType:  r.GetContentType(),
+		ResponseBody: r.GetResponseBody(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.IdempotencyKeyRes{}, nil
+}
+
+// ReleaseIdempotencyKey frees a key whose request failed, so that it can be
+// retried. Completed keys are kept.
+func (s *Server) ReleaseIdempotencyKey(ctx context.Context, r *pb.IdempotencyKeyReq) (*pb.IdempotencyKeyRes, error) {
+	err := s.storer.ReleaseIdempotencyKey(ctx, r.GetUserId(), r.GetKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.IdempotencyKeyRes{}, nil
+}
+
 func (s *Server) ListNotificationEvents(ctx context.Context, lnr *pb.ListNotificationEventsReq) (*pb.ListNotificationEventsRes, error) {
-	notificationEvents, err := s.storer.ListNotificationEvents(ctx)
+	size, err := pageSize(lnr.GetPageSize())
 	if err != nil {
 		return nil, err
 	}
 
+	notificationEvents, next, err := s.storer.ListNotificationEvents(ctx, &storer.NotificationEventFilter{
+		PageSize:  size,
+		PageToken: lnr.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
 	lners := make([]*pb.NotificationEvent, 0, len(notificationEvents))
 	for _, ne := range notificationEvents {
 		lners = append(lners, &pb.NotificationEvent{
@@ -304,7 +1481,8 @@ func (s *Server) ListNotificationEvents(ctx context.Context, lnr *pb.ListNotific
 	}
 
 	return &pb.ListNotificationEventsRes{
-		Events: lners,
+		Events:        lners,
+		NextPageToken: next,
 	}, nil
 }
 
@@ -0,0 +1,2084 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niloy104/Conduit/blobstore"
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/invoice"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/payment"
+	"github.com/niloy104/Conduit/shipping"
+	"github.com/niloy104/Conduit/tax"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestServer(t *testing.T) (*Server, *storer.MemoryStorer) {
+	st := storer.NewMemoryStorer()
+	return NewServer(st), st
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Name: "test user", Email: "test@example.com", Password: "secret"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+
+	or, err := srv.CreateOrder(ctx, &pb.OrderReq{
+		UserId:    u.GetId(),
+		UserEmail: u.GetEmail(),
+		Items:     []*pb.OrderItem{{Name: p.Name, Quantity: 1, Price: int64(p.Price), ProductId: p.ID}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.OrderStatus_PENDING, or.GetStatus())
+
+	evs, err := srv.ListNotificationEvents(ctx, &pb.ListNotificationEventsReq{})
+	require.NoError(t, err)
+	require.Len(t, evs.GetEvents(), 1)
+	require.Equal(t, or.GetId(), evs.GetEvents()[0].GetOrderId())
+}
+
+func TestCreateOrderPricing(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	srv := NewServer(st, WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500, FreeShippingOver: 10000}))
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Image: "test.jpg", Price: 2000, CountInStock: 10})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.OrderReq
+		wantCode codes.Code
+		check    func(*testing.T, *pb.OrderRes)
+	}{
+		{
+			name: "catalog prices",
+			req: &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{
+				{Name: "cheap", Image: "fake.jpg", Quantity: 2, ProductId: p.ID},
+			}},
+			check: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, "test product", res.GetItems()[0].GetName())
+				require.Equal(t, "test.jpg", res.GetItems()[0].GetImage())
+				require.Equal(t, int64(2000), res.GetItems()[0].GetPrice())
+				require.Equal(t, int64(400), res.GetTaxPrice())
+				require.Equal(t, int64(500), res.GetShippingPrice())
+				require.Equal(t, int64(4900), res.GetTotalPrice())
+			},
+		},
+		{
+			name: "free shipping",
+			req:  &pb.OrderReq{UserId: u.ID, TotalPrice: 11000, Items: []*pb.OrderItem{{Quantity: 5, ProductId: p.ID}}},
+			check: func(t *testing.T, res *pb.OrderRes) {
+				require.Equal(t, int64(0), res.GetShippingPrice())
+				require.Equal(t, int64(11000), res.GetTotalPrice())
+			},
+		},
+		{
+			name:     "no items",
+			req:      &pb.OrderReq{UserId: u.ID},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown product",
+			req:      &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{{Quantity: 1, ProductId: 42}}},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "invalid quantity",
+			req:      &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{{Quantity: 0, ProductId: p.ID}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "exceeds stock across lines",
+			req: &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{
+				{Quantity: 6, ProductId: p.ID},
+				{Quantity: 5, ProductId: p.ID},
+			}},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "item price mismatch",
+			req:      &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{{Quantity: 1, Price: 1, ProductId: p.ID}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "total price mismatch",
+			req:      &pb.OrderReq{UserId: u.ID, TotalPrice: 1, Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.CreateOrder(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, res)
+		})
+	}
+}
+
+func TestCreateOrderConcurrentDoesNotOversell(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 3})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	codesc := make(chan codes.Code, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}})
+			codesc <- status.Code(err)
+		}()
+	}
+	wg.Wait()
+	close(codesc)
+
+	placed := 0
+	for c := range codesc {
+		switch c {
+		case codes.OK:
+			placed++
+		default:
+			require.Equal(t, codes.FailedPrecondition, c)
+		}
+	}
+	require.Equal(t, 3, placed)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.CountInStock)
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	admin, err := srv.CreateUser(ctx, &pb.UserReq{Email: "admin@example.com", IsAdmin: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+	or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), UserEmail: u.GetEmail(), Items: []*pb.OrderItem{{Quantity: 2, ProductId: p.ID}}})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.OrderReq
+		wantCode codes.Code
+	}{
+		{
+			name:     "unknown order",
+			req:      &pb.OrderReq{Id: 42, UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PAID},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "owner is not an admin",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: u.GetId(), Status: pb.OrderStatus_SHIPPED},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "same status",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PENDING},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "skips processing",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_SHIPPED},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "paid",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PAID},
+		},
+		{
+			name: "processing",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PROCESSING},
+		},
+		{
+			name: "shipped",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_SHIPPED},
+		},
+		{
+			name:     "back to pending",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PENDING},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "cancel after shipping",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_CANCELLED},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "delivered",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_DELIVERED},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.UpdateOrderStatus(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.req.GetStatus(), res.GetStatus())
+		})
+	}
+
+	history, err := srv.ListOrderStatusHistory(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()})
+	require.NoError(t, err)
+	require.Len(t, history.GetChanges(), 5)
+	require.Nil(t, history.GetChanges()[0].FromStatus)
+	require.Equal(t, pb.OrderStatus_SHIPPED, history.GetChanges()[4].GetFromStatus())
+	require.Equal(t, pb.OrderStatus_DELIVERED, history.GetChanges()[4].GetToStatus())
+	require.Equal(t, admin.GetId(), history.GetChanges()[4].GetChangedBy())
+
+	evs, err := srv.ListNotificationEvents(ctx, &pb.ListNotificationEventsReq{})
+	require.NoError(t, err)
+	for _, ev := range evs.GetEvents() {
+		require.Equal(t, u.GetEmail(), ev.GetUserEmail(), "notifications go to the order owner")
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	other, err := srv.CreateUser(ctx, &pb.UserReq{Email: "other@example.com"})
+	require.NoError(t, err)
+	admin, err := srv.CreateUser(ctx, &pb.UserReq{Email: "admin@example.com", IsAdmin: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+
+	newOrder := func() *pb.OrderRes {
+		or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}})
+		require.NoError(t, err)
+		return or
+	}
+	pending, paid, byAdmin := newOrder(), newOrder(), newOrder()
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: paid.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_PAID})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.OrderReq
+		wantCode codes.Code
+	}{
+		{
+			name:     "not the owner",
+			req:      &pb.OrderReq{Id: pending.GetId(), UserId: other.GetId()},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no longer pending",
+			req:      &pb.OrderReq{Id: paid.GetId(), UserId: u.GetId()},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "owner",
+			req:  &pb.OrderReq{Id: pending.GetId(), UserId: u.GetId()},
+		},
+		{
+			name:     "already cancelled",
+			req:      &pb.OrderReq{Id: pending.GetId(), UserId: u.GetId()},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "admin",
+			req:  &pb.OrderReq{Id: byAdmin.GetId(), UserId: admin.GetId(), IsAdmin: true},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.CancelOrder(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, pb.OrderStatus_CANCELLED, res.GetStatus())
+		})
+	}
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got.CountInStock, "cancelled orders are back in stock")
+}
+
+func TestDeleteOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	admin, err := srv.CreateUser(ctx, &pb.UserReq{Email: "admin@example.com", IsAdmin: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+	// through the storer, the orders of CreateOrder are referenced by their
+	// notifications
+	or, err := st.CreateOrder(ctx, &storer.Order{UserID: u.GetId(), Items: []storer.OrderItem{{Name: p.Name, Quantity: 2, Price: p.Price, ProductID: p.ID}}})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.OrderReq
+		wantCode codes.Code
+	}{
+		{
+			name:     "owner",
+			req:      &pb.OrderReq{Id: or.ID, UserId: u.GetId()},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "admin",
+			req:  &pb.OrderReq{Id: or.ID, UserId: admin.GetId(), IsAdmin: true},
+		},
+		{
+			name:     "unknown order",
+			req:      &pb.OrderReq{Id: or.ID, UserId: admin.GetId(), IsAdmin: true},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.DeleteOrder(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), got.CountInStock, "deleted pending orders are back in stock")
+}
+
+func TestGetOrder(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	other, err := srv.CreateUser(ctx, &pb.UserReq{Email: "other@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+	or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), Items: []*pb.OrderItem{{Quantity: 2, ProductId: p.ID}}})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.OrderReq
+		wantCode codes.Code
+	}{
+		{
+			name: "owner",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()},
+		},
+		{
+			name: "admin",
+			req:  &pb.OrderReq{Id: or.GetId(), UserId: other.GetId(), IsAdmin: true},
+		},
+		{
+			name:     "not the owner",
+			req:      &pb.OrderReq{Id: or.GetId(), UserId: other.GetId()},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unknown order",
+			req:      &pb.OrderReq{Id: 42, UserId: u.GetId()},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.GetOrder(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, u.GetId(), res.GetUserId())
+			require.Len(t, res.GetItems(), 1)
+			require.Equal(t, int64(2), res.GetItems()[0].GetQuantity())
+		})
+	}
+}
+
+func TestListUserOrders(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 50})
+	require.NoError(t, err)
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), UserEmail: "test@example.com", Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}})
+		require.NoError(t, err)
+		ids = append(ids, or.GetId())
+	}
+	_, err = srv.CancelOrder(ctx, &pb.OrderReq{Id: ids[0], UserId: u.GetId()})
+	require.NoError(t, err)
+
+	res, err := srv.ListUserOrders(ctx, &pb.ListUserOrdersReq{UserId: u.GetId(), PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, res.GetOrders(), 2)
+	require.Equal(t, ids[2], res.GetOrders()[0].GetId())
+	require.NotEmpty(t, res.GetNextPageToken())
+
+	res, err = srv.ListUserOrders(ctx, &pb.ListUserOrdersReq{UserId: u.GetId(), PageSize: 2, PageToken: res.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Len(t, res.GetOrders(), 1)
+	require.Equal(t, ids[0], res.GetOrders()[0].GetId())
+	require.Empty(t, res.GetNextPageToken())
+
+	cancelled := pb.OrderStatus_CANCELLED
+	res, err = srv.ListUserOrders(ctx, &pb.ListUserOrdersReq{UserId: u.GetId(), Status: &cancelled})
+	require.NoError(t, err)
+	require.Len(t, res.GetOrders(), 1)
+	require.Equal(t, ids[0], res.GetOrders()[0].GetId())
+
+	_, err = srv.ListUserOrders(ctx, &pb.ListUserOrdersReq{UserId: u.GetId(), PageToken: "garbage"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = srv.ListUserOrders(ctx, &pb.ListUserOrdersReq{UserId: u.GetId(), PageSize: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListProducts(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	for i := 1; i <= 5; i++ {
+		_, err := st.CreateProduct(ctx, &storer.Product{Name: fmt.Sprintf("product %d", i), Price: money.Amount(i * 1000), CountInStock: 1})
+		require.NoError(t, err)
+	}
+
+	res, err := srv.ListProducts(ctx, &pb.ListProductsReq{PageSize: 2, SortBy: "-price"})
+	require.NoError(t, err)
+	require.Len(t, res.GetProducts(), 2)
+	require.Equal(t, int64(5000), res.GetProducts()[0].GetPrice())
+	require.NotEmpty(t, res.GetNextPageToken())
+
+	res, err = srv.ListProducts(ctx, &pb.ListProductsReq{PageSize: 2, SortBy: "-price", PageToken: res.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Equal(t, int64(3000), res.GetProducts()[0].GetPrice())
+
+	maxPrice := int64(2000)
+	res, err = srv.ListProducts(ctx, &pb.ListProductsReq{MaxPrice: &maxPrice})
+	require.NoError(t, err)
+	require.Len(t, res.GetProducts(), 2)
+	require.Empty(t, res.GetNextPageToken())
+
+	tcs := []struct {
+		name string
+		req  *pb.ListProductsReq
+	}{
+		{name: "unknown sort", req: &pb.ListProductsReq{SortBy: "image"}},
+		{name: "garbage token", req: &pb.ListProductsReq{PageToken: "garbage"}},
+		{name: "negative page size", req: &pb.ListProductsReq{PageSize: -1}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.ListProducts(ctx, tc.req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestListProductsCurrencies(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	srv := NewServer(st, WithRateProvider(money.NewStaticRates("USD", map[string]money.Rate{"EUR": 800000})))
+
+	_, err := st.CreateProduct(ctx, &storer.Product{Name: "dollars", Price: 1000, Currency: "USD", CountInStock: 1})
+	require.NoError(t, err)
+	_, err = st.CreateProduct(ctx, &storer.Product{Name: "euros", Price: 1200, Currency: "EUR", CountInStock: 1})
+	require.NoError(t, err)
+
+	price := func(v int64) *int64 { return &v }
+	tcs := []struct {
+		name string
+		req  *pb.ListProductsReq
+		want []string
+	}{
+		{name: "min in display currency", req: &pb.ListProductsReq{MinPrice: price(1000), DisplayCurrency: "EUR"}, want: []string{"euros"}},
+		{name: "max in display currency", req: &pb.ListProductsReq{MaxPrice: price(1000), DisplayCurrency: "EUR"}, want: []string{"dollars"}},
+		{name: "store currency by default", req: &pb.ListProductsReq{MaxPrice: price(1200)}, want: []string{"dollars"}},
+		{name: "both bounds", req: &pb.ListProductsReq{MinPrice: price(1400), MaxPrice: price(1600)}, want: []string{"euros"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.ListProducts(ctx, tc.req)
+			require.NoError(t, err)
+			var got []string
+			for _, p := range res.GetProducts() {
+				got = append(got, p.GetName())
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err = srv.ListProducts(ctx, &pb.ListProductsReq{SortBy: "-price"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "prices in several currencies do not sort")
+}
+
+func TestSearchProducts(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	_, err := st.CreateProduct(ctx, &storer.Product{Name: "Wireless mouse"})
+	require.NoError(t, err)
+
+	res, err := srv.SearchProducts(ctx, &pb.SearchProductsReq{Query: "  mouse "})
+	require.NoError(t, err)
+	require.Len(t, res.GetMatches(), 1)
+	require.Equal(t, "Wireless mouse", res.GetMatches()[0].GetProduct().GetName())
+	require.Equal(t, "Wireless <mark>mouse</mark>", res.GetMatches()[0].GetSnippet())
+
+	_, err = srv.SearchProducts(ctx, &pb.SearchProductsReq{Query: " "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestReviews(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	admin, err := srv.CreateUser(ctx, &pb.UserReq{Email: "admin@example.com", IsAdmin: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+	or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}})
+	require.NoError(t, err)
+
+	review := &pb.ReviewReq{ProductId: p.ID, UserId: u.GetId(), Rating: 4, Comment: " great "}
+	_, err = srv.CreateReview(ctx, review)
+	require.Equal(t, codes.PermissionDenied, status.Code(err), "the order is not delivered yet")
+
+	for _, s := range []pb.OrderStatus{pb.OrderStatus_PROCESSING, pb.OrderStatus_SHIPPED, pb.OrderStatus_DELIVERED} {
+		_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: s})
+		require.NoError(t, err)
+	}
+
+	tcs := []struct {
+		name     string
+		req      *pb.ReviewReq
+		wantCode codes.Code
+	}{
+		{
+			name:     "rating out of range",
+			req:      &pb.ReviewReq{ProductId: p.ID, UserId: u.GetId(), Rating: 6},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown product",
+			req:      &pb.ReviewReq{ProductId: 42, UserId: u.GetId(), Rating: 3},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "not a customer",
+			req:      &pb.ReviewReq{ProductId: p.ID, UserId: admin.GetId(), Rating: 3},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "success",
+			req:  review,
+		},
+		{
+			name:     "second review",
+			req:      review,
+			wantCode: codes.AlreadyExists,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.CreateReview(ctx, tc.req)
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "great", res.GetComment())
+			require.Equal(t, pb.ReviewStatus_PUBLISHED, res.GetStatus())
+		})
+	}
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got.Rating)
+	require.Equal(t, int64(1), got.NumReviews)
+
+	published := pb.ReviewStatus_PUBLISHED
+	res, err := srv.ListReviews(ctx, &pb.ListReviewsReq{ProductId: p.ID, Status: &published})
+	require.NoError(t, err)
+	require.Len(t, res.GetReviews(), 1)
+	id := res.GetReviews()[0].GetId()
+
+	_, err = srv.ModerateReview(ctx, &pb.ReviewReq{Id: id, Status: pb.ReviewStatus_HIDDEN})
+	require.NoError(t, err)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.Rating)
+	require.Zero(t, got.NumReviews)
+
+	res, err = srv.ListReviews(ctx, &pb.ListReviewsReq{ProductId: p.ID, Status: &published})
+	require.NoError(t, err)
+	require.Empty(t, res.GetReviews())
+
+	_, err = srv.DeleteReview(ctx, &pb.ReviewReq{Id: id})
+	require.NoError(t, err)
+	_, err = srv.DeleteReview(ctx, &pb.ReviewReq{Id: id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.ModerateReview(ctx, &pb.ReviewReq{Id: id, Status: pb.ReviewStatus_PUBLISHED})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateOrderCoupon(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	srv := NewServer(st, WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500}))
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	other, err := st.CreateUser(ctx, &storer.User{Email: "other@example.com"})
+	require.NoError(t, err)
+	books, err := st.CreateCategory(ctx, &storer.Category{Name: "Books", Slug: "books"})
+	require.NoError(t, err)
+	fantasy, err := st.CreateCategory(ctx, &storer.Category{ParentID: &books.ID, Name: "Fantasy", Slug: "fantasy"})
+	require.NoError(t, err)
+	office, err := st.CreateCategory(ctx, &storer.Category{Name: "Office", Slug: "office"})
+	require.NoError(t, err)
+	book, err := st.CreateProduct(ctx, &storer.Product{Name: "book", CategoryID: &fantasy.ID, Price: 2000, CountInStock: 100})
+	require.NoError(t, err)
+	pen, err := st.CreateProduct(ctx, &storer.Product{Name: "pen", CategoryID: &office.ID, Price: 500, CountInStock: 100})
+	require.NoError(t, err)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	for _, c := range []*storer.Coupon{
+		{Code: "TENOFF", Kind: storer.PercentageCoupon, Value: 1000},
+		{Code: "FIVER", Kind: storer.FixedCoupon, Value: 500, MinOrderValue: 3000},
+		{Code: "BIGFIXED", Kind: storer.FixedCoupon, Value: 50000},
+		{Code: "BOOKS", Kind: storer.PercentageCoupon, Value: 5000, CategoryID: &books.ID},
+		{Code: "PENS", Kind: storer.PercentageCoupon, Value: 5000, ProductID: &pen.ID},
+		{Code: "EXPIRED", Kind: storer.PercentageCoupon, Value: 1000, ExpiresAt: &yesterday},
+		{Code: "ONCE", Kind: storer.PercentageCoupon, Value: 1000, MaxUses: 1},
+		{Code: "ONCEEACH", Kind: storer.PercentageCoupon, Value: 1000, MaxUsesPerUser: 1},
+	} {
+		_, err := st.CreateCoupon(ctx, c)
+		require.NoError(t, err)
+	}
+
+	items := []*pb.OrderItem{{Quantity: 1, ProductId: book.ID}, {Quantity: 2, ProductId: pen.ID}}
+	tcs := []struct {
+		name         string
+		userID       int64
+		code         string
+		wantCode     codes.Code
+		wantDiscount int64
+		wantTotal    int64
+	}{
+		{name: "percentage", code: "tenoff", wantDiscount: 300, wantTotal: 3470},
+		{name: "fixed", code: "FIVER", wantDiscount: 500, wantTotal: 3250},
+		{name: "fixed above subtotal", code: "BIGFIXED", wantDiscount: 3000, wantTotal: 500},
+		{name: "parent category", code: "BOOKS", wantDiscount: 1000, wantTotal: 2700},
+		{name: "product", code: "PENS", wantDiscount: 500, wantTotal: 3250},
+		{name: "unknown", code: "NOPE", wantCode: codes.NotFound},
+		{name: "expired", code: "EXPIRED", wantCode: codes.FailedPrecondition},
+		{name: "first use", code: "ONCE", wantDiscount: 300, wantTotal: 3470},
+		{name: "global limit", userID: other.ID, code: "ONCE", wantCode: codes.FailedPrecondition},
+		{name: "first use by user", code: "ONCEEACH", wantDiscount: 300, wantTotal: 3470},
+		{name: "per user limit", code: "ONCEEACH", wantCode: codes.FailedPrecondition},
+		{name: "other user", userID: other.ID, code: "ONCEEACH", wantDiscount: 300, wantTotal: 3470},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			userID := tc.userID
+			if userID == 0 {
+				userID = u.ID
+			}
+			res, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: userID, CouponCode: tc.code, Items: items})
+			if tc.wantCode != codes.OK {
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantDiscount, res.GetDiscountPrice())
+			require.Equal(t, tc.wantTotal, res.GetTotalPrice())
+
+			o, err := st.GetOrder(ctx, res.GetId())
+			require.NoError(t, err)
+			require.NotNil(t, o.CouponCode)
+			require.Equal(t, strings.ToUpper(tc.code), *o.CouponCode)
+		})
+	}
+
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.ID, CouponCode: "FIVER", Items: items[1:]})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "below the minimum order value")
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.ID, CouponCode: "BOOKS", Items: items[1:]})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "no eligible item")
+}
+
+func TestCoupons(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	fixed := pb.CouponKind_FIXED
+	tcs := []struct {
+		name     string
+		req      *pb.CouponReq
+		wantCode codes.Code
+	}{
+		{name: "success", req: &pb.CouponReq{Code: " spring ", Value: 1500}},
+		{name: "duplicate code", req: &pb.CouponReq{Code: "SPRING", Value: 1500}, wantCode: codes.AlreadyExists},
+		{name: "missing code", req: &pb.CouponReq{Value: 1500}, wantCode: codes.InvalidArgument},
+		{name: "percentage over 100", req: &pb.CouponReq{Code: "FREE", Value: 15000}, wantCode: codes.InvalidArgument},
+		{name: "fixed over 100", req: &pb.CouponReq{Code: "FREE", Kind: &fixed, Value: 15000}},
+		{name: "negative limit", req: &pb.CouponReq{Code: "LIMIT", Value: 1000, MaxUses: -1}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.CreateCoupon(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	res, err := srv.ListCoupons(ctx, &pb.ListCouponsReq{SortBy: "code"})
+	require.NoError(t, err)
+	require.Len(t, res.GetCoupons(), 2)
+	require.Equal(t, "FREE", res.GetCoupons()[0].GetCode())
+	spring := res.GetCoupons()[1]
+	require.Equal(t, "SPRING", spring.GetCode())
+	require.Equal(t, pb.CouponKind_PERCENTAGE, spring.GetKind())
+
+	updated, err := srv.UpdateCoupon(ctx, &pb.CouponReq{Id: spring.GetId(), Kind: &fixed, MaxUsesPerUser: 2})
+	require.NoError(t, err)
+	require.Equal(t, pb.CouponKind_FIXED, updated.GetKind())
+	require.Equal(t, int64(1500), updated.GetValue())
+	require.Equal(t, int64(2), updated.GetMaxUsesPerUser())
+	_, err = srv.UpdateCoupon(ctx, &pb.CouponReq{Id: spring.GetId(), Code: "free"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = srv.UpdateCoupon(ctx, &pb.CouponReq{Id: spring.GetId(), CategoryId: 42})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "unknown category")
+
+	_, err = srv.DeleteCoupon(ctx, &pb.CouponReq{Id: spring.GetId()})
+	require.NoError(t, err)
+	_, err = srv.GetCoupon(ctx, &pb.CouponReq{Id: spring.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.UpdateCoupon(ctx, &pb.CouponReq{Id: spring.GetId(), Value: 100})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	books, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: " Books & Comics "})
+	require.NoError(t, err)
+	require.Equal(t, "Books & Comics", books.GetName())
+	require.Equal(t, "books-comics", books.GetSlug(), "slugs default to the name")
+	fantasy, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Fantasy", ParentId: &books.Id})
+	require.NoError(t, err)
+	epic, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Epic fantasy", Slug: "epic", ParentId: &fantasy.Id})
+	require.NoError(t, err)
+	games, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Games"})
+	require.NoError(t, err)
+
+	unknown := int64(42)
+	tcs := []struct {
+		name     string
+		req      *pb.CategoryReq
+		wantCode codes.Code
+	}{
+		{name: "missing name", req: &pb.CategoryReq{Slug: "nameless"}, wantCode: codes.InvalidArgument},
+		{name: "invalid slug", req: &pb.CategoryReq{Name: "Puzzles", Slug: "Puzzles!"}, wantCode: codes.InvalidArgument},
+		{name: "unknown parent", req: &pb.CategoryReq{Name: "Puzzles", ParentId: &unknown}, wantCode: codes.InvalidArgument},
+		{name: "duplicate slug", req: &pb.CategoryReq{Name: "Games"}, wantCode: codes.AlreadyExists},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.CreateCategory(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	got, err := srv.GetCategory(ctx, &pb.CategoryReq{Slug: "epic"})
+	require.NoError(t, err)
+	require.Equal(t, epic.GetId(), got.GetId())
+	require.Equal(t, fantasy.GetId(), got.GetParentId())
+	_, err = srv.GetCategory(ctx, &pb.CategoryReq{Slug: "puzzles"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.UpdateCategory(ctx, &pb.CategoryReq{Id: books.GetId(), ParentId: &epic.Id})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "a category cannot be moved below itself")
+	root := int64(0)
+	updated, err := srv.UpdateCategory(ctx, &pb.CategoryReq{Id: epic.GetId(), Name: "Epic", ParentId: &root})
+	require.NoError(t, err)
+	require.Equal(t, "Epic", updated.GetName())
+	require.Equal(t, "epic", updated.GetSlug())
+	require.Zero(t, updated.GetParentId())
+	_, err = srv.UpdateCategory(ctx, &pb.CategoryReq{Id: epic.GetId(), ParentId: &fantasy.Id})
+	require.NoError(t, err)
+
+	for name, categoryID := range map[string]int64{"hobbit": fantasy.GetId(), "dune": epic.GetId(), "chess": games.GetId()} {
+		_, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: name, CategoryId: categoryID})
+		require.NoError(t, err)
+	}
+	_, err = srv.CreateProduct(ctx, &pb.ProductReq{Name: "ghost", CategoryId: unknown})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	names := func(slug string) []string {
+		res, err := srv.ListProducts(ctx, &pb.ListProductsReq{Category: slug, SortBy: "name"})
+		require.NoError(t, err)
+		var got []string
+		for _, p := range res.GetProducts() {
+			got = append(got, p.GetName())
+		}
+		return got
+	}
+	require.Equal(t, []string{"dune", "hobbit"}, names("books-comics"), "products of subcategories are listed")
+	require.Equal(t, []string{"dune"}, names("epic"))
+	_, err = srv.ListProducts(ctx, &pb.ListProductsReq{Category: "puzzles"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	res, err := srv.ListCategories(ctx, &pb.ListCategoriesReq{})
+	require.NoError(t, err)
+	require.Len(t, res.GetCategories(), 4)
+
+	_, err = srv.DeleteCategory(ctx, &pb.CategoryReq{Id: books.GetId()})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestVariants(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "T-shirt", Price: 1500, CountInStock: 100})
+	require.NoError(t, err)
+
+	xl := int64(1800)
+	small, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Sku: " TEE-RED-S ", Options: map[string]string{"size": "S", "color": "red"}, CountInStock: proto.Int64(2)})
+	require.NoError(t, err)
+	require.Equal(t, "TEE-RED-S", small.GetSku())
+	large, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-RED-XL", Options: map[string]string{"size": "XL", "color": "red"}, Price: &xl, CountInStock: proto.Int64(3)})
+	require.NoError(t, err)
+
+	unknown := int64(42)
+	tcs := []struct {
+		name     string
+		req      *pb.VariantReq
+		wantCode codes.Code
+	}{
+		{name: "unknown product", req: &pb.VariantReq{ProductId: unknown, Sku: "GHOST", Options: map[string]string{"size": "M"}}, wantCode: codes.NotFound},
+		{name: "missing sku", req: &pb.VariantReq{ProductId: p.GetId(), Options: map[string]string{"size": "M"}}, wantCode: codes.InvalidArgument},
+		{name: "no options", req: &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE"}, wantCode: codes.InvalidArgument},
+		{name: "negative stock", req: &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-M", Options: map[string]string{"size": "M"}, CountInStock: proto.Int64(-1)}, wantCode: codes.InvalidArgument},
+		{name: "duplicate sku", req: &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-RED-S", Options: map[string]string{"size": "M"}}, wantCode: codes.AlreadyExists},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.CreateVariant(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	got, err := srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), got.GetCountInStock(), "the stock of a product is the stock of its variants")
+	require.Len(t, got.GetVariants(), 2)
+	require.Nil(t, got.GetVariants()[0].Price, "small costs the price of the product")
+	require.Equal(t, xl, got.GetVariants()[1].GetPrice())
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: p.GetId(), CountInStock: 10})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	order := func(items ...*pb.OrderItem) (*pb.OrderRes, error) {
+		return srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), Items: items})
+	}
+	_, err = order(&pb.OrderItem{ProductId: p.GetId(), Quantity: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "orders must name a variant")
+	_, err = order(&pb.OrderItem{ProductId: p.GetId(), VariantId: unknown, Quantity: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = order(&pb.OrderItem{ProductId: p.GetId(), VariantId: small.GetId(), Quantity: 3})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "the product has 5 in stock, the variant 2")
+
+	or, err := order(
+		&pb.OrderItem{ProductId: p.GetId(), VariantId: small.GetId(), Quantity: 1},
+		&pb.OrderItem{ProductId: p.GetId(), VariantId: large.GetId(), Quantity: 2},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "T-shirt (red, S)", or.GetItems()[0].GetName())
+	require.Equal(t, int64(1500), or.GetItems()[0].GetPrice())
+	require.Equal(t, large.GetId(), or.GetItems()[1].GetVariantId())
+	require.Equal(t, xl, or.GetItems()[1].GetPrice())
+
+	got, err = srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.GetCountInStock())
+	require.Equal(t, int64(1), got.GetVariants()[0].GetCountInStock())
+	require.Equal(t, int64(1), got.GetVariants()[1].GetCountInStock())
+
+	_, err = srv.DeleteVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Id: large.GetId()})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "large was ordered")
+	_, err = srv.CancelOrder(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()})
+	require.NoError(t, err)
+	got, err = srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), got.GetCountInStock(), "cancelled orders are back in stock")
+	require.Equal(t, int64(3), got.GetVariants()[1].GetCountInStock())
+
+	zero := int64(0)
+	updated, err := srv.UpdateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Id: large.GetId(), Price: &zero, CountInStock: proto.Int64(0)})
+	require.NoError(t, err)
+	require.Nil(t, updated.Price, "a price of 0 drops the override")
+	require.Zero(t, updated.GetCountInStock())
+	require.Equal(t, "TEE-RED-XL", updated.GetSku())
+	_, err = srv.UpdateVariant(ctx, &pb.VariantReq{ProductId: unknown, Id: large.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	medium, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-RED-M", Options: map[string]string{"size": "M", "color": "red"}, CountInStock: proto.Int64(4)})
+	require.NoError(t, err)
+	_, err = srv.DeleteVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Id: medium.GetId()})
+	require.NoError(t, err)
+	got, err = srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Len(t, got.GetVariants(), 2)
+	require.Equal(t, int64(2), got.GetCountInStock())
+}
+
+func TestCurrencies(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	rates := money.NewStaticRates("USD", map[string]money.Rate{"EUR": 800000, "GBP": 500000})
+	srv := NewServer(st, WithRateProvider(rates), WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500}))
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	usd, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "usd product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+	require.Equal(t, "USD", usd.GetCurrency())
+	eur, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "eur product", Price: 800, Currency: "eur", CountInStock: 5})
+	require.NoError(t, err)
+	require.Equal(t, "EUR", eur.GetCurrency())
+	_, err = srv.CreateProduct(ctx, &pb.ProductReq{Name: "jpy product", Price: 800, Currency: "JPY"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	tcs := []struct {
+		name         string
+		display      string
+		wantPrice    int64
+		wantCurrency string
+		wantCode     codes.Code
+	}{
+		{name: "own currency", wantPrice: 800, wantCurrency: "EUR"},
+		{name: "store currency", display: "usd", wantPrice: 1000, wantCurrency: "USD"},
+		{name: "cross rate", display: "GBP", wantPrice: 500, wantCurrency: "GBP"},
+		{name: "unknown currency", display: "JPY", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := srv.GetProduct(ctx, &pb.ProductReq{Id: eur.GetId(), DisplayCurrency: tc.display})
+			require.Equal(t, tc.wantCode, status.Code(err))
+			if tc.wantCode != codes.OK {
+				return
+			}
+			require.Equal(t, tc.wantPrice, res.GetPrice())
+			require.Equal(t, tc.wantCurrency, res.GetCurrency())
+		})
+	}
+
+	list, err := srv.ListProducts(ctx, &pb.ListProductsReq{DisplayCurrency: "EUR"})
+	require.NoError(t, err)
+	for _, p := range list.GetProducts() {
+		require.Equal(t, int64(800), p.GetPrice())
+		require.Equal(t, "EUR", p.GetCurrency())
+	}
+
+	for _, p := range []*pb.ProductRes{usd, eur} {
+		_, err = srv.AddCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), Quantity: 1})
+		require.NoError(t, err)
+	}
+	cart, err := srv.GetCart(ctx, &pb.CartReq{UserId: u.ID, DisplayCurrency: "EUR"})
+	require.NoError(t, err)
+	require.Equal(t, "EUR", cart.GetCurrency())
+	require.Equal(t, int64(1600), cart.GetSubtotal())
+	require.Equal(t, int64(160), cart.GetTaxPrice())
+	require.Equal(t, int64(400), cart.GetShippingPrice())
+	require.Equal(t, int64(2160), cart.GetTotalPrice())
+
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.ID, Currency: "EUR", TotalPrice: 2700, Items: []*pb.OrderItem{{ProductId: usd.GetId(), Quantity: 1}, {ProductId: eur.GetId(), Quantity: 1}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "total sent in the store currency")
+
+	order, err := srv.Checkout(ctx, &pb.CheckoutReq{UserId: u.ID, Currency: "eur"})
+	require.NoError(t, err)
+	require.Equal(t, "EUR", order.GetCurrency())
+	require.Equal(t, int64(800000), order.GetExchangeRate())
+	require.Equal(t, int64(800), order.GetItems()[0].GetPrice())
+	require.Equal(t, int64(2160), order.GetTotalPrice())
+
+	stored, err := st.GetOrder(ctx, order.GetId())
+	require.NoError(t, err)
+	require.Equal(t, money.Rate(800000), stored.ExchangeRate)
+}
+
+func TestCart(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	srv := NewServer(st, WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500}))
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	p1, err := st.CreateProduct(ctx, &storer.Product{Name: "product 1", Price: 1000, CountInStock: 3})
+	require.NoError(t, err)
+	p2, err := st.CreateProduct(ctx, &storer.Product{Name: "product 2", Price: 2000, CountInStock: 5})
+	require.NoError(t, err)
+
+	_, err = srv.Checkout(ctx, &pb.CheckoutReq{UserId: u.ID})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "cart is empty")
+
+	tcs := []struct {
+		name     string
+		req      *pb.CartItemReq
+		wantCode codes.Code
+	}{
+		{
+			name: "add",
+			req:  &pb.CartItemReq{UserId: u.ID, ProductId: p1.ID, Quantity: 2},
+		},
+		{
+			name:     "more than in stock",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: p1.ID, Quantity: 2},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "add again",
+			req:  &pb.CartItemReq{UserId: u.ID, ProductId: p1.ID, Quantity: 1},
+		},
+		{
+			name: "other product",
+			req:  &pb.CartItemReq{UserId: u.ID, ProductId: p2.ID, Quantity: 1},
+		},
+		{
+			name:     "unknown product",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: 42, Quantity: 1},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "invalid quantity",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: p2.ID},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.AddCartItem(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	cart, err := srv.GetCart(ctx, &pb.CartReq{UserId: u.ID})
+	require.NoError(t, err)
+	require.Len(t, cart.GetItems(), 2)
+	require.Equal(t, int64(3), cart.GetItems()[0].GetQuantity())
+	require.Equal(t, int64(5000), cart.GetSubtotal())
+	require.Equal(t, int64(6000), cart.GetTotalPrice())
+
+	_, err = srv.UpdateCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p2.ID, Quantity: 6})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	cart, err = srv.UpdateCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p2.ID, Quantity: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), cart.GetItems()[1].GetQuantity())
+
+	p2.Price = 2500
+	_, err = st.UpdateProduct(ctx, p2)
+	require.NoError(t, err)
+	cart, err = srv.GetCart(ctx, &pb.CartReq{UserId: u.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2500), cart.GetItems()[1].GetPrice(), "carts show current prices")
+
+	or, err := srv.Checkout(ctx, &pb.CheckoutReq{UserId: u.ID, UserEmail: u.Email, PaymentMethod: "card"})
+	require.NoError(t, err)
+	require.Len(t, or.GetItems(), 2)
+	require.Equal(t, int64(8000), or.GetItems()[0].GetPrice()*3+or.GetItems()[1].GetPrice()*2)
+	require.Equal(t, int64(9300), or.GetTotalPrice())
+
+	cart, err = srv.GetCart(ctx, &pb.CartReq{UserId: u.ID})
+	require.NoError(t, err)
+	require.Empty(t, cart.GetItems())
+	got, err := st.GetProduct(ctx, p1.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.CountInStock)
+
+	_, err = srv.RemoveCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p1.ID})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCartVariants(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "T-shirt", Price: 1500, CountInStock: 100})
+	require.NoError(t, err)
+	xl := int64(1800)
+	small, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-S", Options: map[string]string{"size": "S"}, CountInStock: proto.Int64(2)})
+	require.NoError(t, err)
+	large, err := srv.CreateVariant(ctx, &pb.VariantReq{ProductId: p.GetId(), Sku: "TEE-XL", Options: map[string]string{"size": "XL"}, Price: &xl, CountInStock: proto.Int64(3)})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		req      *pb.CartItemReq
+		wantCode codes.Code
+	}{
+		{
+			name:     "no variant",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), Quantity: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown variant",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: 42, Quantity: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "small",
+			req:  &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: small.GetId(), Quantity: 2},
+		},
+		{
+			name:     "more small than in stock",
+			req:      &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: small.GetId(), Quantity: 1},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "large",
+			req:  &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: large.GetId(), Quantity: 1},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.AddCartItem(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	cart, err := srv.GetCart(ctx, &pb.CartReq{UserId: u.ID})
+	require.NoError(t, err)
+	require.Len(t, cart.GetItems(), 2)
+	require.Equal(t, small.GetId(), cart.GetItems()[0].GetVariantId())
+	require.Equal(t, map[string]string{"size": "S"}, cart.GetItems()[0].GetOptions())
+	require.Equal(t, int64(1800), cart.GetItems()[1].GetPrice(), "variants override the price")
+	require.Equal(t, int64(4800), cart.GetSubtotal())
+
+	_, err = srv.UpdateCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: large.GetId(), Quantity: 4})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = srv.UpdateCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: large.GetId(), Quantity: 3})
+	require.NoError(t, err)
+
+	or, err := srv.Checkout(ctx, &pb.CheckoutReq{UserId: u.ID, UserEmail: u.Email, PaymentMethod: "card"})
+	require.NoError(t, err)
+	require.Len(t, or.GetItems(), 2)
+	require.Equal(t, large.GetId(), or.GetItems()[1].GetVariantId())
+	require.Equal(t, int64(1800), or.GetItems()[1].GetPrice())
+
+	got, err := srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Len(t, got.GetVariants(), 2)
+	for _, v := range got.GetVariants() {
+		require.Zero(t, v.GetCountInStock(), v.GetSku())
+	}
+
+	_, err = srv.RemoveCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p.GetId(), VariantId: small.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGuestCart(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+
+	cart, err := srv.GetCart(ctx, &pb.CartReq{})
+	require.NoError(t, err)
+	require.Empty(t, cart.GetItems())
+	require.Empty(t, cart.GetCartToken())
+
+	cart, err = srv.AddCartItem(ctx, &pb.CartItemReq{ProductId: p.ID, Quantity: 3})
+	require.NoError(t, err)
+	token := cart.GetCartToken()
+	require.NotEmpty(t, token, "guests get a cart token")
+
+	cart, err = srv.AddCartItem(ctx, &pb.CartItemReq{CartToken: token, ProductId: p.ID, Quantity: 1})
+	require.NoError(t, err)
+	require.Equal(t, token, cart.GetCartToken())
+	require.Equal(t, int64(4), cart.GetItems()[0].GetQuantity())
+
+	_, err = srv.AddCartItem(ctx, &pb.CartItemReq{UserId: u.ID, ProductId: p.ID, Quantity: 3})
+	require.NoError(t, err)
+
+	cart, err = srv.MergeCart(ctx, &pb.MergeCartReq{UserId: u.ID, CartToken: token})
+	require.NoError(t, err)
+	require.Empty(t, cart.GetCartToken())
+	require.Len(t, cart.GetItems(), 1)
+	require.Equal(t, int64(5), cart.GetItems()[0].GetQuantity(), "merged quantities are limited to the stock")
+
+	cart, err = srv.GetCart(ctx, &pb.CartReq{CartToken: token})
+	require.NoError(t, err)
+	require.Empty(t, cart.GetItems())
+
+	_, err = srv.MergeCart(ctx, &pb.MergeCartReq{CartToken: token})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestOrderTransitions(t *testing.T) {
+	for from, tos := range orderTransitions {
+		for _, to := range tos {
+			_, ok := orderTransitions[to]
+			require.True(t, ok, "%s -> %s leads to a status without transitions", from, to)
+		}
+	}
+	require.Empty(t, orderTransitions[storer.Refunded])
+}
+
+// racingStorer moves an order to status right after the server reads it, as
+// a concurrent request would.
+type racingStorer struct {
+	*storer.MemoryStorer
+	orderID int64
+	status  storer.OrderStatus
+}
+
+func (rs *racingStorer) GetOrderStatusByID(ctx context.Context, id int64) (*storer.Order, error) {
+	o, err := rs.MemoryStorer.GetOrderStatusByID(ctx, id)
+	if err != nil || id != rs.orderID {
+		return o, err
+	}
+
+	rs.orderID = 0
+	from := o.Status
+	_, err = rs.MemoryStorer.UpdateOrderStatus(ctx, &storer.OrderStatusChange{OrderID: id, FromStatus: &from, ToStatus: rs.status})
+	return o, err
+}
+
+// flakyGateway fails refunds while failRefunds is set.
+type flakyGateway struct {
+	*payment.Fake
+	failRefunds bool
+}
+
+func (fg *flakyGateway) Refund(ctx context.Context, ref string, amount money.Amount) (*payment.Result, error) {
+	if fg.failRefunds {
+		return nil, fmt.Errorf("gateway unavailable")
+	}
+	return fg.Fake.Refund(ctx, ref, amount)
+}
+
+func TestPayments(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	gateway := payment.NewFake([]byte("secret"), "")
+	srv := NewServer(st, WithPaymentGateway(gateway))
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	admin, err := srv.CreateUser(ctx, &pb.UserReq{Email: "admin@example.com", IsAdmin: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 10})
+	require.NoError(t, err)
+	newOrder := func(t *testing.T) *pb.OrderRes {
+		or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.GetId(), UserEmail: u.GetEmail(), PaymentMethod: "card", Items: []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}}})
+		require.NoError(t, err)
+		return or
+	}
+	orderStatus := func(t *testing.T, id int64) pb.OrderStatus {
+		or, err := srv.GetOrder(ctx, &pb.OrderReq{Id: id, IsAdmin: true})
+		require.NoError(t, err)
+		return or.GetStatus()
+	}
+	webhook := func(t *testing.T, ref string) (*pb.PaymentRes, error) {
+		payload, signature, err := gateway.CompleteAction(ref)
+		require.NoError(t, err)
+		return srv.HandlePaymentWebhook(ctx, &pb.PaymentWebhookReq{Payload: payload, Signature: signature})
+	}
+	providerRef := func(t *testing.T, pr *pb.PaymentRes) string {
+		payments, err := st.ListOrderPayments(ctx, pr.GetOrderId())
+		require.NoError(t, err)
+		return payments[len(payments)-1].ProviderRef
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := NewServer(st).CreatePayment(ctx, &pb.PaymentReq{OrderId: newOrder(t).GetId(), UserId: u.GetId()})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("captured", func(t *testing.T) {
+		or := newOrder(t)
+		_, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: admin.GetId()})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_CAPTURED, pr.GetStatus())
+		require.Equal(t, or.GetTotalPrice(), pr.GetAmount())
+		require.Equal(t, pb.OrderStatus_PAID, orderStatus(t, or.GetId()))
+
+		_, err = srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err), "order is already paid")
+
+		history, err := srv.ListOrderStatusHistory(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, int64(systemUserID), history.GetChanges()[1].GetChangedBy())
+	})
+
+	t.Run("declined then retried", func(t *testing.T) {
+		or := newOrder(t)
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId(), Method: payment.FakeDecline})
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_DECLINED, pr.GetStatus())
+		require.NotEmpty(t, pr.GetDeclineReason())
+		require.Equal(t, pb.OrderStatus_PENDING, orderStatus(t, or.GetId()))
+
+		pr, err = srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_CAPTURED, pr.GetStatus())
+	})
+
+	t.Run("3-D Secure", func(t *testing.T) {
+		or := newOrder(t)
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId(), Method: payment.FakeThreeDS})
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_ACTION_REQUIRED, pr.GetStatus())
+		require.NotEmpty(t, pr.GetNextActionUrl())
+		require.Equal(t, pb.OrderStatus_PENDING, orderStatus(t, or.GetId()))
+
+		payload, signature, err := gateway.CompleteAction(providerRef(t, pr))
+		require.NoError(t, err)
+		_, err = srv.HandlePaymentWebhook(ctx, &pb.PaymentWebhookReq{Payload: payload, Signature: "sha256=00"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		pr, err = srv.HandlePaymentWebhook(ctx, &pb.PaymentWebhookReq{Payload: payload, Signature: signature})
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_CAPTURED, pr.GetStatus())
+		require.Equal(t, pb.OrderStatus_PAID, orderStatus(t, or.GetId()))
+
+		pr, err = srv.HandlePaymentWebhook(ctx, &pb.PaymentWebhookReq{Payload: payload, Signature: signature})
+		require.NoError(t, err, "redelivered webhook")
+		require.Equal(t, pb.PaymentStatus_PAYMENT_CAPTURED, pr.GetStatus())
+	})
+
+	t.Run("3-D Secure failed", func(t *testing.T) {
+		or := newOrder(t)
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId(), Method: payment.FakeThreeDSFail})
+		require.NoError(t, err)
+
+		pr, err = webhook(t, providerRef(t, pr))
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_DECLINED, pr.GetStatus())
+		require.Equal(t, pb.OrderStatus_PENDING, orderStatus(t, or.GetId()))
+	})
+
+	t.Run("cancelled while pending", func(t *testing.T) {
+		or := newOrder(t)
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId(), Method: payment.FakeThreeDS})
+		require.NoError(t, err)
+		_, err = srv.CancelOrder(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+
+		pr, err = webhook(t, providerRef(t, pr))
+		require.NoError(t, err)
+		require.Equal(t, pb.PaymentStatus_PAYMENT_REFUNDED, pr.GetStatus())
+		require.Equal(t, pb.OrderStatus_CANCELLED, orderStatus(t, or.GetId()))
+	})
+
+	t.Run("refunded by admin", func(t *testing.T) {
+		or := newOrder(t)
+		pr, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+
+		_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+		require.NoError(t, err)
+
+		payments, err := st.ListOrderPayments(ctx, pr.GetOrderId())
+		require.NoError(t, err)
+		require.Equal(t, storer.PaymentRefunded, payments[0].Status)
+	})
+
+	t.Run("refund conflicting with another change", func(t *testing.T) {
+		or := newOrder(t)
+		_, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+
+		racing := NewServer(&racingStorer{MemoryStorer: st, orderID: or.GetId(), status: storer.Processing}, WithPaymentGateway(gateway))
+		_, err = racing.UpdateOrderStatus(ctx, &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_REFUNDED})
+		require.Equal(t, codes.Aborted, status.Code(err))
+		require.Equal(t, pb.OrderStatus_PROCESSING, orderStatus(t, or.GetId()))
+
+		payments, err := st.ListOrderPayments(ctx, or.GetId())
+		require.NoError(t, err)
+		require.Equal(t, storer.PaymentCaptured, payments[0].Status, "payments of orders moved concurrently are not refunded")
+	})
+
+	t.Run("refund retried", func(t *testing.T) {
+		or := newOrder(t)
+		_, err := srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.GetId()})
+		require.NoError(t, err)
+
+		flaky := &flakyGateway{Fake: gateway, failRefunds: true}
+		fsrv := NewServer(st, WithPaymentGateway(flaky))
+		refund := &pb.OrderReq{Id: or.GetId(), UserId: admin.GetId(), IsAdmin: true, Status: pb.OrderStatus_REFUNDED}
+		_, err = fsrv.UpdateOrderStatus(ctx, refund)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, pb.OrderStatus_REFUNDED, orderStatus(t, or.GetId()))
+		payments, err := st.ListOrderPayments(ctx, or.GetId())
+		require.NoError(t, err)
+		require.Equal(t, storer.PaymentCaptured, payments[0].Status)
+
+		flaky.failRefunds = false
+		_, err = fsrv.UpdateOrderStatus(ctx, refund)
+		require.NoError(t, err)
+		payments, err = st.ListOrderPayments(ctx, or.GetId())
+		require.NoError(t, err)
+		require.Equal(t, storer.PaymentRefunded, payments[0].Status)
+	})
+}
+
+func TestPaymentTransitions(t *testing.T) {
+	for from, tos := range paymentTransitions {
+		for _, to := range tos {
+			_, ok := paymentTransitions[to]
+			require.True(t, ok, "%s -> %s leads to a status without transitions", from, to)
+		}
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	other, err := srv.CreateUser(ctx, &pb.UserReq{Email: "other@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 5})
+	require.NoError(t, err)
+
+	newOrder := func(userID, addressID int64) (*pb.OrderRes, error) {
+		return srv.CreateOrder(ctx, &pb.OrderReq{
+			UserId:    userID,
+			AddressId: addressID,
+			Items:     []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}},
+		})
+	}
+
+	or, err := newOrder(u.GetId(), 0)
+	require.NoError(t, err)
+	require.Nil(t, or.GetShippingAddress(), "no address book")
+
+	_, err = srv.CreateAddress(ctx, &pb.AddressReq{UserId: u.GetId(), Name: "Test User", Line1: "1 Test Street", City: "Testville", Country: "France"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	home, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: u.GetId(), Name: "Test User", Line1: "1 Test Street", City: "Testville", Country: "fr"})
+	require.NoError(t, err)
+	require.True(t, home.GetIsDefault(), "the first address is the default one")
+	require.Equal(t, "FR", home.GetCountry())
+	work, err := srv.CreateAddress(ctx, &pb.AddressReq{UserId: u.GetId(), Name: "Test User", Line1: "2 Work Avenue", City: "Worktown", Country: "DE"})
+	require.NoError(t, err)
+	require.False(t, work.GetIsDefault())
+
+	_, err = srv.GetAddress(ctx, &pb.AddressReq{Id: home.GetId(), UserId: other.GetId()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = newOrder(other.GetId(), home.GetId())
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	or, err = newOrder(u.GetId(), 0)
+	require.NoError(t, err)
+	require.Equal(t, "1 Test Street", or.GetShippingAddress().GetLine1(), "orders ship to the default address")
+	or, err = newOrder(u.GetId(), work.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "DE", or.GetShippingAddress().GetCountry())
+
+	_, err = srv.UpdateAddress(ctx, &pb.AddressReq{Id: work.GetId(), UserId: u.GetId(), Line1: "3 New Road", IsDefault: proto.Bool(true)})
+	require.NoError(t, err)
+	got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "2 Work Avenue", got.GetShippingAddress().GetLine1(), "orders keep the address they were placed with")
+
+	addresses, err := srv.ListAddresses(ctx, &pb.AddressReq{UserId: u.GetId()})
+	require.NoError(t, err)
+	require.Len(t, addresses.GetAddresses(), 2)
+	require.Equal(t, work.GetId(), addresses.GetAddresses()[0].GetId())
+	require.False(t, addresses.GetAddresses()[1].GetIsDefault())
+
+	_, err = srv.DeleteAddress(ctx, &pb.AddressReq{Id: work.GetId(), UserId: other.GetId()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.DeleteAddress(ctx, &pb.AddressReq{Id: work.GetId(), UserId: u.GetId()})
+	require.NoError(t, err)
+	_, err = srv.GetAddress(ctx, &pb.AddressReq{Id: work.GetId(), UserId: u.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestShipping(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	rates := money.NewStaticRates("USD", map[string]money.Rate{"EUR": 800000})
+	methods := shipping.Methods{
+		{ID: "standard", Name: "Standard", MinDays: 3, MaxDays: 5, Rate: &shipping.FreeOver{
+			Threshold: 3000,
+			Base:      &shipping.WeightTable{Brackets: []shipping.WeightBracket{{MaxWeight: 2000, Price: 500}, {MaxWeight: 5000, Price: 900}}},
+		}},
+		{ID: "express", Name: "Express", MinDays: 1, MaxDays: 1, Countries: []string{"DE"}, Rate: &shipping.FlatRate{Price: 1500}},
+	}
+	srv := NewServer(st, WithRateProvider(rates), WithPricingPolicy(&FlatPricingPolicy{ShippingPrice: 300}), WithShippingMethods(methods))
+
+	u, err := srv.CreateUser(ctx, &pb.UserReq{Email: "test@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 10, Weight: 1200})
+	require.NoError(t, err)
+
+	newOrder := func(quantity int64, method string) (*pb.OrderRes, error) {
+		return srv.CreateOrder(ctx, &pb.OrderReq{
+			UserId:         u.GetId(),
+			ShippingMethod: method,
+			Items:          []*pb.OrderItem{{Quantity: quantity, ProductId: p.ID}},
+		})
+	}
+
+	_, err = newOrder(1, "")
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "no shipping address")
+	_, err = srv.CreateAddress(ctx, &pb.AddressReq{UserId: u.GetId(), Name: "Test User", Line1: "1 Test Street", City: "Testville", Country: "FR"})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		quantity int64
+		method   string
+		code     codes.Code
+		want     string
+		shipping money.Amount
+	}{
+		{name: "cheapest by default", quantity: 1, want: "standard", shipping: 500},
+		{name: "chosen method", quantity: 1, method: "standard", want: "standard", shipping: 500},
+		{name: "heavier bracket", quantity: 2, want: "standard", shipping: 900},
+		{name: "free over threshold", quantity: 3, want: "standard", shipping: 0},
+		{name: "method not shipping to the address", quantity: 1, method: "express", code: codes.FailedPrecondition},
+		{name: "unknown method", quantity: 1, method: "drone", code: codes.InvalidArgument},
+		{name: "over the weight limit", quantity: 5, code: codes.FailedPrecondition},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			or, err := newOrder(tc.quantity, tc.method)
+			require.Equal(t, tc.code, status.Code(err))
+			if tc.code != codes.OK {
+				return
+			}
+			require.Equal(t, tc.want, or.GetShippingMethod())
+			require.Equal(t, int64(tc.shipping), or.GetShippingPrice())
+			require.Equal(t, 1000*tc.quantity+int64(tc.shipping), or.GetTotalPrice())
+		})
+	}
+
+	_, err = srv.CreateOrder(ctx, &pb.OrderReq{
+		UserId:        u.GetId(),
+		ShippingPrice: 300,
+		Items:         []*pb.OrderItem{{Quantity: 1, ProductId: p.ID}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "client shipping price is checked against the method")
+
+	_, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: u.GetId()})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "empty cart")
+
+	cart, err := srv.AddCartItem(ctx, &pb.CartItemReq{ProductId: p.ID, Quantity: 1})
+	require.NoError(t, err)
+	_, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{CartToken: cart.GetCartToken(), Country: "Germany"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	quote, err := srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{CartToken: cart.GetCartToken(), Country: "de", DisplayCurrency: "eur"})
+	require.NoError(t, err)
+	require.Equal(t, "EUR", quote.GetCurrency())
+	require.Len(t, quote.GetOptions(), 2)
+	require.Equal(t, "standard", quote.GetOptions()[0].GetMethodId())
+	require.Equal(t, int64(400), quote.GetOptions()[0].GetPrice())
+	require.Equal(t, "express", quote.GetOptions()[1].GetMethodId())
+	require.Equal(t, int64(1200), quote.GetOptions()[1].GetPrice())
+
+	_, err = srv.AddCartItem(ctx, &pb.CartItemReq{UserId: u.GetId(), ProductId: p.ID, Quantity: 1})
+	require.NoError(t, err)
+	quote, err = srv.QuoteShipping(ctx, &pb.ShippingQuoteReq{UserId: u.GetId()})
+	require.NoError(t, err)
+	require.Len(t, quote.GetOptions(), 1, "the default address is in FR")
+
+	plain, _ := newTestServer(t)
+	_, err = plain.QuoteShipping(ctx, &pb.ShippingQuoteReq{Country: "DE"})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestTax(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	rates := money.NewStaticRates("USD", map[string]money.Rate{"EUR": 800000})
+	zones := []tax.Zone{
+		{Country: "FR", Rates: map[string]int64{"standard": 2000, "books": 550}},
+		{Country: "US", Region: "CA", Rates: map[string]int64{"standard": 725}},
+	}
+	pricing := WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500})
+
+	fr, err := st.CreateUser(ctx, &storer.User{Email: "fr@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &storer.Address{UserID: fr.ID, Name: "Test User", Line1: "1 Rue de Test", City: "Paris", Country: "FR", IsDefault: true})
+	require.NoError(t, err)
+	ca, err := st.CreateUser(ctx, &storer.User{Email: "ca@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &storer.Address{UserID: ca.ID, Name: "Test User", Line1: "1 Test Street", City: "Testville", Region: "CA", Country: "US", IsDefault: true})
+	require.NoError(t, err)
+	homeless, err := st.CreateUser(ctx, &storer.User{Email: "homeless@example.com"})
+	require.NoError(t, err)
+
+	books, err := st.CreateCategory(ctx, &storer.Category{Name: "Books", Slug: "books"})
+	require.NoError(t, err)
+	book, err := st.CreateProduct(ctx, &storer.Product{Name: "book", CategoryID: &books.ID, TaxCategory: "books", Price: 1000, CountInStock: 100})
+	require.NoError(t, err)
+	gadget, err := st.CreateProduct(ctx, &storer.Product{Name: "gadget", Price: 2000, CountInStock: 100})
+	require.NoError(t, err)
+	_, err = st.CreateCoupon(ctx, &storer.Coupon{Code: "BOOKS", Kind: storer.PercentageCoupon, Value: 1000, CategoryID: &books.ID})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name      string
+		inclusive bool
+		userID    int64
+		coupon    string
+		currency  string
+		code      codes.Code
+		wantRates []int64
+		wantItems []int64
+		wantTax   int64
+		wantTotal int64
+	}{
+		{name: "exclusive", userID: fr.ID, wantRates: []int64{550, 2000}, wantItems: []int64{55, 400}, wantTax: 455, wantTotal: 3955},
+		{name: "discount on the discounted items", userID: fr.ID, coupon: "BOOKS", wantRates: []int64{550, 2000}, wantItems: []int64{50, 400}, wantTax: 450, wantTotal: 3850},
+		{name: "inclusive", inclusive: true, userID: fr.ID, wantRates: []int64{550, 2000}, wantItems: []int64{52, 333}, wantTax: 385, wantTotal: 3500},
+		{name: "converted item by item", userID: fr.ID, currency: "EUR", wantRates: []int64{550, 2000}, wantItems: []int64{44, 320}, wantTax: 364, wantTotal: 3164},
+		{name: "region", userID: ca.ID, wantRates: []int64{725, 725}, wantItems: []int64{73, 145}, wantTax: 218, wantTotal: 3718},
+		{name: "no shipping address", userID: homeless.ID, code: codes.FailedPrecondition},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := NewServer(st, pricing, WithRateProvider(rates), WithTaxTable(&tax.Table{Inclusive: tc.inclusive, Zones: zones}))
+			or, err := srv.CreateOrder(ctx, &pb.OrderReq{
+				UserId:     tc.userID,
+				CouponCode: tc.coupon,
+				Currency:   tc.currency,
+				Items:      []*pb.OrderItem{{Quantity: 1, ProductId: book.ID}, {Quantity: 1, ProductId: gadget.ID}},
+			})
+			require.Equal(t, tc.code, status.Code(err))
+			if tc.code != codes.OK {
+				return
+			}
+
+			require.Equal(t, tc.wantTax, or.GetTaxPrice())
+			require.Equal(t, tc.wantTotal, or.GetTotalPrice())
+			require.Equal(t, tc.inclusive, or.GetTaxInclusive())
+
+			got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: or.GetId(), UserId: tc.userID})
+			require.NoError(t, err)
+			for i, oi := range got.GetItems() {
+				require.Equal(t, tc.wantRates[i], oi.GetTaxRate())
+				require.Equal(t, tc.wantItems[i], oi.GetTaxPrice())
+			}
+		})
+	}
+}
+
+func TestCartCharges(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	methods := shipping.Methods{{ID: "standard", Name: "Standard", Countries: []string{"FR"}, Rate: &shipping.FlatRate{Price: 700}}}
+	zones := []tax.Zone{{Country: "FR", Rates: map[string]int64{"standard": 2000}}}
+	srv := NewServer(st,
+		WithPricingPolicy(&FlatPricingPolicy{TaxRate: 1000, ShippingPrice: 500}),
+		WithShippingMethods(methods),
+		WithTaxTable(&tax.Table{Zones: zones}),
+	)
+
+	fr, err := st.CreateUser(ctx, &storer.User{Email: "fr@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &storer.Address{UserID: fr.ID, Name: "Test User", Line1: "1 Rue de Test", City: "Paris", Country: "FR", IsDefault: true})
+	require.NoError(t, err)
+	de, err := st.CreateUser(ctx, &storer.User{Email: "de@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &storer.Address{UserID: de.ID, Name: "Test User", Line1: "1 Teststraße", City: "Berlin", Country: "DE", IsDefault: true})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "gadget", Price: 2000, CountInStock: 10})
+	require.NoError(t, err)
+
+	cart, err := srv.AddCartItem(ctx, &pb.CartItemReq{UserId: fr.ID, ProductId: p.ID, Quantity: 1})
+	require.NoError(t, err)
+	require.False(t, cart.GetChargesPending())
+	require.Equal(t, int64(400), cart.GetTaxPrice())
+	require.Equal(t, int64(700), cart.GetShippingPrice())
+	require.Equal(t, int64(3100), cart.GetTotalPrice())
+
+	or, err := srv.Checkout(ctx, &pb.CheckoutReq{UserId: fr.ID, UserEmail: fr.Email, PaymentMethod: "card"})
+	require.NoError(t, err)
+	require.Equal(t, cart.GetTaxPrice(), or.GetTaxPrice())
+	require.Equal(t, cart.GetShippingPrice(), or.GetShippingPrice())
+	require.Equal(t, cart.GetTotalPrice(), or.GetTotalPrice(), "carts cost what their checkout charges")
+
+	tcs := []struct {
+		name string
+		req  *pb.CartItemReq
+	}{
+		{name: "guest", req: &pb.CartItemReq{ProductId: p.ID, Quantity: 1}},
+		{name: "no shipping method to the address", req: &pb.CartItemReq{UserId: de.ID, ProductId: p.ID, Quantity: 1}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cart, err := srv.AddCartItem(ctx, tc.req)
+			require.NoError(t, err)
+			require.True(t, cart.GetChargesPending())
+			require.Zero(t, cart.GetShippingPrice())
+			require.Equal(t, cart.GetSubtotal()+cart.GetTaxPrice(), cart.GetTotalPrice())
+		})
+	}
+}
+
+func TestInvoices(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	seller := invoice.Seller{Name: "Test Store", Address: "1 Store Street", TaxID: "FR00123"}
+	srv := NewServer(st,
+		WithPricingPolicy(&FlatPricingPolicy{ShippingPrice: 500}),
+		WithPaymentGateway(payment.NewFake([]byte("secret"), "")),
+		WithInvoiceSeller(seller),
+		WithTaxTable(&tax.Table{Zones: []tax.Zone{{Country: "FR", Rates: map[string]int64{"standard": 2000}}}}),
+	)
+
+	u, err := st.CreateUser(ctx, &storer.User{Name: "Test User", Email: "test@example.com"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &storer.Address{UserID: u.ID, Name: "Test User", Line1: "1 Rue de Test", City: "Paris", Country: "FR", IsDefault: true})
+	require.NoError(t, err)
+	other, err := st.CreateUser(ctx, &storer.User{Email: "other@example.com"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &storer.Product{Name: "test product", Price: 1000, CountInStock: 10})
+	require.NoError(t, err)
+	newOrder := func(t *testing.T) *pb.OrderRes {
+		or, err := srv.CreateOrder(ctx, &pb.OrderReq{UserId: u.ID, Items: []*pb.OrderItem{{Quantity: 2, ProductId: p.ID}}})
+		require.NoError(t, err)
+		return or
+	}
+
+	or := newOrder(t)
+	_, err = srv.GetInvoice(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.ID})
+	require.Equal(t, codes.NotFound, status.Code(err), "unpaid orders have no invoice")
+
+	_, err = srv.CreatePayment(ctx, &pb.PaymentReq{OrderId: or.GetId(), UserId: u.ID})
+	require.NoError(t, err)
+
+	inv, err := srv.GetInvoice(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), inv.GetNumber())
+	require.Equal(t, seller.Name, inv.GetSellerName())
+	require.Equal(t, "Test User", inv.GetBillName())
+	require.Equal(t, "Paris", inv.GetShippingAddress().GetCity())
+	require.Equal(t, int64(2000), inv.GetSubtotal())
+	require.Equal(t, int64(400), inv.GetTaxPrice())
+	require.Equal(t, or.GetTotalPrice(), inv.GetTotalPrice())
+	require.Len(t, inv.GetLines(), 1)
+	require.Equal(t, int64(2000), inv.GetLines()[0].GetTaxRate())
+
+	_, err = srv.GetInvoice(ctx, &pb.OrderReq{Id: or.GetId(), UserId: other.ID})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = srv.GetInvoice(ctx, &pb.OrderReq{Id: or.GetId(), IsAdmin: true})
+	require.NoError(t, err)
+
+	_, err = st.UpdateAddress(ctx, &storer.Address{ID: 1, UserID: u.ID, Name: "Test User", Line1: "2 Rue Neuve", City: "Lyon", Country: "FR", IsDefault: true})
+	require.NoError(t, err)
+	inv, err = srv.GetInvoice(ctx, &pb.OrderReq{Id: or.GetId(), UserId: u.ID})
+	require.NoError(t, err)
+	require.Equal(t, "Paris", inv.GetShippingAddress().GetCity(), "invoices keep the address they were issued with")
+
+	next := newOrder(t)
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: next.GetId(), Status: pb.OrderStatus_PAID, IsAdmin: true})
+	require.NoError(t, err)
+	inv, err = srv.GetInvoice(ctx, &pb.OrderReq{Id: next.GetId(), UserId: u.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), inv.GetNumber(), "numbers follow each other")
+
+	failing := newOrder(t)
+	_, err = st.CreateInvoice(ctx, &storer.Invoice{OrderID: failing.GetId(), UserID: u.ID})
+	require.NoError(t, err)
+	_, err = srv.UpdateOrderStatus(ctx, &pb.OrderReq{Id: failing.GetId(), Status: pb.OrderStatus_PAID, IsAdmin: true})
+	require.ErrorIs(t, err, storer.ErrInvoiceExists)
+	got, err := srv.GetOrder(ctx, &pb.OrderReq{Id: failing.GetId(), IsAdmin: true})
+	require.NoError(t, err)
+	require.Equal(t, pb.OrderStatus_PENDING, got.GetStatus(), "orders are not paid without their invoice")
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+	u, err := st.CreateUser(ctx, &storer.User{Email: "test@example.com"})
+	require.NoError(t, err)
+
+	req := &pb.IdempotencyKeyReq{
+		UserId:      u.ID,
+		Key:         "key",
+		RequestHash: "hash",
+		ExpiresAt:   timestamppb.New(time.Now().Add(time.Hour)),
+	}
+
+	_, err = srv.ReserveIdempotencyKey(ctx, &pb.IdempotencyKeyReq{UserId: u.ID, Key: "key"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	res, err := srv.ReserveIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+	require.True(t, res.GetReserved())
+
+	res, err = srv.ReserveIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+	require.False(t, res.GetReserved(), "reserving a key in progress")
+	require.Zero(t, res.GetStatusCode())
+
+	_, err = srv.CompleteIdempotencyKey(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "completing without a status code")
+
+	req.StatusCode = 201
+	req.ContentType = "application/json"
+	req.ResponseBody = []byte(`{"id":1}`)
+	_, err = srv.CompleteIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+
+	// completed keys survive a release
+	_, err = srv.ReleaseIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+
+	res, err = srv.ReserveIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+	require.False(t, res.GetReserved())
+	require.Equal(t, "hash", res.GetRequestHash())
+	require.EqualValues(t, 201, res.GetStatusCode())
+	require.Equal(t, "application/json", res.GetContentType())
+	require.Equal(t, []byte(`{"id":1}`), res.GetResponseBody())
+}
+
+func TestProductImages(t *testing.T) {
+	ctx := context.Background()
+	st := storer.NewMemoryStorer()
+	dir := t.TempDir()
+	srv := NewServer(st, WithBlobStore(blobstore.NewFS(dir, "/images")))
+
+	p, err := srv.CreateProduct(ctx, &pb.ProductReq{Name: "Lamp", Price: 4000})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 800, 400))))
+	photo := buf.Bytes()
+
+	tcs := []struct {
+		name     string
+		srv      *Server
+		req      *pb.ProductImageReq
+		wantCode codes.Code
+	}{
+		{name: "disabled", srv: NewServer(st), req: &pb.ProductImageReq{ProductId: p.GetId(), Data: photo}, wantCode: codes.Unimplemented},
+		{name: "unknown product", srv: srv, req: &pb.ProductImageReq{ProductId: 42, Data: photo}, wantCode: codes.NotFound},
+		{name: "not an image", srv: srv, req: &pb.ProductImageReq{ProductId: p.GetId(), Data: []byte("<svg></svg>")}, wantCode: codes.InvalidArgument},
+		{name: "truncated image", srv: srv, req: &pb.ProductImageReq{ProductId: p.GetId(), Data: photo[:100]}, wantCode: codes.InvalidArgument},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.srv.AddProductImage(ctx, tc.req)
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	front, err := srv.AddProductImage(ctx, &pb.ProductImageReq{ProductId: p.GetId(), Data: photo})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", front.GetContentType())
+	require.Equal(t, []int64{800, 400}, []int64{front.GetWidth(), front.GetHeight()})
+	require.True(t, strings.HasPrefix(front.GetUrl(), fmt.Sprintf("/images/products/%d/", p.GetId())))
+	back, err := srv.AddProductImage(ctx, &pb.ProductImageReq{ProductId: p.GetId(), Data: photo})
+	require.NoError(t, err)
+	require.NotEqual(t, front.GetUrl(), back.GetUrl())
+
+	thumb, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(front.GetThumbnailUrl(), "/images/")))
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	require.Equal(t, []int{320, 160}, []int{cfg.Width, cfg.Height})
+
+	got, err := srv.GetProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	require.Len(t, got.GetImages(), 2)
+	require.Equal(t, front.GetUrl(), got.GetImage())
+
+	_, err = srv.UpdateProduct(ctx, &pb.ProductReq{Id: p.GetId(), Image: "other.jpg"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "the image of a product with a gallery is its first image")
+
+	_, err = srv.ReorderProductImages(ctx, &pb.ReorderProductImagesReq{ProductId: p.GetId(), ImageIds: []int64{back.GetId()}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	got, err = srv.ReorderProductImages(ctx, &pb.ReorderProductImagesReq{ProductId: p.GetId(), ImageIds: []int64{back.GetId(), front.GetId()}})
+	require.NoError(t, err)
+	require.Equal(t, back.GetUrl(), got.GetImage())
+	require.Equal(t, back.GetId(), got.GetImages()[0].GetId())
+
+	_, err = srv.DeleteProductImage(ctx, &pb.ProductImageReq{ProductId: 42, Id: back.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.DeleteProductImage(ctx, &pb.ProductImageReq{ProductId: p.GetId(), Id: back.GetId()})
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, strings.TrimPrefix(back.GetUrl(), "/images/")))
+	require.ErrorIs(t, err, fs.ErrNotExist, "the blobs of deleted images are deleted")
+
+	_, err = srv.DeleteProduct(ctx, &pb.ProductReq{Id: p.GetId()})
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, strings.TrimPrefix(front.GetThumbnailUrl(), "/images/")))
+	require.ErrorIs(t, err, fs.ErrNotExist, "the blobs of deleted products are deleted")
+}
+
+// importStream streams rows to ImportProducts.
+type importStream struct {
+	grpc.ServerStream
+	rows []*pb.ImportProductsReq
+	res  *pb.ImportProductsRes
+}
+
+func (s *importStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importStream) Recv() (*pb.ImportProductsReq, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	r := s.rows[0]
+	s.rows = s.rows[1:]
+	return r, nil
+}
+
+func (s *importStream) SendAndClose(res *pb.ImportProductsRes) error {
+	s.res = res
+	return nil
+}
+
+func TestImportProducts(t *testing.T) {
+	ctx := context.Background()
+	srv, st := newTestServer(t)
+
+	c, err := srv.CreateCategory(ctx, &pb.CategoryReq{Name: "Furniture", Slug: "furniture"})
+	require.NoError(t, err)
+	lamp, err := srv.CreateProduct(ctx, &pb.ProductReq{Sku: "LAMP", Name: "Lamp", Price: 4000, CountInStock: 3})
+	require.NoError(t, err)
+	_, err = srv.CreateProduct(ctx, &pb.ProductReq{Sku: "LAMP", Name: "Other lamp"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	rows := func(dryRun bool) []*pb.ImportProductsReq {
+		return []*pb.ImportProductsReq{
+			{Row: 2, DryRun: dryRun, Product: &pb.ProductReq{Sku: "DESK", Name: "Desk", Price: 12000, CategoryId: c.GetId()}},
+			{Row: 3, Product: &pb.ProductReq{Sku: " LAMP ", Price: 4500}},
+			{Row: 4, Product: &pb.ProductReq{Name: "No sku"}},
+			{Row: 5, Product: &pb.ProductReq{Sku: "DESK", Name: "Desk again"}},
+			{Row: 6, Product: &pb.ProductReq{Sku: "CHAIR"}},
+			{Row: 7, Product: &pb.ProductReq{Sku: "STOOL", Name: "Stool", Price: -100}},
+			{Row: 8, Product: &pb.ProductReq{Sku: "SOFA", Name: "Sofa", CategoryId: 42}},
+			{Row: 9, Product: &pb.ProductReq{Sku: "BED", Name: "Bed", Currency: "XYZ"}},
+		}
+	}
+	wantErrors := []int64{4, 5, 6, 7, 8, 9}
+
+	stream := &importStream{rows: rows(true)}
+	require.NoError(t, srv.ImportProducts(stream))
+	require.True(t, stream.res.GetDryRun())
+	require.Equal(t, []int64{1, 1}, []int64{stream.res.GetCreated(), stream.res.GetUpdated()})
+	var got []int64
+	for _, e := range stream.res.GetErrors() {
+		got = append(got, e.GetRow())
+	}
+	require.Equal(t, wantErrors, got)
+	require.Equal(t, "DESK", stream.res.GetErrors()[1].GetSku())
+	ps, err := st.GetProductsBySKU(ctx, []string{"DESK", "LAMP"})
+	require.NoError(t, err)
+	require.Len(t, ps, 1, "a dry run saves nothing")
+	require.Equal(t, money.Amount(4000), ps[0].Price)
+
+	stream = &importStream{rows: rows(false)}
+	require.NoError(t, srv.ImportProducts(stream))
+	require.False(t, stream.res.GetDryRun())
+	require.Equal(t, []int64{1, 1}, []int64{stream.res.GetCreated(), stream.res.GetUpdated()})
+	require.Len(t, stream.res.GetErrors(), len(wantErrors))
+	ps, err = st.GetProductsBySKU(ctx, []string{"DESK", "LAMP"})
+	require.NoError(t, err)
+	require.Len(t, ps, 2)
+	require.Equal(t, lamp.GetId(), ps[0].ID)
+	require.Equal(t, money.Amount(4500), ps[0].Price)
+	require.Equal(t, int64(3), ps[0].CountInStock, "empty fields keep their value")
+	require.Equal(t, c.GetId(), *ps[1].CategoryID)
+
+	stream = &importStream{}
+	for i := range 2*importBatchSize + 1 {
+		stream.rows = append(stream.rows, &pb.ImportProductsReq{Product: &pb.ProductReq{Sku: fmt.Sprintf("SKU-%d", i), Name: "Product"}})
+	}
+	require.NoError(t, srv.ImportProducts(stream))
+	require.Equal(t, int64(2*importBatchSize+1), stream.res.GetCreated())
+	require.Empty(t, stream.res.GetErrors())
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/shipping"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QuoteShipping returns the shipping options for the cart of a user or
+// guest, cheapest first, priced in the display currency, the store currency
+// if empty.
+func (s *Server) QuoteShipping(ctx context.Context, r *pb.ShippingQuoteReq) (*pb.ShippingQuoteRes, error) {
+	if s.shipping == nil {
+		return nil, status.Error(codes.Unimplemented, "shipping methods are not enabled")
+	}
+
+	to, err := s.quoteDestination(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.storer.GetCart(ctx, cartOwner(r.GetUserId(), r.GetCartToken()))
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+
+	parcel := &shipping.Parcel{}
+	for _, ci := range cart.Items {
+		p, err := s.storer.GetProduct(ctx, ci.ProductID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "product %d does not exist", ci.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		parcel.Add(p.Weight, p.Length, p.Width, p.Height, ci.Quantity)
+
+		price, err := s.convert(ctx, ci.Price, ci.Currency, money.StoreCurrency)
+		if err != nil {
+			return nil, err
+		}
+		parcel.Value += price.Mul(ci.Quantity)
+	}
+
+	options, err := s.shipping.Quote(ctx, parcel, to)
+	if err != nil {
+		return nil, err
+	}
+
+	display := currencyCode(r.GetDisplayCurrency(), money.StoreCurrency)
+	rate, err := s.exchangeRate(ctx, money.StoreCurrency, display)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ShippingQuoteRes{Options: make([]*pb.ShippingOption, 0, len(options)), Currency: display}
+	for _, o := range options {
+		so := toPBShippingOption(o)
+		so.Price = int64(o.Price.Convert(rate))
+		res.Options = append(res.Options, so)
+	}
+
+	return res, nil
+}
+
+// quoteDestination returns where a quote ships to: the destination sent by
+// the client, or else an address from the address book of the user, the
+// default one if the request names none.
+func (s *Server) quoteDestination(ctx context.Context, r *pb.ShippingQuoteReq) (*shipping.Destination, error) {
+	if r.GetUserId() != 0 && (r.GetAddressId() != 0 || r.GetCountry() == "") {
+		oa, err := s.shippingAddress(ctx, r.GetUserId(), r.GetAddressId())
+		if err != nil {
+			return nil, err
+		}
+		if oa.IsZero() {
+			return nil, status.Errorf(codes.FailedPrecondition, "user %d has no default address", r.GetUserId())
+		}
+		return orderDestination(oa), nil
+	}
+
+	to := &shipping.Destination{
+		Country:    normalizeCountry(r.GetCountry()),
+		Region:     r.GetRegion(),
+		PostalCode: r.GetPostalCode(),
+	}
+	if len(to.Country) != 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid country code %q", r.GetCountry())
+	}
+
+	return to, nil
+}
+
+// chargeShipping replaces the shipping charge of the pricing policy in q with
+// the price of the shipping method of the order, or of the cheapest method
+// able to ship its parcel if method is empty. The quote is in the store
+// currency.
+func (s *Server) chargeShipping(ctx context.Context, order *storer.Order, q *Quote, parcel *shipping.Parcel, method string) error {
+	if s.shipping == nil {
+		if method != "" {
+			return status.Error(codes.InvalidArgument, "shipping methods are not enabled")
+		}
+		return nil
+	}
+	if order.OrderAddress.IsZero() {
+		return status.Error(codes.FailedPrecondition, "order has no shipping address")
+	}
+
+	parcel.Value = q.Subtotal - q.Discount
+	opt, err := s.shippingOption(ctx, parcel, orderDestination(order.OrderAddress), method)
+	if err != nil {
+		return err
+	}
+
+	q.Total += opt.Price - q.Shipping
+	q.Shipping = opt.Price
+	order.ShippingMethod = opt.MethodID
+
+	return nil
+}
+
+// shippingOption returns the option of the method shipping the parcel to the
+// destination, the cheapest one if method is empty.
+func (s *Server) shippingOption(ctx context.Context, parcel *shipping.Parcel, to *shipping.Destination, method string) (*shipping.Option, error) {
+	if method == "" {
+		options, err := s.shipping.Quote(ctx, parcel, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(options) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "no shipping method ships the order to %s", to.Country)
+		}
+		return options[0], nil
+	}
+
+	opt, err := s.shipping.Option(ctx, method, parcel, to)
+	if errors.Is(err, shipping.ErrUnknownMethod) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, shipping.ErrNotShippable) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return opt, nil
+}
+
+func orderDestination(oa storer.OrderAddress) *shipping.Destination {
+	return &shipping.Destination{
+		Country:    oa.ShipCountry,
+		Region:     oa.ShipRegion,
+		PostalCode: oa.ShipPostalCode,
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"github.com/niloy104/Conduit/grpc/storer"
+	"github.com/niloy104/Conduit/money"
+	"github.com/niloy104/Conduit/tax"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chargeTax replaces the tax of the pricing policy in q with the tax of the
+// tax table for the region the order ships to, and records the tax of every
+// item. Items are taxed on their amount less their share of the discount,
+// which goes to the discounted items. The quote is in the store currency and
+// categories maps products to their tax category.
+func (s *Server) chargeTax(order *storer.Order, q *Quote, discounted []bool, categories map[int64]string) error {
+	if s.tax == nil {
+		return nil
+	}
+	if order.OrderAddress.IsZero() {
+		return status.Error(codes.FailedPrecondition, "order has no shipping address")
+	}
+
+	amounts := itemAmounts(order.Items, q.Discount, discounted)
+	lines := make([]tax.Line, len(order.Items))
+	for i, oi := range order.Items {
+		lines[i] = tax.Line{Amount: amounts[i], Category: categories[oi.ProductID]}
+	}
+	taxes, total := s.tax.Compute(order.ShipCountry, order.ShipRegion, lines)
+	for i, t := range taxes {
+		order.Items[i].TaxRate = t.Rate
+		order.Items[i].TaxPrice = t.Tax
+	}
+
+	q.Total -= q.Tax
+	if !s.tax.Inclusive {
+		q.Total += total
+	}
+	q.Tax = total
+	order.TaxInclusive = s.tax.Inclusive
+
+	return nil
+}
+
+// itemAmounts returns the amount every item is charged once discount is
+// shared between the discounted items, in proportion to their amounts. The
+// cents left over by rounding go to the last discounted item.
+func itemAmounts(items []storer.OrderItem, discount money.Amount, discounted []bool) []money.Amount {
+	amounts := make([]money.Amount, len(items))
+	var base money.Amount
+	last := -1
+	for i, oi := range items {
+		amounts[i] = oi.Price.Mul(oi.Quantity)
+		if discounted[i] {
+			base += amounts[i]
+			last = i
+		}
+	}
+	if base == 0 {
+		return amounts
+	}
+
+	left := discount
+	for i := range items {
+		if !discounted[i] {
+			continue
+		}
+		share := left
+		if i != last {
+			share = money.Amount(int64(discount) * int64(amounts[i]) / int64(base))
+		}
+		amounts[i] -= share
+		left -= share
+	}
+
+	return amounts
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/niloy104/Conduit/grpc/pb"
+	"github.com/niloy104/Conduit/grpc/storer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validateVariant checks the variant an admin creates or updates.
+func validateVariant(v *storer.Variant) error {
+	switch {
+	case v.SKU == "":
+		return status.Error(codes.InvalidArgument, "variant sku is required")
+	case len(v.Options) == 0:
+		return status.Errorf(codes.InvalidArgument, "variant %s has no options", v.SKU)
+	case v.Price != nil && *v.Price < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid price %s for variant %s", *v.Price, v.SKU)
+	case v.CountInStock < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid stock %d for variant %s", v.CountInStock, v.SKU)
+	}
+	for name, value := range v.Options {
+		if name == "" || value == "" {
+			return status.Errorf(codes.InvalidArgument, "variant %s has an empty option", v.SKU)
+		}
+	}
+	return nil
+}
+
+func variantError(v *storer.Variant, err error) error {
+	if errors.Is(err, storer.ErrDuplicateSKU) {
+		return status.Errorf(codes.AlreadyExists, "sku %s already exists", v.SKU)
+	}
+	return err
+}
+
+// getVariant returns a variant of the product, NotFound if the product has no
+// variant with the ID.
+func (s *Server) getVariant(ctx context.Context, productID, id int64) (*storer.Variant, error) {
+	v, err := s.storer.GetVariant(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && v.ProductID != productID {
+		return nil, status.Errorf(codes.NotFound, "product %d has no variant %d", productID, id)
+	}
+	return v, err
+}
+
+func (s *Server) CreateVariant(ctx context.Context, v *pb.VariantReq) (*pb.VariantRes, error) {
+	_, err := s.storer.GetProduct(ctx, v.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "product %d does not exist", v.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	variant := toStorerVariant(v)
+	err = validateVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.storer.CreateVariant(ctx, variant)
+	if err != nil {
+		return nil, variantError(variant, err)
+	}
+
+	return toPBVariantRes(created), nil
+}
+
+func (s *Server) UpdateVariant(ctx context.Context, v *pb.VariantReq) (*pb.VariantRes, error) {
+	variant, err := s.getVariant(ctx, v.GetProductId(), v.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	patchVariantReq(variant, v)
+	err = validateVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storer.UpdateVariant(ctx, variant)
+	if err != nil {
+		return nil, variantError(variant, err)
+	}
+
+	return toPBVariantRes(updated), nil
+}
+
+// DeleteVariant deletes a variant that was never ordered.
+func (s *Server) DeleteVariant(ctx context.Context, v *pb.VariantReq) (*pb.VariantRes, error) {
+	_, err := s.getVariant(ctx, v.GetProductId(), v.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storer.DeleteVariant(ctx, v.GetId())
+	if errors.Is(err, storer.ErrVariantOrdered) {
+		return nil, status.Errorf(codes.FailedPrecondition, "variant %d was ordered", v.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.VariantRes{}, nil
+}
+
+// orderedVariant returns the variant of the product an order item names, nil
+// for products without variants.
+func orderedVariant(p *storer.Product, id *int64) (*storer.Variant, error) {
+	switch {
+	case id == nil && len(p.Variants) > 0:
+		return nil, status.Errorf(codes.InvalidArgument, "product %d needs a variant", p.ID)
+	case id == nil:
+		return nil, nil
+	}
+
+	i := slices.IndexFunc(p.Variants, func(v storer.Variant) bool { return v.ID == *id })
+	if i < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "product %d has no variant %d", p.ID, *id)
+	}
+	return &p.Variants[i], nil
+}
+
+// variantName names an ordered variant after its product and the values of
+// its options, sorted by option name: "T-shirt (red, M)".
+func variantName(p *storer.Product, v *storer.Variant) string {
+	names := slices.Sorted(maps.Keys(v.Options))
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = v.Options[name]
+	}
+	return p.Name + " (" + strings.Join(values, ", ") + ")"
+}
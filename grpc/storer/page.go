@@ -0,0 +1,228 @@
+package storer
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// pageCursor is the position of the last row of a page in a keyset ordering.
+// It is handed to clients as an opaque base64 token and is only valid for the
+// sort it was issued for.
+type pageCursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v,omitempty"`
+	ID    int64           `json:"i"`
+
+	value any
+}
+
+// keyset orders rows of E by one of its sortable fields, then by id, which
+// makes the ordering total so that pages can resume after a cursor.
+type keyset[E any] struct {
+	field string
+	desc  bool
+	value func(*E) any
+	id    func(*E) int64
+}
+
+// newKeyset parses sort, a field name optionally prefixed with "-" for a
+// descending order, against fields. An empty sort falls back to def.
+func newKeyset[E any](sort, def string, fields map[string]func(*E) any, id func(*E) int64) (*keyset[E], error) {
+	if sort == "" {
+		sort = def
+	}
+
+	k := &keyset[E]{id: id}
+	k.field, k.desc = strings.CutPrefix(sort, "-")
+
+	var ok bool
+	k.value, ok = fields[k.field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSort, sort)
+	}
+
+	return k, nil
+}
+
+func (k *keyset[E]) String() string {
+	if k.desc {
+		return "-" + k.field
+	}
+	return k.field
+}
+
+func (k *keyset[E]) orderBy() string {
+	if k.field == "id" {
+		return "id" + k.direction()
+	}
+	return k.field + k.direction() + ", id" + k.direction()
+}
+
+func (k *keyset[E]) direction() string {
+	if k.desc {
+		return " DESC"
+	}
+	return ""
+}
+
+// decode returns the cursor of token, nil for an empty token, meaning the
+// first page.
+func (k *keyset[E]) decode(token string) (*pageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidPageToken
+	}
+	if c.Sort != k.String() {
+		return nil, fmt.Errorf("%w: issued for sort %q", ErrInvalidPageToken, c.Sort)
+	}
+
+	if k.field != "id" {
+		c.value, err = decodeValue(c.Value, k.value(new(E)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+		}
+	}
+
+	return &c, nil
+}
+
+func (k *keyset[E]) encode(e *E) string {
+	c := pageCursor{Sort: k.String(), ID: k.id(e)}
+	if k.field != "id" {
+		c.Value, _ = json.Marshal(k.value(e))
+	}
+
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// where returns the SQL condition selecting the rows after c.
+func (k *keyset[E]) where(c *pageCursor) (string, []any) {
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+
+	if k.field == "id" {
+		return "id" + op + "?", []any{c.ID}
+	}
+	return fmt.Sprintf("(%[1]s%[2]s? OR (%[1]s=? AND id%[2]s?))", k.field, op), []any{c.value, c.value, c.ID}
+}
+
+// compare orders a and b like the SQL ORDER BY of the keyset.
+func (k *keyset[E]) compare(a, b *E) int {
+	n := 0
+	if k.field != "id" {
+		n = compareValues(k.value(a), k.value(b))
+	}
+	if n == 0 {
+		n = compareValues(k.id(a), k.id(b))
+	}
+	if k.desc {
+		return -n
+	}
+	return n
+}
+
+// after reports whether e comes after the cursor c.
+func (k *keyset[E]) after(c *pageCursor, e *E) bool {
+	n := 0
+	if k.field != "id" {
+		n = compareValues(k.value(e), c.value)
+	}
+	if n == 0 {
+		n = compareValues(k.id(e), c.ID)
+	}
+	if k.desc {
+		return n < 0
+	}
+	return n > 0
+}
+
+// page cuts rows, fetched with a limit of size+1, down to size and returns
+// the token of the next page, empty on the last page. A size of zero or less
+// means no limit.
+func (k *keyset[E]) page(rows []*E, size int) ([]*E, string) {
+	if size <= 0 || len(rows) <= size {
+		return rows, ""
+	}
+
+	rows = rows[:size]
+	return rows, k.encode(rows[len(rows)-1])
+}
+
+func decodeValue(raw json.RawMessage, like any) (any, error) {
+	switch like.(type) {
+	case time.Time:
+		return unmarshalAs[time.Time](raw)
+	case money.Amount:
+		return unmarshalAs[money.Amount](raw)
+	case int64:
+		return unmarshalAs[int64](raw)
+	case string:
+		return unmarshalAs[string](raw)
+	default:
+		return nil, fmt.Errorf("unsupported sort value %T", like)
+	}
+}
+
+func unmarshalAs[V any](raw json.RawMessage) (any, error) {
+	var v V
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case money.Amount:
+		return cmp.Compare(a, b.(money.Amount))
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case string:
+		return strings.Compare(a, b.(string))
+	default:
+		panic(fmt.Sprintf("unsupported sort value %T", a))
+	}
+}
+
+func productKeyset(sort string) (*keyset[Product], error) {
+	return newKeyset(sort, "id", productSortFields, func(p *Product) int64 { return p.ID })
+}
+
+func reviewKeyset(sort string) (*keyset[Review], error) {
+	return newKeyset(sort, "-created_at", reviewSortFields, func(r *Review) int64 { return r.ID })
+}
+
+func couponKeyset(sort string) (*keyset[Coupon], error) {
+	return newKeyset(sort, "id", couponSortFields, func(c *Coupon) int64 { return c.ID })
+}
+
+func orderKeyset(sort string) (*keyset[Order], error) {
+	return newKeyset(sort, "-created_at", orderSortFields, func(o *Order) int64 { return o.ID })
+}
+
+func userKeyset(sort string) (*keyset[User], error) {
+	return newKeyset(sort, "id", userSortFields, func(u *User) int64 { return u.ID })
+}
+
+func notificationEventKeyset() *keyset[NotificationEvent] {
+	k, _ := newKeyset("created_at", "", notificationEventSortFields, func(ev *NotificationEvent) int64 { return ev.ID })
+	return k
+}
@@ -0,0 +1,207 @@
+package storer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minTokenLen mirrors innodb_ft_min_token_size: shorter words are not
+	// indexed.
+	minTokenLen = 3
+	// snippetLen is the approximate length in runes of a search snippet.
+	snippetLen = 160
+)
+
+// ProductSearch is a full-text query over the name, description and
+// category name of products. A PageSize of zero returns every match.
+type ProductSearch struct {
+	Query     string
+	PageSize  int
+	PageToken string
+}
+
+// ProductMatch is a product found by a search, with its relevance and an
+// HTML snippet where the query terms are wrapped in <mark> tags.
+type ProductMatch struct {
+	Product
+	Score   float64 `db:"score"`
+	Snippet string  `db:"-"`
+}
+
+// searchCursor is the position of a search page. Relevance is not a stable
+// sort key, so search pages are cut by offset and the token is tied to the
+// query it was issued for.
+type searchCursor struct {
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+func decodeSearchCursor(token, query string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+
+	var c searchCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Offset <= 0 || c.Query != query {
+		return 0, ErrInvalidPageToken
+	}
+
+	return c.Offset, nil
+}
+
+func encodeSearchCursor(query string, offset int) string {
+	b, _ := json.Marshal(searchCursor{Query: query, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// searchPage cuts matches, fetched with a limit of size+1 from offset, down
+// to size and returns the token of the next page.
+func searchPage(matches []*ProductMatch, s *ProductSearch, offset int) ([]*ProductMatch, string) {
+	if s.PageSize <= 0 || len(matches) <= s.PageSize {
+		return matches, ""
+	}
+	return matches[:s.PageSize], encodeSearchCursor(s.Query, offset+s.PageSize)
+}
+
+// tokenize splits s into lowercase words of at least minTokenLen runes.
+func tokenize(s string) []string {
+	var tokens []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), isNotWordRune) {
+		if len([]rune(w)) >= minTokenLen {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// scoreProduct ranks p, in the category named category, against terms the
+// way a natural language search would roughly do it: each occurrence of a
+// term counts, more so in the name than in the category, and in the category
+// than in the description.
+func scoreProduct(p *Product, category string, terms []string) float64 {
+	var score float64
+	for _, f := range []struct {
+		text   string
+		weight float64
+	}{
+		{p.Name, 3},
+		{category, 2},
+		{p.Description, 1},
+	} {
+		for _, tok := range tokenize(f.text) {
+			for _, term := range terms {
+				if tok == term {
+					score += f.weight
+				}
+			}
+		}
+	}
+	return score
+}
+
+// productSnippet highlights terms in a window of the description of p around
+// the first match, falling back to the name when the description does not
+// match.
+func productSnippet(p *Product, terms []string) string {
+	if s, ok := snippet(p.Description, terms); ok {
+		return s
+	}
+	s, _ := snippet(p.Name, terms)
+	return s
+}
+
+func snippet(text string, terms []string) (string, bool) {
+	words := splitWords(text)
+
+	first := -1
+	for i, w := range words {
+		if w.word && matchesAny(w.text, terms) {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return html.EscapeString(truncate(text, snippetLen)), false
+	}
+
+	// start a few words before the first match and stop after snippetLen runes
+	start := max(first-8, 0)
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	n := 0
+	for i := start; i < len(words); i++ {
+		if n >= snippetLen {
+			b.WriteString("…")
+			break
+		}
+		w := words[i]
+		n += len([]rune(w.text))
+		if w.word && matchesAny(w.text, terms) {
+			b.WriteString("<mark>" + html.EscapeString(w.text) + "</mark>")
+			continue
+		}
+		b.WriteString(html.EscapeString(w.text))
+	}
+
+	return strings.TrimSpace(b.String()), true
+}
+
+type textRun struct {
+	text string
+	word bool
+}
+
+// splitWords cuts text into alternating runs of word and non-word runes, so
+// that joining them gives text back.
+func splitWords(text string) []textRun {
+	var runs []textRun
+	start := 0
+	for i, r := range text {
+		word := !isNotWordRune(r)
+		if i > start && runs[len(runs)-1].word != word {
+			runs[len(runs)-1].text = text[start:i]
+			start = i
+		}
+		if i == start {
+			runs = append(runs, textRun{word: word})
+		}
+	}
+	if len(runs) > 0 {
+		runs[len(runs)-1].text = text[start:]
+	}
+	return runs
+}
+
+func matchesAny(word string, terms []string) bool {
+	word = strings.ToLower(word)
+	for _, t := range terms {
+		if word == t {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,104 @@
+package storer
+
+import "context"
+
+// Storer is the persistence port of the gRPC service. MySQLStorer is the
+// production adapter, MemoryStorer backs unit tests and local demos.
+type Storer interface {
+	CreateProduct(ctx context.Context, p *Product) (*Product, error)
+	GetProduct(ctx context.Context, id int64) (*Product, error)
+	ListProducts(ctx context.Context, f *ProductFilter) ([]*Product, string, error)
+	ListProductCurrencies(ctx context.Context) ([]string, error)
+	SearchProducts(ctx context.Context, ps *ProductSearch) ([]*ProductMatch, string, error)
+	UpdateProduct(ctx context.Context, p *Product) (*Product, error)
+	DeleteProduct(ctx context.Context, id int64) error
+	GetProductsBySKU(ctx context.Context, skus []string) ([]*Product, error)
+	SaveProducts(ctx context.Context, products []*Product) error
+
+	CreateVariant(ctx context.Context, v *Variant) (*Variant, error)
+	GetVariant(ctx context.Context, id int64) (*Variant, error)
+	UpdateVariant(ctx context.Context, v *Variant) (*Variant, error)
+	DeleteVariant(ctx context.Context, id int64) error
+
+	CreateProductImage(ctx context.Context, img *ProductImage) (*ProductImage, error)
+	GetProductImage(ctx context.Context, id int64) (*ProductImage, error)
+	DeleteProductImage(ctx context.Context, id int64) error
+	ReorderProductImages(ctx context.Context, productID int64, ids []int64) error
+
+	CreateCategory(ctx context.Context, c *Category) (*Category, error)
+	GetCategory(ctx context.Context, id int64) (*Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*Category, error)
+	ListCategories(ctx context.Context) ([]*Category, error)
+	UpdateCategory(ctx context.Context, c *Category) (*Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+
+	CreateReview(ctx context.Context, r *Review) (*Review, error)
+	GetReview(ctx context.Context, id int64) (*Review, error)
+	ListReviews(ctx context.Context, f *ReviewFilter) ([]*Review, string, error)
+	UpdateReviewStatus(ctx context.Context, id int64, status ReviewStatus) (*Review, error)
+	DeleteReview(ctx context.Context, id int64) error
+	HasDeliveredProduct(ctx context.Context, userID, productID int64) (bool, error)
+
+	CreateOrder(ctx context.Context, o *Order) (*Order, error)
+	GetOrder(ctx context.Context, id int64) (*Order, error)
+	GetOrderStatusByID(ctx context.Context, id int64) (*Order, error)
+	ListOrders(ctx context.Context, f *OrderFilter) ([]*Order, string, error)
+	UpdateOrderStatus(ctx context.Context, c *OrderStatusChange) (*OrderStatusChange, error)
+	ListOrderStatusHistory(ctx context.Context, orderID int64) ([]*OrderStatusChange, error)
+	DeleteOrder(ctx context.Context, id int64) error
+
+	CreatePayment(ctx context.Context, p *Payment) (*Payment, error)
+	GetPaymentByRef(ctx context.Context, provider, ref string) (*Payment, error)
+	ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error)
+	UpdatePaymentStatus(ctx context.Context, p *Payment, from PaymentStatus) (*Payment, error)
+
+	CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error)
+	GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error)
+
+	CreateCoupon(ctx context.Context, c *Coupon) (*Coupon, error)
+	GetCoupon(ctx context.Context, id int64) (*Coupon, error)
+	GetCouponByCode(ctx context.Context, code string) (*Coupon, error)
+	ListCoupons(ctx context.Context, f *CouponFilter) ([]*Coupon, string, error)
+	UpdateCoupon(ctx context.Context, c *Coupon) (*Coupon, error)
+	DeleteCoupon(ctx context.Context, id int64) error
+	CountCouponUses(ctx context.Context, couponID, userID int64) (int64, int64, error)
+
+	GetCart(ctx context.Context, co CartOwner) (*Cart, error)
+	AddCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error
+	SetCartItemQuantity(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error
+	RemoveCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64) error
+	ClearCart(ctx context.Context, co CartOwner) error
+	MergeCart(ctx context.Context, token string, userID int64) error
+	CheckoutCart(ctx context.Context, o *Order) (*Order, error)
+
+	CreateUser(ctx context.Context, u *User) (*User, error)
+	GetUser(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, id int64) (*User, error)
+	ListUsers(ctx context.Context, f *UserFilter) ([]*User, string, error)
+	UpdateUser(ctx context.Context, u *User) (*User, error)
+	DeleteUser(ctx context.Context, id int64) error
+
+	CreateAddress(ctx context.Context, a *Address) (*Address, error)
+	GetAddress(ctx context.Context, id int64) (*Address, error)
+	ListUserAddresses(ctx context.Context, userID int64) ([]*Address, error)
+	UpdateAddress(ctx context.Context, a *Address) (*Address, error)
+	DeleteAddress(ctx context.Context, id int64) error
+
+	CreateSession(ctx context.Context, s *Session) (*Session, error)
+	GetSession(ctx context.Context, id string) (*Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	DeleteSession(ctx context.Context, id string) error
+
+	ReserveIdempotencyKey(ctx context.Context, k *IdempotencyKey) (*IdempotencyKey, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, k *IdempotencyKey) error
+	ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error
+
+	EnqueueNotificationEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error)
+	ListNotificationEvents(ctx context.Context, f *NotificationEventFilter) ([]*NotificationEvent, string, error)
+	UpdateNotificationEvent(ctx context.Context, ev *NotificationEvent, es *NotificationState, responseType NotificationResponseType) (bool, error)
+}
+
+var (
+	_ Storer = (*MySQLStorer)(nil)
+	_ Storer = (*MemoryStorer)(nil)
+)
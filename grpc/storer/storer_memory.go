@@ -0,0 +1,1987 @@
+package storer
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// MemoryStorer is a thread-safe in-memory Storer. It mirrors the behaviour of
+// MySQLStorer, including auto-increment IDs, default timestamps and the
+// foreign keys declared in db/migrations, so the gRPC service can run without
+// a database.
+type MemoryStorer struct {
+	mu sync.RWMutex
+
+	products map[int64]*Product
+	variants map[int64]*Variant
+	images   map[int64]*ProductImage
+	cats     map[int64]*Category
+	reviews  map[int64]*Review
+	coupons  map[int64]*Coupon
+	orders   map[int64]*Order
+	payments map[int64]*Payment
+	invoices map[int64]*Invoice
+	carts    map[CartOwner]*Cart
+	users    map[int64]*User
+	addrs    map[int64]*Address
+	sessions map[string]*Session
+	idemKeys map[idemKeyID]*IdempotencyKey
+	history  []*OrderStatusChange
+	states   map[int64]*NotificationState
+	events   map[int64]*NotificationEvent
+
+	lastProductID     int64
+	lastVariantID     int64
+	lastImageID       int64
+	lastCategoryID    int64
+	lastReviewID      int64
+	lastCouponID      int64
+	lastOrderID       int64
+	lastOrderItemID   int64
+	lastCartID        int64
+	lastCartItemID    int64
+	lastChangeID      int64
+	lastPaymentID     int64
+	lastInvoiceID     int64
+	lastInvoiceNumber int64
+	lastInvoiceLineID int64
+	lastUserID        int64
+	lastAddressID     int64
+	lastStateID       int64
+	lastEventID       int64
+}
+
+func NewMemoryStorer() *MemoryStorer {
+	return &MemoryStorer{
+		products: make(map[int64]*Product),
+		variants: make(map[int64]*Variant),
+		images:   make(map[int64]*ProductImage),
+		cats:     make(map[int64]*Category),
+		reviews:  make(map[int64]*Review),
+		coupons:  make(map[int64]*Coupon),
+		orders:   make(map[int64]*Order),
+		payments: make(map[int64]*Payment),
+		invoices: make(map[int64]*Invoice),
+		carts:    make(map[CartOwner]*Cart),
+		users:    make(map[int64]*User),
+		addrs:    make(map[int64]*Address),
+		sessions: make(map[string]*Session),
+		idemKeys: make(map[idemKeyID]*IdempotencyKey),
+		states:   make(map[int64]*NotificationState),
+		events:   make(map[int64]*NotificationEvent),
+	}
+}
+
+func (ms *MemoryStorer) CreateProduct(ctx context.Context, p *Product) (*Product, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.createProduct(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// createProduct adds a product. ms.mu must be held.
+func (ms *MemoryStorer) createProduct(p *Product) error {
+	if err := ms.checkProduct(p); err != nil {
+		return err
+	}
+
+	ms.lastProductID++
+	p.ID = ms.lastProductID
+	p.CreatedAt = time.Now()
+	if p.Currency == "" {
+		p.Currency = money.StoreCurrency
+	}
+
+	cp := *p
+	cp.Variants = nil
+	cp.Images = nil
+	ms.products[p.ID] = &cp
+
+	return nil
+}
+
+// checkProduct enforces the category foreign key and the unique SKU of
+// products. ms.mu must be held.
+func (ms *MemoryStorer) checkProduct(p *Product) error {
+	if p.CategoryID != nil {
+		if _, ok := ms.cats[*p.CategoryID]; !ok {
+			return fmt.Errorf("error inserting product: category %d does not exist", *p.CategoryID)
+		}
+	}
+	if p.SKU != nil {
+		for _, existing := range ms.products {
+			if existing.SKU != nil && *existing.SKU == *p.SKU && existing.ID != p.ID {
+				return fmt.Errorf("error inserting product: %w", ErrDuplicateSKU)
+			}
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	p, ok := ms.products[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting product : %w", sql.ErrNoRows)
+	}
+
+	return ms.copyProduct(p), nil
+}
+
+// copyProduct copies a product along with its variants and images. ms.mu
+// must be held.
+func (ms *MemoryStorer) copyProduct(p *Product) *Product {
+	cp := *p
+	cp.Variants = nil
+	for _, id := range sortedKeys(ms.variants) {
+		if v := ms.variants[id]; v.ProductID == p.ID {
+			cp.Variants = append(cp.Variants, copyVariant(v))
+		}
+	}
+	cp.Images = ms.productImages(p.ID)
+	return &cp
+}
+
+// productImages returns copies of the images of a product, ordered by
+// position. ms.mu must be held.
+func (ms *MemoryStorer) productImages(productID int64) []ProductImage {
+	var images []ProductImage
+	for _, id := range sortedKeys(ms.images) {
+		if img := ms.images[id]; img.ProductID == productID {
+			images = append(images, *img)
+		}
+	}
+	slices.SortStableFunc(images, func(a, b ProductImage) int { return cmp.Compare(a.Position, b.Position) })
+	return images
+}
+
+func (ms *MemoryStorer) ListProducts(ctx context.Context, f *ProductFilter) ([]*Product, string, error) {
+	k, err := productKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var products []*Product
+	for _, p := range ms.products {
+		switch {
+		case len(f.CategoryIDs) > 0 && (p.CategoryID == nil || !slices.Contains(f.CategoryIDs, *p.CategoryID)),
+			len(f.Prices) > 0 && !slices.ContainsFunc(f.Prices, func(r PriceRange) bool { return r.contains(p) }),
+			f.InStock && p.CountInStock <= 0,
+			cur != nil && !k.after(cur, p):
+			continue
+		}
+		products = append(products, ms.copyProduct(p))
+	}
+
+	products, next := memoryPage(k, products, f.PageSize)
+	return products, next, nil
+}
+
+func (ms *MemoryStorer) ListProductCurrencies(ctx context.Context) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var currencies []string
+	for _, p := range ms.products {
+		if !slices.Contains(currencies, p.Currency) {
+			currencies = append(currencies, p.Currency)
+		}
+	}
+	slices.Sort(currencies)
+	return currencies, nil
+}
+
+// SearchProducts matches the words of the query against the words of the
+// name, category and description of products, see scoreProduct.
+func (ms *MemoryStorer) SearchProducts(ctx context.Context, ps *ProductSearch) ([]*ProductMatch, string, error) {
+	offset, err := decodeSearchCursor(ps.PageToken, ps.Query)
+	if err != nil {
+		return nil, "", err
+	}
+	terms := tokenize(ps.Query)
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var matches []*ProductMatch
+	for _, p := range ms.products {
+		var category string
+		if p.CategoryID != nil {
+			if c, ok := ms.cats[*p.CategoryID]; ok {
+				category = c.Name
+			}
+		}
+		if score := scoreProduct(p, category, terms); score > 0 {
+			matches = append(matches, &ProductMatch{Product: *ms.copyProduct(p), Score: score})
+		}
+	}
+	slices.SortFunc(matches, func(a, b *ProductMatch) int {
+		if n := cmp.Compare(b.Score, a.Score); n != 0 {
+			return n
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+	matches, next := searchPage(matches[offset:], ps, offset)
+	for _, m := range matches {
+		m.Snippet = productSnippet(&m.Product, terms)
+	}
+
+	return matches, next, nil
+}
+
+func (ms *MemoryStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.updateProduct(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// updateProduct updates a product. ms.mu must be held.
+func (ms *MemoryStorer) updateProduct(p *Product) error {
+	if err := ms.checkProduct(p); err != nil {
+		return err
+	}
+
+	// like the UPDATE statement, a missing row is not an error
+	if existing, ok := ms.products[p.ID]; ok {
+		cp := *p
+		cp.Variants = nil
+		cp.Images = nil
+		cp.CreatedAt = existing.CreatedAt
+		cp.Rating = existing.Rating
+		cp.NumReviews = existing.NumReviews
+		ms.products[p.ID] = &cp
+	}
+
+	return nil
+}
+
+// GetProductsBySKU returns the products with the SKUs, along with their
+// variants and images. SKUs no product has are skipped.
+func (ms *MemoryStorer) GetProductsBySKU(ctx context.Context, skus []string) ([]*Product, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var products []*Product
+	for _, id := range sortedKeys(ms.products) {
+		if p := ms.products[id]; p.SKU != nil && slices.Contains(skus, *p.SKU) {
+			products = append(products, ms.copyProduct(p))
+		}
+	}
+	return products, nil
+}
+
+// SaveProducts creates the products without an ID and updates the others.
+// Like the transaction of MySQLStorer, it saves none of them if one fails.
+func (ms *MemoryStorer) SaveProducts(ctx context.Context, products []*Product) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// products are replaced rather than modified, so a shallow copy restores them
+	saved := maps.Clone(ms.products)
+	var created []*Product
+	for _, p := range products {
+		var err error
+		if p.ID == 0 {
+			created = append(created, p)
+			err = ms.createProduct(p)
+		} else {
+			err = ms.updateProduct(p)
+		}
+		if err != nil {
+			ms.products = saved
+			for _, p := range created {
+				p.ID = 0
+			}
+			return fmt.Errorf("error saving products: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (ms *MemoryStorer) DeleteProduct(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, o := range ms.orders {
+		for _, oi := range o.Items {
+			if oi.ProductID == id {
+				return fmt.Errorf("error deleting product: product %d is referenced by order %d", id, o.ID)
+			}
+		}
+	}
+	delete(ms.products, id)
+	for vid, v := range ms.variants {
+		if v.ProductID == id {
+			delete(ms.variants, vid)
+		}
+	}
+	for iid, img := range ms.images {
+		if img.ProductID == id {
+			delete(ms.images, iid)
+		}
+	}
+	for cid, c := range ms.coupons {
+		if c.ProductID != nil && *c.ProductID == id {
+			ms.deleteCoupon(cid)
+		}
+	}
+	for _, c := range ms.carts {
+		c.Items = slices.DeleteFunc(c.Items, func(ci CartItem) bool { return ci.ProductID == id })
+	}
+	for rid, r := range ms.reviews {
+		if r.ProductID == id {
+			delete(ms.reviews, rid)
+		}
+	}
+
+	return nil
+}
+
+// CreateVariant adds a variant to its product, whose stock becomes the total
+// stock of its variants.
+func (ms *MemoryStorer) CreateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[v.ProductID]; !ok {
+		return nil, fmt.Errorf("error creating variant: error locking product: %w", sql.ErrNoRows)
+	}
+	if err := ms.checkVariant(v); err != nil {
+		return nil, err
+	}
+
+	ms.lastVariantID++
+	v.ID = ms.lastVariantID
+	v.CreatedAt = time.Now()
+	cp := copyVariant(v)
+	ms.variants[v.ID] = &cp
+	ms.syncProductStock(v.ProductID)
+
+	return v, nil
+}
+
+// checkVariant enforces the unique SKU of variants. ms.mu must be held.
+func (ms *MemoryStorer) checkVariant(v *Variant) error {
+	for _, existing := range ms.variants {
+		if existing.SKU == v.SKU && existing.ID != v.ID {
+			return fmt.Errorf("variant %s: %w", v.SKU, ErrDuplicateSKU)
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) GetVariant(ctx context.Context, id int64) (*Variant, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	v, ok := ms.variants[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting variant: %w", sql.ErrNoRows)
+	}
+
+	cp := copyVariant(v)
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) UpdateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[v.ProductID]; !ok {
+		return nil, fmt.Errorf("error updating variant: error locking product: %w", sql.ErrNoRows)
+	}
+	if err := ms.checkVariant(v); err != nil {
+		return nil, err
+	}
+
+	// like the UPDATE statement, a missing row is not an error
+	if existing, ok := ms.variants[v.ID]; ok {
+		cp := copyVariant(v)
+		cp.ProductID = existing.ProductID
+		cp.CreatedAt = existing.CreatedAt
+		ms.variants[v.ID] = &cp
+		ms.syncProductStock(existing.ProductID)
+	}
+
+	return v, nil
+}
+
+func (ms *MemoryStorer) DeleteVariant(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	v, ok := ms.variants[id]
+	if !ok {
+		return fmt.Errorf("error deleting variant: error getting variant: %w", sql.ErrNoRows)
+	}
+	for _, o := range ms.orders {
+		for _, oi := range o.Items {
+			if oi.VariantID != nil && *oi.VariantID == id {
+				return fmt.Errorf("error deleting variant: variant %d: %w", id, ErrVariantOrdered)
+			}
+		}
+	}
+	delete(ms.variants, id)
+	ms.syncProductStock(v.ProductID)
+	for _, c := range ms.carts {
+		c.Items = slices.DeleteFunc(c.Items, func(ci CartItem) bool { return ci.VariantID != nil && *ci.VariantID == id })
+	}
+
+	return nil
+}
+
+// syncProductStock sets the stock of a product to the total stock of its
+// variants. ms.mu must be held.
+func (ms *MemoryStorer) syncProductStock(productID int64) {
+	p, ok := ms.products[productID]
+	if !ok {
+		return
+	}
+	p.CountInStock = 0
+	for _, v := range ms.variants {
+		if v.ProductID == productID {
+			p.CountInStock += v.CountInStock
+		}
+	}
+}
+
+func copyVariant(v *Variant) Variant {
+	cp := *v
+	cp.Options = maps.Clone(v.Options)
+	return cp
+}
+
+// CreateProductImage appends an image to the gallery of its product.
+func (ms *MemoryStorer) CreateProductImage(ctx context.Context, img *ProductImage) (*ProductImage, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[img.ProductID]; !ok {
+		return nil, fmt.Errorf("error creating image: error locking product: %w", sql.ErrNoRows)
+	}
+
+	img.Position = 0
+	for _, existing := range ms.images {
+		if existing.ProductID == img.ProductID {
+			img.Position = max(img.Position, existing.Position+1)
+		}
+	}
+	ms.lastImageID++
+	img.ID = ms.lastImageID
+	img.CreatedAt = time.Now()
+	cp := *img
+	ms.images[img.ID] = &cp
+	ms.syncProductImage(img.ProductID)
+
+	return img, nil
+}
+
+func (ms *MemoryStorer) GetProductImage(ctx context.Context, id int64) (*ProductImage, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	img, ok := ms.images[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting image: %w", sql.ErrNoRows)
+	}
+
+	cp := *img
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) DeleteProductImage(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	img, ok := ms.images[id]
+	if !ok {
+		return fmt.Errorf("error deleting image: error getting image: %w", sql.ErrNoRows)
+	}
+	delete(ms.images, id)
+	ms.syncProductImage(img.ProductID)
+
+	return nil
+}
+
+// ReorderProductImages orders the gallery of a product as ids. It fails with
+// ErrImagesMismatch unless ids are the IDs of the images of the product, each
+// once.
+func (ms *MemoryStorer) ReorderProductImages(ctx context.Context, productID int64, ids []int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[productID]; !ok {
+		return fmt.Errorf("error reordering images: error locking product: %w", sql.ErrNoRows)
+	}
+	var current []int64
+	for id, img := range ms.images {
+		if img.ProductID == productID {
+			current = append(current, id)
+		}
+	}
+	if !samePermutation(current, ids) {
+		return fmt.Errorf("error reordering images: product %d: %w", productID, ErrImagesMismatch)
+	}
+
+	for i, id := range ids {
+		ms.images[id].Position = int64(i)
+	}
+	ms.syncProductImage(productID)
+
+	return nil
+}
+
+// syncProductImage sets the image of a product to the URL of the first image
+// of its gallery. ms.mu must be held.
+func (ms *MemoryStorer) syncProductImage(productID int64) {
+	p, ok := ms.products[productID]
+	if !ok {
+		return
+	}
+	p.Image = ""
+	if images := ms.productImages(productID); len(images) > 0 {
+		p.Image = images[0].URL
+	}
+}
+
+func (ms *MemoryStorer) CreateCategory(ctx context.Context, c *Category) (*Category, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategory(c); err != nil {
+		return nil, err
+	}
+
+	ms.lastCategoryID++
+	c.ID = ms.lastCategoryID
+	c.CreatedAt = time.Now()
+
+	cp := *c
+	ms.cats[c.ID] = &cp
+	return c, nil
+}
+
+// checkCategory enforces the unique slug and the parent foreign key of
+// categories. ms.mu must be held.
+func (ms *MemoryStorer) checkCategory(c *Category) error {
+	for _, existing := range ms.cats {
+		if existing.Slug == c.Slug && existing.ID != c.ID {
+			return fmt.Errorf("category %s: %w", c.Slug, ErrDuplicateCategory)
+		}
+	}
+	if c.ParentID != nil {
+		if _, ok := ms.cats[*c.ParentID]; !ok {
+			return fmt.Errorf("error inserting category: category %d does not exist", *c.ParentID)
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	c, ok := ms.cats[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting category: %w", sql.ErrNoRows)
+	}
+
+	cp := *c
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, c := range ms.cats {
+		if c.Slug == slug {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting category: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListCategories(ctx context.Context) ([]*Category, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	categories := make([]*Category, 0, len(ms.cats))
+	for _, id := range sortedKeys(ms.cats) {
+		cp := *ms.cats[id]
+		categories = append(categories, &cp)
+	}
+	slices.SortStableFunc(categories, func(a, b *Category) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return categories, nil
+}
+
+func (ms *MemoryStorer) UpdateCategory(ctx context.Context, c *Category) (*Category, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCategory(c); err != nil {
+		return nil, err
+	}
+	if existing, ok := ms.cats[c.ID]; ok {
+		cp := *c
+		cp.CreatedAt = existing.CreatedAt
+		ms.cats[c.ID] = &cp
+	}
+
+	return c, nil
+}
+
+func (ms *MemoryStorer) DeleteCategory(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, c := range ms.cats {
+		if c.ParentID != nil && *c.ParentID == id {
+			return fmt.Errorf("category %d: %w", id, ErrCategoryInUse)
+		}
+	}
+	for _, p := range ms.products {
+		if p.CategoryID != nil && *p.CategoryID == id {
+			return fmt.Errorf("category %d: %w", id, ErrCategoryInUse)
+		}
+	}
+	for _, c := range ms.coupons {
+		if c.CategoryID != nil && *c.CategoryID == id {
+			return fmt.Errorf("category %d: %w", id, ErrCategoryInUse)
+		}
+	}
+	delete(ms.cats, id)
+
+	return nil
+}
+
+func (ms *MemoryStorer) CreateReview(ctx context.Context, r *Review) (*Review, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.products[r.ProductID]; !ok {
+		return nil, fmt.Errorf("error creating review: error locking product: %w", sql.ErrNoRows)
+	}
+	if _, ok := ms.users[r.UserID]; !ok {
+		return nil, fmt.Errorf("error creating review: user %d does not exist", r.UserID)
+	}
+	for _, existing := range ms.reviews {
+		if existing.ProductID == r.ProductID && existing.UserID == r.UserID {
+			return nil, fmt.Errorf("error creating review: product %d, user %d: %w", r.ProductID, r.UserID, ErrDuplicateReview)
+		}
+	}
+
+	ms.lastReviewID++
+	r.ID = ms.lastReviewID
+	r.CreatedAt = time.Now()
+
+	cp := *r
+	ms.reviews[r.ID] = &cp
+	ms.updateProductRating(r.ProductID)
+
+	return r, nil
+}
+
+func (ms *MemoryStorer) GetReview(ctx context.Context, id int64) (*Review, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting review: %w", sql.ErrNoRows)
+	}
+
+	cp := *r
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) ListReviews(ctx context.Context, f *ReviewFilter) ([]*Review, string, error) {
+	k, err := reviewKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var reviews []*Review
+	for _, r := range ms.reviews {
+		switch {
+		case f.ProductID != 0 && r.ProductID != f.ProductID,
+			f.Status != nil && r.Status != *f.Status,
+			cur != nil && !k.after(cur, r):
+			continue
+		}
+		cp := *r
+		reviews = append(reviews, &cp)
+	}
+
+	reviews, next := memoryPage(k, reviews, f.PageSize)
+	return reviews, next, nil
+}
+
+func (ms *MemoryStorer) UpdateReviewStatus(ctx context.Context, id int64, status ReviewStatus) (*Review, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return nil, fmt.Errorf("error moderating review: error getting review: %w", sql.ErrNoRows)
+	}
+
+	r.Status = status
+	r.UpdatedAt = toTimePtr(time.Now())
+	ms.updateProductRating(r.ProductID)
+
+	cp := *r
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) DeleteReview(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	r, ok := ms.reviews[id]
+	if !ok {
+		return fmt.Errorf("error deleting review: error getting review: %w", sql.ErrNoRows)
+	}
+
+	delete(ms.reviews, id)
+	ms.updateProductRating(r.ProductID)
+
+	return nil
+}
+
+func (ms *MemoryStorer) HasDeliveredProduct(ctx context.Context, userID, productID int64) (bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, o := range ms.orders {
+		if o.UserID != userID || o.Status != Delivered {
+			continue
+		}
+		for _, oi := range o.Items {
+			if oi.ProductID == productID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// updateProductRating sets the rating of a product to the rounded average of
+// its published reviews. ms.mu must be held.
+func (ms *MemoryStorer) updateProductRating(productID int64) {
+	p, ok := ms.products[productID]
+	if !ok {
+		return
+	}
+
+	var sum, n int64
+	for _, r := range ms.reviews {
+		if r.ProductID == productID && r.Status == ReviewPublished {
+			sum += r.Rating
+			n++
+		}
+	}
+
+	p.NumReviews = n
+	p.Rating = 0
+	if n > 0 {
+		p.Rating = int64(math.Round(float64(sum) / float64(n)))
+	}
+}
+
+func (ms *MemoryStorer) CreateCoupon(ctx context.Context, c *Coupon) (*Coupon, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if err := ms.checkCoupon(c); err != nil {
+		return nil, err
+	}
+
+	ms.lastCouponID++
+	c.ID = ms.lastCouponID
+	c.CreatedAt = time.Now()
+
+	cp := *c
+	ms.coupons[c.ID] = &cp
+	return c, nil
+}
+
+// checkCoupon enforces the unique code and the product and category foreign
+// keys of coupons. ms.mu must be held.
+func (ms *MemoryStorer) checkCoupon(c *Coupon) error {
+	for _, existing := range ms.coupons {
+		if existing.Code == c.Code && existing.ID != c.ID {
+			return fmt.Errorf("coupon %s: %w", c.Code, ErrDuplicateCoupon)
+		}
+	}
+	if c.ProductID != nil {
+		if _, ok := ms.products[*c.ProductID]; !ok {
+			return fmt.Errorf("error inserting coupon: product %d does not exist", *c.ProductID)
+		}
+	}
+	if c.CategoryID != nil {
+		if _, ok := ms.cats[*c.CategoryID]; !ok {
+			return fmt.Errorf("error inserting coupon: category %d does not exist", *c.CategoryID)
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) GetCoupon(ctx context.Context, id int64) (*Coupon, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	c, ok := ms.coupons[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting coupon: %w", sql.ErrNoRows)
+	}
+
+	cp := *c
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) GetCouponByCode(ctx context.Context, code string) (*Coupon, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, c := range ms.coupons {
+		if c.Code == code {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting coupon: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListCoupons(ctx context.Context, f *CouponFilter) ([]*Coupon, string, error) {
+	k, err := couponKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var coupons []*Coupon
+	for _, c := range ms.coupons {
+		if cur != nil && !k.after(cur, c) {
+			continue
+		}
+		cp := *c
+		coupons = append(coupons, &cp)
+	}
+
+	coupons, next := memoryPage(k, coupons, f.PageSize)
+	return coupons, next, nil
+}
+
+func (ms *MemoryStorer) UpdateCoupon(ctx context.Context, c *Coupon) (*Coupon, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.coupons[c.ID]; !ok {
+		return c, nil
+	}
+	if err := ms.checkCoupon(c); err != nil {
+		return nil, err
+	}
+
+	cp := *c
+	ms.coupons[c.ID] = &cp
+	return c, nil
+}
+
+func (ms *MemoryStorer) DeleteCoupon(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.deleteCoupon(id)
+	return nil
+}
+
+// deleteCoupon deletes the coupon and unlinks the orders that redeemed it.
+// ms.mu must be held.
+func (ms *MemoryStorer) deleteCoupon(id int64) {
+	delete(ms.coupons, id)
+	for _, o := range ms.orders {
+		if o.CouponID != nil && *o.CouponID == id {
+			o.CouponID = nil
+		}
+	}
+}
+
+func (ms *MemoryStorer) CountCouponUses(ctx context.Context, couponID, userID int64) (int64, int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	uses, userUses := ms.countCouponUses(couponID, userID)
+	return uses, userUses, nil
+}
+
+// countCouponUses counts the orders that redeemed the coupon, in total and by
+// the user. ms.mu must be held.
+func (ms *MemoryStorer) countCouponUses(couponID, userID int64) (int64, int64) {
+	var uses, userUses int64
+	for _, o := range ms.orders {
+		if o.CouponID == nil || *o.CouponID != couponID {
+			continue
+		}
+		uses++
+		if o.UserID == userID {
+			userUses++
+		}
+	}
+	return uses, userUses
+}
+
+func (ms *MemoryStorer) CreateOrder(ctx context.Context, o *Order) (*Order, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	err := ms.placeOrder(o)
+	if err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+
+	return o, nil
+}
+
+// placeOrder takes the items of the order out of stock and stores the order.
+// ms.mu must be held.
+func (ms *MemoryStorer) placeOrder(o *Order) error {
+	if _, ok := ms.users[o.UserID]; !ok {
+		return fmt.Errorf("user %d does not exist", o.UserID)
+	}
+	if o.CouponID != nil {
+		c, ok := ms.coupons[*o.CouponID]
+		if !ok {
+			return fmt.Errorf("error locking coupon: %w", sql.ErrNoRows)
+		}
+		if c.Exhausted(ms.countCouponUses(c.ID, o.UserID)) {
+			return fmt.Errorf("coupon %s: %w", c.Code, ErrCouponExhausted)
+		}
+	}
+	quantities := stockQuantities(o.Items)
+	for id, quantity := range quantities {
+		p, ok := ms.products[id]
+		if !ok {
+			return fmt.Errorf("error creating order item: product %d does not exist", id)
+		}
+		if p.CountInStock < quantity {
+			return fmt.Errorf("product %d: %w", id, ErrInsufficientStock)
+		}
+	}
+	variantQuantities := variantQuantities(o.Items)
+	for id, quantity := range variantQuantities {
+		v, ok := ms.variants[id]
+		if !ok {
+			return fmt.Errorf("error creating order item: variant %d does not exist", id)
+		}
+		if v.CountInStock < quantity {
+			return fmt.Errorf("variant %d: %w", id, ErrInsufficientStock)
+		}
+	}
+	for id, quantity := range quantities {
+		ms.products[id].CountInStock -= quantity
+	}
+	for id, quantity := range variantQuantities {
+		ms.variants[id].CountInStock -= quantity
+	}
+
+	ms.lastOrderID++
+	o.ID = ms.lastOrderID
+	o.Status = Pending
+	o.CreatedAt = time.Now()
+	o.UpdatedAt = nil
+
+	items := make([]OrderItem, len(o.Items))
+	for i, oi := range o.Items {
+		ms.lastOrderItemID++
+		oi.ID = ms.lastOrderItemID
+		oi.OrderID = o.ID
+		items[i] = oi
+	}
+	o.Items = items
+
+	ms.orders[o.ID] = copyOrder(o)
+	ms.addOrderStatusChange(&OrderStatusChange{
+		OrderID:   o.ID,
+		ToStatus:  Pending,
+		ChangedBy: o.UserID,
+	})
+
+	return nil
+}
+
+func (ms *MemoryStorer) GetOrder(ctx context.Context, id int64) (*Order, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	o, ok := ms.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting order: %w", sql.ErrNoRows)
+	}
+
+	return copyOrder(o), nil
+}
+
+func (ms *MemoryStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	o, ok := ms.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting order: %w", sql.ErrNoRows)
+	}
+
+	return &Order{
+		ID:     o.ID,
+		UserID: o.UserID,
+		Status: o.Status,
+	}, nil
+}
+
+func (ms *MemoryStorer) ListOrders(ctx context.Context, f *OrderFilter) ([]*Order, string, error) {
+	k, err := orderKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var orders []*Order
+	for _, o := range ms.orders {
+		switch {
+		case f.UserID != 0 && o.UserID != f.UserID,
+			f.Status != nil && o.Status != *f.Status,
+			!f.CreatedAfter.IsZero() && o.CreatedAt.Before(f.CreatedAfter),
+			!f.CreatedBefore.IsZero() && !o.CreatedAt.Before(f.CreatedBefore),
+			cur != nil && !k.after(cur, o):
+			continue
+		}
+		orders = append(orders, copyOrder(o))
+	}
+
+	orders, next := memoryPage(k, orders, f.PageSize)
+	return orders, next, nil
+}
+
+func (ms *MemoryStorer) UpdateOrderStatus(ctx context.Context, c *OrderStatusChange) (*OrderStatusChange, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	o, ok := ms.orders[c.OrderID]
+	if !ok || c.FromStatus == nil || o.Status != *c.FromStatus {
+		return nil, fmt.Errorf("error updating order status: order %d: %w", c.OrderID, ErrOrderStatusConflict)
+	}
+	if c.Invoice != nil {
+		err := ms.addInvoice(c.Invoice)
+		if err != nil {
+			return nil, fmt.Errorf("error updating order status: %w", err)
+		}
+	}
+
+	o.Status = c.ToStatus
+	o.UpdatedAt = toTimePtr(time.Now())
+	if c.restocks() {
+		ms.restock(o)
+	}
+	ms.addOrderStatusChange(c)
+
+	return c, nil
+}
+
+func (ms *MemoryStorer) addOrderStatusChange(c *OrderStatusChange) {
+	ms.lastChangeID++
+	c.ID = ms.lastChangeID
+	c.CreatedAt = time.Now()
+
+	cp := *c
+	ms.history = append(ms.history, &cp)
+}
+
+func (ms *MemoryStorer) ListOrderStatusHistory(ctx context.Context, orderID int64) ([]*OrderStatusChange, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var changes []*OrderStatusChange
+	for _, c := range ms.history {
+		if c.OrderID == orderID {
+			cp := *c
+			changes = append(changes, &cp)
+		}
+	}
+
+	return changes, nil
+}
+
+func (ms *MemoryStorer) restock(o *Order) {
+	for _, oi := range o.Items {
+		if p, ok := ms.products[oi.ProductID]; ok {
+			p.CountInStock += oi.Quantity
+		}
+		if oi.VariantID == nil {
+			continue
+		}
+		if v, ok := ms.variants[*oi.VariantID]; ok {
+			v.CountInStock += oi.Quantity
+		}
+	}
+}
+
+func (ms *MemoryStorer) DeleteOrder(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, es := range ms.states {
+		if es.OrderID == id {
+			return fmt.Errorf("error deleting order transaction: order %d is referenced by notification state %d", id, es.ID)
+		}
+	}
+	for _, ev := range ms.events {
+		if ev.OrderID == id {
+			return fmt.Errorf("error deleting order transaction: order %d is referenced by notification event %d", id, ev.ID)
+		}
+	}
+	o, ok := ms.orders[id]
+	if !ok {
+		return fmt.Errorf("error deleting order transaction: %w", sql.ErrNoRows)
+	}
+	if o.Status.holdsStock() {
+		ms.restock(o)
+	}
+	delete(ms.orders, id)
+	for pid, p := range ms.payments {
+		if p.OrderID == id {
+			delete(ms.payments, pid)
+		}
+	}
+
+	history := ms.history[:0]
+	for _, c := range ms.history {
+		if c.OrderID != id {
+			history = append(history, c)
+		}
+	}
+	ms.history = history
+
+	return nil
+}
+
+func (ms *MemoryStorer) CreatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.orders[p.OrderID]; !ok {
+		return nil, fmt.Errorf("error inserting payment: order %d does not exist", p.OrderID)
+	}
+	for _, existing := range ms.payments {
+		if existing.Provider == p.Provider && existing.ProviderRef == p.ProviderRef {
+			return nil, fmt.Errorf("error inserting payment: duplicate payment %s %s", p.Provider, p.ProviderRef)
+		}
+	}
+
+	ms.lastPaymentID++
+	p.ID = ms.lastPaymentID
+	p.CreatedAt = time.Now()
+
+	cp := *p
+	ms.payments[p.ID] = &cp
+	return p, nil
+}
+
+func (ms *MemoryStorer) GetPaymentByRef(ctx context.Context, provider, ref string) (*Payment, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, p := range ms.payments {
+		if p.Provider == provider && p.ProviderRef == ref {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting payment: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var payments []*Payment
+	for _, id := range sortedKeys(ms.payments) {
+		if p := ms.payments[id]; p.OrderID == orderID {
+			cp := *p
+			payments = append(payments, &cp)
+		}
+	}
+	return payments, nil
+}
+
+func (ms *MemoryStorer) UpdatePaymentStatus(ctx context.Context, p *Payment, from PaymentStatus) (*Payment, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.payments[p.ID]
+	if !ok || existing.Status != from {
+		return nil, fmt.Errorf("error updating payment status: payment %d: %w", p.ID, ErrPaymentStatusConflict)
+	}
+
+	p.UpdatedAt = toTimePtr(time.Now())
+	existing.Status = p.Status
+	existing.DeclineReason = p.DeclineReason
+	existing.UpdatedAt = copyTime(p.UpdatedAt)
+	return p, nil
+}
+
+func (ms *MemoryStorer) CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	err := ms.addInvoice(inv)
+	if err != nil {
+		return nil, fmt.Errorf("error creating invoice: %w", err)
+	}
+	return inv, nil
+}
+
+func (ms *MemoryStorer) addInvoice(inv *Invoice) error {
+	for _, existing := range ms.invoices {
+		if existing.OrderID == inv.OrderID {
+			return fmt.Errorf("order %d: %w", inv.OrderID, ErrInvoiceExists)
+		}
+	}
+
+	ms.lastInvoiceNumber++
+	ms.lastInvoiceID++
+	inv.ID = ms.lastInvoiceID
+	inv.Number = ms.lastInvoiceNumber
+	inv.IssuedAt = time.Now()
+	for i := range inv.Lines {
+		ms.lastInvoiceLineID++
+		inv.Lines[i].ID = ms.lastInvoiceLineID
+		inv.Lines[i].InvoiceID = inv.ID
+	}
+
+	cp := *inv
+	cp.Lines = slices.Clone(inv.Lines)
+	ms.invoices[inv.ID] = &cp
+	return nil
+}
+
+func (ms *MemoryStorer) GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, inv := range ms.invoices {
+		if inv.OrderID == orderID {
+			cp := *inv
+			cp.Lines = slices.Clone(inv.Lines)
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting invoice: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) GetCart(ctx context.Context, co CartOwner) (*Cart, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	c, ok := ms.carts[co.key()]
+	if !ok {
+		userID, token := co.values()
+		return &Cart{UserID: userID, Token: token}, nil
+	}
+
+	cp := *c
+	cp.Items = ms.cartItems(c)
+	return &cp, nil
+}
+
+// cartItems returns a copy of the items of the cart with the details of their
+// products and variants. ms.mu must be held.
+func (ms *MemoryStorer) cartItems(c *Cart) []CartItem {
+	items := make([]CartItem, len(c.Items))
+	for i, ci := range c.Items {
+		p := ms.products[ci.ProductID]
+		ci.Name = p.Name
+		ci.Image = p.Image
+		ci.Price = p.Price
+		ci.Currency = p.Currency
+		ci.CountInStock = p.CountInStock
+		ci.Options = nil
+		if ci.VariantID != nil {
+			v := ms.variants[*ci.VariantID]
+			if v.Price != nil {
+				ci.Price = *v.Price
+			}
+			ci.CountInStock = v.CountInStock
+			ci.Options = maps.Clone(v.Options)
+		}
+		items[i] = ci
+	}
+	return items
+}
+
+func (ms *MemoryStorer) AddCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[co.UserID]; co.UserID != 0 && !ok {
+		return fmt.Errorf("error adding cart item: user %d does not exist", co.UserID)
+	}
+	if _, ok := ms.products[productID]; !ok {
+		return fmt.Errorf("error adding cart item: product %d does not exist", productID)
+	}
+	if variantID != nil {
+		if v, ok := ms.variants[*variantID]; !ok || v.ProductID != productID {
+			return fmt.Errorf("error adding cart item: product %d has no variant %d", productID, *variantID)
+		}
+	}
+
+	now := time.Now()
+	c := ms.upsertCart(co, now)
+	if i := cartItemIndex(c, productID, variantID); i >= 0 {
+		c.Items[i].Quantity += quantity
+		c.Items[i].UpdatedAt = &now
+		return nil
+	}
+
+	ms.addCartItem(c, productID, variantID, quantity, now)
+	return nil
+}
+
+// upsertCart returns the cart of the owner, creating it if needed. ms.mu must
+// be held.
+func (ms *MemoryStorer) upsertCart(co CartOwner, now time.Time) *Cart {
+	c, ok := ms.carts[co.key()]
+	if ok {
+		c.UpdatedAt = &now
+		return c
+	}
+
+	ms.lastCartID++
+	userID, token := co.values()
+	c = &Cart{ID: ms.lastCartID, UserID: userID, Token: token, CreatedAt: now}
+	ms.carts[co.key()] = c
+	return c
+}
+
+func (ms *MemoryStorer) addCartItem(c *Cart, productID int64, variantID *int64, quantity int64, now time.Time) {
+	ms.lastCartItemID++
+	c.Items = append(c.Items, CartItem{
+		ID:        ms.lastCartItemID,
+		CartID:    c.ID,
+		ProductID: productID,
+		VariantID: copyID(variantID),
+		Quantity:  quantity,
+		CreatedAt: now,
+	})
+}
+
+func (ms *MemoryStorer) SetCartItemQuantity(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	c := ms.carts[co.key()]
+	i := cartItemIndex(c, productID, variantID)
+	if i < 0 {
+		return fmt.Errorf("error getting cart item: %w", sql.ErrNoRows)
+	}
+
+	c.Items[i].Quantity = quantity
+	c.Items[i].UpdatedAt = toTimePtr(time.Now())
+	return nil
+}
+
+func (ms *MemoryStorer) RemoveCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	c := ms.carts[co.key()]
+	i := cartItemIndex(c, productID, variantID)
+	if i < 0 {
+		return fmt.Errorf("error getting cart item: %w", sql.ErrNoRows)
+	}
+
+	c.Items = slices.Delete(c.Items, i, i+1)
+	return nil
+}
+
+func (ms *MemoryStorer) ClearCart(ctx context.Context, co CartOwner) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if c, ok := ms.carts[co.key()]; ok {
+		c.Items = nil
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) MergeCart(ctx context.Context, token string, userID int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	guestOwner := CartOwner{Token: token}
+	guest, ok := ms.carts[guestOwner]
+	if !ok {
+		return nil
+	}
+	if _, ok := ms.users[userID]; !ok {
+		return fmt.Errorf("error merging cart: user %d does not exist", userID)
+	}
+
+	now := time.Now()
+	c := ms.upsertCart(CartOwner{UserID: userID}, now)
+	for _, ci := range ms.cartItems(guest) {
+		i := cartItemIndex(c, ci.ProductID, ci.VariantID)
+		if i < 0 {
+			if quantity := mergedQuantity(0, ci.Quantity, ci.CountInStock); quantity > 0 {
+				ms.addCartItem(c, ci.ProductID, ci.VariantID, quantity, now)
+			}
+			continue
+		}
+		if quantity := mergedQuantity(c.Items[i].Quantity, ci.Quantity, ci.CountInStock); quantity != c.Items[i].Quantity {
+			c.Items[i].Quantity = quantity
+			c.Items[i].UpdatedAt = &now
+		}
+	}
+	delete(ms.carts, guestOwner)
+
+	return nil
+}
+
+func (ms *MemoryStorer) CheckoutCart(ctx context.Context, o *Order) (*Order, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	c, ok := ms.carts[CartOwner{UserID: o.UserID}]
+	if !ok || !sameItems(c.Items, o.Items) {
+		return nil, fmt.Errorf("error checking out cart: %w", ErrCartChanged)
+	}
+
+	err := ms.placeOrder(o)
+	if err != nil {
+		return nil, fmt.Errorf("error checking out cart: %w", err)
+	}
+	c.Items = nil
+
+	return o, nil
+}
+
+// cartItemIndex returns the index of the product, or of its variant unless
+// variantID is nil, in the cart, or -1 if the cart is nil or does not hold it.
+func cartItemIndex(c *Cart, productID int64, variantID *int64) int {
+	if c == nil {
+		return -1
+	}
+	key := itemKey(productID, variantID)
+	return slices.IndexFunc(c.Items, func(ci CartItem) bool { return itemKey(ci.ProductID, ci.VariantID) == key })
+}
+
+func (ms *MemoryStorer) CreateUser(ctx context.Context, u *User) (*User, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, existing := range ms.users {
+		if existing.Email == u.Email {
+			return nil, fmt.Errorf("error inserting user: duplicate email %q", u.Email)
+		}
+	}
+
+	ms.lastUserID++
+	u.ID = ms.lastUserID
+	u.CreatedAt = time.Now()
+
+	cp := *u
+	ms.users[u.ID] = &cp
+
+	return u, nil
+}
+
+func (ms *MemoryStorer) GetUser(ctx context.Context, email string) (*User, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	for _, u := range ms.users {
+		if u.Email == email {
+			cp := *u
+			return &cp, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error getting user: %w", sql.ErrNoRows)
+}
+
+func (ms *MemoryStorer) GetUserByID(ctx context.Context, id int64) (*User, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	u, ok := ms.users[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting user: %w", sql.ErrNoRows)
+	}
+
+	cp := *u
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) ListUsers(ctx context.Context, f *UserFilter) ([]*User, string, error) {
+	k, err := userKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var users []*User
+	for _, u := range ms.users {
+		switch {
+		case f.IsAdmin != nil && u.IsAdmin != *f.IsAdmin,
+			!f.CreatedAfter.IsZero() && u.CreatedAt.Before(f.CreatedAfter),
+			!f.CreatedBefore.IsZero() && !u.CreatedAt.Before(f.CreatedBefore),
+			cur != nil && !k.after(cur, u):
+			continue
+		}
+		cp := *u
+		users = append(users, &cp)
+	}
+
+	users, next := memoryPage(k, users, f.PageSize)
+	return users, next, nil
+}
+
+func (ms *MemoryStorer) UpdateUser(ctx context.Context, u *User) (*User, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	existing, ok := ms.users[u.ID]
+	if !ok {
+		return u, nil
+	}
+	for id, other := range ms.users {
+		if id != u.ID && other.Email == u.Email {
+			return nil, fmt.Errorf("error updating user: duplicate email %q", u.Email)
+		}
+	}
+
+	cp := *u
+	cp.CreatedAt = existing.CreatedAt
+	cp.UpdatedAt = toTimePtr(time.Now())
+	ms.users[u.ID] = &cp
+
+	return u, nil
+}
+
+func (ms *MemoryStorer) DeleteUser(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, o := range ms.orders {
+		if o.UserID == id {
+			return fmt.Errorf("error deleting user: user %d is referenced by order %d", id, o.ID)
+		}
+	}
+	for _, r := range ms.reviews {
+		if r.UserID == id {
+			return fmt.Errorf("error deleting user: user %d is referenced by review %d", id, r.ID)
+		}
+	}
+	delete(ms.users, id)
+	delete(ms.carts, CartOwner{UserID: id})
+	for aid, a := range ms.addrs {
+		if a.UserID == id {
+			delete(ms.addrs, aid)
+		}
+	}
+	for kid := range ms.idemKeys {
+		if kid.userID == id {
+			delete(ms.idemKeys, kid)
+		}
+	}
+
+	return nil
+}
+
+func (ms *MemoryStorer) CreateAddress(ctx context.Context, a *Address) (*Address, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[a.UserID]; !ok {
+		return nil, fmt.Errorf("error inserting address: user %d does not exist", a.UserID)
+	}
+
+	ms.lastAddressID++
+	a.ID = ms.lastAddressID
+	a.CreatedAt = time.Now()
+
+	cp := *a
+	ms.addrs[a.ID] = &cp
+	ms.clearDefaultAddress(a)
+
+	return a, nil
+}
+
+func (ms *MemoryStorer) GetAddress(ctx context.Context, id int64) (*Address, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	a, ok := ms.addrs[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting address: %w", sql.ErrNoRows)
+	}
+
+	cp := *a
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) ListUserAddresses(ctx context.Context, userID int64) ([]*Address, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var addresses []*Address
+	for _, id := range sortedKeys(ms.addrs) {
+		if a := ms.addrs[id]; a.UserID == userID {
+			cp := *a
+			addresses = append(addresses, &cp)
+		}
+	}
+	slices.SortStableFunc(addresses, func(a, b *Address) int {
+		switch {
+		case a.IsDefault == b.IsDefault:
+			return 0
+		case a.IsDefault:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return addresses, nil
+}
+
+func (ms *MemoryStorer) UpdateAddress(ctx context.Context, a *Address) (*Address, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.addrs[a.ID]; !ok {
+		return a, nil
+	}
+
+	cp := *a
+	ms.addrs[a.ID] = &cp
+	ms.clearDefaultAddress(a)
+
+	return a, nil
+}
+
+// clearDefaultAddress unsets the other default addresses of the user if a is
+// the default one. ms.mu must be held.
+func (ms *MemoryStorer) clearDefaultAddress(a *Address) {
+	if !a.IsDefault {
+		return
+	}
+	for id, other := range ms.addrs {
+		if other.UserID == a.UserID && id != a.ID {
+			other.IsDefault = false
+		}
+	}
+}
+
+func (ms *MemoryStorer) DeleteAddress(ctx context.Context, id int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.addrs, id)
+	return nil
+}
+
+func (ms *MemoryStorer) CreateSession(ctx context.Context, s *Session) (*Session, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.sessions[s.ID]; ok {
+		return nil, fmt.Errorf("error inserting session: duplicate id %q", s.ID)
+	}
+
+	cp := *s
+	cp.CreatedAt = time.Now()
+	ms.sessions[s.ID] = &cp
+
+	return s, nil
+}
+
+func (ms *MemoryStorer) GetSession(ctx context.Context, id string) (*Session, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	s, ok := ms.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("error getting session: %w", sql.ErrNoRows)
+	}
+
+	cp := *s
+	return &cp, nil
+}
+
+func (ms *MemoryStorer) RevokeSession(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if s, ok := ms.sessions[id]; ok {
+		s.IsRevoked = true
+	}
+
+	return nil
+}
+
+func (ms *MemoryStorer) DeleteSession(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.sessions, id)
+
+	return nil
+}
+
+// idemKeyID is the primary key of idempotency keys.
+type idemKeyID struct {
+	userID int64
+	key    string
+}
+
+func (ms *MemoryStorer) ReserveIdempotencyKey(ctx context.Context, k *IdempotencyKey) (*IdempotencyKey, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.users[k.UserID]; !ok {
+		return nil, false, fmt.Errorf("error inserting idempotency key: user %d does not exist", k.UserID)
+	}
+
+	now := time.Now()
+	id := idemKeyID{userID: k.UserID, key: k.Key}
+	if stored, ok := ms.idemKeys[id]; ok && stored.ExpiresAt.After(now) {
+		cp := *stored
+		return &cp, false, nil
+	}
+
+	k.StatusCode = 0
+	k.ContentType = ""
+	k.ResponseBody = nil
+	k.CreatedAt = now
+	cp := *k
+	ms.idemKeys[id] = &cp
+	return k, true, nil
+}
+
+func (ms *MemoryStorer) CompleteIdempotencyKey(ctx context.Context, k *IdempotencyKey) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if stored, ok := ms.idemKeys[idemKeyID{userID: k.UserID, key: k.Key}]; ok {
+		stored.StatusCode = k.StatusCode
+		stored.ContentType = k.ContentType
+		stored.ResponseBody = slices.Clone(k.ResponseBody)
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	id := idemKeyID{userID: userID, key: key}
+	if stored, ok := ms.idemKeys[id]; ok && stored.StatusCode == 0 {
+		delete(ms.idemKeys, id)
+	}
+	return nil
+}
+
+func (ms *MemoryStorer) EnqueueNotificationEvent(ctx context.Context, ne *NotificationEvent) (*NotificationEvent, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.orders[ne.OrderID]; !ok {
+		return nil, fmt.Errorf("error enqueuing notification event: order %d does not exist", ne.OrderID)
+	}
+
+	now := time.Now()
+	ms.lastStateID++
+	ms.states[ms.lastStateID] = &NotificationState{
+		ID:          ms.lastStateID,
+		OrderID:     ne.OrderID,
+		State:       NotSent,
+		RequestedAt: now,
+	}
+	ne.StateID = ms.lastStateID
+
+	ms.lastEventID++
+	ne.ID = ms.lastEventID
+	ne.CreatedAt = now
+
+	cp := *ne
+	ms.events[ne.ID] = &cp
+
+	return ne, nil
+}
+
+func (ms *MemoryStorer) ListNotificationEvents(ctx context.Context, f *NotificationEventFilter) ([]*NotificationEvent, string, error) {
+	k := notificationEventKeyset()
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var events []*NotificationEvent
+	for _, ev := range ms.events {
+		if ev.Attempts >= maxAttempts || cur != nil && !k.after(cur, ev) {
+			continue
+		}
+		cp := *ev
+		events = append(events, &cp)
+	}
+
+	events, next := memoryPage(k, events, f.PageSize)
+	return events, next, nil
+}
+
+func (ms *MemoryStorer) UpdateNotificationEvent(ctx context.Context, ev *NotificationEvent, es *NotificationState, responseType NotificationResponseType) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	switch responseType {
+	case NotificationSucess:
+		ms.setNotificationState(ev.StateID, Sent, es.Message)
+		delete(ms.events, ev.ID)
+		return true, nil
+	case NotificationFailure:
+		u, ok := ms.events[ev.ID]
+		if !ok {
+			return false, fmt.Errorf("error getting notification event: %w", sql.ErrNoRows)
+		}
+
+		if u.Attempts+1 < maxAttempts {
+			u.UpdatedAt = toTimePtr(time.Now())
+			u.Attempts += 1
+		} else {
+			ms.setNotificationState(ev.StateID, Failed, es.Message)
+			delete(ms.events, u.ID)
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid notification response type: %v", responseType)
+	}
+}
+
+func (ms *MemoryStorer) setNotificationState(id int64, state NotificationEventState, message string) {
+	es, ok := ms.states[id]
+	if !ok {
+		return
+	}
+
+	es.State = state
+	es.Message = message
+	if state == Sent {
+		es.CompletedAt = toTimePtr(time.Now())
+	}
+}
+
+func copyOrder(o *Order) *Order {
+	cp := *o
+	cp.UpdatedAt = copyTime(o.UpdatedAt)
+	cp.Items = append([]OrderItem(nil), o.Items...)
+	return &cp
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return toTimePtr(*t)
+}
+
+func copyID(id *int64) *int64 {
+	if id == nil {
+		return nil
+	}
+	cp := *id
+	return &cp
+}
+
+func toTimePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// memoryPage sorts rows, all the rows after the cursor, in the order of k and
+// cuts the first page out of them.
+func memoryPage[E any](k *keyset[E], rows []*E, size int) ([]*E, string) {
+	slices.SortFunc(rows, k.compare)
+	return k.page(rows, size)
+}
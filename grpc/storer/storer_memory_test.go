@@ -0,0 +1,1017 @@
+package storer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+	"github.com/stretchr/testify/require"
+)
+
+func seedMemoryStorer(t *testing.T) (*MemoryStorer, *User, *Product) {
+	st := NewMemoryStorer()
+	u, err := st.CreateUser(context.Background(), &User{Name: "test user", Email: "test@example.com", Password: "secret"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(context.Background(), &Product{Name: "test product", Image: "test.jpg", Price: 9999, CountInStock: 10})
+	require.NoError(t, err)
+	return st, u, p
+}
+
+func TestMemoryStorerProducts(t *testing.T) {
+	ctx := context.Background()
+	st, _, p := seedMemoryStorer(t)
+
+	require.Equal(t, int64(1), p.ID)
+	require.False(t, p.CreatedAt.IsZero())
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p, got)
+
+	got.Name = "renamed"
+	_, err = st.UpdateProduct(ctx, got)
+	require.NoError(t, err)
+
+	ps, _, err := st.ListProducts(ctx, &ProductFilter{})
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	require.Equal(t, "renamed", ps[0].Name)
+
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	_, err = st.GetProduct(ctx, p.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryStorerListProducts(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+	books, err := st.CreateCategory(ctx, &Category{Name: "Books", Slug: "books"})
+	require.NoError(t, err)
+	games, err := st.CreateCategory(ctx, &Category{Name: "Games", Slug: "games"})
+	require.NoError(t, err)
+	for _, p := range []*Product{
+		{Name: "a", CategoryID: &books.ID, Price: 2000, CountInStock: 1},
+		{Name: "b", CategoryID: &books.ID, Price: 500, CountInStock: 0},
+		{Name: "c", CategoryID: &games.ID, Price: 5000, CountInStock: 3},
+		{Name: "d", CategoryID: &books.ID, Price: 2000, CountInStock: 2},
+		{Name: "e", CategoryID: &books.ID, Price: 3500, CountInStock: 4},
+	} {
+		_, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+	}
+
+	names := func(f *ProductFilter) []string {
+		var got []string
+		for {
+			ps, next, err := st.ListProducts(ctx, f)
+			require.NoError(t, err)
+			for _, p := range ps {
+				got = append(got, p.Name)
+			}
+			if next == "" {
+				return got
+			}
+			f.PageToken = next
+		}
+	}
+
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, names(&ProductFilter{PageSize: 2}))
+	require.Equal(t, []string{"c", "e", "d", "a", "b"}, names(&ProductFilter{Sort: "-price", PageSize: 2}), "ties are broken by id")
+	require.Equal(t, []string{"b", "a", "d", "e", "c"}, names(&ProductFilter{Sort: "price", PageSize: 3}))
+
+	minPrice, maxPrice := money.Amount(1000), money.Amount(4000)
+	require.Equal(t, []string{"a", "d", "e"}, names(&ProductFilter{CategoryIDs: []int64{books.ID}, Prices: []PriceRange{{Currency: money.StoreCurrency, Min: &minPrice, Max: &maxPrice}}, InStock: true, Sort: "price", PageSize: 1}))
+
+	_, err = st.CreateProduct(ctx, &Product{Name: "f", Price: 1500, Currency: "EUR", CountInStock: 1})
+	require.NoError(t, err)
+	currencies, err := st.ListProductCurrencies(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EUR", "USD"}, currencies)
+	usd, eur := money.Amount(2000), money.Amount(1500)
+	require.Equal(t, []string{"a", "d", "f"}, names(&ProductFilter{Prices: []PriceRange{
+		{Currency: money.StoreCurrency, Min: &usd, Max: &usd},
+		{Currency: "EUR", Max: &eur},
+	}}), "each range bounds the prices in its currency")
+
+	_, _, err = st.ListProducts(ctx, &ProductFilter{Sort: "count_in_stock"})
+	require.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestMemoryStorerCategories(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	books, err := st.CreateCategory(ctx, &Category{Name: "Books", Slug: "books"})
+	require.NoError(t, err)
+	fantasy, err := st.CreateCategory(ctx, &Category{ParentID: &books.ID, Name: "Fantasy", Slug: "fantasy"})
+	require.NoError(t, err)
+	_, err = st.CreateCategory(ctx, &Category{Name: "More books", Slug: "books"})
+	require.ErrorIs(t, err, ErrDuplicateCategory)
+	unknown := int64(42)
+	_, err = st.CreateCategory(ctx, &Category{ParentID: &unknown, Name: "Orphan", Slug: "orphan"})
+	require.Error(t, err)
+
+	got, err := st.GetCategoryBySlug(ctx, "fantasy")
+	require.NoError(t, err)
+	require.Equal(t, fantasy.ID, got.ID)
+	_, err = st.GetCategoryBySlug(ctx, "orphan")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = st.CreateProduct(ctx, &Product{Name: "ghost", CategoryID: &unknown})
+	require.Error(t, err)
+	p, err := st.CreateProduct(ctx, &Product{Name: "dragons", CategoryID: &fantasy.ID})
+	require.NoError(t, err)
+
+	fantasy.Name = "Adventure"
+	fantasy.Slug = "adventure"
+	_, err = st.UpdateCategory(ctx, fantasy)
+	require.NoError(t, err)
+	cs, err := st.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, cs, 2)
+	require.Equal(t, "adventure", cs[0].Slug, "categories are sorted by name")
+
+	require.ErrorIs(t, st.DeleteCategory(ctx, books.ID), ErrCategoryInUse, "books has a subcategory")
+	require.ErrorIs(t, st.DeleteCategory(ctx, fantasy.ID), ErrCategoryInUse, "adventure has a product")
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	require.NoError(t, st.DeleteCategory(ctx, fantasy.ID))
+	require.NoError(t, st.DeleteCategory(ctx, books.ID))
+	_, err = st.GetCategory(ctx, books.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryStorerVariants(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	red, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-RED", Options: VariantOptions{"color": "red"}, CountInStock: 3})
+	require.NoError(t, err)
+	blue, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-BLUE", Options: VariantOptions{"color": "blue"}, CountInStock: 2})
+	require.NoError(t, err)
+	_, err = st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-RED"})
+	require.ErrorIs(t, err, ErrDuplicateSKU)
+	_, err = st.CreateVariant(ctx, &Variant{ProductID: 42, SKU: "GHOST"})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), got.CountInStock, "the stock of a product is the stock of its variants")
+	require.Len(t, got.Variants, 2)
+	require.Equal(t, "TEE-RED", got.Variants[0].SKU)
+
+	item := OrderItem{Name: p.Name, Quantity: 3, ProductID: p.ID, VariantID: &blue.ID}
+	_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{item}})
+	require.ErrorIs(t, err, ErrInsufficientStock)
+	item.VariantID = &red.ID
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{item}})
+	require.NoError(t, err)
+
+	v, err := st.GetVariant(ctx, red.ID)
+	require.NoError(t, err)
+	require.Zero(t, v.CountInStock)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.CountInStock)
+
+	require.ErrorIs(t, st.DeleteVariant(ctx, red.ID), ErrVariantOrdered)
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	v, err = st.GetVariant(ctx, red.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), v.CountInStock, "deleting a pending order restocks the variant")
+
+	blue.CountInStock = 7
+	_, err = st.UpdateVariant(ctx, blue)
+	require.NoError(t, err)
+	require.NoError(t, st.DeleteVariant(ctx, red.ID))
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), got.CountInStock)
+
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	_, err = st.GetVariant(ctx, blue.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows, "variants are deleted with their product")
+}
+
+func TestMemoryStorerSaveProducts(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+	lamp, desk := "LAMP-1", "DESK-1"
+
+	existing, err := st.CreateProduct(ctx, &Product{SKU: &lamp, Name: "Lamp", Price: 4000})
+	require.NoError(t, err)
+	_, err = st.CreateProduct(ctx, &Product{SKU: &lamp, Name: "Other lamp"})
+	require.ErrorIs(t, err, ErrDuplicateSKU)
+
+	created := &Product{SKU: &desk, Name: "Desk"}
+	err = st.SaveProducts(ctx, []*Product{created, {ID: existing.ID, SKU: &desk, Name: "Lamp"}})
+	require.ErrorIs(t, err, ErrDuplicateSKU)
+	require.Zero(t, created.ID)
+	ps, err := st.GetProductsBySKU(ctx, []string{lamp, desk})
+	require.NoError(t, err)
+	require.Len(t, ps, 1, "a failed save saves none of the products")
+	require.Equal(t, "Lamp", ps[0].Name)
+
+	err = st.SaveProducts(ctx, []*Product{created, {ID: existing.ID, SKU: &lamp, Name: "Desk lamp", Price: 4500}})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	ps, err = st.GetProductsBySKU(ctx, []string{lamp, desk})
+	require.NoError(t, err)
+	require.Len(t, ps, 2)
+	require.Equal(t, "Desk lamp", ps[0].Name)
+	require.Equal(t, "Desk", ps[1].Name)
+}
+
+func TestMemoryStorerProductImages(t *testing.T) {
+	ctx := context.Background()
+	st, _, p := seedMemoryStorer(t)
+
+	front, err := st.CreateProductImage(ctx, &ProductImage{ProductID: p.ID, ImageKey: "products/1/front.jpg", URL: "/images/products/1/front.jpg"})
+	require.NoError(t, err)
+	back, err := st.CreateProductImage(ctx, &ProductImage{ProductID: p.ID, ImageKey: "products/1/back.jpg", URL: "/images/products/1/back.jpg"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), back.Position, "images are appended to the gallery")
+	_, err = st.CreateProductImage(ctx, &ProductImage{ProductID: 42})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Images, 2)
+	require.Equal(t, front.URL, got.Image, "the image of a product is the first image of its gallery")
+
+	require.ErrorIs(t, st.ReorderProductImages(ctx, p.ID, []int64{back.ID}), ErrImagesMismatch)
+	require.ErrorIs(t, st.ReorderProductImages(ctx, p.ID, []int64{back.ID, back.ID}), ErrImagesMismatch)
+	require.NoError(t, st.ReorderProductImages(ctx, p.ID, []int64{back.ID, front.ID}))
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{back.ID, front.ID}, []int64{got.Images[0].ID, got.Images[1].ID})
+	require.Equal(t, back.URL, got.Image)
+
+	require.NoError(t, st.DeleteProductImage(ctx, back.ID))
+	require.NoError(t, st.DeleteProductImage(ctx, front.ID))
+	_, err = st.GetProductImage(ctx, front.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Images)
+	require.Empty(t, got.Image)
+}
+
+func TestMemoryStorerSearchProducts(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+	for _, p := range []*Product{
+		{Name: "Wireless mouse", Description: "A quiet <b>wireless</b> mouse with a USB receiver."},
+		{Name: "Keyboard", Description: "Mechanical keyboard, pairs with any wireless receiver."},
+		{Name: "Monitor", Description: "A 27 inch monitor."},
+		{Name: "Wireless headset", Description: "Wireless headset."},
+	} {
+		_, err := st.CreateProduct(ctx, p)
+		require.NoError(t, err)
+	}
+
+	ms, next, err := st.SearchProducts(ctx, &ProductSearch{Query: "Wireless"})
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Len(t, ms, 3)
+	require.Equal(t, "Wireless headset", ms[0].Name, "name and description match, ties broken by newest")
+	require.Equal(t, "Wireless mouse", ms[1].Name)
+	require.Equal(t, "Keyboard", ms[2].Name)
+	require.Greater(t, ms[1].Score, ms[2].Score)
+	require.Equal(t, "A quiet &lt;b&gt;<mark>wireless</mark>&lt;/b&gt; mouse with a USB receiver.", ms[1].Snippet)
+
+	ms, next, err = st.SearchProducts(ctx, &ProductSearch{Query: "wireless", PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	require.NotEmpty(t, next)
+
+	_, _, err = st.SearchProducts(ctx, &ProductSearch{Query: "keyboard", PageSize: 2, PageToken: next})
+	require.ErrorIs(t, err, ErrInvalidPageToken, "token was issued for another query")
+
+	ms, next, err = st.SearchProducts(ctx, &ProductSearch{Query: "wireless", PageSize: 2, PageToken: next})
+	require.NoError(t, err)
+	require.Len(t, ms, 1)
+	require.Equal(t, "Keyboard", ms[0].Name)
+	require.Empty(t, next)
+
+	ms, _, err = st.SearchProducts(ctx, &ProductSearch{Query: "a 27"})
+	require.NoError(t, err)
+	require.Empty(t, ms, "words shorter than the minimum token size are ignored")
+
+	displays, err := st.CreateCategory(ctx, &Category{Name: "Displays", Slug: "displays"})
+	require.NoError(t, err)
+	_, err = st.CreateProduct(ctx, &Product{Name: "Curved screen", Description: "A 34 inch screen.", CategoryID: &displays.ID})
+	require.NoError(t, err)
+	ms, _, err = st.SearchProducts(ctx, &ProductSearch{Query: "displays"})
+	require.NoError(t, err)
+	require.Len(t, ms, 1, "products are found by the name of their category")
+	require.Equal(t, "Curved screen", ms[0].Name)
+	require.Equal(t, "Curved screen", ms[0].Snippet, "the name stands in for a description without the terms")
+}
+
+func TestMemoryStorerReviews(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	other, err := st.CreateUser(ctx, &User{Email: "other@example.com"})
+	require.NoError(t, err)
+
+	r1, err := st.CreateReview(ctx, &Review{ProductID: p.ID, UserID: u.ID, Rating: 5, Status: ReviewPublished})
+	require.NoError(t, err)
+	_, err = st.CreateReview(ctx, &Review{ProductID: p.ID, UserID: other.ID, Rating: 2, Status: ReviewPublished})
+	require.NoError(t, err)
+	_, err = st.CreateReview(ctx, &Review{ProductID: p.ID, UserID: u.ID, Rating: 1, Status: ReviewPublished})
+	require.ErrorIs(t, err, ErrDuplicateReview)
+	_, err = st.CreateReview(ctx, &Review{ProductID: 42, UserID: u.ID, Rating: 1, Status: ReviewPublished})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got.Rating, "3.5 rounds half away from zero")
+	require.Equal(t, int64(2), got.NumReviews)
+
+	_, err = st.UpdateReviewStatus(ctx, r1.ID, ReviewHidden)
+	require.NoError(t, err)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.Rating, "hidden reviews do not count")
+	require.Equal(t, int64(1), got.NumReviews)
+
+	published := ReviewPublished
+	reviews, _, err := st.ListReviews(ctx, &ReviewFilter{ProductID: p.ID, Status: &published})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	require.Equal(t, other.ID, reviews[0].UserID)
+
+	require.Error(t, st.DeleteUser(ctx, other.ID), "user is referenced by a review")
+	require.NoError(t, st.DeleteReview(ctx, reviews[0].ID))
+	require.ErrorIs(t, st.DeleteReview(ctx, reviews[0].ID), sql.ErrNoRows)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.Rating)
+	require.Zero(t, got.NumReviews)
+
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	reviews, _, err = st.ListReviews(ctx, &ReviewFilter{})
+	require.NoError(t, err)
+	require.Empty(t, reviews, "reviews are deleted with their product")
+}
+
+func TestMemoryStorerCoupons(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	c, err := st.CreateCoupon(ctx, &Coupon{Code: "SPRING", Kind: PercentageCoupon, Value: 10, MaxUsesPerUser: 1, ProductID: &p.ID})
+	require.NoError(t, err)
+	_, err = st.CreateCoupon(ctx, &Coupon{Code: "SPRING", Kind: FixedCoupon, Value: 5})
+	require.ErrorIs(t, err, ErrDuplicateCoupon)
+	unknown := int64(42)
+	_, err = st.CreateCoupon(ctx, &Coupon{Code: "GHOST", Kind: FixedCoupon, Value: 5, ProductID: &unknown})
+	require.Error(t, err)
+
+	got, err := st.GetCouponByCode(ctx, "SPRING")
+	require.NoError(t, err)
+	require.Equal(t, c.ID, got.ID)
+
+	order := func() *Order {
+		return &Order{UserID: u.ID, CouponID: &c.ID, CouponCode: &c.Code, Items: []OrderItem{{Name: p.Name, Quantity: 1, ProductID: p.ID}}}
+	}
+	o, err := st.CreateOrder(ctx, order())
+	require.NoError(t, err)
+	_, err = st.CreateOrder(ctx, order())
+	require.ErrorIs(t, err, ErrCouponExhausted)
+
+	uses, userUses, err := st.CountCouponUses(ctx, c.ID, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), uses)
+	require.Equal(t, int64(1), userUses)
+
+	require.NoError(t, st.DeleteCoupon(ctx, c.ID))
+	o, err = st.GetOrder(ctx, o.ID)
+	require.NoError(t, err)
+	require.Nil(t, o.CouponID, "deleting the coupon unlinks its orders")
+	require.Equal(t, "SPRING", *o.CouponCode, "the redeemed code is kept on the order")
+}
+
+func TestMemoryStorerOrders(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	tcs := []struct {
+		name    string
+		order   *Order
+		wantErr bool
+	}{
+		{
+			name:  "success",
+			order: &Order{UserID: u.ID, PaymentMethod: "card", Items: []OrderItem{{Name: p.Name, Quantity: 1, ProductID: p.ID}}},
+		},
+		{
+			name:    "unknown user",
+			order:   &Order{UserID: 42, Items: []OrderItem{{ProductID: p.ID}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown product",
+			order:   &Order{UserID: u.ID, Items: []OrderItem{{ProductID: 42}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := st.CreateOrder(ctx, tc.order)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, Pending, o.Status)
+			require.Equal(t, o.ID, o.Items[0].OrderID)
+			require.NotZero(t, o.Items[0].ID)
+		})
+	}
+
+	orders, _, err := st.ListOrders(ctx, &OrderFilter{UserID: u.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	o, err := st.GetOrder(ctx, orders[0].ID)
+	require.NoError(t, err)
+	require.Len(t, o.Items, 1)
+
+	require.Error(t, st.DeleteProduct(ctx, p.ID), "product is referenced by an order item")
+	require.Error(t, st.DeleteUser(ctx, u.ID), "user is referenced by an order")
+
+	from := Pending
+	_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &from, ToStatus: Processing, ChangedBy: u.ID})
+	require.NoError(t, err)
+	os, err := st.GetOrderStatusByID(ctx, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, Processing, os.Status)
+
+	_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &from, ToStatus: Paid, ChangedBy: u.ID})
+	require.ErrorIs(t, err, ErrOrderStatusConflict)
+
+	history, err := st.ListOrderStatusHistory(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Nil(t, history[0].FromStatus)
+	require.Equal(t, Pending, history[0].ToStatus)
+	require.Equal(t, Pending, *history[1].FromStatus)
+	require.Equal(t, Processing, history[1].ToStatus)
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	orders, _, err = st.ListOrders(ctx, &OrderFilter{})
+	require.NoError(t, err)
+	require.Empty(t, orders)
+}
+
+func TestMemoryStorerPayments(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{Name: p.Name, Quantity: 1, ProductID: p.ID}}})
+	require.NoError(t, err)
+
+	pay, err := st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", ProviderRef: "fake_pay_1", Amount: 1000, Status: PaymentAuthorized})
+	require.NoError(t, err)
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: o.ID, Provider: "fake", ProviderRef: "fake_pay_1", Status: PaymentAuthorized})
+	require.Error(t, err, "provider reference is taken")
+	_, err = st.CreatePayment(ctx, &Payment{OrderID: 42, Provider: "fake", ProviderRef: "fake_pay_2", Status: PaymentAuthorized})
+	require.Error(t, err, "unknown order")
+
+	pay.Status = PaymentCaptured
+	_, err = st.UpdatePaymentStatus(ctx, pay, PaymentAuthorized)
+	require.NoError(t, err)
+	_, err = st.UpdatePaymentStatus(ctx, pay, PaymentAuthorized)
+	require.ErrorIs(t, err, ErrPaymentStatusConflict)
+
+	got, err := st.GetPaymentByRef(ctx, "fake", "fake_pay_1")
+	require.NoError(t, err)
+	require.Equal(t, PaymentCaptured, got.Status)
+	_, err = st.GetPaymentByRef(ctx, "other", "fake_pay_1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	payments, err := st.ListOrderPayments(ctx, o.ID)
+	require.NoError(t, err)
+	require.Empty(t, payments, "deleting the order deletes its payments")
+}
+
+func TestMemoryStorerInvoices(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	_, err := st.GetOrderInvoice(ctx, 1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	inv, err := st.CreateInvoice(ctx, &Invoice{OrderID: 1, TotalPrice: 1000, Lines: []InvoiceLine{{ProductID: 1, Quantity: 1, Price: 1000}}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), inv.Number)
+	require.Equal(t, inv.ID, inv.Lines[0].InvoiceID)
+
+	_, err = st.CreateInvoice(ctx, &Invoice{OrderID: 1})
+	require.ErrorIs(t, err, ErrInvoiceExists)
+
+	next, err := st.CreateInvoice(ctx, &Invoice{OrderID: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), next.Number, "a rejected invoice takes no number")
+
+	got, err := st.GetOrderInvoice(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, inv, got)
+
+	pending := Pending
+	pay := func(o *Order) error {
+		_, err := st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &pending, ToStatus: Paid, ChangedBy: u.ID, Invoice: &Invoice{OrderID: o.ID}})
+		return err
+	}
+	for range 3 {
+		_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{Name: p.Name, Quantity: 1, Price: p.Price, ProductID: p.ID}}})
+		require.NoError(t, err)
+	}
+
+	invoiced, err := st.GetOrder(ctx, 1)
+	require.NoError(t, err)
+	require.ErrorIs(t, pay(invoiced), ErrInvoiceExists)
+	os, err := st.GetOrderStatusByID(ctx, invoiced.ID)
+	require.NoError(t, err)
+	require.Equal(t, Pending, os.Status, "orders whose invoice fails stay unpaid")
+
+	o, err := st.GetOrder(ctx, 3)
+	require.NoError(t, err)
+	require.NoError(t, pay(o))
+	got, err = st.GetOrderInvoice(ctx, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got.Number)
+}
+
+func TestMemoryStorerAddresses(t *testing.T) {
+	ctx := context.Background()
+	st, u, _ := seedMemoryStorer(t)
+
+	home, err := st.CreateAddress(ctx, &Address{UserID: u.ID, Name: "home", IsDefault: true})
+	require.NoError(t, err)
+	work, err := st.CreateAddress(ctx, &Address{UserID: u.ID, Name: "work"})
+	require.NoError(t, err)
+	_, err = st.CreateAddress(ctx, &Address{UserID: 42, Name: "unknown"})
+	require.Error(t, err, "unknown user")
+
+	work.IsDefault = true
+	_, err = st.UpdateAddress(ctx, work)
+	require.NoError(t, err)
+
+	addresses, err := st.ListUserAddresses(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, addresses, 2)
+	require.Equal(t, work.ID, addresses[0].ID, "the default address comes first")
+	require.False(t, addresses[1].IsDefault, "a new default address replaces the previous one")
+
+	require.NoError(t, st.DeleteAddress(ctx, home.ID))
+	_, err = st.GetAddress(ctx, home.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryStorerListOrders(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	other, err := st.CreateUser(ctx, &User{Name: "other", Email: "other@example.com"})
+	require.NoError(t, err)
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}})
+		require.NoError(t, err)
+		ids = append(ids, o.ID)
+	}
+	_, err = st.CreateOrder(ctx, &Order{UserID: other.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}})
+	require.NoError(t, err)
+
+	// orders created within the same clock tick are ordered by id
+	var got []int64
+	f := &OrderFilter{UserID: u.ID, PageSize: 2}
+	for {
+		orders, next, err := st.ListOrders(ctx, f)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(orders), 2)
+		for _, o := range orders {
+			require.Len(t, o.Items, 1)
+			got = append(got, o.ID)
+		}
+		if next == "" {
+			break
+		}
+		f.PageToken = next
+	}
+	require.Equal(t, []int64{ids[4], ids[3], ids[2], ids[1], ids[0]}, got)
+
+	from := Pending
+	_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: ids[1], FromStatus: &from, ToStatus: Cancelled, ChangedBy: u.ID})
+	require.NoError(t, err)
+
+	cancelled := Cancelled
+	orders, next, err := st.ListOrders(ctx, &OrderFilter{UserID: u.ID, Status: &cancelled, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Len(t, orders, 1)
+	require.Equal(t, ids[1], orders[0].ID)
+
+	orders, _, err = st.ListOrders(ctx, &OrderFilter{UserID: u.ID, CreatedAfter: time.Now().Add(time.Hour), PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, orders)
+
+	_, _, err = st.ListOrders(ctx, &OrderFilter{UserID: u.ID, PageSize: 10, PageToken: "garbage"})
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+}
+
+func TestMemoryStorerRefundRestocks(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	place := func(from OrderStatus) *Order {
+		o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{Name: p.Name, Quantity: 2, Price: p.Price, ProductID: p.ID}}})
+		require.NoError(t, err)
+		if from != Pending {
+			pending := Pending
+			_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &pending, ToStatus: from, ChangedBy: u.ID})
+			require.NoError(t, err)
+		}
+		return o
+	}
+
+	paid := Paid
+	o := place(paid)
+	_, err := st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &paid, ToStatus: Refunded, ChangedBy: u.ID})
+	require.NoError(t, err)
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), got.CountInStock, "refunding a paid order restocks its items")
+
+	delivered := Delivered
+	o = place(delivered)
+	_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &delivered, ToStatus: Refunded, ChangedBy: u.ID})
+	require.NoError(t, err)
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), got.CountInStock, "delivered items are with the customer")
+}
+
+func TestMemoryStorerCart(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	owner := CartOwner{UserID: u.ID}
+
+	c, err := st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Zero(t, c.ID)
+	require.Empty(t, c.Items)
+
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, nil, 2))
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, nil, 1))
+	require.Error(t, st.AddCartItem(ctx, owner, 42, nil, 1), "unknown product")
+	c, err = st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, c.Items, 1)
+	require.Equal(t, int64(3), c.Items[0].Quantity)
+	require.Equal(t, p.Name, c.Items[0].Name)
+	require.Equal(t, p.CountInStock, c.Items[0].CountInStock)
+
+	require.ErrorIs(t, st.SetCartItemQuantity(ctx, owner, 42, nil, 1), sql.ErrNoRows)
+	require.NoError(t, st.SetCartItemQuantity(ctx, owner, p.ID, nil, 4))
+
+	_, err = st.CheckoutCart(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 3}}})
+	require.ErrorIs(t, err, ErrCartChanged)
+	o, err := st.CheckoutCart(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 4}}})
+	require.NoError(t, err)
+	require.NotZero(t, o.ID)
+
+	c, err = st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Empty(t, c.Items)
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), got.CountInStock)
+
+	other, err := st.CreateProduct(ctx, &Product{Name: "other product", CountInStock: 1})
+	require.NoError(t, err)
+	require.NoError(t, st.AddCartItem(ctx, owner, other.ID, nil, 1))
+	require.NoError(t, st.DeleteProduct(ctx, other.ID))
+	c, err = st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Empty(t, c.Items, "deleted products leave the cart")
+	require.ErrorIs(t, st.RemoveCartItem(ctx, owner, other.ID, nil), sql.ErrNoRows)
+}
+
+func TestMemoryStorerMergeCart(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	other, err := st.CreateProduct(ctx, &Product{Name: "other product", CountInStock: 3})
+	require.NoError(t, err)
+	owner := CartOwner{UserID: u.ID}
+	guest := CartOwner{Token: "guest-token"}
+
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, nil, 4))
+	require.NoError(t, st.AddCartItem(ctx, guest, p.ID, nil, 8))
+	require.NoError(t, st.AddCartItem(ctx, guest, other.ID, nil, 2))
+
+	c, err := st.GetCart(ctx, guest)
+	require.NoError(t, err)
+	require.Nil(t, c.UserID)
+	require.Equal(t, "guest-token", *c.Token)
+	require.Len(t, c.Items, 2)
+
+	require.NoError(t, st.MergeCart(ctx, "unknown", u.ID))
+	require.NoError(t, st.MergeCart(ctx, "guest-token", u.ID))
+
+	c, err = st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, c.Items, 2)
+	require.Equal(t, int64(10), c.Items[0].Quantity, "quantities add up to the stock")
+	require.Equal(t, int64(2), c.Items[1].Quantity)
+
+	c, err = st.GetCart(ctx, guest)
+	require.NoError(t, err)
+	require.Zero(t, c.ID, "the guest cart is deleted")
+}
+
+func TestMemoryStorerCartVariants(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	owner := CartOwner{UserID: u.ID}
+	price := money.Amount(2500)
+	red, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-RED", Options: VariantOptions{"color": "red"}, Price: &price, CountInStock: 3})
+	require.NoError(t, err)
+	blue, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-BLUE", Options: VariantOptions{"color": "blue"}, CountInStock: 2})
+	require.NoError(t, err)
+	green, err := st.CreateVariant(ctx, &Variant{ProductID: p.ID, SKU: "TEE-GREEN", Options: VariantOptions{"color": "green"}, CountInStock: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, &red.ID, 1))
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, &blue.ID, 2))
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, &red.ID, 1))
+	ghost := int64(42)
+	require.Error(t, st.AddCartItem(ctx, owner, p.ID, &ghost, 1), "unknown variant")
+
+	c, err := st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, c.Items, 2, "a cart holds a product once per variant")
+	require.Equal(t, red.ID, *c.Items[0].VariantID)
+	require.Equal(t, int64(2), c.Items[0].Quantity)
+	require.Equal(t, price, c.Items[0].Price, "the price of the variant overrides the price of the product")
+	require.Equal(t, int64(3), c.Items[0].CountInStock)
+	require.Equal(t, VariantOptions{"color": "red"}, c.Items[0].Options)
+	require.Equal(t, p.Price, c.Items[1].Price)
+	require.Equal(t, int64(2), c.Items[1].CountInStock)
+
+	require.ErrorIs(t, st.SetCartItemQuantity(ctx, owner, p.ID, nil, 1), sql.ErrNoRows, "the product is only in the cart as variants")
+	require.NoError(t, st.SetCartItemQuantity(ctx, owner, p.ID, &blue.ID, 1))
+
+	_, err = st.CheckoutCart(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, VariantID: &red.ID, Quantity: 3}}})
+	require.ErrorIs(t, err, ErrCartChanged)
+	_, err = st.CheckoutCart(ctx, &Order{UserID: u.ID, Items: []OrderItem{
+		{Name: p.Name, ProductID: p.ID, VariantID: &red.ID, Quantity: 2},
+		{Name: p.Name, ProductID: p.ID, VariantID: &blue.ID, Quantity: 1},
+	}})
+	require.NoError(t, err)
+	v, err := st.GetVariant(ctx, red.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), v.CountInStock)
+
+	require.NoError(t, st.AddCartItem(ctx, owner, p.ID, &green.ID, 1))
+	require.NoError(t, st.DeleteVariant(ctx, green.ID))
+	c, err = st.GetCart(ctx, owner)
+	require.NoError(t, err)
+	require.Empty(t, c.Items, "deleted variants leave the cart")
+}
+
+func TestMemoryStorerUsersAndSessions(t *testing.T) {
+	ctx := context.Background()
+	st, u, _ := seedMemoryStorer(t)
+
+	_, err := st.CreateUser(ctx, &User{Email: u.Email})
+	require.Error(t, err, "email is unique")
+
+	u.Name = "renamed"
+	_, err = st.UpdateUser(ctx, u)
+	require.NoError(t, err)
+	got, err := st.GetUser(ctx, u.Email)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", got.Name)
+	require.NotNil(t, got.UpdatedAt)
+	got, err = st.GetUserByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u.Email, got.Email)
+
+	_, err = st.CreateSession(ctx, &Session{ID: "s1", UserEmail: u.Email})
+	require.NoError(t, err)
+	require.NoError(t, st.RevokeSession(ctx, "s1"))
+	s, err := st.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, s.IsRevoked)
+	require.NoError(t, st.DeleteSession(ctx, "s1"))
+	_, err = st.GetSession(ctx, "s1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, st.DeleteUser(ctx, u.ID))
+	users, _, err := st.ListUsers(ctx, &UserFilter{})
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
+
+func TestMemoryStorerIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	st, u, _ := seedMemoryStorer(t)
+	key := func(expiresAt time.Time) *IdempotencyKey {
+		return &IdempotencyKey{UserID: u.ID, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt}
+	}
+
+	_, reserved, err := st.ReserveIdempotencyKey(ctx, key(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	require.True(t, reserved)
+	_, reserved, err = st.ReserveIdempotencyKey(ctx, key(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.True(t, reserved, "expired keys are taken over")
+
+	k, reserved, err := st.ReserveIdempotencyKey(ctx, key(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Zero(t, k.StatusCode, "request in progress")
+
+	require.NoError(t, st.CompleteIdempotencyKey(ctx, &IdempotencyKey{UserID: u.ID, Key: "key", StatusCode: 201, ResponseBody: []byte("{}")}))
+	require.NoError(t, st.ReleaseIdempotencyKey(ctx, u.ID, "key"), "completed keys are kept")
+	k, reserved, err = st.ReserveIdempotencyKey(ctx, key(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, int32(201), k.StatusCode)
+	require.Equal(t, "{}", string(k.ResponseBody))
+
+	_, _, err = st.ReserveIdempotencyKey(ctx, &IdempotencyKey{UserID: 42, Key: "key"})
+	require.Error(t, err, "unknown user")
+}
+
+func TestMemoryStorerNotificationEvents(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 1}}})
+	require.NoError(t, err)
+
+	_, err = st.EnqueueNotificationEvent(ctx, &NotificationEvent{UserEmail: u.Email, OrderID: 42})
+	require.Error(t, err, "order does not exist")
+
+	ok, err := st.EnqueueNotificationEvent(ctx, &NotificationEvent{UserEmail: u.Email, OrderStatus: Pending, OrderID: o.ID})
+	require.NoError(t, err)
+	failing, err := st.EnqueueNotificationEvent(ctx, &NotificationEvent{UserEmail: u.Email, OrderStatus: Pending, OrderID: o.ID})
+	require.NoError(t, err)
+
+	evs, _, err := st.ListNotificationEvents(ctx, &NotificationEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+
+	succeeded, err := st.UpdateNotificationEvent(ctx, ok, &NotificationState{Message: "sent"}, NotificationSucess)
+	require.NoError(t, err)
+	require.True(t, succeeded)
+	require.Equal(t, Sent, st.states[ok.StateID].State)
+	require.NotNil(t, st.states[ok.StateID].CompletedAt)
+
+	for i := 0; i < maxAttempts; i++ {
+		succeeded, err = st.UpdateNotificationEvent(ctx, failing, &NotificationState{Message: "boom"}, NotificationFailure)
+		require.NoError(t, err)
+		require.False(t, succeeded)
+	}
+	require.Equal(t, Failed, st.states[failing.StateID].State)
+
+	evs, _, err = st.ListNotificationEvents(ctx, &NotificationEventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, evs)
+
+	require.Error(t, st.DeleteOrder(ctx, o.ID), "order is referenced by notification states")
+}
+
+func TestMemoryStorerConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStorer()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.CreateProduct(ctx, &Product{Name: "p"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	ps, _, err := st.ListProducts(ctx, &ProductFilter{})
+	require.NoError(t, err)
+	require.Len(t, ps, 50)
+	require.Equal(t, int64(50), ps[49].ID)
+}
+
+func TestMemoryStorerCancelRestoresStock(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 4}}})
+	require.NoError(t, err)
+
+	from := Pending
+	_, err = st.UpdateOrderStatus(ctx, &OrderStatusChange{OrderID: o.ID, FromStatus: &from, ToStatus: Cancelled, ChangedBy: u.ID})
+	require.NoError(t, err)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), got.CountInStock)
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), got.CountInStock, "cancelled orders no longer hold stock")
+}
+
+func TestMemoryStorerConcurrentOrdersDoNotOversell(t *testing.T) {
+	testConcurrentOrdersDoNotOversell(t, NewMemoryStorer())
+}
+
+func TestMemoryStorerDeleteOrderRestoresStock(t *testing.T) {
+	ctx := context.Background()
+	st, u, p := seedMemoryStorer(t)
+
+	o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 4}}})
+	require.NoError(t, err)
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), got.CountInStock)
+
+	_, err = st.CreateOrder(ctx, &Order{UserID: u.ID, Items: []OrderItem{{ProductID: p.ID, Quantity: 7}}})
+	require.ErrorIs(t, err, ErrInsufficientStock)
+
+	require.NoError(t, st.DeleteOrder(ctx, o.ID))
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), got.CountInStock)
+}
+
+// testConcurrentOrdersDoNotOversell places more parallel orders than there is
+// stock and checks that exactly the available stock was sold.
+func testConcurrentOrdersDoNotOversell(t *testing.T, st Storer) {
+	const (
+		stock  = 10
+		orders = 50
+	)
+	ctx := context.Background()
+
+	u, err := st.CreateUser(ctx, &User{Name: "oversell", Email: fmt.Sprintf("oversell-%d@example.com", time.Now().UnixNano()), Password: "secret"})
+	require.NoError(t, err)
+	p, err := st.CreateProduct(ctx, &Product{Name: "oversell", Image: "oversell.jpg", Price: 100, CountInStock: stock})
+	require.NoError(t, err)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		placed []int64
+		errs   = make(chan error, orders)
+	)
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o, err := st.CreateOrder(ctx, &Order{UserID: u.ID, PaymentMethod: "test", Items: []OrderItem{{Name: p.Name, Image: p.Image, Quantity: 1, Price: p.Price, ProductID: p.ID}}})
+			if err != nil {
+				errs <- err
+				return
+			}
+			mu.Lock()
+			placed = append(placed, o.ID)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.ErrorIs(t, err, ErrInsufficientStock)
+	}
+	require.Len(t, placed, stock)
+
+	got, err := st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.CountInStock)
+
+	for _, id := range placed {
+		require.NoError(t, st.DeleteOrder(ctx, id))
+	}
+	got, err = st.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(stock), got.CountInStock)
+
+	require.NoError(t, st.DeleteProduct(ctx, p.ID))
+	require.NoError(t, st.DeleteUser(ctx, u.ID))
+}
@@ -2,213 +2,1783 @@ package storer
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
 const (
 	maxAttempts = 3
+
+	// mysqlErrDupEntry is ER_DUP_ENTRY, a unique key violation.
+	mysqlErrDupEntry = 1062
+	// mysqlErrRowIsReferenced is ER_ROW_IS_REFERENCED_2, the deletion of a
+	// row other rows reference through a foreign key.
+	mysqlErrRowIsReferenced = 1451
 )
 
-type MySQLStorer struct {
-	db *sqlx.DB
+type MySQLStorer struct {
+	db *sqlx.DB
+}
+
+func NewMySQLStorer(db *sqlx.DB) *MySQLStorer {
+	return &MySQLStorer{
+		db: db,
+	}
+}
+
+// CreateProduct adds a product. It fails with ErrDuplicateSKU if the SKU is
+// taken.
+func (ms *MySQLStorer) CreateProduct(ctx context.Context, p *Product) (*Product, error) {
+	err := insertProduct(ctx, ms.db, p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func insertProduct(ctx context.Context, e sqlx.ExtContext, p *Product) error {
+	res, err := sqlx.NamedExecContext(ctx, e, `INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height)
+		VALUES (:sku, :name, :image, :category_id, :tax_category, :description, :rating, :num_reviews, :price, :currency, :count_in_stock, :weight, :length, :width, :height)`, p)
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("error inserting product: %w", ErrDuplicateSKU)
+	}
+	if err != nil {
+		return fmt.Errorf("error inserting product: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	p.ID = id
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
+	var p Product
+	err := ms.db.GetContext(ctx, &p, "SELECT * FROM products WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting product : %w", err)
+	}
+
+	err = ms.loadVariants(ctx, []*Product{&p})
+	if err != nil {
+		return nil, err
+	}
+	err = ms.loadImages(ctx, []*Product{&p})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListProducts returns a page of the products matching f and the token of the
+// next page, empty on the last page.
+func (ms *MySQLStorer) ListProducts(ctx context.Context, f *ProductFilter) ([]*Product, string, error) {
+	k, err := productKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	if len(f.CategoryIDs) > 0 {
+		cond, args, err := sqlx.In("category_id IN (?)", f.CategoryIDs)
+		if err != nil {
+			return nil, "", fmt.Errorf("error listing products: %w", err)
+		}
+		q.where(cond, args...)
+	}
+	if len(f.Prices) > 0 {
+		var ranges []string
+		var args []any
+		for _, r := range f.Prices {
+			cond := "currency=?"
+			args = append(args, r.Currency)
+			if r.Min != nil {
+				cond += " AND price>=?"
+				args = append(args, *r.Min)
+			}
+			if r.Max != nil {
+				cond += " AND price<=?"
+				args = append(args, *r.Max)
+			}
+			ranges = append(ranges, "("+cond+")")
+		}
+		cond := strings.Join(ranges, " OR ")
+		if len(ranges) > 1 {
+			cond = "(" + cond + ")"
+		}
+		q.where(cond, args...)
+	}
+	if f.InStock {
+		q.where("count_in_stock>0")
+	}
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
+
+	var products []*Product
+	query, args := q.build("products", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &products, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing products: %w", err)
+	}
+
+	products, next := k.page(products, f.PageSize)
+	err = ms.loadVariants(ctx, products)
+	if err != nil {
+		return nil, "", err
+	}
+	err = ms.loadImages(ctx, products)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return products, next, nil
+}
+
+// ListProductCurrencies returns the currencies products are priced in.
+func (ms *MySQLStorer) ListProductCurrencies(ctx context.Context) ([]string, error) {
+	var currencies []string
+	err := ms.db.SelectContext(ctx, &currencies, "SELECT DISTINCT currency FROM products ORDER BY currency")
+	if err != nil {
+		return nil, fmt.Errorf("error listing product currencies: %w", err)
+	}
+
+	return currencies, nil
+}
+
+// SearchProducts ranks products with the FULLTEXT indexes on their name and
+// description and on the name of their category.
+func (ms *MySQLStorer) SearchProducts(ctx context.Context, ps *ProductSearch) ([]*ProductMatch, string, error) {
+	offset, err := decodeSearchCursor(ps.PageToken, ps.Query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT p.*, MATCH (p.name, p.description) AGAINST (? IN NATURAL LANGUAGE MODE)
+		+ COALESCE(MATCH (c.name) AGAINST (? IN NATURAL LANGUAGE MODE), 0) AS score
+		FROM products p LEFT JOIN categories c ON c.id = p.category_id
+		WHERE MATCH (p.name, p.description) AGAINST (? IN NATURAL LANGUAGE MODE) OR MATCH (c.name) AGAINST (? IN NATURAL LANGUAGE MODE)
+		ORDER BY score DESC, p.id DESC`
+	args := []any{ps.Query, ps.Query, ps.Query, ps.Query}
+	if ps.PageSize > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, ps.PageSize+1, offset)
+	}
+
+	var matches []*ProductMatch
+	err = ms.db.SelectContext(ctx, &matches, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error searching products: %w", err)
+	}
+
+	matches, next := searchPage(matches, ps, offset)
+	terms := tokenize(ps.Query)
+	products := make([]*Product, len(matches))
+	for i, m := range matches {
+		m.Snippet = productSnippet(&m.Product, terms)
+		products[i] = &m.Product
+	}
+	err = ms.loadVariants(ctx, products)
+	if err != nil {
+		return nil, "", err
+	}
+	err = ms.loadImages(ctx, products)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return matches, next, nil
+}
+
+// UpdateProduct updates a product. It fails with ErrDuplicateSKU if the SKU
+// is taken.
+func (ms *MySQLStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
+	err := updateProduct(ctx, ms.db, p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func updateProduct(ctx context.Context, e sqlx.ExtContext, p *Product) error {
+	// rating and num_reviews are computed from the reviews, see updateProductRating
+	_, err := sqlx.NamedExecContext(ctx, e, `UPDATE products SET sku=:sku, name=:name, image=:image, category_id=:category_id, tax_category=:tax_category, description=:description,
+		price=:price, currency=:currency, count_in_stock=:count_in_stock, weight=:weight, length=:length, width=:width, height=:height,
+		updated_at=:updated_at WHERE id=:id`, p)
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("error updating product: %w", ErrDuplicateSKU)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating product: %w", err)
+	}
+	return nil
+}
+
+// GetProductsBySKU returns the products with the SKUs, along with their
+// variants and images. SKUs no product has are skipped.
+func (ms *MySQLStorer) GetProductsBySKU(ctx context.Context, skus []string) ([]*Product, error) {
+	if len(skus) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM products WHERE sku IN (?) ORDER BY id", skus)
+	if err != nil {
+		return nil, fmt.Errorf("error building products query: %w", err)
+	}
+
+	var products []*Product
+	err = ms.db.SelectContext(ctx, &products, ms.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting products: %w", err)
+	}
+
+	err = ms.loadVariants(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+	err = ms.loadImages(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// SaveProducts creates the products without an ID and updates the others, in
+// a single transaction. It fails with ErrDuplicateSKU, saving none of them,
+// if the SKU of one is taken.
+func (ms *MySQLStorer) SaveProducts(ctx context.Context, products []*Product) error {
+	var created []*Product
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		for _, p := range products {
+			var err error
+			if p.ID == 0 {
+				created = append(created, p)
+				err = insertProduct(ctx, tx, p)
+			} else {
+				err = updateProduct(ctx, tx, p)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// the inserts were rolled back
+		for _, p := range created {
+			p.ID = 0
+		}
+		return fmt.Errorf("error saving products: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) DeleteProduct(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM products WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting product: %w", err)
+	}
+	return nil
+}
+
+// loadVariants fills in the variants of products with a single query,
+// whatever the number of products.
+func (ms *MySQLStorer) loadVariants(ctx context.Context, products []*Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*Product, len(products))
+	ids := make([]int64, 0, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id", ids)
+	if err != nil {
+		return fmt.Errorf("error building variants query: %w", err)
+	}
+
+	var variants []Variant
+	err = ms.db.SelectContext(ctx, &variants, ms.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("error getting variants: %w", err)
+	}
+
+	for _, v := range variants {
+		p := byID[v.ProductID]
+		p.Variants = append(p.Variants, v)
+	}
+
+	return nil
+}
+
+// loadImages fills in the galleries of products with a single query, like
+// loadVariants.
+func (ms *MySQLStorer) loadImages(ctx context.Context, products []*Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*Product, len(products))
+	ids := make([]int64, 0, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id", ids)
+	if err != nil {
+		return fmt.Errorf("error building images query: %w", err)
+	}
+
+	var images []ProductImage
+	err = ms.db.SelectContext(ctx, &images, ms.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("error getting images: %w", err)
+	}
+
+	for _, img := range images {
+		p := byID[img.ProductID]
+		p.Images = append(p.Images, img)
+	}
+
+	return nil
+}
+
+// CreateVariant adds a variant to its product, whose stock becomes the total
+// stock of its variants. It fails with ErrDuplicateSKU if the SKU is taken.
+func (ms *MySQLStorer) CreateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := lockProduct(ctx, tx, v.ProductID)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.NamedExecContext(ctx, `INSERT INTO product_variants (product_id, sku, options, price, count_in_stock)
+			VALUES (:product_id, :sku, :options, :price, :count_in_stock)`, v)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("variant %s: %w", v.SKU, ErrDuplicateSKU)
+		}
+		if err != nil {
+			return fmt.Errorf("error inserting variant: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		v.ID = id
+
+		return syncProductStock(ctx, tx, v.ProductID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating variant: %w", err)
+	}
+	v.CreatedAt = time.Now()
+
+	return v, nil
+}
+
+func (ms *MySQLStorer) GetVariant(ctx context.Context, id int64) (*Variant, error) {
+	var v Variant
+	err := ms.db.GetContext(ctx, &v, "SELECT * FROM product_variants WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting variant: %w", err)
+	}
+	return &v, nil
+}
+
+// UpdateVariant updates a variant and the stock of its product. It fails
+// with ErrDuplicateSKU if the SKU is taken.
+func (ms *MySQLStorer) UpdateVariant(ctx context.Context, v *Variant) (*Variant, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := lockProduct(ctx, tx, v.ProductID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NamedExecContext(ctx, `UPDATE product_variants SET sku=:sku, options=:options, price=:price, count_in_stock=:count_in_stock,
+			updated_at=:updated_at WHERE id=:id`, v)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("variant %s: %w", v.SKU, ErrDuplicateSKU)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating variant: %w", err)
+		}
+
+		return syncProductStock(ctx, tx, v.ProductID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating variant: %w", err)
+	}
+
+	return v, nil
+}
+
+// DeleteVariant deletes a variant and takes its stock out of the stock of its
+// product. It fails with ErrVariantOrdered if the variant was ordered.
+func (ms *MySQLStorer) DeleteVariant(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var productID int64
+		err := tx.GetContext(ctx, &productID, "SELECT product_id FROM product_variants WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error getting variant: %w", err)
+		}
+		err = lockProduct(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM product_variants WHERE id=?", id)
+		if isRowReferenced(err) {
+			return fmt.Errorf("variant %d: %w", id, ErrVariantOrdered)
+		}
+		if err != nil {
+			return fmt.Errorf("error deleting variant: %w", err)
+		}
+
+		return syncProductStock(ctx, tx, productID)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting variant: %w", err)
+	}
+
+	return nil
+}
+
+// syncProductStock sets the stock of a product to the total stock of its
+// variants, zero once its last variant is deleted.
+func syncProductStock(ctx context.Context, tx *sqlx.Tx, productID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE products SET count_in_stock=(SELECT COALESCE(SUM(count_in_stock), 0) FROM product_variants WHERE product_id=?) WHERE id=?", productID, productID)
+	if err != nil {
+		return fmt.Errorf("error updating product stock: %w", err)
+	}
+	return nil
+}
+
+// CreateProductImage appends an image to the gallery of its product.
+func (ms *MySQLStorer) CreateProductImage(ctx context.Context, img *ProductImage) (*ProductImage, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := lockProduct(ctx, tx, img.ProductID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.GetContext(ctx, &img.Position, "SELECT COALESCE(MAX(position)+1, 0) FROM product_images WHERE product_id=?", img.ProductID)
+		if err != nil {
+			return fmt.Errorf("error getting image position: %w", err)
+		}
+
+		res, err := tx.NamedExecContext(ctx, `INSERT INTO product_images (product_id, position, image_key, url, thumbnail_key, thumbnail_url, content_type, size, width, height)
+			VALUES (:product_id, :position, :image_key, :url, :thumbnail_key, :thumbnail_url, :content_type, :size, :width, :height)`, img)
+		if err != nil {
+			return fmt.Errorf("error inserting image: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		img.ID = id
+
+		return syncProductImage(ctx, tx, img.ProductID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating image: %w", err)
+	}
+	img.CreatedAt = time.Now()
+
+	return img, nil
+}
+
+func (ms *MySQLStorer) GetProductImage(ctx context.Context, id int64) (*ProductImage, error) {
+	var img ProductImage
+	err := ms.db.GetContext(ctx, &img, "SELECT * FROM product_images WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting image: %w", err)
+	}
+	return &img, nil
+}
+
+func (ms *MySQLStorer) DeleteProductImage(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var productID int64
+		err := tx.GetContext(ctx, &productID, "SELECT product_id FROM product_images WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error getting image: %w", err)
+		}
+		err = lockProduct(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM product_images WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting image: %w", err)
+		}
+
+		return syncProductImage(ctx, tx, productID)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting image: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderProductImages orders the gallery of a product as ids. It fails with
+// ErrImagesMismatch unless ids are the IDs of the images of the product, each
+// once.
+func (ms *MySQLStorer) ReorderProductImages(ctx context.Context, productID int64, ids []int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := lockProduct(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		var current []int64
+		err = tx.SelectContext(ctx, &current, "SELECT id FROM product_images WHERE product_id=?", productID)
+		if err != nil {
+			return fmt.Errorf("error getting images: %w", err)
+		}
+		if !samePermutation(current, ids) {
+			return fmt.Errorf("product %d: %w", productID, ErrImagesMismatch)
+		}
+
+		for i, id := range ids {
+			_, err = tx.ExecContext(ctx, "UPDATE product_images SET position=? WHERE id=?", i, id)
+			if err != nil {
+				return fmt.Errorf("error updating image position: %w", err)
+			}
+		}
+
+		return syncProductImage(ctx, tx, productID)
+	})
+	if err != nil {
+		return fmt.Errorf("error reordering images: %w", err)
+	}
+
+	return nil
+}
+
+// syncProductImage sets the image of a product to the URL of the first image
+// of its gallery, empty once its last image is deleted.
+func syncProductImage(ctx context.Context, tx *sqlx.Tx, productID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE products SET image=COALESCE((SELECT url FROM product_images WHERE product_id=? ORDER BY position, id LIMIT 1), '') WHERE id=?", productID, productID)
+	if err != nil {
+		return fmt.Errorf("error updating product image: %w", err)
+	}
+	return nil
+}
+
+// samePermutation reports whether ids hold every ID of current exactly once.
+func samePermutation(current, ids []int64) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	a, b := slices.Clone(current), slices.Clone(ids)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// CreateCategory adds a category. It fails with ErrDuplicateCategory if the
+// slug is taken.
+func (ms *MySQLStorer) CreateCategory(ctx context.Context, c *Category) (*Category, error) {
+	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO categories (parent_id, name, slug) VALUES (:parent_id, :name, :slug)", c)
+	if isDuplicateEntry(err) {
+		return nil, fmt.Errorf("category %s: %w", c.Slug, ErrDuplicateCategory)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error inserting category: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	c.ID = id
+	c.CreatedAt = time.Now()
+
+	return c, nil
+}
+
+func (ms *MySQLStorer) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	var c Category
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM categories WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category: %w", err)
+	}
+	return &c, nil
+}
+
+func (ms *MySQLStorer) GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	var c Category
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM categories WHERE slug=?", slug)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category: %w", err)
+	}
+	return &c, nil
+}
+
+// ListCategories returns every category, sorted by name.
+func (ms *MySQLStorer) ListCategories(ctx context.Context) ([]*Category, error) {
+	var categories []*Category
+	err := ms.db.SelectContext(ctx, &categories, "SELECT * FROM categories ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("error listing categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (ms *MySQLStorer) UpdateCategory(ctx context.Context, c *Category) (*Category, error) {
+	_, err := ms.db.NamedExecContext(ctx, "UPDATE categories SET parent_id=:parent_id, name=:name, slug=:slug, updated_at=:updated_at WHERE id=:id", c)
+	if isDuplicateEntry(err) {
+		return nil, fmt.Errorf("category %s: %w", c.Slug, ErrDuplicateCategory)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating category: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteCategory deletes a category. It fails with ErrCategoryInUse if the
+// category has subcategories, products or coupons.
+func (ms *MySQLStorer) DeleteCategory(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM categories WHERE id=?", id)
+	if isRowReferenced(err) {
+		return fmt.Errorf("category %d: %w", id, ErrCategoryInUse)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting category: %w", err)
+	}
+	return nil
+}
+
+// CreateReview adds a published review and updates the rating of its
+// product. It fails with ErrDuplicateReview if the user already reviewed
+// the product.
+func (ms *MySQLStorer) CreateReview(ctx context.Context, r *Review) (*Review, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		err := lockProduct(ctx, tx, r.ProductID)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.NamedExecContext(ctx, "INSERT INTO reviews (product_id, user_id, rating, comment, status) VALUES (:product_id, :user_id, :rating, :comment, :status)", r)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("product %d, user %d: %w", r.ProductID, r.UserID, ErrDuplicateReview)
+		}
+		if err != nil {
+			return fmt.Errorf("error inserting review: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		r.ID = id
+
+		return updateProductRating(ctx, tx, r.ProductID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating review: %w", err)
+	}
+
+	return r, nil
+}
+
+func (ms *MySQLStorer) GetReview(ctx context.Context, id int64) (*Review, error) {
+	var r Review
+	err := ms.db.GetContext(ctx, &r, "SELECT * FROM reviews WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting review: %w", err)
+	}
+	return &r, nil
+}
+
+func (ms *MySQLStorer) ListReviews(ctx context.Context, f *ReviewFilter) ([]*Review, string, error) {
+	k, err := reviewKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	if f.ProductID != 0 {
+		q.where("product_id=?", f.ProductID)
+	}
+	if f.Status != nil {
+		q.where("status=?", *f.Status)
+	}
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
+
+	var reviews []*Review
+	query, args := q.build("reviews", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &reviews, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing reviews: %w", err)
+	}
+
+	reviews, next := k.page(reviews, f.PageSize)
+	return reviews, next, nil
+}
+
+// UpdateReviewStatus publishes or hides a review and updates the rating of
+// its product.
+func (ms *MySQLStorer) UpdateReviewStatus(ctx context.Context, id int64, status ReviewStatus) (*Review, error) {
+	var r Review
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		productID, err := lockReviewProduct(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE reviews SET status=?, updated_at=? WHERE id=?", status, time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("error updating review status: %w", err)
+		}
+
+		err = updateProductRating(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.GetContext(ctx, &r, "SELECT * FROM reviews WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error getting review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error moderating review: %w", err)
+	}
+
+	return &r, nil
+}
+
+// DeleteReview removes a review and updates the rating of its product.
+func (ms *MySQLStorer) DeleteReview(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		productID, err := lockReviewProduct(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM reviews WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting review: %w", err)
+		}
+
+		return updateProductRating(ctx, tx, productID)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting review: %w", err)
+	}
+
+	return nil
+}
+
+// HasDeliveredProduct reports whether the user received the product in one of
+// their orders.
+func (ms *MySQLStorer) HasDeliveredProduct(ctx context.Context, userID, productID int64) (bool, error) {
+	var ok bool
+	err := ms.db.GetContext(ctx, &ok, `SELECT EXISTS (SELECT 1 FROM orders o JOIN order_items oi ON oi.order_id=o.id
+		WHERE o.user_id=? AND oi.product_id=? AND o.status=?)`, userID, productID, Delivered)
+	if err != nil {
+		return false, fmt.Errorf("error checking delivered orders: %w", err)
+	}
+	return ok, nil
+}
+
+// lockProduct serializes the changes to the reviews and variants of a
+// product, so that its rating and stock are always computed from the latest
+// ones.
+func lockProduct(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	var locked int64
+	err := tx.GetContext(ctx, &locked, "SELECT id FROM products WHERE id=? FOR UPDATE", id)
+	if err != nil {
+		return fmt.Errorf("error locking product: %w", err)
+	}
+	return nil
+}
+
+func lockReviewProduct(ctx context.Context, tx *sqlx.Tx, reviewID int64) (int64, error) {
+	var productID int64
+	err := tx.GetContext(ctx, &productID, "SELECT product_id FROM reviews WHERE id=?", reviewID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting review: %w", err)
+	}
+
+	return productID, lockProduct(ctx, tx, productID)
+}
+
+func updateProductRating(ctx context.Context, tx *sqlx.Tx, productID int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE products SET
+		rating=(SELECT COALESCE(ROUND(AVG(rating)), 0) FROM reviews WHERE product_id=? AND status=?),
+		num_reviews=(SELECT COUNT(*) FROM reviews WHERE product_id=? AND status=?) WHERE id=?`,
+		productID, ReviewPublished, productID, ReviewPublished, productID)
+	if err != nil {
+		return fmt.Errorf("error updating product rating: %w", err)
+	}
+	return nil
+}
+
+// CreateCoupon adds a coupon. It fails with ErrDuplicateCoupon if the code is
+// taken.
+func (ms *MySQLStorer) CreateCoupon(ctx context.Context, c *Coupon) (*Coupon, error) {
+	res, err := ms.db.NamedExecContext(ctx, `INSERT INTO coupons (code, kind, value, min_order_value, expires_at, max_uses, max_uses_per_user, category_id, product_id)
+		VALUES (:code, :kind, :value, :min_order_value, :expires_at, :max_uses, :max_uses_per_user, :category_id, :product_id)`, c)
+	if isDuplicateEntry(err) {
+		return nil, fmt.Errorf("coupon %s: %w", c.Code, ErrDuplicateCoupon)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error inserting coupon: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	c.ID = id
+
+	return c, nil
+}
+
+func (ms *MySQLStorer) GetCoupon(ctx context.Context, id int64) (*Coupon, error) {
+	var c Coupon
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM coupons WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting coupon: %w", err)
+	}
+	return &c, nil
+}
+
+func (ms *MySQLStorer) GetCouponByCode(ctx context.Context, code string) (*Coupon, error) {
+	var c Coupon
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM coupons WHERE code=?", code)
+	if err != nil {
+		return nil, fmt.Errorf("error getting coupon: %w", err)
+	}
+	return &c, nil
+}
+
+func (ms *MySQLStorer) ListCoupons(ctx context.Context, f *CouponFilter) ([]*Coupon, string, error) {
+	k, err := couponKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
+
+	var coupons []*Coupon
+	query, args := q.build("coupons", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &coupons, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing coupons: %w", err)
+	}
+
+	coupons, next := k.page(coupons, f.PageSize)
+	return coupons, next, nil
+}
+
+func (ms *MySQLStorer) UpdateCoupon(ctx context.Context, c *Coupon) (*Coupon, error) {
+	_, err := ms.db.NamedExecContext(ctx, `UPDATE coupons SET code=:code, kind=:kind, value=:value, min_order_value=:min_order_value, expires_at=:expires_at,
+		max_uses=:max_uses, max_uses_per_user=:max_uses_per_user, category_id=:category_id, product_id=:product_id, updated_at=:updated_at WHERE id=:id`, c)
+	if isDuplicateEntry(err) {
+		return nil, fmt.Errorf("coupon %s: %w", c.Code, ErrDuplicateCoupon)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating coupon: %w", err)
+	}
+	return c, nil
+}
+
+func (ms *MySQLStorer) DeleteCoupon(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM coupons WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting coupon: %w", err)
+	}
+	return nil
+}
+
+// CountCouponUses returns how many orders redeemed the coupon, in total and
+// by the user.
+func (ms *MySQLStorer) CountCouponUses(ctx context.Context, couponID, userID int64) (int64, int64, error) {
+	return countCouponUses(ctx, ms.db, couponID, userID)
+}
+
+func countCouponUses(ctx context.Context, q sqlx.QueryerContext, couponID, userID int64) (int64, int64, error) {
+	var uses struct {
+		Total  int64 `db:"total"`
+		ByUser int64 `db:"by_user"`
+	}
+	err := sqlx.GetContext(ctx, q, &uses, "SELECT COUNT(*) AS total, COALESCE(SUM(user_id=?), 0) AS by_user FROM orders WHERE coupon_id=?", userID, couponID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error counting coupon uses: %w", err)
+	}
+	return uses.Total, uses.ByUser, nil
+}
+
+// redeemCoupon checks the usage limits of the coupon of the order. The coupon
+// stays locked until the order is inserted, so concurrent orders cannot
+// redeem it past its limits.
+func redeemCoupon(ctx context.Context, tx *sqlx.Tx, o *Order) error {
+	var c Coupon
+	err := tx.GetContext(ctx, &c, "SELECT * FROM coupons WHERE id=? FOR UPDATE", *o.CouponID)
+	if err != nil {
+		return fmt.Errorf("error locking coupon: %w", err)
+	}
+
+	uses, userUses, err := countCouponUses(ctx, tx, c.ID, o.UserID)
+	if err != nil {
+		return err
+	}
+	if c.Exhausted(uses, userUses) {
+		return fmt.Errorf("coupon %s: %w", c.Code, ErrCouponExhausted)
+	}
+	return nil
+}
+
+// Additional methods for Orders and OrderItems would follow a similar pattern.
+
+func (ms *MySQLStorer) CreateOrder(ctx context.Context, o *Order) (*Order, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return placeOrder(ctx, tx, o)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+
+	return o, nil
+
+}
+
+// placeOrder takes the items of the order out of stock and inserts the order
+// with its items and its first status change.
+func placeOrder(ctx context.Context, tx *sqlx.Tx, o *Order) error {
+	if o.CouponID != nil {
+		err := redeemCoupon(ctx, tx, o)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := reserveStock(ctx, tx, o.Items)
+	if err != nil {
+		return err
+	}
+
+	order, err := createOrder(ctx, tx, o)
+	if err != nil {
+		return fmt.Errorf("error creating order: %w", err)
+	}
+
+	for _, oi := range o.Items {
+		oi.OrderID = order.ID
+		err = createOrderItem(ctx, tx, &oi)
+		if err != nil {
+			return fmt.Errorf("error creating order item: %w", err)
+		}
+	}
+
+	_, err = insertOrderStatusChange(ctx, tx, &OrderStatusChange{
+		OrderID:   order.ID,
+		ToStatus:  Pending,
+		ChangedBy: order.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// reserveStock decrements the stock of every ordered product and variant. The
+// conditional UPDATE locks the row, so concurrent orders can never take the
+// stock below zero; rows are locked in ID order, products before variants, to
+// avoid deadlocks.
+func reserveStock(ctx context.Context, tx *sqlx.Tx, items []OrderItem) error {
+	quantities := stockQuantities(items)
+	for _, id := range sortedKeys(quantities) {
+		res, err := tx.ExecContext(ctx, "UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?", quantities[id], id, quantities[id])
+		if err != nil {
+			return fmt.Errorf("error reserving stock for product %d: %w", id, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("product %d: %w", id, ErrInsufficientStock)
+		}
+	}
+
+	quantities = variantQuantities(items)
+	for _, id := range sortedKeys(quantities) {
+		res, err := tx.ExecContext(ctx, "UPDATE product_variants SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?", quantities[id], id, quantities[id])
+		if err != nil {
+			return fmt.Errorf("error reserving stock for variant %d: %w", id, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("variant %d: %w", id, ErrInsufficientStock)
+		}
+	}
+
+	return nil
+}
+
+// stockQuantities sums the ordered quantity of every product.
+func stockQuantities(items []OrderItem) map[int64]int64 {
+	quantities := make(map[int64]int64)
+	for _, oi := range items {
+		quantities[oi.ProductID] += oi.Quantity
+	}
+	return quantities
+}
+
+// variantQuantities sums the ordered quantity of every variant.
+func variantQuantities(items []OrderItem) map[int64]int64 {
+	quantities := make(map[int64]int64)
+	for _, oi := range items {
+		if oi.VariantID != nil {
+			quantities[*oi.VariantID] += oi.Quantity
+		}
+	}
+	return quantities
+}
+
+// restoreStock puts the items of an order back in stock.
+func restoreStock(ctx context.Context, tx *sqlx.Tx, orderID int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE products p JOIN (
+		SELECT product_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? GROUP BY product_id
+	) oi ON oi.product_id=p.id SET p.count_in_stock=p.count_in_stock+oi.quantity`, orderID)
+	if err != nil {
+		return fmt.Errorf("error restoring stock: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE product_variants v JOIN (
+		SELECT variant_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND variant_id IS NOT NULL GROUP BY variant_id
+	) oi ON oi.variant_id=v.id SET v.count_in_stock=v.count_in_stock+oi.quantity`, orderID)
+	if err != nil {
+		return fmt.Errorf("error restoring variant stock: %w", err)
+	}
+
+	return nil
+}
+
+func createOrder(ctx context.Context, tx *sqlx.Tx, o *Order) (*Order, error) {
+	res, err := tx.NamedExecContext(ctx, `INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate,
+		ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id)
+		VALUES (:payment_method, :coupon_id, :coupon_code, :discount_price, :tax_price, :tax_inclusive, :shipping_price, :shipping_method, :total_price, :currency, :exchange_rate,
+		:ship_name, :ship_line1, :ship_line2, :ship_city, :ship_region, :ship_postal_code, :ship_country, :ship_phone, :user_id)`, o)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting order: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	o.ID = id
+
+	return o, nil
+}
+
+func createOrderItem(ctx context.Context, tx *sqlx.Tx, oi *OrderItem) error {
+	res, err := tx.NamedExecContext(ctx, `
+        INSERT INTO order_items (
+            name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id
+        )
+        VALUES (
+            :name, :quantity, :image, :price, :tax_rate, :tax_price, :product_id, :variant_id, :order_id
+        )
+    `, oi)
+
+	if err != nil {
+		return fmt.Errorf("error inserting order item: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id for order item: %w", err)
+	}
+	oi.ID = id
+
+	return nil
+}
+
+func (ms *MySQLStorer) GetOrder(ctx context.Context, id int64) (*Order, error) {
+	var o Order
+	err := ms.db.GetContext(ctx, &o, "SELECT * FROM orders WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order: %w", err)
+	}
+
+	var items []OrderItem
+	err = ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", o.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order items: %w", err)
+	}
+	o.Items = items
+
+	return &o, nil
+}
+
+func (ms *MySQLStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
+	var o Order
+	err := ms.db.GetContext(ctx, &o, "SELECT id, user_id, status FROM orders WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order: %w", err)
+	}
+	return &o, nil
+}
+
+// ListOrders returns a page of the orders matching f, with their items, and
+// the token of the next page, empty on the last page.
+func (ms *MySQLStorer) ListOrders(ctx context.Context, f *OrderFilter) ([]*Order, string, error) {
+	k, err := orderKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	if f.UserID != 0 {
+		q.where("user_id=?", f.UserID)
+	}
+	if f.Status != nil {
+		q.where("status=?", *f.Status)
+	}
+	if !f.CreatedAfter.IsZero() {
+		q.where("created_at>=?", f.CreatedAfter)
+	}
+	if !f.CreatedBefore.IsZero() {
+		q.where("created_at<?", f.CreatedBefore)
+	}
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
+
+	var orders []*Order
+	query, args := q.build("orders", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &orders, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing orders: %w", err)
+	}
+
+	orders, next := k.page(orders, f.PageSize)
+	err = ms.loadOrderItems(ctx, orders)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return orders, next, nil
+}
+
+// loadOrderItems fills in the items of orders with a single query, whatever
+// the number of orders.
+func (ms *MySQLStorer) loadOrderItems(ctx context.Context, orders []*Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*Order, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for _, o := range orders {
+		byID[o.ID] = o
+		ids = append(ids, o.ID)
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY order_id, id", ids)
+	if err != nil {
+		return fmt.Errorf("error building order items query: %w", err)
+	}
+
+	var items []OrderItem
+	err = ms.db.SelectContext(ctx, &items, ms.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("error getting order items: %w", err)
+	}
+
+	for _, oi := range items {
+		o := byID[oi.OrderID]
+		o.Items = append(o.Items, oi)
+	}
+
+	return nil
+}
+
+// UpdateOrderStatus moves an order from c.FromStatus to c.ToStatus, records
+// the change in the order history and issues c.Invoice, if any, all in one
+// transaction. It fails with ErrOrderStatusConflict if the order is no longer
+// in c.FromStatus.
+func (ms *MySQLStorer) UpdateOrderStatus(ctx context.Context, c *OrderStatusChange) (*OrderStatusChange, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?", c.ToStatus, time.Now(), c.OrderID, c.FromStatus)
+		if err != nil {
+			return fmt.Errorf("error updating order status: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("order %d: %w", c.OrderID, ErrOrderStatusConflict)
+		}
+
+		if c.restocks() {
+			err = restoreStock(ctx, tx, c.OrderID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if c.Invoice != nil {
+			err = insertInvoice(ctx, tx, c.Invoice)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = insertOrderStatusChange(ctx, tx, c)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating order status: %w", err)
+	}
+
+	return c, nil
+}
+
+func insertOrderStatusChange(ctx context.Context, tx *sqlx.Tx, c *OrderStatusChange) (*OrderStatusChange, error) {
+	res, err := tx.NamedExecContext(ctx, "INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (:order_id, :from_status, :to_status, :changed_by)", c)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting order status change: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	c.ID = id
+	c.CreatedAt = time.Now()
+
+	return c, nil
+}
+
+func (ms *MySQLStorer) ListOrderStatusHistory(ctx context.Context, orderID int64) ([]*OrderStatusChange, error) {
+	var changes []*OrderStatusChange
+	err := ms.db.SelectContext(ctx, &changes, "SELECT * FROM order_status_history WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing order status history: %w", err)
+	}
+
+	return changes, nil
+}
+
+func (ms *MySQLStorer) DeleteOrder(ctx context.Context, id int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var st OrderStatus
+		err := tx.GetContext(ctx, &st, "SELECT status FROM orders WHERE id=? FOR UPDATE", id)
+		if err != nil {
+			return fmt.Errorf("error getting order: %w", err)
+		}
+
+		if st.holdsStock() {
+			err = restoreStock(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting order items: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE id=?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting order: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting order transaction: %w", err)
+	}
+	return nil
+}
+
+func (ms *MySQLStorer) CreatePayment(ctx context.Context, p *Payment) (*Payment, error) {
+	res, err := ms.db.NamedExecContext(ctx, `INSERT INTO payments (order_id, provider, provider_ref, amount, currency, status, decline_reason)
+		VALUES (:order_id, :provider, :provider_ref, :amount, :currency, :status, :decline_reason)`, p)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting payment: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+	}
+	p.ID = id
+	p.CreatedAt = time.Now()
+
+	return p, nil
+}
+
+func (ms *MySQLStorer) GetPaymentByRef(ctx context.Context, provider, ref string) (*Payment, error) {
+	var p Payment
+	err := ms.db.GetContext(ctx, &p, "SELECT * FROM payments WHERE provider=? AND provider_ref=?", provider, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error getting payment: %w", err)
+	}
+	return &p, nil
+}
+
+// ListOrderPayments returns the payments of an order, oldest first.
+func (ms *MySQLStorer) ListOrderPayments(ctx context.Context, orderID int64) ([]*Payment, error) {
+	var payments []*Payment
+	err := ms.db.SelectContext(ctx, &payments, "SELECT * FROM payments WHERE order_id=? ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing payments: %w", err)
+	}
+	return payments, nil
+}
+
+// UpdatePaymentStatus moves a payment from the from status to p.Status. It
+// fails with ErrPaymentStatusConflict if the payment is no longer in from.
+func (ms *MySQLStorer) UpdatePaymentStatus(ctx context.Context, p *Payment, from PaymentStatus) (*Payment, error) {
+	p.UpdatedAt = toTimePtr(time.Now())
+	res, err := ms.db.ExecContext(ctx, "UPDATE payments SET status=?, decline_reason=?, updated_at=? WHERE id=? AND status=?", p.Status, p.DeclineReason, p.UpdatedAt, p.ID, from)
+	if err != nil {
+		return nil, fmt.Errorf("error updating payment status: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("payment %d: %w", p.ID, ErrPaymentStatusConflict)
+	}
+
+	return p, nil
 }
 
-func NewMySQLStorer(db *sqlx.DB) *MySQLStorer {
-	return &MySQLStorer{
-		db: db,
+// CreateInvoice issues an invoice with the next invoice number. The number
+// is taken in the transaction inserting the invoice, so that a failed insert
+// leaves no gap. It fails with ErrInvoiceExists if the order already has an
+// invoice.
+func (ms *MySQLStorer) CreateInvoice(ctx context.Context, inv *Invoice) (*Invoice, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		return insertInvoice(ctx, tx, inv)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating invoice: %w", err)
 	}
+
+	return inv, nil
 }
 
-func (ms *MySQLStorer) CreateProduct(ctx context.Context, p *Product) (*Product, error) {
-	res, err := ms.db.NamedExecContext(ctx, "INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock) VALUES (:name, :image, :category, :description, :rating, :num_reviews, :price, :count_in_stock)", p)
+func insertInvoice(ctx context.Context, tx *sqlx.Tx, inv *Invoice) error {
+	_, err := tx.ExecContext(ctx, "UPDATE invoice_sequence SET last_number=last_number+1 WHERE id=1")
+	if err != nil {
+		return fmt.Errorf("error incrementing invoice number: %w", err)
+	}
+	err = tx.GetContext(ctx, &inv.Number, "SELECT last_number FROM invoice_sequence WHERE id=1")
+	if err != nil {
+		return fmt.Errorf("error getting invoice number: %w", err)
+	}
+
+	inv.IssuedAt = time.Now()
+	res, err := tx.NamedExecContext(ctx, `INSERT INTO invoices (number, order_id, user_id, seller_name, seller_address, seller_tax_id, bill_name, bill_email,
+		ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone,
+		currency, subtotal, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, issued_at)
+		VALUES (:number, :order_id, :user_id, :seller_name, :seller_address, :seller_tax_id, :bill_name, :bill_email,
+		:ship_name, :ship_line1, :ship_line2, :ship_city, :ship_region, :ship_postal_code, :ship_country, :ship_phone,
+		:currency, :subtotal, :discount_price, :tax_price, :tax_inclusive, :shipping_price, :shipping_method, :total_price, :issued_at)`, inv)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting product: %w", err)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("order %d: %w", inv.OrderID, ErrInvoiceExists)
+		}
+		return fmt.Errorf("error inserting invoice: %w", err)
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+		return fmt.Errorf("error getting last insert ID: %w", err)
 	}
-	p.ID = id
+	inv.ID = id
 
-	return p, nil
+	for i := range inv.Lines {
+		l := &inv.Lines[i]
+		l.InvoiceID = inv.ID
+		res, err := tx.NamedExecContext(ctx, `INSERT INTO invoice_lines (invoice_id, product_id, name, quantity, price, tax_rate, tax_price)
+			VALUES (:invoice_id, :product_id, :name, :quantity, :price, :tax_rate, :tax_price)`, l)
+		if err != nil {
+			return fmt.Errorf("error inserting invoice line: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		l.ID = id
+	}
+
+	return nil
 }
 
-func (ms *MySQLStorer) GetProduct(ctx context.Context, id int64) (*Product, error) {
-	var p Product
-	err := ms.db.GetContext(ctx, &p, "SELECT * FROM products WHERE id=?", id)
+func (ms *MySQLStorer) GetOrderInvoice(ctx context.Context, orderID int64) (*Invoice, error) {
+	var inv Invoice
+	err := ms.db.GetContext(ctx, &inv, "SELECT * FROM invoices WHERE order_id=?", orderID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting product : %w", err)
+		return nil, fmt.Errorf("error getting invoice: %w", err)
 	}
-	return &p, nil
-}
 
-func (ms *MySQLStorer) ListProducts(ctx context.Context) ([]*Product, error) {
-	var products []*Product
-	err := ms.db.SelectContext(ctx, &products, "SELECT * FROM products")
+	var lines []InvoiceLine
+	err = ms.db.SelectContext(ctx, &lines, "SELECT * FROM invoice_lines WHERE invoice_id=? ORDER BY id", inv.ID)
 	if err != nil {
-		return nil, fmt.Errorf("error listing products: %w", err)
+		return nil, fmt.Errorf("error getting invoice lines: %w", err)
 	}
-	return products, nil
+	inv.Lines = lines
+
+	return &inv, nil
 }
 
-func (ms *MySQLStorer) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
-	_, err := ms.db.NamedExecContext(ctx, `UPDATE products SET name=:name, image=:image, category=:category, description=:description,
-		rating=:rating, num_reviews=:num_reviews, price=:price, count_in_stock=:count_in_stock, updated_at=:updated_at WHERE id=:id`, p)
+// GetCart returns the cart of the owner with the current details of its
+// products and variants, in the order they were added.
+func (ms *MySQLStorer) GetCart(ctx context.Context, co CartOwner) (*Cart, error) {
+	var c Cart
+	cond, arg := co.cond()
+	err := ms.db.GetContext(ctx, &c, "SELECT * FROM carts WHERE "+cond, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		userID, token := co.values()
+		return &Cart{UserID: userID, Token: token}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting cart: %w", err)
+	}
 
+	c.Items, err = selectCartItems(ctx, ms.db, c.ID)
 	if err != nil {
-		return nil, fmt.Errorf("error updating product: %w", err)
+		return nil, err
 	}
-	return p, nil
+
+	return &c, nil
 }
 
-func (ms *MySQLStorer) DeleteProduct(ctx context.Context, id int64) error {
-	_, err := ms.db.ExecContext(ctx, "DELETE FROM products WHERE id=?", id)
+func selectCartItems(ctx context.Context, q sqlx.QueryerContext, cartID int64) ([]CartItem, error) {
+	var items []CartItem
+	err := sqlx.SelectContext(ctx, q, &items, `SELECT ci.*, p.name, p.image, COALESCE(v.price, p.price) AS price, p.currency,
+		COALESCE(v.count_in_stock, p.count_in_stock) AS count_in_stock, v.options
+		FROM cart_items ci JOIN products p ON p.id=ci.product_id LEFT JOIN product_variants v ON v.id=ci.variant_id
+		WHERE ci.cart_id=? ORDER BY ci.id`, cartID)
 	if err != nil {
-		return fmt.Errorf("error deleting product: %w", err)
+		return nil, fmt.Errorf("error getting cart items: %w", err)
 	}
-	return nil
+	return items, nil
 }
 
-// Additional methods for Orders and OrderItems would follow a similar pattern.
+// cond returns the condition selecting the cart of the owner.
+func (co CartOwner) cond() (string, any) {
+	if co.UserID != 0 {
+		return "user_id=?", co.UserID
+	}
+	return "token=?", co.Token
+}
 
-func (ms *MySQLStorer) CreateOrder(ctx context.Context, o *Order) (*Order, error) {
+// values returns the user_id and token columns of the cart of the owner.
+func (co CartOwner) values() (*int64, *string) {
+	co = co.key()
+	if co.UserID != 0 {
+		return &co.UserID, nil
+	}
+	return nil, &co.Token
+}
+
+// AddCartItem puts quantity more items of the product, or of its variant
+// unless variantID is nil, in the cart of the owner, creating the cart on
+// first use.
+func (ms *MySQLStorer) AddCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error {
 	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
-		order, err := createOrder(ctx, tx, o)
+		now := time.Now()
+		cartID, err := upsertCart(ctx, tx, co, now)
 		if err != nil {
-			return fmt.Errorf("error creating order: %w", err)
+			return err
 		}
 
-		for _, oi := range o.Items {
-			oi.OrderID = order.ID
-			createOrderItem(ctx, tx, &oi)
-			if err != nil {
-				return fmt.Errorf("error creating order item: %w", err)
-			}
+		// the unique key of cart_items lets NULL variants repeat, the lock
+		// of the cart keeps the item from being inserted twice; quantity is
+		// positive, so an existing item is always changed
+		res, err := tx.ExecContext(ctx, "UPDATE cart_items SET quantity=quantity+?, updated_at=? WHERE cart_id=? AND product_id=? AND variant_id<=>?", quantity, now, cartID, productID, variantID)
+		if err != nil {
+			return fmt.Errorf("error updating cart item: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+		if n > 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)", cartID, productID, variantID, quantity)
+		if err != nil {
+			return fmt.Errorf("error inserting cart item: %w", err)
 		}
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating order: %w", err)
+		return fmt.Errorf("error adding cart item: %w", err)
 	}
 
-	return o, nil
-
+	return nil
 }
 
-func createOrder(ctx context.Context, tx *sqlx.Tx, o *Order) (*Order, error) {
-	res, err := tx.NamedExecContext(ctx, "INSERT INTO orders (payment_method, tax_price, shipping_price, total_price, user_id) VALUES (:payment_method, :tax_price, :shipping_price, :total_price, :user_id)", o)
+// upsertCart returns the ID of the cart of the owner, creating it if needed.
+// Either way the cart row stays locked until the end of the transaction.
+func upsertCart(ctx context.Context, tx *sqlx.Tx, co CartOwner, now time.Time) (int64, error) {
+	userID, token := co.values()
+	res, err := tx.ExecContext(ctx, "INSERT INTO carts (user_id, token) VALUES (?, ?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?", userID, token, now)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting order: %w", err)
+		return 0, fmt.Errorf("error upserting cart: %w", err)
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("error getting last insert ID: %w", err)
+		return 0, fmt.Errorf("error getting last insert ID: %w", err)
 	}
-	o.ID = id
-
-	return o, nil
+	return id, nil
 }
 
-func createOrderItem(ctx context.Context, tx *sqlx.Tx, oi *OrderItem) error {
-	res, err := tx.NamedExecContext(ctx, `
-        INSERT INTO order_items (
-            name, quantity, image, price, product_id, order_id
-        )
-        VALUES (
-            :name, :quantity, :image, :price, :product_id, :order_id
-        )
-    `, oi)
-
+// SetCartItemQuantity changes the quantity of a product, or of a variant of
+// it, already in the cart of the owner.
+func (ms *MySQLStorer) SetCartItemQuantity(ctx context.Context, co CartOwner, productID int64, variantID *int64, quantity int64) error {
+	cond, arg := co.cond()
+	res, err := ms.db.ExecContext(ctx, "UPDATE cart_items ci JOIN carts c ON c.id=ci.cart_id SET ci.quantity=?, ci.updated_at=? WHERE c."+cond+" AND ci.product_id=? AND ci.variant_id<=>?", quantity, time.Now(), arg, productID, variantID)
 	if err != nil {
-		return fmt.Errorf("error inserting order item: %w", err)
+		return fmt.Errorf("error updating cart item: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	return cartItemAffected(res)
+}
+
+func (ms *MySQLStorer) RemoveCartItem(ctx context.Context, co CartOwner, productID int64, variantID *int64) error {
+	cond, arg := co.cond()
+	res, err := ms.db.ExecContext(ctx, "DELETE ci FROM cart_items ci JOIN carts c ON c.id=ci.cart_id WHERE c."+cond+" AND ci.product_id=? AND ci.variant_id<=>?", arg, productID, variantID)
 	if err != nil {
-		return fmt.Errorf("error getting last insert id for order item: %w", err)
+		return fmt.Errorf("error removing cart item: %w", err)
 	}
-	oi.ID = id
 
-	return nil
+	return cartItemAffected(res)
 }
 
-func (ms *MySQLStorer) GetOrder(ctx context.Context, userId int64) (*Order, error) {
-	var o Order
-	err := ms.db.GetContext(ctx, &o, "SELECT * FROM orders WHERE user_id=?", userId)
+func cartItemAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("error getting order: %w", err)
+		return fmt.Errorf("error getting rows affected: %w", err)
 	}
-
-	var items []OrderItem
-	err = ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", o.ID)
-	if err != nil {
-		return nil, fmt.Errorf("error getting order items: %w", err)
+	if n == 0 {
+		return fmt.Errorf("error getting cart item: %w", sql.ErrNoRows)
 	}
-	o.Items = items
-
-	return &o, nil
+	return nil
 }
 
-func (ms *MySQLStorer) GetOrderStatusByID(ctx context.Context, id int64) (*Order, error) {
-	var o Order
-	err := ms.db.GetContext(ctx, &o, "SELECT id, user_id, status FROM orders WHERE id=?", id)
+func (ms *MySQLStorer) ClearCart(ctx context.Context, co CartOwner) error {
+	cond, arg := co.cond()
+	_, err := ms.db.ExecContext(ctx, "DELETE ci FROM cart_items ci JOIN carts c ON c.id=ci.cart_id WHERE c."+cond, arg)
 	if err != nil {
-		return nil, fmt.Errorf("error getting order: %w", err)
+		return fmt.Errorf("error clearing cart: %w", err)
 	}
-	return &o, nil
+	return nil
 }
 
-func (ms *MySQLStorer) ListOrders(ctx context.Context) ([]*Order, error) {
-	var orders []*Order
-	err := ms.db.SelectContext(ctx, &orders, "SELECT * FROM orders")
-	if err != nil {
-		return nil, fmt.Errorf("error listing orders: %w", err)
-	}
+// MergeCart moves the items of the guest cart with the token into the cart of
+// the user and deletes the guest cart. Quantities of an item in both carts add
+// up, limited to the stock of its product or variant. An unknown token merges
+// nothing.
+func (ms *MySQLStorer) MergeCart(ctx context.Context, token string, userID int64) error {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		var guestID int64
+		err := tx.GetContext(ctx, &guestID, "SELECT id FROM carts WHERE token=? FOR UPDATE", token)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error locking guest cart: %w", err)
+		}
 
-	for i := range orders {
-		var items []OrderItem
-		err = ms.db.SelectContext(ctx, &items, "SELECT * FROM order_items WHERE order_id=?", orders[i].ID)
+		now := time.Now()
+		cartID, err := upsertCart(ctx, tx, CartOwner{UserID: userID}, now)
 		if err != nil {
-			return nil, fmt.Errorf("error getting order items for order id %d: %w", orders[i].ID, err)
+			return err
 		}
-		orders[i].Items = items
-	}
 
-	return orders, nil
-}
+		guest, err := selectCartItems(ctx, tx, guestID)
+		if err != nil {
+			return err
+		}
+		items, err := selectCartItems(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+		have := make(map[cartKey]int64, len(items))
+		for _, ci := range items {
+			have[itemKey(ci.ProductID, ci.VariantID)] = ci.Quantity
+		}
+
+		for _, ci := range guest {
+			had, ok := have[itemKey(ci.ProductID, ci.VariantID)]
+			quantity := mergedQuantity(had, ci.Quantity, ci.CountInStock)
+			switch {
+			case quantity == had:
+				continue
+			case ok:
+				_, err = tx.ExecContext(ctx, "UPDATE cart_items SET quantity=?, updated_at=? WHERE cart_id=? AND product_id=? AND variant_id<=>?", quantity, now, cartID, ci.ProductID, ci.VariantID)
+			default:
+				_, err = tx.ExecContext(ctx, "INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)", cartID, ci.ProductID, ci.VariantID, quantity)
+			}
+			if err != nil {
+				return fmt.Errorf("error merging cart item: %w", err)
+			}
+		}
 
-func (ms *MySQLStorer) UpdateOrderStatus(ctx context.Context, o *Order) (*Order, error) {
-	_, err := ms.db.NamedExecContext(ctx,
-		"UPDATE orders SET status=:status, updated_at=:updated_at WHERE id=:id", o)
+		_, err = tx.ExecContext(ctx, "DELETE FROM carts WHERE id=?", guestID)
+		if err != nil {
+			return fmt.Errorf("error deleting guest cart: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error updating order status: %w", err)
+		return fmt.Errorf("error merging cart: %w", err)
 	}
-	return o, nil
+
+	return nil
 }
 
-func (ms *MySQLStorer) DeleteOrder(ctx context.Context, id int64) error {
+// CheckoutCart places the order and empties the cart of its user in one
+// transaction. The cart is locked while its items are compared with the order,
+// so it fails with ErrCartChanged rather than dropping an item added since the
+// order was priced.
+func (ms *MySQLStorer) CheckoutCart(ctx context.Context, o *Order) (*Order, error) {
 	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
-		_, err := tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id=?", id)
+		var cartID int64
+		err := tx.GetContext(ctx, &cartID, "SELECT id FROM carts WHERE user_id=? FOR UPDATE", o.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCartChanged
+		}
 		if err != nil {
-			return fmt.Errorf("error deleting order items: %w", err)
+			return fmt.Errorf("error locking cart: %w", err)
 		}
 
-		_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE id=?", id)
+		var items []CartItem
+		err = tx.SelectContext(ctx, &items, "SELECT product_id, variant_id, quantity FROM cart_items WHERE cart_id=?", cartID)
 		if err != nil {
-			return fmt.Errorf("error deleting order: %w", err)
+			return fmt.Errorf("error getting cart items: %w", err)
+		}
+		if !sameItems(items, o.Items) {
+			return ErrCartChanged
+		}
+
+		err = placeOrder(ctx, tx, o)
+		if err != nil {
+			return err
 		}
 
+		_, err = tx.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id=?", cartID)
+		if err != nil {
+			return fmt.Errorf("error clearing cart: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("error deleting order transaction: %w", err)
+		return nil, fmt.Errorf("error checking out cart: %w", err)
 	}
-	return nil
+
+	return o, nil
+}
+
+// listQuery builds the SELECT of a page of a list.
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+func (q *listQuery) where(cond string, args ...any) {
+	q.conds = append(q.conds, cond)
+	q.args = append(q.args, args...)
+}
+
+// build fetches one row more than size to tell whether there is a next page.
+func (q *listQuery) build(table, orderBy string, size int) (string, []any) {
+	query := "SELECT * FROM " + table
+	if len(q.conds) > 0 {
+		query += " WHERE " + strings.Join(q.conds, " AND ")
+	}
+	query += " ORDER BY " + orderBy
+
+	args := q.args
+	if size > 0 {
+		query += " LIMIT ?"
+		args = append(args, size+1)
+	}
+
+	return query, args
+}
+
+func isDuplicateEntry(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == mysqlErrDupEntry
+}
+
+func isRowReferenced(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == mysqlErrRowIsReferenced
 }
 
 func (ms *MySQLStorer) execTx(ctx context.Context, fn func(*sqlx.Tx) error) error {
@@ -257,14 +1827,50 @@ func (ms *MySQLStorer) GetUser(ctx context.Context, email string) (*User, error)
 	return &u, nil
 }
 
-func (ms *MySQLStorer) ListUsers(ctx context.Context) ([]*User, error) {
+func (ms *MySQLStorer) GetUserByID(ctx context.Context, id int64) (*User, error) {
+	var u User
+	err := ms.db.GetContext(ctx, &u, "SELECT * FROM users WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	return &u, nil
+}
+
+func (ms *MySQLStorer) ListUsers(ctx context.Context, f *UserFilter) ([]*User, string, error) {
+	k, err := userKeyset(f.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	if f.IsAdmin != nil {
+		q.where("is_admin=?", *f.IsAdmin)
+	}
+	if !f.CreatedAfter.IsZero() {
+		q.where("created_at>=?", f.CreatedAfter)
+	}
+	if !f.CreatedBefore.IsZero() {
+		q.where("created_at<?", f.CreatedBefore)
+	}
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
+
 	var users []*User
-	err := ms.db.SelectContext(ctx, &users, "SELECT * FROM users")
+	query, args := q.build("users", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &users, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error listing users: %w", err)
+		return nil, "", fmt.Errorf("error listing users: %w", err)
 	}
 
-	return users, nil
+	users, next := k.page(users, f.PageSize)
+	return users, next, nil
 }
 
 func (ms *MySQLStorer) UpdateUser(ctx context.Context, u *User) (*User, error) {
@@ -285,6 +1891,96 @@ func (ms *MySQLStorer) DeleteUser(ctx context.Context, id int64) error {
 	return nil
 }
 
+// CreateAddress adds an address to the address book of its user. A default
+// address replaces the previous default one.
+func (ms *MySQLStorer) CreateAddress(ctx context.Context, a *Address) (*Address, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, `INSERT INTO user_addresses (user_id, name, line1, line2, city, region, postal_code, country, phone, is_default)
+			VALUES (:user_id, :name, :line1, :line2, :city, :region, :postal_code, :country, :phone, :is_default)`, a)
+		if err != nil {
+			return fmt.Errorf("error inserting address: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID: %w", err)
+		}
+		a.ID = id
+
+		return clearDefaultAddress(ctx, tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (ms *MySQLStorer) GetAddress(ctx context.Context, id int64) (*Address, error) {
+	var a Address
+	err := ms.db.GetContext(ctx, &a, "SELECT * FROM user_addresses WHERE id=?", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting address: %w", err)
+	}
+
+	return &a, nil
+}
+
+// ListUserAddresses returns the address book of a user, the default address
+// first.
+func (ms *MySQLStorer) ListUserAddresses(ctx context.Context, userID int64) ([]*Address, error) {
+	var addresses []*Address
+	err := ms.db.SelectContext(ctx, &addresses, "SELECT * FROM user_addresses WHERE user_id=? ORDER BY is_default DESC, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// UpdateAddress saves an address. A default address replaces the previous
+// default one.
+func (ms *MySQLStorer) UpdateAddress(ctx context.Context, a *Address) (*Address, error) {
+	err := ms.execTx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.NamedExecContext(ctx, `UPDATE user_addresses SET name=:name, line1=:line1, line2=:line2, city=:city, region=:region,
+			postal_code=:postal_code, country=:country, phone=:phone, is_default=:is_default, updated_at=:updated_at WHERE id=:id`, a)
+		if err != nil {
+			return fmt.Errorf("error updating address: %w", err)
+		}
+
+		return clearDefaultAddress(ctx, tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// clearDefaultAddress unsets the other default addresses of the user if a is
+// the default one.
+func clearDefaultAddress(ctx context.Context, tx *sqlx.Tx, a *Address) error {
+	if !a.IsDefault {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE user_addresses SET is_default=false WHERE user_id=? AND id<>? AND is_default", a.UserID, a.ID)
+	if err != nil {
+		return fmt.Errorf("error clearing default address: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *MySQLStorer) DeleteAddress(ctx context.Context, id int64) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM user_addresses WHERE id=?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting address: %w", err)
+	}
+
+	return nil
+}
+
 func (ms *MySQLStorer) CreateSession(ctx context.Context, s *Session) (*Session, error) {
 	_, err := ms.db.NamedExecContext(ctx, "INSERT INTO sessions (id, user_email, refresh_token, is_revoked, expires_at) VALUES (:id, :user_email, :refresh_token, :is_revoked, :expires_at)", s)
 	if err != nil {
@@ -322,6 +2018,63 @@ func (ms *MySQLStorer) DeleteSession(ctx context.Context, id string) error {
 	return nil
 }
 
+// ReserveIdempotencyKey stores k as in progress, unless the user already sent
+// the key: then it returns the stored key and false. Expired keys are taken
+// over as if they did not exist.
+func (ms *MySQLStorer) ReserveIdempotencyKey(ctx context.Context, k *IdempotencyKey) (*IdempotencyKey, bool, error) {
+	now := time.Now()
+	_, err := ms.db.NamedExecContext(ctx, "INSERT INTO idempotency_keys (user_id, idem_key, request_hash, expires_at) VALUES (:user_id, :idem_key, :request_hash, :expires_at)", k)
+	if err == nil {
+		k.CreatedAt = now
+		return k, true, nil
+	}
+	if !isDuplicateEntry(err) {
+		return nil, false, fmt.Errorf("error inserting idempotency key: %w", err)
+	}
+
+	res, err := ms.db.ExecContext(ctx, `UPDATE idempotency_keys SET request_hash=?, status_code=0, content_type='', response_body=NULL, created_at=?, expires_at=?
+		WHERE user_id=? AND idem_key=? AND expires_at<=?`, k.RequestHash, now, k.ExpiresAt, k.UserID, k.Key, now)
+	if err != nil {
+		return nil, false, fmt.Errorf("error taking over idempotency key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if n == 1 {
+		k.CreatedAt = now
+		return k, true, nil
+	}
+
+	var stored IdempotencyKey
+	err = ms.db.GetContext(ctx, &stored, "SELECT * FROM idempotency_keys WHERE user_id=? AND idem_key=?", k.UserID, k.Key)
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting idempotency key: %w", err)
+	}
+	return &stored, false, nil
+}
+
+// CompleteIdempotencyKey stores the response to the request of a reserved
+// key.
+func (ms *MySQLStorer) CompleteIdempotencyKey(ctx context.Context, k *IdempotencyKey) error {
+	_, err := ms.db.NamedExecContext(ctx, `UPDATE idempotency_keys SET status_code=:status_code, content_type=:content_type, response_body=:response_body
+		WHERE user_id=:user_id AND idem_key=:idem_key`, k)
+	if err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey deletes a key still in progress, so that the request
+// can be retried.
+func (ms *MySQLStorer) ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	_, err := ms.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE user_id=? AND idem_key=? AND status_code=0", userID, key)
+	if err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
 func insertNotificationState(ctx context.Context, tx *sqlx.Tx, es *NotificationState) (*NotificationState, error) {
 	res, err := tx.NamedExecContext(ctx, "INSERT INTO notification_states (order_id, state, message) VALUES (:order_id, :state, :message)", es)
 	if err != nil {
@@ -379,16 +2132,29 @@ func (ms *MySQLStorer) EnqueueNotificationEvent(ctx context.Context, ne *Notific
 	return ev, nil
 }
 
-func (ms *MySQLStorer) ListNotificationEvents(ctx context.Context) ([]*NotificationEvent, error) {
-	var events []*NotificationEvent
+func (ms *MySQLStorer) ListNotificationEvents(ctx context.Context, f *NotificationEventFilter) ([]*NotificationEvent, string, error) {
+	k := notificationEventKeyset()
+	cur, err := k.decode(f.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var q listQuery
+	q.where("attempts<?", maxAttempts)
+	if cur != nil {
+		cond, args := k.where(cur)
+		q.where(cond, args...)
+	}
 
-	q := fmt.Sprintf("SELECT * FROM notification_events_queue WHERE attempts < %d ORDER BY created_at", maxAttempts)
-	err := ms.db.SelectContext(ctx, &events, q)
+	var events []*NotificationEvent
+	query, args := q.build("notification_events_queue", k.orderBy(), f.PageSize)
+	err = ms.db.SelectContext(ctx, &events, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error listing notification events: %w", err)
+		return nil, "", fmt.Errorf("error listing notification events: %w", err)
 	}
 
-	return events, nil
+	events, next := k.page(events, f.PageSize)
+	return events, next, nil
 }
 
 func getNotificationEventAttempts(ctx context.Context, tx *sqlx.Tx, id int64) (*NotificationEvent, error) {
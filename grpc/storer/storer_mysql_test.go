@@ -2,16 +2,22 @@ package storer
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	"github.com/niloy104/Conduit/money"
 	"github.com/stretchr/testify/require"
 )
 
-func withTestDB(t *testing.T, fn func(*sqlx.DB, sqlmock.Sqlmock)) {
+func withTestDB(t testing.TB, fn func(*sqlx.DB, sqlmock.Sqlmock)) {
 	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("error creating sqlmock: %v", err)
@@ -23,15 +29,24 @@ func withTestDB(t *testing.T, fn func(*sqlx.DB, sqlmock.Sqlmock)) {
 }
 
 func TestCreateProduct(t *testing.T) {
+	categoryID := int64(3)
+	sku := "TEST-1"
 	product := &Product{
+		SKU:          &sku,
 		Name:         "test Product",
 		Image:        "test.jpg",
-		Category:     "test Category",
+		CategoryID:   &categoryID,
+		TaxCategory:  "books",
 		Description:  "this is a test product",
 		Rating:       5,
 		NumReviews:   10,
-		Price:        99.99,
+		Price:        9999,
+		Currency:     "USD",
 		CountInStock: 50,
+		Weight:       1200,
+		Length:       300,
+		Width:        200,
+		Height:       100,
 		CreatedAt:    time.Now(),
 	}
 
@@ -42,8 +57,8 @@ func TestCreateProduct(t *testing.T) {
 		{
 			name: "sucess",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
-					WithArgs(product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, sqlmock.AnyArg(), nil).
+				mock.ExpectExec("INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("TEST-1", product.Name, product.Image, product.CategoryID, product.TaxCategory, product.Description, product.Rating, product.NumReviews, product.Price, product.Currency, product.CountInStock, product.Weight, product.Length, product.Width, product.Height).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				cp, err := st.CreateProduct(context.Background(), product)
 				require.NoError(t, err)
@@ -55,8 +70,8 @@ func TestCreateProduct(t *testing.T) {
 		{
 			name: "insert error",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
-					WithArgs(product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, sqlmock.AnyArg(), nil).
+				mock.ExpectExec("INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("TEST-1", product.Name, product.Image, product.CategoryID, product.TaxCategory, product.Description, product.Rating, product.NumReviews, product.Price, product.Currency, product.CountInStock, product.Weight, product.Length, product.Width, product.Height).
 					WillReturnError(sqlmock.ErrCancelled)
 				cp, err := st.CreateProduct(context.Background(), product)
 				require.Error(t, err)
@@ -65,11 +80,22 @@ func TestCreateProduct(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "duplicate sku",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				_, err := st.CreateProduct(context.Background(), product)
+				require.ErrorIs(t, err, ErrDuplicateSKU)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
 		{
 			name: "last insert id error",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("INSERT INTO products (name, image, category, description, rating, num_reviews, price, count_in_stock, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
-					WithArgs(product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, sqlmock.AnyArg(), nil).
+				mock.ExpectExec("INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs("TEST-1", product.Name, product.Image, product.CategoryID, product.TaxCategory, product.Description, product.Rating, product.NumReviews, product.Price, product.Currency, product.CountInStock, product.Weight, product.Length, product.Width, product.Height).
 					WillReturnResult(sqlmock.NewErrorResult(sqlmock.ErrCancelled))
 				cp, err := st.CreateProduct(context.Background(), product)
 				require.Error(t, err)
@@ -92,17 +118,27 @@ func TestCreateProduct(t *testing.T) {
 }
 
 func TestGetProduct(t *testing.T) {
+	categoryID := int64(3)
+	price := money.Amount(10999)
 	product := &Product{
 		ID:           1,
 		Name:         "test Product",
 		Image:        "test.jpg",
-		Category:     "test Category",
+		CategoryID:   &categoryID,
 		Description:  "this is a test product",
 		Rating:       5,
 		NumReviews:   10,
-		Price:        99.99,
+		Price:        9999,
 		CountInStock: 50,
 		CreatedAt:    time.Now(),
+		Variants: []Variant{
+			{ID: 1, ProductID: 1, SKU: "TEST-S", Options: VariantOptions{"size": "S"}, CountInStock: 20},
+			{ID: 2, ProductID: 1, SKU: "TEST-XL", Options: VariantOptions{"size": "XL"}, Price: &price, CountInStock: 30},
+		},
+		Images: []ProductImage{
+			{ID: 4, ProductID: 1, ImageKey: "products/1/a.jpg", URL: "/images/products/1/a.jpg", ThumbnailKey: "products/1/a_thumb.jpg",
+				ThumbnailURL: "/images/products/1/a_thumb.jpg", ContentType: "image/jpeg", Size: 2048, Width: 800, Height: 600},
+		},
 	}
 
 	tcs := []struct {
@@ -112,13 +148,26 @@ func TestGetProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "image", "category", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).
-					AddRow(product.ID, product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, product.CreatedAt, nil)
+				rows := sqlmock.NewRows([]string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).
+					AddRow(product.ID, product.Name, product.Image, product.CategoryID, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, product.CreatedAt, nil)
 
 				mock.ExpectQuery("SELECT * FROM products WHERE id=?").
 					WithArgs(product.ID).
 					WillReturnRows(rows)
 
+				rows = sqlmock.NewRows([]string{"id", "product_id", "sku", "options", "price", "count_in_stock"}).
+					AddRow(1, 1, "TEST-S", []byte(`{"size": "S"}`), nil, 20).
+					AddRow(2, 1, "TEST-XL", []byte(`{"size": "XL"}`), "109.99", 30)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(product.ID).
+					WillReturnRows(rows)
+
+				rows = sqlmock.NewRows([]string{"id", "product_id", "position", "image_key", "url", "thumbnail_key", "thumbnail_url", "content_type", "size", "width", "height"}).
+					AddRow(4, 1, 0, "products/1/a.jpg", "/images/products/1/a.jpg", "products/1/a_thumb.jpg", "/images/products/1/a_thumb.jpg", "image/jpeg", 2048, 800, 600)
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(product.ID).
+					WillReturnRows(rows)
+
 				p, err := st.GetProduct(context.Background(), product.ID)
 				require.NoError(t, err)
 				require.Equal(t, product, p)
@@ -153,16 +202,17 @@ func TestGetProduct(t *testing.T) {
 }
 
 func TestListProducts(t *testing.T) {
-	products := []Product{
+	categoryIDs := []int64{3, 4}
+	products := []*Product{
 		{
 			ID:           1,
 			Name:         "test Product 1",
 			Image:        "test1.jpg",
-			Category:     "test Category 1",
+			CategoryID:   &categoryIDs[0],
 			Description:  "this is a test product 1",
 			Rating:       5,
 			NumReviews:   10,
-			Price:        99.99,
+			Price:        9999,
 			CountInStock: 50,
 			CreatedAt:    time.Now(),
 		},
@@ -170,11 +220,11 @@ func TestListProducts(t *testing.T) {
 			ID:           2,
 			Name:         "test Product 2",
 			Image:        "test2.jpg",
-			Category:     "test Category 2",
+			CategoryID:   &categoryIDs[1],
 			Description:  "this is a test product 2",
 			Rating:       4,
 			NumReviews:   5,
-			Price:        49.99,
+			Price:        4999,
 			CountInStock: 30,
 			CreatedAt:    time.Now(),
 		},
@@ -187,25 +237,108 @@ func TestListProducts(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "image", "category", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).
-					AddRow(products[0].ID, products[0].Name, products[0].Image, products[0].Category, products[0].Description, products[0].Rating, products[0].NumReviews, products[0].Price, products[0].CountInStock, products[0].CreatedAt, nil).
-					AddRow(products[1].ID, products[1].Name, products[1].Image, products[1].Category, products[1].Description, products[1].Rating, products[1].NumReviews, products[1].Price, products[1].CountInStock, products[1].CreatedAt, nil)
+				rows := sqlmock.NewRows([]string{"id", "name", "image", "category_id", "description", "rating", "num_reviews", "price", "count_in_stock", "created_at", "updated_at"}).
+					AddRow(products[0].ID, products[0].Name, products[0].Image, products[0].CategoryID, products[0].Description, products[0].Rating, products[0].NumReviews, products[0].Price, products[0].CountInStock, products[0].CreatedAt, nil).
+					AddRow(products[1].ID, products[1].Name, products[1].Image, products[1].CategoryID, products[1].Description, products[1].Rating, products[1].NumReviews, products[1].Price, products[1].CountInStock, products[1].CreatedAt, nil)
+
+				mock.ExpectQuery("SELECT * FROM products ORDER BY id").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?, ?) ORDER BY id").
+					WithArgs(products[0].ID, products[1].ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?, ?) ORDER BY position, id").
+					WithArgs(products[0].ID, products[1].ID).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				ps, next, err := st.ListProducts(context.Background(), &ProductFilter{})
+				require.NoError(t, err)
+				require.Equal(t, products, ps)
+				require.Empty(t, next)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "filtered and sorted pages",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				cols := []string{"id", "category_id", "price", "count_in_stock"}
+				minPrice, maxPrice := money.Amount(1000), money.Amount(9999)
+				f := &ProductFilter{
+					CategoryIDs: categoryIDs,
+					Prices:      []PriceRange{{Currency: "USD", Min: &minPrice, Max: &maxPrice}},
+					InStock:     true,
+					Sort:        "-price",
+					PageSize:    1,
+				}
+
+				rows := sqlmock.NewRows(cols).
+					AddRow(1, 3, 99.99, 5).
+					AddRow(2, 4, 49.99, 5)
+				mock.ExpectQuery("SELECT * FROM products WHERE category_id IN (?, ?) AND (currency=? AND price>=? AND price<=?) AND count_in_stock>0 ORDER BY price DESC, id DESC LIMIT ?").
+					WithArgs(3, 4, "USD", "10.00", "99.99", 2).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku", "options"}).AddRow(7, 1, "P1-RED", []byte(`{"color": "red"}`)))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				ps, next, err := st.ListProducts(context.Background(), f)
+				require.NoError(t, err)
+				require.Len(t, ps, 1)
+				require.Equal(t, []Variant{{ID: 7, ProductID: 1, SKU: "P1-RED", Options: VariantOptions{"color": "red"}}}, ps[0].Variants)
+				require.NotEmpty(t, next)
+
+				rows = sqlmock.NewRows(cols).AddRow(2, 4, 49.99, 5)
+				mock.ExpectQuery("SELECT * FROM products WHERE category_id IN (?, ?) AND (currency=? AND price>=? AND price<=?) AND count_in_stock>0 AND (price<? OR (price=? AND id<?)) ORDER BY price DESC, id DESC LIMIT ?").
+					WithArgs(3, 4, "USD", "10.00", "99.99", "99.99", "99.99", 1, 2).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				f.PageToken = next
+				ps, next, err = st.ListProducts(context.Background(), f)
+				require.NoError(t, err)
+				require.Len(t, ps, 1)
+				require.Equal(t, int64(2), ps[0].ID)
+				require.Empty(t, next)
 
-				mock.ExpectQuery("SELECT * FROM products").WillReturnRows(rows)
+				f.Sort = "price"
+				_, _, err = st.ListProducts(context.Background(), f)
+				require.ErrorIs(t, err, ErrInvalidPageToken, "token was issued for another sort")
 
-				ps, err := st.ListProducts(context.Background())
+				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
-				require.Equal(t, products, ps)
+			},
+		},
+		{
+			name: "price ranges in several currencies",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				minUSD, minEUR, maxEUR := money.Amount(1000), money.Amount(800), money.Amount(1600)
+				mock.ExpectQuery("SELECT * FROM products WHERE ((currency=? AND price>=?) OR (currency=? AND price>=? AND price<=?)) ORDER BY id").
+					WithArgs("USD", "10.00", "EUR", "8.00", "16.00").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				ps, _, err := st.ListProducts(context.Background(), &ProductFilter{Prices: []PriceRange{
+					{Currency: "USD", Min: &minUSD},
+					{Currency: "EUR", Min: &minEUR, Max: &maxEUR},
+				}})
+				require.NoError(t, err)
+				require.Empty(t, ps)
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "invalid sort",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				_, _, err := st.ListProducts(context.Background(), &ProductFilter{Sort: "description"})
+				require.ErrorIs(t, err, ErrInvalidSort)
+			},
+		},
 		{
 			name: "list error",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT * FROM products").WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectQuery("SELECT * FROM products ORDER BY id").WillReturnError(sqlmock.ErrCancelled)
 
-				ps, err := st.ListProducts(context.Background())
+				ps, _, err := st.ListProducts(context.Background(), &ProductFilter{})
 				require.Error(t, err)
 				require.Nil(t, ps)
 				err = mock.ExpectationsWereMet()
@@ -224,17 +357,88 @@ func TestListProducts(t *testing.T) {
 	}
 }
 
+func TestSearchProducts(t *testing.T) {
+	const searchQuery = `SELECT p.*, MATCH (p.name, p.description) AGAINST (? IN NATURAL LANGUAGE MODE)
+		+ COALESCE(MATCH (c.name) AGAINST (? IN NATURAL LANGUAGE MODE), 0) AS score
+		FROM products p LEFT JOIN categories c ON c.id = p.category_id
+		WHERE MATCH (p.name, p.description) AGAINST (? IN NATURAL LANGUAGE MODE) OR MATCH (c.name) AGAINST (? IN NATURAL LANGUAGE MODE)
+		ORDER BY score DESC, p.id DESC LIMIT ? OFFSET ?`
+	cols := []string{"id", "name", "description", "category_id", "score"}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(cols).
+					AddRow(2, "Wireless headset", "Wireless headset.", 3, 1.5).
+					AddRow(1, "Keyboard", "Pairs with any wireless receiver.", 4, 0.5)
+				mock.ExpectQuery(searchQuery).WithArgs("wireless", "wireless", "wireless", "wireless", 2, 0).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				ms, next, err := st.SearchProducts(context.Background(), &ProductSearch{Query: "wireless", PageSize: 1})
+				require.NoError(t, err)
+				require.Len(t, ms, 1)
+				require.Equal(t, int64(2), ms[0].ID)
+				require.Equal(t, 1.5, ms[0].Score)
+				require.Equal(t, "<mark>Wireless</mark> headset.", ms[0].Snippet)
+
+				rows = sqlmock.NewRows(cols).AddRow(1, "Keyboard", "Pairs with any wireless receiver.", 4, 0.5)
+				mock.ExpectQuery(searchQuery).WithArgs("wireless", "wireless", "wireless", "wireless", 2, 1).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				ms, next, err = st.SearchProducts(context.Background(), &ProductSearch{Query: "wireless", PageSize: 1, PageToken: next})
+				require.NoError(t, err)
+				require.Len(t, ms, 1)
+				require.Equal(t, "Pairs with any <mark>wireless</mark> receiver.", ms[0].Snippet)
+				require.Empty(t, next)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed searching",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(searchQuery).WithArgs("wireless", "wireless", "wireless", "wireless", 2, 0).WillReturnError(fmt.Errorf("error searching"))
+
+				_, _, err := st.SearchProducts(context.Background(), &ProductSearch{Query: "wireless", PageSize: 1})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
 func TestUpdateProduct(t *testing.T) {
 	now := time.Now()
+	categoryID := int64(3)
 	product := &Product{
 		ID:           1,
 		Name:         "updated Product",
 		Image:        "updated.jpg",
-		Category:     "updated Category",
+		CategoryID:   &categoryID,
 		Description:  "this is an updated product",
 		Rating:       4,
 		NumReviews:   15,
-		Price:        89.99,
+		Price:        8999,
 		CountInStock: 40,
 		UpdatedAt:    &now,
 	}
@@ -246,8 +450,8 @@ func TestUpdateProduct(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE products SET name=?, image=?, category=?, description=?, rating=?, num_reviews=?, price=?, count_in_stock=?, updated_at=? WHERE id=?").
-					WithArgs(product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, sqlmock.AnyArg(), product.ID).
+				mock.ExpectExec("UPDATE products SET sku=?, name=?, image=?, category_id=?, tax_category=?, description=?, price=?, currency=?, count_in_stock=?, weight=?, length=?, width=?, height=?, updated_at=? WHERE id=?").
+					WithArgs(nil, product.Name, product.Image, product.CategoryID, product.TaxCategory, product.Description, product.Price, product.Currency, product.CountInStock, product.Weight, product.Length, product.Width, product.Height, sqlmock.AnyArg(), product.ID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
 				p, err := st.UpdateProduct(context.Background(), product)
@@ -260,8 +464,8 @@ func TestUpdateProduct(t *testing.T) {
 		{
 			name: "update error",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE products SET name=?, image=?, category=?, description=?, rating=?, num_reviews=?, price=?, count_in_stock=?, updated_at=? WHERE id=?").
-					WithArgs(product.Name, product.Image, product.Category, product.Description, product.Rating, product.NumReviews, product.Price, product.CountInStock, sqlmock.AnyArg(), product.ID).
+				mock.ExpectExec("UPDATE products SET sku=?, name=?, image=?, category_id=?, tax_category=?, description=?, price=?, currency=?, count_in_stock=?, weight=?, length=?, width=?, height=?, updated_at=? WHERE id=?").
+					WithArgs(nil, product.Name, product.Image, product.CategoryID, product.TaxCategory, product.Description, product.Price, product.Currency, product.CountInStock, product.Weight, product.Length, product.Width, product.Height, sqlmock.AnyArg(), product.ID).
 					WillReturnError(sqlmock.ErrCancelled)
 
 				p, err := st.UpdateProduct(context.Background(), product)
@@ -283,6 +487,89 @@ func TestUpdateProduct(t *testing.T) {
 	}
 }
 
+func TestSaveProducts(t *testing.T) {
+	const (
+		insertQuery = `INSERT INTO products (sku, name, image, category_id, tax_category, description, rating, num_reviews, price, currency, count_in_stock, weight, length, width, height)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		updateQuery = `UPDATE products SET sku=?, name=?, image=?, category_id=?, tax_category=?, description=?, price=?, currency=?, count_in_stock=?, weight=?, length=?, width=?, height=?,
+			updated_at=? WHERE id=?`
+	)
+	lamp, desk := "LAMP-1", "DESK-1"
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "get by sku",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM products WHERE sku IN (?, ?) ORDER BY id").
+					WithArgs(lamp, desk).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "name"}).AddRow(4, lamp, "Lamp"))
+				mock.ExpectQuery("SELECT * FROM product_variants WHERE product_id IN (?) ORDER BY id").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+				mock.ExpectQuery("SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, id").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+				ps, err := st.GetProductsBySKU(context.Background(), []string{lamp, desk})
+				require.NoError(t, err)
+				require.Equal(t, []*Product{{ID: 4, SKU: &lamp, Name: "Lamp"}}, ps)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "create and update",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertQuery).
+					WithArgs(desk, "Desk", "", nil, "", "", 0, 0, "120.00", "USD", 2, 0, 0, 0, 0).
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(updateQuery).
+					WithArgs(lamp, "Lamp", "", nil, "", "", "45.00", "USD", 9, 0, 0, 0, 0, sqlmock.AnyArg(), 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				created := &Product{SKU: &desk, Name: "Desk", Price: 12000, Currency: "USD", CountInStock: 2}
+				updated := &Product{ID: 4, SKU: &lamp, Name: "Lamp", Price: 4500, Currency: "USD", CountInStock: 9}
+				err := st.SaveProducts(context.Background(), []*Product{created, updated})
+				require.NoError(t, err)
+				require.Equal(t, int64(7), created.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "duplicate sku",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(updateQuery).WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				created := &Product{SKU: &desk, Name: "Desk"}
+				err := st.SaveProducts(context.Background(), []*Product{created, {ID: 4, SKU: &desk, Name: "Lamp"}})
+				require.ErrorIs(t, err, ErrDuplicateSKU)
+				require.Zero(t, created.ID, "the insert was rolled back")
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
 func TestDeleteProduct(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -328,102 +615,68 @@ func TestDeleteProduct(t *testing.T) {
 
 // Additional tests for Order and OrderItem can be added similarly.
 
-func TestCreateOrder(t *testing.T) {
-	ois := []OrderItem{
-		{
-			Name:      "test product",
-			Quantity:  1,
-			Image:     "test.jpg",
-			Price:     99.99,
-			ProductID: 1,
-		},
-		{
-			Name:      "test product 2",
-			Quantity:  2,
-			Image:     "test2.jpg",
-			Price:     199.99,
-			ProductID: 2,
-		},
-	}
-
-	o := &Order{
-		UserID:        1, // <- make sure to set a userID here
-		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
-		Items:         ois,
-	}
-
+func TestCategories(t *testing.T) {
+	parentID := int64(1)
 	tcs := []struct {
 		name string
 		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
 	}{
 		{
-			name: "success",
+			name: "create",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO orders\s*\(\s*user_id\s*,\s*payment_method\s*,\s*tax_price\s*,\s*shipping_price\s*,\s*total_price\s*,\s*created_at\s*,\s*updated_at\s*\)\s*VALUES\s*\(\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*\)`).
-					WithArgs(o.UserID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, sqlmock.AnyArg(), nil).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				mock.ExpectExec(`INSERT INTO order_items\s*\(\s*name\s*,\s*quantity\s*,\s*image\s*,\s*price\s*,\s*product_id\s*,\s*order_id\s*\)\s*VALUES\s*\(\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*\)`).
-					WithArgs(o.Items[0].Name, o.Items[0].Quantity, o.Items[0].Image, o.Items[0].Price, o.Items[0].ProductID, 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				mock.ExpectExec(`INSERT INTO order_items\s*\(\s*name\s*,\s*quantity\s*,\s*image\s*,\s*price\s*,\s*product_id\s*,\s*order_id\s*\)\s*VALUES\s*\(\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*,\s*\?\s*\)`).
-					WithArgs(o.Items[1].Name, o.Items[1].Quantity, o.Items[1].Image, o.Items[1].Price, o.Items[1].ProductID, 1).
+				mock.ExpectExec("INSERT INTO categories (parent_id, name, slug) VALUES (?, ?, ?)").
+					WithArgs(&parentID, "Fantasy", "fantasy").
 					WillReturnResult(sqlmock.NewResult(2, 1))
 
-				mock.ExpectCommit()
-
-				mo, err := st.CreateOrder(context.Background(), o)
+				c, err := st.CreateCategory(context.Background(), &Category{ParentID: &parentID, Name: "Fantasy", Slug: "fantasy"})
 				require.NoError(t, err)
-				require.Equal(t, int64(1), mo.ID)
+				require.Equal(t, int64(2), c.ID)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed inserting order",
+			name: "duplicate slug",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO categories (parent_id, name, slug) VALUES (?, ?, ?)").
+					WithArgs(nil, "Books", "books").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
 
-				mock.ExpectExec(
-					"INSERT INTO orders \\(\\s*user_id, payment_method, tax_price, shipping_price, total_price, created_at, updated_at\\s*\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)",
-				).
-					WithArgs(o.UserID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, sqlmock.AnyArg(), nil).
-					WillReturnError(fmt.Errorf("error inserting order"))
+				_, err := st.CreateCategory(context.Background(), &Category{Name: "Books", Slug: "books"})
+				require.ErrorIs(t, err, ErrDuplicateCategory)
 
-				mock.ExpectRollback()
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "list",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "parent_id", "name", "slug"}).
+					AddRow(1, nil, "Books", "books").
+					AddRow(2, 1, "Fantasy", "fantasy")
+				mock.ExpectQuery("SELECT * FROM categories ORDER BY name, id").WillReturnRows(rows)
 
-				_, err := st.CreateOrder(context.Background(), o)
-				require.Error(t, err)
+				cs, err := st.ListCategories(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, []*Category{
+					{ID: 1, Name: "Books", Slug: "books"},
+					{ID: 2, ParentID: &parentID, Name: "Fantasy", Slug: "fantasy"},
+				}, cs)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed inserting order item",
+			name: "delete category in use",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-
-				mock.ExpectExec(
-					"INSERT INTO orders \\(\\s*user_id, payment_method, tax_price, shipping_price, total_price, created_at, updated_at\\s*\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)",
-				).
-					WithArgs(o.UserID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, sqlmock.AnyArg(), nil).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec(
-					"INSERT INTO order_items \\(\\s*name, quantity, image, price, product_id, order_id\\s*\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)",
-				).
-					WithArgs(o.Items[0].Name, o.Items[0].Quantity, o.Items[0].Image, o.Items[0].Price, o.Items[0].ProductID, 1).
-					WillReturnError(fmt.Errorf("error inserting order item"))	
-				mock.ExpectRollback()
+				mock.ExpectExec("DELETE FROM categories WHERE id=?").WithArgs(1).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrRowIsReferenced, Message: "Cannot delete or update a parent row"})
 
-				_, err := st.CreateOrder(context.Background(), o)
-				require.Error(t, err)
+				err := st.DeleteCategory(context.Background(), 1)
+				require.ErrorIs(t, err, ErrCategoryInUse)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
@@ -432,94 +685,100 @@ func TestCreateOrder(t *testing.T) {
 	}
 
 	for _, tc := range tcs {
-		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
-			st := NewMySQLStorer(db)
-			tc.test(t, st, mock)
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
 		})
 	}
 }
 
-func TestGetOrder(t *testing.T) {
-	ois := []OrderItem{
-		{
-			ID:        1,
-			Name:      "test product",
-			Quantity:  1,
-			Image:     "test.jpg",
-			Price:     99.99,
-			ProductID: 1,
-			OrderID:   1,
-		},
-		{
-			ID:        2,
-			Name:      "test product 2",
-			Quantity:  2,
-			Image:     "test2.jpg",
-			Price:     199.99,
-			ProductID: 2,
-			OrderID:   1,
-		},
-	}
-
-	o := &Order{
-		ID:            1,
-		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
-		Items:         ois,
-	}
+func TestVariants(t *testing.T) {
+	const syncStockQuery = "UPDATE products SET count_in_stock=(SELECT COALESCE(SUM(count_in_stock), 0) FROM product_variants WHERE product_id=?) WHERE id=?"
+	price := money.Amount(1250)
+	variant := &Variant{ProductID: 1, SKU: "TEE-RED-M", Options: VariantOptions{"color": "red", "size": "M"}, Price: &price, CountInStock: 4}
 
 	tcs := []struct {
 		name string
 		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
 	}{
 		{
-			name: "success",
+			name: "create",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
-					AddRow(o.ID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
-
-				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnRows(orows)
-
-				oirows := sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "order_id"}).
-					AddRow(ois[0].ID, ois[0].Name, ois[0].Quantity, ois[0].Image, ois[0].Price, ois[0].ProductID, ois[0].OrderID).
-					AddRow(ois[1].ID, ois[1].Name, ois[1].Quantity, ois[1].Image, ois[1].Price, ois[1].ProductID, ois[1].OrderID)
-
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(o.ID).WillReturnRows(oirows)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_variants (product_id, sku, options, price, count_in_stock) VALUES (?, ?, ?, ?, ?)").
+					WithArgs(1, "TEE-RED-M", `{"color":"red","size":"M"}`, "12.50", 4).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(syncStockQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 
-				mo, err := st.GetOrder(context.Background(), o.ID)
+				v, err := st.CreateVariant(context.Background(), variant)
 				require.NoError(t, err)
-				require.Equal(t, o, mo)
+				require.Equal(t, int64(3), v.ID)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed querying order",
+			name: "duplicate sku",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnError(fmt.Errorf("error querying order"))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_variants (product_id, sku, options, price, count_in_stock) VALUES (?, ?, ?, ?, ?)").
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
 
-				_, err := st.GetOrder(context.Background(), o.ID)
-				require.Error(t, err)
+				_, err := st.CreateVariant(context.Background(), variant)
+				require.ErrorIs(t, err, ErrDuplicateSKU)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed querying order items",
+			name: "delete",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
-					AddRow(o.ID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM product_variants WHERE id=?").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM product_variants WHERE id=?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(syncStockQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 
-				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnRows(orows)
+				err := st.DeleteVariant(context.Background(), 3)
+				require.NoError(t, err)
 
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(o.ID).WillReturnError(fmt.Errorf("error querying order items"))
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete ordered variant",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM product_variants WHERE id=?").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM product_variants WHERE id=?").WithArgs(3).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrRowIsReferenced, Message: "Cannot delete or update a parent row"})
+				mock.ExpectRollback()
 
-				_, err := st.GetOrder(context.Background(), o.ID)
-				require.Error(t, err)
+				err := st.DeleteVariant(context.Background(), 3)
+				require.ErrorIs(t, err, ErrVariantOrdered)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
@@ -528,39 +787,1306 @@ func TestGetOrder(t *testing.T) {
 	}
 
 	for _, tc := range tcs {
-		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
-			st := NewMySQLStorer(db)
-			tc.test(t, st, mock)
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
 		})
 	}
 }
 
-func TestListOrders(t *testing.T) {
-	ois := []OrderItem{
+func TestProductImages(t *testing.T) {
+	const syncImageQuery = "UPDATE products SET image=COALESCE((SELECT url FROM product_images WHERE product_id=? ORDER BY position, id LIMIT 1), '') WHERE id=?"
+	image := &ProductImage{ProductID: 1, ImageKey: "products/1/b.png", URL: "/images/products/1/b.png", ThumbnailKey: "products/1/b_thumb.png",
+		ThumbnailURL: "/images/products/1/b_thumb.png", ContentType: "image/png", Size: 1024, Width: 640, Height: 480}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
 		{
-			Name:      "test product",
-			Quantity:  1,
+			name: "create",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("SELECT COALESCE(MAX(position)+1, 0) FROM product_images WHERE product_id=?").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(2))
+				mock.ExpectExec(`INSERT INTO product_images (product_id, position, image_key, url, thumbnail_key, thumbnail_url, content_type, size, width, height)
+					VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`).
+					WithArgs(1, 2, image.ImageKey, image.URL, image.ThumbnailKey, image.ThumbnailURL, "image/png", 1024, 640, 480).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(syncImageQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				img, err := st.CreateProductImage(context.Background(), image)
+				require.NoError(t, err)
+				require.Equal(t, int64(5), img.ID)
+				require.Equal(t, int64(2), img.Position)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "delete",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM product_images WHERE id=?").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM product_images WHERE id=?").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(syncImageQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteProductImage(context.Background(), 5)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "reorder",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM product_images WHERE product_id=?").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+				mock.ExpectExec("UPDATE product_images SET position=? WHERE id=?").WithArgs(0, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE product_images SET position=? WHERE id=?").WithArgs(1, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(syncImageQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.ReorderProductImages(context.Background(), 1, []int64{5, 4})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "reorder other images",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM product_images WHERE product_id=?").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5).AddRow(6))
+				mock.ExpectRollback()
+
+				err := st.ReorderProductImages(context.Background(), 1, []int64{5, 4})
+				require.ErrorIs(t, err, ErrImagesMismatch)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestCreateReview(t *testing.T) {
+	review := &Review{
+		ProductID: 1,
+		UserID:    2,
+		Rating:    4,
+		Comment:   "works as advertised",
+		Status:    ReviewPublished,
+	}
+
+	expectRatingUpdate := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("UPDATE products SET rating=(SELECT COALESCE(ROUND(AVG(rating)), 0) FROM reviews WHERE product_id=? AND status=?), num_reviews=(SELECT COUNT(*) FROM reviews WHERE product_id=? AND status=?) WHERE id=?").
+			WithArgs(review.ProductID, ReviewPublished, review.ProductID, ReviewPublished, review.ProductID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	tcs := []struct {
+		name string
+		test func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(review.ProductID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(review.ProductID))
+				mock.ExpectExec("INSERT INTO reviews (product_id, user_id, rating, comment, status) VALUES (?, ?, ?, ?, ?)").
+					WithArgs(review.ProductID, review.UserID, review.Rating, review.Comment, review.Status).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectRatingUpdate(mock)
+				mock.ExpectCommit()
+
+				r, err := st.CreateReview(context.Background(), review)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), r.ID)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "duplicate review",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(review.ProductID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(review.ProductID))
+				mock.ExpectExec("INSERT INTO reviews (product_id, user_id, rating, comment, status) VALUES (?, ?, ?, ?, ?)").
+					WithArgs(review.ProductID, review.UserID, review.Rating, review.Comment, review.Status).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				r, err := st.CreateReview(context.Background(), review)
+				require.ErrorIs(t, err, ErrDuplicateReview)
+				require.Nil(t, r)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unknown product",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(review.ProductID).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				r, err := st.CreateReview(context.Background(), review)
+				require.ErrorIs(t, err, sql.ErrNoRows)
+				require.Nil(t, r)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM reviews WHERE id=?").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(1))
+				mock.ExpectQuery("SELECT id FROM products WHERE id=? FOR UPDATE").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("DELETE FROM reviews WHERE id=?").
+					WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE products SET rating=(SELECT COALESCE(ROUND(AVG(rating)), 0) FROM reviews WHERE product_id=? AND status=?), num_reviews=(SELECT COUNT(*) FROM reviews WHERE product_id=? AND status=?) WHERE id=?").
+					WithArgs(1, ReviewPublished, 1, ReviewPublished, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteReview(context.Background(), 3)
+				require.NoError(t, err)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unknown review",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT product_id FROM reviews WHERE id=?").
+					WithArgs(3).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				err := st.DeleteReview(context.Background(), 3)
+				require.ErrorIs(t, err, sql.ErrNoRows)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestAddCartItem(t *testing.T) {
+	variantID := int64(5)
+	expectCart := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO carts (user_id, token) VALUES (?, ?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?").
+			WithArgs(1, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+	}
+	const updateQuery = "UPDATE cart_items SET quantity=quantity+?, updated_at=? WHERE cart_id=? AND product_id=? AND variant_id<=>?"
+
+	tcs := []struct {
+		name string
+		test func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "item in the cart",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCart(mock)
+				mock.ExpectExec(updateQuery).
+					WithArgs(2, sqlmock.AnyArg(), 7, 3, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.AddCartItem(context.Background(), CartOwner{UserID: 1}, 3, nil, 2)
+				require.NoError(t, err)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "new variant",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCart(mock)
+				mock.ExpectExec(updateQuery).
+					WithArgs(2, sqlmock.AnyArg(), 7, 3, variantID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)").
+					WithArgs(7, 3, variantID, 2).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				err := st.AddCartItem(context.Background(), CartOwner{UserID: 1}, 3, &variantID, 2)
+				require.NoError(t, err)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestCheckoutCart(t *testing.T) {
+	order := &Order{
+		PaymentMethod: "card",
+		TaxPrice:      100,
+		ShippingPrice: 500,
+		TotalPrice:    2600,
+		UserID:        1,
+		Items:         []OrderItem{{Name: "test product", Quantity: 2, Image: "test.jpg", Price: 1000, ProductID: 3}},
+	}
+
+	expectCart := func(mock sqlmock.Sqlmock, quantity int64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM carts WHERE user_id=? FOR UPDATE").
+			WithArgs(order.UserID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery("SELECT product_id, variant_id, quantity FROM cart_items WHERE cart_id=?").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "quantity"}).AddRow(3, nil, quantity))
+	}
+
+	tcs := []struct {
+		name string
+		test func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCart(mock, 2)
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(2, 3, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate, ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(order.PaymentMethod, nil, nil, order.DiscountPrice, order.TaxPrice, order.TaxInclusive, order.ShippingPrice, order.ShippingMethod, order.TotalPrice, order.Currency, order.ExchangeRate, order.ShipName, order.ShipLine1, order.ShipLine2, order.ShipCity, order.ShipRegion, order.ShipPostalCode, order.ShipCountry, order.ShipPhone, order.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items ( name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )").
+					WithArgs("test product", 2, "test.jpg", "10.00", 0, "0.00", 3, nil, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, nil, Pending, order.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM cart_items WHERE cart_id=?").
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				o, err := st.CheckoutCart(context.Background(), order)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), o.ID)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "cart changed",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCart(mock, 3)
+				mock.ExpectRollback()
+
+				o, err := st.CheckoutCart(context.Background(), order)
+				require.ErrorIs(t, err, ErrCartChanged)
+				require.Nil(t, o)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no cart",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM carts WHERE user_id=? FOR UPDATE").
+					WithArgs(order.UserID).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				o, err := st.CheckoutCart(context.Background(), order)
+				require.ErrorIs(t, err, ErrCartChanged)
+				require.Nil(t, o)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestMergeCart(t *testing.T) {
+	itemCols := []string{"id", "cart_id", "product_id", "variant_id", "quantity", "created_at", "updated_at", "name", "image", "price", "count_in_stock", "options"}
+	itemsQuery := "SELECT ci.*, p.name, p.image, COALESCE(v.price, p.price) AS price, p.currency, COALESCE(v.count_in_stock, p.count_in_stock) AS count_in_stock, v.options FROM cart_items ci JOIN products p ON p.id=ci.product_id LEFT JOIN product_variants v ON v.id=ci.variant_id WHERE ci.cart_id=? ORDER BY ci.id"
+
+	tcs := []struct {
+		name string
+		test func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				now := time.Now()
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM carts WHERE token=? FOR UPDATE").
+					WithArgs("guest-token").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec("INSERT INTO carts (user_id, token) VALUES (?, ?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), updated_at=?").
+					WithArgs(1, nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectQuery(itemsQuery).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(itemCols).
+						AddRow(3, 2, 10, nil, 8, now, nil, "in both carts", "a.jpg", 10, 10, nil).
+						AddRow(4, 2, 11, nil, 1, now, nil, "out of stock", "b.jpg", 10, 0, nil).
+						AddRow(5, 2, 12, nil, 2, now, nil, "guest only", "c.jpg", 10, 5, nil).
+						AddRow(6, 2, 10, 20, 1, now, nil, "variant of the guest only", "a.jpg", 10, 3, `{"color": "red"}`))
+				mock.ExpectQuery(itemsQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(itemCols).
+						AddRow(1, 1, 10, nil, 4, now, nil, "in both carts", "a.jpg", 10, 10, nil))
+				mock.ExpectExec("UPDATE cart_items SET quantity=?, updated_at=? WHERE cart_id=? AND product_id=? AND variant_id<=>?").
+					WithArgs(10, sqlmock.AnyArg(), 1, 10, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)").
+					WithArgs(1, 12, nil, 2).
+					WillReturnResult(sqlmock.NewResult(6, 1))
+				mock.ExpectExec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)").
+					WithArgs(1, 10, 20, 1).
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec("DELETE FROM carts WHERE id=?").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err := st.MergeCart(context.Background(), "guest-token", 1)
+				require.NoError(t, err)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unknown token",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM carts WHERE token=? FOR UPDATE").
+					WithArgs("guest-token").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectCommit()
+
+				err := st.MergeCart(context.Background(), "guest-token", 1)
+				require.NoError(t, err)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	variantID := int64(5)
+	ois := []OrderItem{
+		{
+			Name:      "test product",
+			Quantity:  1,
+			Image:     "test.jpg",
+			Price:     9999,
+			TaxRate:   550,
+			TaxPrice:  550,
+			ProductID: 1,
+		},
+		{
+			Name:      "test product 2",
+			Quantity:  2,
+			Image:     "test2.jpg",
+			Price:     19999,
+			TaxRate:   2000,
+			TaxPrice:  450,
+			ProductID: 2,
+			VariantID: &variantID,
+		},
+	}
+
+	o := &Order{
+		UserID:         1, // <- make sure to set a userID here
+		PaymentMethod:  "test payment method",
+		TaxPrice:       1000,
+		ShippingPrice:  2000,
+		ShippingMethod: "standard",
+		TotalPrice:     12999,
+		Currency:       "EUR",
+		ExchangeRate:   921500,
+		OrderAddress: OrderAddress{
+			ShipName:       "Test User",
+			ShipLine1:      "1 Test Street",
+			ShipCity:       "Testville",
+			ShipPostalCode: "12345",
+			ShipCountry:    "FR",
+		},
+		Items: ois,
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, o.Items[1].ProductID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE product_variants SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, variantID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate, ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(o.PaymentMethod, nil, nil, o.DiscountPrice, o.TaxPrice, o.TaxInclusive, o.ShippingPrice, o.ShippingMethod, o.TotalPrice, o.Currency, o.ExchangeRate, o.ShipName, o.ShipLine1, o.ShipLine2, o.ShipCity, o.ShipRegion, o.ShipPostalCode, o.ShipCountry, o.ShipPhone, o.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO order_items ( name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )").
+					WithArgs(o.Items[0].Name, o.Items[0].Quantity, o.Items[0].Image, o.Items[0].Price, o.Items[0].TaxRate, o.Items[0].TaxPrice, o.Items[0].ProductID, nil, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO order_items ( name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )").
+					WithArgs(o.Items[1].Name, o.Items[1].Quantity, o.Items[1].Image, o.Items[1].Price, o.Items[1].TaxRate, o.Items[1].TaxPrice, o.Items[1].ProductID, 5, 1).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, nil, Pending, o.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mo, err := st.CreateOrder(context.Background(), o)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), mo.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "insufficient stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), o)
+				require.ErrorIs(t, err, ErrInsufficientStock)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "insufficient variant stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, o.Items[1].ProductID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE product_variants SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, variantID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), o)
+				require.ErrorIs(t, err, ErrInsufficientStock)
+				require.ErrorContains(t, err, "variant 5")
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed inserting order",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, o.Items[1].ProductID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE product_variants SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, variantID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate, ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(o.PaymentMethod, nil, nil, o.DiscountPrice, o.TaxPrice, o.TaxInclusive, o.ShippingPrice, o.ShippingMethod, o.TotalPrice, o.Currency, o.ExchangeRate, o.ShipName, o.ShipLine1, o.ShipLine2, o.ShipCity, o.ShipRegion, o.ShipPostalCode, o.ShipCountry, o.ShipPhone, o.UserID).
+					WillReturnError(fmt.Errorf("error inserting order"))
+
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), o)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed inserting order item",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, o.Items[1].ProductID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE product_variants SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[1].Quantity, variantID, o.Items[1].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate, ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(o.PaymentMethod, nil, nil, o.DiscountPrice, o.TaxPrice, o.TaxInclusive, o.ShippingPrice, o.ShippingMethod, o.TotalPrice, o.Currency, o.ExchangeRate, o.ShipName, o.ShipLine1, o.ShipLine2, o.ShipCity, o.ShipRegion, o.ShipPostalCode, o.ShipCountry, o.ShipPhone, o.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items ( name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )").
+					WithArgs(o.Items[0].Name, o.Items[0].Quantity, o.Items[0].Image, o.Items[0].Price, o.Items[0].TaxRate, o.Items[0].TaxPrice, o.Items[0].ProductID, nil, 1).
+					WillReturnError(fmt.Errorf("error inserting order item"))
+				mock.ExpectRollback()
+
+				_, err := st.CreateOrder(context.Background(), o)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+func TestCreateOrderCoupon(t *testing.T) {
+	couponID, couponCode := int64(3), "SPRING"
+	o := &Order{
+		UserID:        1,
+		PaymentMethod: "test payment method",
+		CouponID:      &couponID,
+		CouponCode:    &couponCode,
+		DiscountPrice: 1000,
+		TotalPrice:    8999,
+		Items:         []OrderItem{{Name: "test product", Quantity: 1, Price: 9999, ProductID: 1}},
+	}
+
+	expectCoupon := func(mock sqlmock.Sqlmock, uses, userUses int64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT * FROM coupons WHERE id=? FOR UPDATE").
+			WithArgs(couponID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "kind", "value", "max_uses", "max_uses_per_user"}).
+				AddRow(couponID, couponCode, FixedCoupon, 10, 5, 1))
+		mock.ExpectQuery("SELECT COUNT(*) AS total, COALESCE(SUM(user_id=?), 0) AS by_user FROM orders WHERE coupon_id=?").
+			WithArgs(o.UserID, couponID).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "by_user"}).AddRow(uses, userUses))
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCoupon(mock, 4, 0)
+				mock.ExpectExec("UPDATE products SET count_in_stock=count_in_stock-? WHERE id=? AND count_in_stock>=?").
+					WithArgs(o.Items[0].Quantity, o.Items[0].ProductID, o.Items[0].Quantity).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO orders (payment_method, coupon_id, coupon_code, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, currency, exchange_rate, ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone, user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+					WithArgs(o.PaymentMethod, couponID, couponCode, o.DiscountPrice, o.TaxPrice, o.TaxInclusive, o.ShippingPrice, o.ShippingMethod, o.TotalPrice, o.Currency, o.ExchangeRate, o.ShipName, o.ShipLine1, o.ShipLine2, o.ShipCity, o.ShipRegion, o.ShipPostalCode, o.ShipCountry, o.ShipPhone, o.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_items ( name, quantity, image, price, tax_rate, tax_price, product_id, variant_id, order_id ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )").
+					WithArgs(o.Items[0].Name, o.Items[0].Quantity, o.Items[0].Image, o.Items[0].Price, o.Items[0].TaxRate, o.Items[0].TaxPrice, o.Items[0].ProductID, nil, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, nil, Pending, o.UserID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				mo, err := st.CreateOrder(context.Background(), o)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), mo.ID)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "global limit reached",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCoupon(mock, 5, 0)
+				mock.ExpectRollback()
+
+				mo, err := st.CreateOrder(context.Background(), o)
+				require.ErrorIs(t, err, ErrCouponExhausted)
+				require.Nil(t, mo)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "per user limit reached",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectCoupon(mock, 2, 1)
+				mock.ExpectRollback()
+
+				mo, err := st.CreateOrder(context.Background(), o)
+				require.ErrorIs(t, err, ErrCouponExhausted)
+				require.Nil(t, mo)
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	ois := []OrderItem{
+		{
+			ID:        1,
+			Name:      "test product",
+			Quantity:  1,
+			Image:     "test.jpg",
+			Price:     9999,
+			ProductID: 1,
+			OrderID:   1,
+		},
+		{
+			ID:        2,
+			Name:      "test product 2",
+			Quantity:  2,
+			Image:     "test2.jpg",
+			Price:     19999,
+			ProductID: 2,
+			OrderID:   1,
+		},
+	}
+
+	o := &Order{
+		ID:            1,
+		PaymentMethod: "test payment method",
+		TaxPrice:      1000,
+		ShippingPrice: 2000,
+		TotalPrice:    12999,
+		Items:         ois,
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
+					AddRow(o.ID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+
+				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnRows(orows)
+
+				oirows := sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "order_id"}).
+					AddRow(ois[0].ID, ois[0].Name, ois[0].Quantity, ois[0].Image, ois[0].Price, ois[0].ProductID, ois[0].OrderID).
+					AddRow(ois[1].ID, ois[1].Name, ois[1].Quantity, ois[1].Image, ois[1].Price, ois[1].ProductID, ois[1].OrderID)
+
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(o.ID).WillReturnRows(oirows)
+
+				mo, err := st.GetOrder(context.Background(), o.ID)
+				require.NoError(t, err)
+				require.Equal(t, o, mo)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed querying order",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnError(fmt.Errorf("error querying order"))
+
+				_, err := st.GetOrder(context.Background(), o.ID)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed querying order items",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
+					AddRow(o.ID, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+
+				mock.ExpectQuery("SELECT * FROM orders WHERE id=?").WithArgs(o.ID).WillReturnRows(orows)
+
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(o.ID).WillReturnError(fmt.Errorf("error querying order items"))
+
+				_, err := st.GetOrder(context.Background(), o.ID)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	ois := []OrderItem{
+		{
+			Name:      "test product",
+			Quantity:  1,
 			Image:     "test.jpg",
-			Price:     99.99,
+			Price:     9999,
 			ProductID: 1,
 		},
 		{
-			Name:      "test product 2",
-			Quantity:  2,
-			Image:     "test2.jpg",
-			Price:     199.99,
-			ProductID: 2,
+			Name:      "test product 2",
+			Quantity:  2,
+			Image:     "test2.jpg",
+			Price:     19999,
+			ProductID: 2,
+		},
+	}
+
+	o := &Order{
+		PaymentMethod: "test payment method",
+		TaxPrice:      1000,
+		ShippingPrice: 2000,
+		TotalPrice:    12999,
+		Items:         ois,
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
+					AddRow(1, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+
+				mock.ExpectQuery("SELECT * FROM orders ORDER BY created_at DESC, id DESC").WillReturnRows(orows)
+
+				oirows := sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "order_id"}).
+					AddRow(1, ois[0].Name, ois[0].Quantity, ois[0].Image, ois[0].Price, ois[0].ProductID, 1).
+					AddRow(2, ois[1].Name, ois[1].Quantity, ois[1].Image, ois[1].Price, ois[1].ProductID, 1)
+
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY order_id, id").WithArgs(1).WillReturnRows(oirows)
+
+				mo, _, err := st.ListOrders(context.Background(), &OrderFilter{})
+				require.NoError(t, err)
+				require.Len(t, mo, 1)
+				require.Len(t, mo[0].Items, 2)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed querying orders",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM orders ORDER BY created_at DESC, id DESC").WillReturnError(fmt.Errorf("error querying orders"))
+
+				_, _, err := st.ListOrders(context.Background(), &OrderFilter{})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed querying order items",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
+					AddRow(1, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+
+				mock.ExpectQuery("SELECT * FROM orders ORDER BY created_at DESC, id DESC").WillReturnRows(orows)
+
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY order_id, id").WithArgs(1).WillReturnError(fmt.Errorf("error querying order items"))
+
+				_, _, err := st.ListOrders(context.Background(), &OrderFilter{})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+// BenchmarkListOrders checks that listing orders costs the same number of
+// queries whatever the number of orders.
+func BenchmarkListOrders(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("orders=%d", n), func(b *testing.B) {
+			var queries int
+			matcher := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+				queries++
+				return sqlmock.QueryMatcherEqual.Match(expectedSQL, actualSQL)
+			})
+
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+			if err != nil {
+				b.Fatalf("error creating sqlmock: %v", err)
+			}
+			defer mockDB.Close()
+			st := NewMySQLStorer(sqlx.NewDb(mockDB, "sqlmock"))
+
+			ids := make([]driver.Value, n)
+			for i := range ids {
+				ids[i] = int64(i + 1)
+			}
+			itemsQuery := "SELECT * FROM order_items WHERE order_id IN (?" + strings.Repeat(", ?", n-1) + ") ORDER BY order_id, id"
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				orows := sqlmock.NewRows([]string{"id"})
+				oirows := sqlmock.NewRows([]string{"id", "order_id"})
+				for _, id := range ids {
+					orows.AddRow(id)
+					oirows.AddRow(id, id)
+				}
+				mock.ExpectQuery("SELECT * FROM orders ORDER BY created_at DESC, id DESC").WillReturnRows(orows)
+				mock.ExpectQuery(itemsQuery).WithArgs(ids...).WillReturnRows(oirows)
+				b.StartTimer()
+
+				orders, _, err := st.ListOrders(context.Background(), &OrderFilter{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(orders) != n {
+					b.Fatalf("got %d orders, want %d", len(orders), n)
+				}
+			}
+			b.StopTimer()
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				b.Fatal(err)
+			}
+			if perOp := queries / b.N; perOp != 2 {
+				b.Fatalf("got %d queries per call, want 2", perOp)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestListUserOrders(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	ocols := []string{"id", "user_id", "status", "created_at"}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "first page",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				orows := sqlmock.NewRows(ocols).
+					AddRow(3, 1, Pending, now).
+					AddRow(2, 1, Pending, now).
+					AddRow(1, 1, Pending, now.Add(-time.Hour))
+				mock.ExpectQuery("SELECT * FROM orders WHERE user_id=? ORDER BY created_at DESC, id DESC LIMIT ?").
+					WithArgs(1, 3).WillReturnRows(orows)
+
+				oirows := sqlmock.NewRows([]string{"id", "order_id"}).AddRow(2, 2).AddRow(3, 3)
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?, ?) ORDER BY order_id, id").WithArgs(3, 2).WillReturnRows(oirows)
+
+				orders, next, err := st.ListOrders(context.Background(), &OrderFilter{UserID: 1, PageSize: 2})
+				require.NoError(t, err)
+				require.Len(t, orders, 2)
+				require.Equal(t, int64(3), orders[0].ID)
+				require.Len(t, orders[1].Items, 1)
+
+				k, err := orderKeyset("")
+				require.NoError(t, err)
+				cur, err := k.decode(next)
+				require.NoError(t, err)
+				require.Equal(t, int64(2), cur.ID)
+				require.True(t, now.Equal(cur.value.(time.Time)))
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "filtered last page",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				status := Shipped
+				after := now.Add(-24 * time.Hour)
+				k, err := orderKeyset("")
+				require.NoError(t, err)
+				token := k.encode(&Order{ID: 2, CreatedAt: now})
+
+				orows := sqlmock.NewRows(ocols).AddRow(1, 1, Shipped, now.Add(-time.Hour))
+				mock.ExpectQuery("SELECT * FROM orders WHERE user_id=? AND status=? AND created_at>=? AND created_at<? AND (created_at<? OR (created_at=? AND id<?)) ORDER BY created_at DESC, id DESC LIMIT ?").
+					WithArgs(1, status, after, now, sqlmock.AnyArg(), sqlmock.AnyArg(), 2, 3).WillReturnRows(orows)
+				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY order_id, id").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				orders, next, err := st.ListOrders(context.Background(), &OrderFilter{
+					UserID:        1,
+					Status:        &status,
+					CreatedAfter:  after,
+					CreatedBefore: now,
+					PageSize:      2,
+					PageToken:     token,
+				})
+				require.NoError(t, err)
+				require.Len(t, orders, 1)
+				require.Empty(t, next)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid page token",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				_, _, err := st.ListOrders(context.Background(), &OrderFilter{UserID: 1, PageSize: 2, PageToken: "not a token"})
+				require.ErrorIs(t, err, ErrInvalidPageToken)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed querying orders",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM orders WHERE user_id=? ORDER BY created_at DESC, id DESC LIMIT ?").
+					WithArgs(1, 3).WillReturnError(fmt.Errorf("error querying orders"))
+
+				_, _, err := st.ListOrders(context.Background(), &OrderFilter{UserID: 1, PageSize: 2})
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	pending := Pending
+	processing := Processing
+	paid := Paid
+	delivered := Delivered
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Processing, sqlmock.AnyArg(), 1, &pending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, &pending, Processing, 2).
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+
+				c, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &pending, ToStatus: Processing, ChangedBy: 2})
+				require.NoError(t, err)
+				require.Equal(t, int64(7), c.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "cancel restores stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Cancelled, sqlmock.AnyArg(), 1, &processing).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(restoreVariantStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, &processing, Cancelled, 2).
+					WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectCommit()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &processing, ToStatus: Cancelled, ChangedBy: 2})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund of a paid order restores stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Refunded, sqlmock.AnyArg(), 1, &paid).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(restoreVariantStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, &paid, Refunded, 2).
+					WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectCommit()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &paid, ToStatus: Refunded, ChangedBy: 2})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "refund of a delivered order keeps stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Refunded, sqlmock.AnyArg(), 1, &delivered).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, &delivered, Refunded, 2).
+					WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectCommit()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &delivered, ToStatus: Refunded, ChangedBy: 2})
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "pay with invoice",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Paid, sqlmock.AnyArg(), 1, &pending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE invoice_sequence SET last_number=last_number+1 WHERE id=1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT last_number FROM invoice_sequence WHERE id=1").WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(42))
+				mock.ExpectExec(insertInvoiceQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("INSERT INTO order_status_history (order_id, from_status, to_status, changed_by) VALUES (?, ?, ?, ?)").
+					WithArgs(1, &pending, Paid, 2).
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectCommit()
+
+				c, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &pending, ToStatus: Paid, ChangedBy: 2, Invoice: &Invoice{OrderID: 1, UserID: 2}})
+				require.NoError(t, err)
+				require.Equal(t, int64(3), c.Invoice.ID)
+				require.Equal(t, int64(42), c.Invoice.Number)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invoice failure keeps order unpaid",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Paid, sqlmock.AnyArg(), 1, &pending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE invoice_sequence SET last_number=last_number+1 WHERE id=1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT last_number FROM invoice_sequence WHERE id=1").WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(42))
+				mock.ExpectExec(insertInvoiceQuery).WillReturnError(sqlmock.ErrCancelled)
+				mock.ExpectRollback()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &pending, ToStatus: Paid, ChangedBy: 2, Invoice: &Invoice{OrderID: 1, UserID: 2}})
+				require.ErrorContains(t, err, "error inserting invoice")
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "status changed concurrently",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(Processing, sqlmock.AnyArg(), 1, &pending).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				_, err := st.UpdateOrderStatus(context.Background(), &OrderStatusChange{OrderID: 1, FromStatus: &pending, ToStatus: Processing, ChangedBy: 2})
+				require.ErrorIs(t, err, ErrOrderStatusConflict)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
 		},
 	}
 
-	o := &Order{
-		PaymentMethod: "test payment method",
-		TaxPrice:      10.0,
-		ShippingPrice: 20.0,
-		TotalPrice:    129.99,
-		Items:         ois,
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
 	}
+}
+
+func TestCreatePayment(t *testing.T) {
+	withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+		st := NewMySQLStorer(db)
+		mock.ExpectExec(`INSERT INTO payments (order_id, provider, provider_ref, amount, currency, status, decline_reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`).
+			WithArgs(1, "fake", "fake_pay_1", "21.60", "EUR", PaymentAuthorized, "").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		p, err := st.CreatePayment(context.Background(), &Payment{
+			OrderID:     1,
+			Provider:    "fake",
+			ProviderRef: "fake_pay_1",
+			Amount:      2160,
+			Currency:    "EUR",
+			Status:      PaymentAuthorized,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), p.ID)
+
+		err = mock.ExpectationsWereMet()
+		require.NoError(t, err)
+	})
+}
 
+func TestUpdatePaymentStatus(t *testing.T) {
 	tcs := []struct {
 		name string
 		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
@@ -568,49 +2094,108 @@ func TestListOrders(t *testing.T) {
 		{
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
-					AddRow(1, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
-
-				mock.ExpectQuery("SELECT * FROM orders").WillReturnRows(orows)
-
-				oirows := sqlmock.NewRows([]string{"id", "name", "quantity", "image", "price", "product_id", "order_id"}).
-					AddRow(1, ois[0].Name, ois[0].Quantity, ois[0].Image, ois[0].Price, ois[0].ProductID, 1).
-					AddRow(2, ois[1].Name, ois[1].Quantity, ois[1].Image, ois[1].Price, ois[1].ProductID, 1)
-
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnRows(oirows)
+				mock.ExpectExec("UPDATE payments SET status=?, decline_reason=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(PaymentCaptured, "", sqlmock.AnyArg(), 3, PaymentAuthorized).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mo, err := st.ListOrders(context.Background())
+				p, err := st.UpdatePaymentStatus(context.Background(), &Payment{ID: 3, Status: PaymentCaptured}, PaymentAuthorized)
 				require.NoError(t, err)
-				require.Len(t, mo, 1)
+				require.NotNil(t, p.UpdatedAt)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
 		{
-			name: "failed querying orders",
+			name: "status changed concurrently",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT * FROM orders").WillReturnError(fmt.Errorf("error querying orders"))
+				mock.ExpectExec("UPDATE payments SET status=?, decline_reason=?, updated_at=? WHERE id=? AND status=?").
+					WithArgs(PaymentDeclined, "card declined", sqlmock.AnyArg(), 3, PaymentActionRequired).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 
-				_, err := st.ListOrders(context.Background())
-				require.Error(t, err)
+				_, err := st.UpdatePaymentStatus(context.Background(), &Payment{ID: 3, Status: PaymentDeclined, DeclineReason: "card declined"}, PaymentActionRequired)
+				require.ErrorIs(t, err, ErrPaymentStatusConflict)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
 		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+const insertInvoiceQuery = `INSERT INTO invoices (number, order_id, user_id, seller_name, seller_address, seller_tax_id, bill_name, bill_email,
+	ship_name, ship_line1, ship_line2, ship_city, ship_region, ship_postal_code, ship_country, ship_phone,
+	currency, subtotal, discount_price, tax_price, tax_inclusive, shipping_price, shipping_method, total_price, issued_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+func TestCreateInvoice(t *testing.T) {
+	inv := func() *Invoice {
+		return &Invoice{
+			OrderID:       7,
+			UserID:        2,
+			SellerName:    "Conduit",
+			BillName:      "Ada",
+			BillEmail:     "ada@example.com",
+			OrderAddress:  OrderAddress{ShipName: "Ada", ShipLine1: "1 Main St", ShipCity: "Paris", ShipCountry: "FR"},
+			Currency:      "EUR",
+			Subtotal:      2000,
+			TaxPrice:      400,
+			ShippingPrice: 500,
+			TotalPrice:    2900,
+			Lines:         []InvoiceLine{{ProductID: 1, Name: "Book", Quantity: 2, Price: 1000, TaxRate: 2000, TaxPrice: 400}},
+		}
+	}
+	expectNumber := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE invoice_sequence SET last_number=last_number+1 WHERE id=1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT last_number FROM invoice_sequence WHERE id=1").WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(42))
+	}
+	invoiceArgs := []driver.Value{int64(42), 7, 2, "Conduit", "", "", "Ada", "ada@example.com",
+		"Ada", "1 Main St", "", "Paris", "", "", "FR", "",
+		"EUR", "20.00", "0.00", "4.00", false, "5.00", "", "29.00", sqlmock.AnyArg()}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
 		{
-			name: "failed querying order items",
+			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
-				orows := sqlmock.NewRows([]string{"id", "payment_method", "tax_price", "shipping_price", "total_price", "created_at", "updated_at"}).
-					AddRow(1, o.PaymentMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.CreatedAt, o.UpdatedAt)
+				expectNumber(mock)
+				mock.ExpectExec(insertInvoiceQuery).WithArgs(invoiceArgs...).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(`INSERT INTO invoice_lines (invoice_id, product_id, name, quantity, price, tax_rate, tax_price)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`).
+					WithArgs(3, 1, "Book", 2, "10.00", 2000, "4.00").
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
 
-				mock.ExpectQuery("SELECT * FROM orders").WillReturnRows(orows)
+				got, err := st.CreateInvoice(context.Background(), inv())
+				require.NoError(t, err)
+				require.Equal(t, int64(3), got.ID)
+				require.Equal(t, int64(42), got.Number)
+				require.Equal(t, int64(5), got.Lines[0].ID)
+				require.False(t, got.IssuedAt.IsZero())
 
-				mock.ExpectQuery("SELECT * FROM order_items WHERE order_id=?").WithArgs(1).WillReturnError(fmt.Errorf("error querying order items"))
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "order already invoiced",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				expectNumber(mock)
+				mock.ExpectExec(insertInvoiceQuery).WithArgs(invoiceArgs...).WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry})
+				mock.ExpectRollback()
 
-				_, err := st.ListOrders(context.Background())
-				require.Error(t, err)
+				_, err := st.CreateInvoice(context.Background(), inv())
+				require.ErrorIs(t, err, ErrInvoiceExists)
 
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
@@ -626,6 +2211,33 @@ func TestListOrders(t *testing.T) {
 	}
 }
 
+func TestGetOrderInvoice(t *testing.T) {
+	withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+		st := NewMySQLStorer(db)
+		mock.ExpectQuery("SELECT * FROM invoices WHERE order_id=?").WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "number", "order_id", "total_price"}).AddRow(3, 42, 7, "29.00"))
+		mock.ExpectQuery("SELECT * FROM invoice_lines WHERE invoice_id=? ORDER BY id").WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_id", "name", "quantity", "price"}).AddRow(5, 3, "Book", 2, "10.00"))
+
+		inv, err := st.GetOrderInvoice(context.Background(), 7)
+		require.NoError(t, err)
+		require.Equal(t, int64(42), inv.Number)
+		require.Equal(t, money.Amount(2900), inv.TotalPrice)
+		require.Equal(t, []InvoiceLine{{ID: 5, InvoiceID: 3, Name: "Book", Quantity: 2, Price: 1000}}, inv.Lines)
+
+		err = mock.ExpectationsWereMet()
+		require.NoError(t, err)
+	})
+}
+
+const restoreStockQuery = `UPDATE products p JOIN (
+		SELECT product_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? GROUP BY product_id
+	) oi ON oi.product_id=p.id SET p.count_in_stock=p.count_in_stock+oi.quantity`
+
+const restoreVariantStockQuery = `UPDATE product_variants v JOIN (
+		SELECT variant_id, SUM(quantity) AS quantity FROM order_items WHERE order_id=? AND variant_id IS NOT NULL GROUP BY variant_id
+	) oi ON oi.variant_id=v.id SET v.count_in_stock=v.count_in_stock+oi.quantity`
+
 func TestDeleteOrder(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -635,6 +2247,9 @@ func TestDeleteOrder(t *testing.T) {
 			name: "success",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(Pending))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(restoreVariantStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -650,6 +2265,9 @@ func TestDeleteOrder(t *testing.T) {
 			name: "failed deleting order item",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(Pending))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(restoreVariantStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order item"))
 				mock.ExpectRollback()
 
@@ -664,6 +2282,9 @@ func TestDeleteOrder(t *testing.T) {
 			name: "failed deleting order",
 			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(Pending))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(restoreVariantStockQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnError(fmt.Errorf("error deleting order"))
 				mock.ExpectRollback()
@@ -671,6 +2292,192 @@ func TestDeleteOrder(t *testing.T) {
 				err := st.DeleteOrder(context.Background(), 1)
 				require.Error(t, err)
 
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "shipped order keeps stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(Shipped))
+				mock.ExpectExec("DELETE FROM order_items WHERE order_id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM orders WHERE id=?").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed restoring stock",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM orders WHERE id=? FOR UPDATE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(Pending))
+				mock.ExpectExec(restoreStockQuery).WithArgs(1).WillReturnError(fmt.Errorf("error restoring stock"))
+				mock.ExpectRollback()
+
+				err := st.DeleteOrder(context.Background(), 1)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+			st := NewMySQLStorer(db)
+			tc.test(t, st, mock)
+		})
+	}
+}
+
+// TestMySQLStorerConcurrentOrdersDoNotOversell runs against the database
+// brought up by dev/up, e.g. MYSQL_TEST_ADDR=127.0.0.1:3306 go test ./grpc/storer.
+func TestCreateAddress(t *testing.T) {
+	const (
+		insertQuery = `INSERT INTO user_addresses (user_id, name, line1, line2, city, region, postal_code, country, phone, is_default)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		clearQuery = "UPDATE user_addresses SET is_default=false WHERE user_id=? AND id<>? AND is_default"
+	)
+	newAddress := func(isDefault bool) *Address {
+		return &Address{UserID: 1, Name: "Test User", Line1: "1 Test Street", City: "Testville", Country: "FR", IsDefault: isDefault}
+	}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				a := newAddress(false)
+				mock.ExpectBegin()
+				mock.ExpectExec(insertQuery).
+					WithArgs(a.UserID, a.Name, a.Line1, "", a.City, "", "", a.Country, "", false).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+
+				created, err := st.CreateAddress(context.Background(), a)
+				require.NoError(t, err)
+				require.Equal(t, int64(2), created.ID)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "default address",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				a := newAddress(true)
+				mock.ExpectBegin()
+				mock.ExpectExec(insertQuery).
+					WithArgs(a.UserID, a.Name, a.Line1, "", a.City, "", "", a.Country, "", true).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(clearQuery).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				_, err := st.CreateAddress(context.Background(), a)
+				require.NoError(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failed clearing default address",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				a := newAddress(true)
+				mock.ExpectBegin()
+				mock.ExpectExec(insertQuery).
+					WithArgs(a.UserID, a.Name, a.Line1, "", a.City, "", "", a.Country, "", true).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(clearQuery).WithArgs(1, 2).WillReturnError(fmt.Errorf("error clearing default address"))
+				mock.ExpectRollback()
+
+				_, err := st.CreateAddress(context.Background(), a)
+				require.Error(t, err)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			withTestDB(t, func(db *sqlx.DB, mock sqlmock.Sqlmock) {
+				st := NewMySQLStorer(db)
+				tc.test(t, st, mock)
+			})
+		})
+	}
+}
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	const (
+		insertQuery   = "INSERT INTO idempotency_keys (user_id, idem_key, request_hash, expires_at) VALUES (?, ?, ?, ?)"
+		takeOverQuery = `UPDATE idempotency_keys SET request_hash=?, status_code=0, content_type='', response_body=NULL, created_at=?, expires_at=?
+		WHERE user_id=? AND idem_key=? AND expires_at<=?`
+	)
+	dupErr := &mysql.MySQLError{Number: mysqlErrDupEntry}
+
+	tcs := []struct {
+		name string
+		test func(*testing.T, *MySQLStorer, sqlmock.Sqlmock)
+	}{
+		{
+			name: "new key",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insertQuery).WithArgs(1, "key", "hash", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				_, reserved, err := st.ReserveIdempotencyKey(context.Background(), &IdempotencyKey{UserID: 1, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt})
+				require.NoError(t, err)
+				require.True(t, reserved)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "expired key",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insertQuery).WithArgs(1, "key", "hash", expiresAt).WillReturnError(dupErr)
+				mock.ExpectExec(takeOverQuery).
+					WithArgs("hash", sqlmock.AnyArg(), expiresAt, 1, "key", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				_, reserved, err := st.ReserveIdempotencyKey(context.Background(), &IdempotencyKey{UserID: 1, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt})
+				require.NoError(t, err)
+				require.True(t, reserved)
+
+				err = mock.ExpectationsWereMet()
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "key in use",
+			test: func(t *testing.T, st *MySQLStorer, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insertQuery).WithArgs(1, "key", "hash", expiresAt).WillReturnError(dupErr)
+				mock.ExpectExec(takeOverQuery).
+					WithArgs("hash", sqlmock.AnyArg(), expiresAt, 1, "key", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				rows := sqlmock.NewRows([]string{"user_id", "idem_key", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}).
+					AddRow(1, "key", "hash", 201, "application/json", []byte(`{"id":1}`), time.Now(), expiresAt)
+				mock.ExpectQuery("SELECT * FROM idempotency_keys WHERE user_id=? AND idem_key=?").WithArgs(1, "key").WillReturnRows(rows)
+
+				k, reserved, err := st.ReserveIdempotencyKey(context.Background(), &IdempotencyKey{UserID: 1, Key: "key", RequestHash: "hash", ExpiresAt: expiresAt})
+				require.NoError(t, err)
+				require.False(t, reserved)
+				require.Equal(t, int32(201), k.StatusCode)
+				require.Equal(t, `{"id":1}`, string(k.ResponseBody))
+
 				err = mock.ExpectationsWereMet()
 				require.NoError(t, err)
 			},
@@ -684,3 +2491,16 @@ func TestDeleteOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestMySQLStorerConcurrentOrdersDoNotOversell(t *testing.T) {
+	addr := os.Getenv("MYSQL_TEST_ADDR")
+	if addr == "" {
+		t.Skip("MYSQL_TEST_ADDR is not set")
+	}
+
+	db, err := sqlx.Open("mysql", fmt.Sprintf("root:password@tcp(%s)/ecomm?parseTime=true", addr))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testConcurrentOrdersDoNotOversell(t, NewMySQLStorer(db))
+}
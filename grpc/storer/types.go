@@ -1,50 +1,593 @@
 package storer
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/niloy104/Conduit/money"
+)
+
+var (
+	// ErrInsufficientStock is returned when an order asks for more items of a
+	// product than are left in stock.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrOrderStatusConflict is returned when the status of an order changed
+	// since it was read.
+	ErrOrderStatusConflict = errors.New("order status changed concurrently")
+	// ErrInvalidPageToken is returned when a page token was not issued by a
+	// previous list call.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrInvalidSort is returned when a list is sorted on an unknown field.
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrDuplicateReview is returned when a user reviews a product twice.
+	ErrDuplicateReview = errors.New("product already reviewed by user")
+	// ErrCartChanged is returned when a cart is checked out with other items
+	// than it holds.
+	ErrCartChanged = errors.New("cart changed concurrently")
+	// ErrDuplicateCoupon is returned when a coupon code is already taken.
+	ErrDuplicateCoupon = errors.New("coupon code already exists")
+	// ErrCouponExhausted is returned when an order redeems a coupon past one
+	// of its usage limits.
+	ErrCouponExhausted = errors.New("coupon usage limit reached")
+	// ErrPaymentStatusConflict is returned when the status of a payment
+	// changed since it was read.
+	ErrPaymentStatusConflict = errors.New("payment status changed concurrently")
+	// ErrInvoiceExists is returned when an invoice is issued for an order
+	// that already has one.
+	ErrInvoiceExists = errors.New("order already invoiced")
+	// ErrDuplicateCategory is returned when a category slug is already
+	// taken.
+	ErrDuplicateCategory = errors.New("category slug already exists")
+	// ErrCategoryInUse is returned when a category with subcategories,
+	// products or coupons is deleted.
+	ErrCategoryInUse = errors.New("category is in use")
+	// ErrDuplicateSKU is returned when the SKU of a product or of a variant is
+	// already taken.
+	ErrDuplicateSKU = errors.New("sku already exists")
+	// ErrVariantOrdered is returned when a variant that was ordered is
+	// deleted.
+	ErrVariantOrdered = errors.New("variant was ordered")
+	// ErrImagesMismatch is returned when the images of a product are
+	// reordered with other images than it has.
+	ErrImagesMismatch = errors.New("images do not match the gallery of the product")
+)
+
+// Product is an item of the catalog. The stock of a product with variants is
+// the total stock of its variants, which the storer keeps in CountInStock.
+// Image mirrors the URL of the first image of the gallery of a product.
+// Reads of a single product and of lists of products fill in Variants and
+// Images.
 type Product struct {
-	ID           int64      `db:"id"`
-	Name         string     `db:"name"`
-	Image        string     `db:"image"`
-	Category     string     `db:"category"`
-	Description  string     `db:"description"`
-	Rating       int64      `db:"rating"`
-	NumReviews   int64      `db:"num_reviews"`
-	Price        float32    `db:"price"`
-	CountInStock int64      `db:"count_in_stock"`
-	CreatedAt    time.Time  `db:"created_at"`
-	UpdatedAt    *time.Time `db:"updated_at"`
+	ID           int64        `db:"id"`
+	SKU          *string      `db:"sku"` // nil for products without one
+	Name         string       `db:"name"`
+	Image        string       `db:"image"`
+	CategoryID   *int64       `db:"category_id"`
+	TaxCategory  string       `db:"tax_category"` // empty for the standard rate
+	Description  string       `db:"description"`
+	Rating       int64        `db:"rating"`
+	NumReviews   int64        `db:"num_reviews"`
+	Price        money.Amount `db:"price"`
+	Currency     string       `db:"currency"`
+	CountInStock int64        `db:"count_in_stock"`
+	Weight       int64        `db:"weight"` // grams
+	Length       int64        `db:"length"` // millimetres, as Width and Height
+	Width        int64        `db:"width"`
+	Height       int64        `db:"height"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    *time.Time   `db:"updated_at"`
+	Variants     []Variant
+	Images       []ProductImage
+}
+
+// Variant is a version of a product that differs in its options, such as
+// size or color, with its own SKU and stock. Price overrides the price of the
+// product, in the currency of the product, unless nil.
+type Variant struct {
+	ID           int64          `db:"id"`
+	ProductID    int64          `db:"product_id"`
+	SKU          string         `db:"sku"`
+	Options      VariantOptions `db:"options"`
+	Price        *money.Amount  `db:"price"`
+	CountInStock int64          `db:"count_in_stock"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    *time.Time     `db:"updated_at"`
+}
+
+// VariantOptions maps option names to the values of a variant, e.g. size to
+// M. They are stored as a JSON object.
+type VariantOptions map[string]string
+
+// Scan reads a JSON column.
+func (vo *VariantOptions) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, vo)
+	case string:
+		return json.Unmarshal([]byte(v), vo)
+	case nil:
+		*vo = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into variant options", src)
+	}
+}
+
+// Value writes the options as a JSON object, with sorted keys.
+func (vo VariantOptions) Value() (driver.Value, error) {
+	if vo == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(vo))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// ProductImage is an image of the gallery of a product, ordered by Position.
+// The image and its thumbnail are stored in a blob store under ImageKey and
+// ThumbnailKey.
+type ProductImage struct {
+	ID           int64     `db:"id"`
+	ProductID    int64     `db:"product_id"`
+	Position     int64     `db:"position"`
+	ImageKey     string    `db:"image_key"`
+	URL          string    `db:"url"`
+	ThumbnailKey string    `db:"thumbnail_key"`
+	ThumbnailURL string    `db:"thumbnail_url"`
+	ContentType  string    `db:"content_type"`
+	Size         int64     `db:"size"` // bytes
+	Width        int64     `db:"width"`
+	Height       int64     `db:"height"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// Category groups products. Categories form a tree: ParentID is nil for
+// top-level categories. Slug identifies the category in URLs.
+type Category struct {
+	ID        int64      `db:"id"`
+	ParentID  *int64     `db:"parent_id"`
+	Name      string     `db:"name"`
+	Slug      string     `db:"slug"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// ProductFilter selects products. Sort is one of productSortFields, prefixed
+// with "-" for a descending order, and defaults to id. A PageSize of zero
+// lists every product. The price sort compares prices as stored, in the
+// currency of each product.
+type ProductFilter struct {
+	// CategoryIDs lists the categories products may belong to, every
+	// category if empty.
+	CategoryIDs []int64
+	// Prices lists the price range of every currency products may be priced
+	// in, every price if empty.
+	Prices    []PriceRange
+	InStock   bool
+	Sort      string
+	PageSize  int
+	PageToken string
+}
+
+// PriceRange bounds prices in Currency. Nil bounds are open.
+type PriceRange struct {
+	Currency string
+	Min      *money.Amount
+	Max      *money.Amount
+}
+
+// contains reports whether the range holds the price of p.
+func (r PriceRange) contains(p *Product) bool {
+	return p.Currency == r.Currency &&
+		(r.Min == nil || p.Price >= *r.Min) &&
+		(r.Max == nil || p.Price <= *r.Max)
+}
+
+var productSortFields = map[string]func(*Product) any{
+	"id":         func(p *Product) any { return p.ID },
+	"created_at": func(p *Product) any { return p.CreatedAt },
+	"name":       func(p *Product) any { return p.Name },
+	"price":      func(p *Product) any { return p.Price },
+	"rating":     func(p *Product) any { return p.Rating },
+}
+
+type ReviewStatus string
+
+const (
+	ReviewPublished ReviewStatus = "published"
+	ReviewHidden    ReviewStatus = "hidden"
+)
+
+// Review is a rating of a product by a customer who received it. Only
+// published reviews count towards the rating of the product.
+type Review struct {
+	ID        int64        `db:"id"`
+	ProductID int64        `db:"product_id"`
+	UserID    int64        `db:"user_id"`
+	Rating    int64        `db:"rating"`
+	Comment   string       `db:"comment"`
+	Status    ReviewStatus `db:"status"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt *time.Time   `db:"updated_at"`
+}
+
+// ReviewFilter selects reviews, newest first unless Sort says otherwise. A
+// zero ProductID or nil Status matches every review.
+type ReviewFilter struct {
+	ProductID int64
+	Status    *ReviewStatus
+	Sort      string
+	PageSize  int
+	PageToken string
+}
+
+var reviewSortFields = map[string]func(*Review) any{
+	"id":         func(r *Review) any { return r.ID },
+	"created_at": func(r *Review) any { return r.CreatedAt },
+	"rating":     func(r *Review) any { return r.Rating },
 }
 
 type OrderStatus string
 
 const (
-	Pending   OrderStatus = "pending"
-	Shipped   OrderStatus = "shipped"
-	Delivered OrderStatus = "delivered"
+	Pending    OrderStatus = "pending"
+	Paid       OrderStatus = "paid"
+	Processing OrderStatus = "processing"
+	Shipped    OrderStatus = "shipped"
+	Delivered  OrderStatus = "delivered"
+	Cancelled  OrderStatus = "cancelled"
+	Refunded   OrderStatus = "refunded"
+	Returned   OrderStatus = "returned"
 )
 
+// holdsStock reports whether the items of an order in this status are still
+// in the warehouse, so that removing the order puts them back in stock.
+func (os OrderStatus) holdsStock() bool {
+	switch os {
+	case Pending, Paid, Processing:
+		return true
+	default:
+		return false
+	}
+}
+
+// Order is a placed order. Orders redeeming a coupon keep its code and the
+// discount it gave, even once the coupon is deleted and CouponID is nil.
+// Amounts are in Currency, converted from the store currency at
+// ExchangeRate when the order was placed.
 type Order struct {
-	ID            int64       `db:"id"`
-	PaymentMethod string      `db:"payment_method"`
-	TaxPrice      float32     `db:"tax_price"`
-	ShippingPrice float32     `db:"shipping_price"`
-	TotalPrice    float32     `db:"total_price"`
-	UserID        int64       `db:"user_id"`
-	Status        OrderStatus `db:"status"`
-	CreatedAt     time.Time   `db:"created_at"`
-	UpdatedAt     *time.Time  `db:"updated_at"`
-	Items         []OrderItem
+	ID             int64        `db:"id"`
+	PaymentMethod  string       `db:"payment_method"`
+	CouponID       *int64       `db:"coupon_id"`
+	CouponCode     *string      `db:"coupon_code"`
+	DiscountPrice  money.Amount `db:"discount_price"`
+	TaxPrice       money.Amount `db:"tax_price"`
+	TaxInclusive   bool         `db:"tax_inclusive"` // item prices include TaxPrice
+	ShippingPrice  money.Amount `db:"shipping_price"`
+	ShippingMethod string       `db:"shipping_method"` // empty for flat shipping prices
+	TotalPrice     money.Amount `db:"total_price"`
+	Currency       string       `db:"currency"`
+	ExchangeRate   money.Rate   `db:"exchange_rate"`
+	OrderAddress
+	UserID    int64       `db:"user_id"`
+	Status    OrderStatus `db:"status"`
+	CreatedAt time.Time   `db:"created_at"`
+	UpdatedAt *time.Time  `db:"updated_at"`
+	Items     []OrderItem
+}
+
+// OrderAddress is the address an order ships to, copied from the address book
+// of its user when the order is placed, so that later edits of the address
+// book leave the order untouched. It is zero for orders placed without one.
+type OrderAddress struct {
+	ShipName       string `db:"ship_name"`
+	ShipLine1      string `db:"ship_line1"`
+	ShipLine2      string `db:"ship_line2"`
+	ShipCity       string `db:"ship_city"`
+	ShipRegion     string `db:"ship_region"`
+	ShipPostalCode string `db:"ship_postal_code"`
+	ShipCountry    string `db:"ship_country"`
+	ShipPhone      string `db:"ship_phone"`
+}
+
+// IsZero reports whether the order has no shipping address.
+func (oa OrderAddress) IsZero() bool {
+	return oa == OrderAddress{}
+}
+
+// OrderFilter selects orders, newest first unless Sort says otherwise. A zero
+// UserID, nil Status or zero time matches every order.
+type OrderFilter struct {
+	UserID        int64
+	Status        *OrderStatus
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Sort          string
+	PageSize      int
+	PageToken     string
+}
+
+var orderSortFields = map[string]func(*Order) any{
+	"id":          func(o *Order) any { return o.ID },
+	"created_at":  func(o *Order) any { return o.CreatedAt },
+	"total_price": func(o *Order) any { return o.TotalPrice },
 }
 
 type OrderItem struct {
-	ID        int64   `db:"id"`
-	Name      string  `db:"name"`
-	Quantity  int64   `db:"quantity"`
-	Image     string  `db:"image"`
-	Price     float32 `db:"price"`
-	ProductID int64   `db:"product_id"`
-	OrderID   int64   `db:"order_id"`
+	ID        int64        `db:"id"`
+	Name      string       `db:"name"`
+	Quantity  int64        `db:"quantity"`
+	Image     string       `db:"image"`
+	Price     money.Amount `db:"price"`
+	TaxRate   int64        `db:"tax_rate"` // basis points
+	TaxPrice  money.Amount `db:"tax_price"`
+	ProductID int64        `db:"product_id"`
+	VariantID *int64       `db:"variant_id"`
+	OrderID   int64        `db:"order_id"`
+}
+
+// CartOwner identifies the cart of a user, or the cart of a guest by its
+// token when UserID is zero.
+type CartOwner struct {
+	UserID int64
+	Token  string
+}
+
+// key drops the token of user carts, so that equal owners have equal keys.
+func (co CartOwner) key() CartOwner {
+	if co.UserID != 0 {
+		return CartOwner{UserID: co.UserID}
+	}
+	return CartOwner{Token: co.Token}
+}
+
+type CouponKind string
+
+const (
+	// PercentageCoupon takes Value percent off the eligible items, read as
+	// basis points: a Value of 10.00 is 1000, for 10%.
+	PercentageCoupon CouponKind = "percentage"
+	// FixedCoupon takes Value off the eligible items.
+	FixedCoupon CouponKind = "fixed"
+)
+
+// Coupon is a discount on orders. Zero limits, a nil CategoryID and a nil
+// ProductID do not restrict the coupon; otherwise only the items of the
+// category, or of its subcategories, or of the product are eligible for the
+// discount.
+type Coupon struct {
+	ID             int64        `db:"id"`
+	Code           string       `db:"code"`
+	Kind           CouponKind   `db:"kind"`
+	Value          money.Amount `db:"value"`
+	MinOrderValue  money.Amount `db:"min_order_value"`
+	ExpiresAt      *time.Time   `db:"expires_at"`
+	MaxUses        int64        `db:"max_uses"`
+	MaxUsesPerUser int64        `db:"max_uses_per_user"`
+	CategoryID     *int64       `db:"category_id"`
+	ProductID      *int64       `db:"product_id"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      *time.Time   `db:"updated_at"`
+}
+
+// Exhausted reports whether a coupon redeemed uses times, userUses of them
+// by the user, reached one of its usage limits.
+func (c *Coupon) Exhausted(uses, userUses int64) bool {
+	return (c.MaxUses > 0 && uses >= c.MaxUses) || (c.MaxUsesPerUser > 0 && userUses >= c.MaxUsesPerUser)
+}
+
+// CouponFilter selects coupons, by ID unless Sort says otherwise.
+type CouponFilter struct {
+	Sort      string
+	PageSize  int
+	PageToken string
+}
+
+var couponSortFields = map[string]func(*Coupon) any{
+	"id":         func(c *Coupon) any { return c.ID },
+	"created_at": func(c *Coupon) any { return c.CreatedAt },
+	"code":       func(c *Coupon) any { return c.Code },
+}
+
+// Cart is the shopping cart of a user or a guest. An owner without a cart has
+// an empty one with a zero ID.
+type Cart struct {
+	ID        int64      `db:"id"`
+	UserID    *int64     `db:"user_id"`
+	Token     *string    `db:"token"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+	Items     []CartItem
+}
+
+// CartItem is a product in a cart, or a variant of it unless VariantID is
+// nil. Name, Image, Price, Currency, CountInStock and Options are read from
+// the product and the variant, so they are always current.
+type CartItem struct {
+	ID           int64          `db:"id"`
+	CartID       int64          `db:"cart_id"`
+	ProductID    int64          `db:"product_id"`
+	VariantID    *int64         `db:"variant_id"`
+	Quantity     int64          `db:"quantity"`
+	Name         string         `db:"name"`
+	Image        string         `db:"image"`
+	Price        money.Amount   `db:"price"`
+	Currency     string         `db:"currency"`
+	CountInStock int64          `db:"count_in_stock"`
+	Options      VariantOptions `db:"options"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    *time.Time     `db:"updated_at"`
+}
+
+// cartKey identifies the items of carts and orders: a product, and its
+// variant unless variantID is zero.
+type cartKey struct {
+	productID int64
+	variantID int64
+}
+
+func itemKey(productID int64, variantID *int64) cartKey {
+	k := cartKey{productID: productID}
+	if variantID != nil {
+		k.variantID = *variantID
+	}
+	return k
+}
+
+// mergedQuantity is the quantity of a product in a cart holding have items of
+// it once add more are merged in. It is limited to the stock, unless the cart
+// already held more.
+func mergedQuantity(have, add, stock int64) int64 {
+	return max(have, min(have+add, stock))
+}
+
+// sameItems reports whether the order has exactly the products, variants and
+// quantities of the cart.
+func sameItems(cart []CartItem, items []OrderItem) bool {
+	quantities := make(map[cartKey]int64)
+	for _, oi := range items {
+		quantities[itemKey(oi.ProductID, oi.VariantID)] += oi.Quantity
+	}
+	if len(quantities) != len(cart) {
+		return false
+	}
+	for _, ci := range cart {
+		if quantities[itemKey(ci.ProductID, ci.VariantID)] != ci.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// OrderStatusChange is an entry of the status history of an order. FromStatus
+// is nil for the entry recorded when the order is placed. Invoice, when set,
+// is issued together with the change, so that orders are never paid without
+// their invoice.
+type OrderStatusChange struct {
+	ID         int64        `db:"id"`
+	OrderID    int64        `db:"order_id"`
+	FromStatus *OrderStatus `db:"from_status"`
+	ToStatus   OrderStatus  `db:"to_status"`
+	ChangedBy  int64        `db:"changed_by"`
+	CreatedAt  time.Time    `db:"created_at"`
+	Invoice    *Invoice     `db:"-"`
+}
+
+// restocks reports whether the change puts the items of the order back in
+// stock: cancelling or returning an order does, and so does refunding one
+// whose items never left the warehouse.
+func (c *OrderStatusChange) restocks() bool {
+	switch c.ToStatus {
+	case Cancelled, Returned:
+		return true
+	case Refunded:
+		return c.FromStatus != nil && c.FromStatus.holdsStock()
+	default:
+		return false
+	}
+}
+
+// PaymentStatus mirrors payment.Status, the state of a payment at its
+// provider.
+type PaymentStatus string
+
+const (
+	PaymentActionRequired PaymentStatus = "action_required"
+	PaymentAuthorized     PaymentStatus = "authorized"
+	PaymentCaptured       PaymentStatus = "captured"
+	PaymentDeclined       PaymentStatus = "declined"
+	PaymentRefunded       PaymentStatus = "refunded"
+)
+
+// Payment is an attempt to pay an order through a payment provider, which
+// knows it by ProviderRef. Amount is in Currency, the currency of the order.
+type Payment struct {
+	ID            int64         `db:"id"`
+	OrderID       int64         `db:"order_id"`
+	Provider      string        `db:"provider"`
+	ProviderRef   string        `db:"provider_ref"`
+	Amount        money.Amount  `db:"amount"`
+	Currency      string        `db:"currency"`
+	Status        PaymentStatus `db:"status"`
+	DeclineReason string        `db:"decline_reason"`
+	CreatedAt     time.Time     `db:"created_at"`
+	UpdatedAt     *time.Time    `db:"updated_at"`
+}
+
+// Invoice is the invoice issued for a paid order. Numbers follow each other
+// without gaps in the order invoices are issued. Invoices copy the seller,
+// the customer and the lines and charges of their order, and never change
+// once issued. Amounts are in Currency.
+type Invoice struct {
+	ID            int64  `db:"id"`
+	Number        int64  `db:"number"`
+	OrderID       int64  `db:"order_id"`
+	UserID        int64  `db:"user_id"`
+	SellerName    string `db:"seller_name"`
+	SellerAddress string `db:"seller_address"`
+	SellerTaxID   string `db:"seller_tax_id"`
+	BillName      string `db:"bill_name"`
+	BillEmail     string `db:"bill_email"`
+	OrderAddress
+	Currency       string       `db:"currency"`
+	Subtotal       money.Amount `db:"subtotal"`
+	DiscountPrice  money.Amount `db:"discount_price"`
+	TaxPrice       money.Amount `db:"tax_price"`
+	TaxInclusive   bool         `db:"tax_inclusive"`
+	ShippingPrice  money.Amount `db:"shipping_price"`
+	ShippingMethod string       `db:"shipping_method"`
+	TotalPrice     money.Amount `db:"total_price"`
+	IssuedAt       time.Time    `db:"issued_at"`
+	Lines          []InvoiceLine
+}
+
+// InvoiceLine is an item of an invoice, at its unit Price.
+type InvoiceLine struct {
+	ID        int64        `db:"id"`
+	InvoiceID int64        `db:"invoice_id"`
+	ProductID int64        `db:"product_id"`
+	Name      string       `db:"name"`
+	Quantity  int64        `db:"quantity"`
+	Price     money.Amount `db:"price"`
+	TaxRate   int64        `db:"tax_rate"` // basis points
+	TaxPrice  money.Amount `db:"tax_price"`
+}
+
+// Address is a postal address in the address book of a user. Country is an
+// ISO 3166-1 alpha-2 code. At most one address of a user is the default one.
+type Address struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	Name       string     `db:"name"`
+	Line1      string     `db:"line1"`
+	Line2      string     `db:"line2"`
+	City       string     `db:"city"`
+	Region     string     `db:"region"`
+	PostalCode string     `db:"postal_code"`
+	Country    string     `db:"country"`
+	Phone      string     `db:"phone"`
+	IsDefault  bool       `db:"is_default"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
+
+// OrderAddress returns the copy of the address an order keeps.
+func (a *Address) OrderAddress() OrderAddress {
+	return OrderAddress{
+		ShipName:       a.Name,
+		ShipLine1:      a.Line1,
+		ShipLine2:      a.Line2,
+		ShipCity:       a.City,
+		ShipRegion:     a.Region,
+		ShipPostalCode: a.PostalCode,
+		ShipCountry:    a.Country,
+		ShipPhone:      a.Phone,
+	}
 }
 
 type User struct {
@@ -57,6 +600,23 @@ type User struct {
 	UpdatedAt *time.Time `db:"updated_at"`
 }
 
+// UserFilter selects users. A nil IsAdmin or zero time matches every user.
+type UserFilter struct {
+	IsAdmin       *bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Sort          string
+	PageSize      int
+	PageToken     string
+}
+
+var userSortFields = map[string]func(*User) any{
+	"id":         func(u *User) any { return u.ID },
+	"created_at": func(u *User) any { return u.CreatedAt },
+	"name":       func(u *User) any { return u.Name },
+	"email":      func(u *User) any { return u.Email },
+}
+
 type Session struct {
 	ID           string    `db:"id"`
 	UserEmail    string    `db:"user_email"`
@@ -66,6 +626,21 @@ type Session struct {
 	ExpiresAt    time.Time `db:"expires_at"`
 }
 
+// IdempotencyKey holds the response to the first request a user sent with an
+// idempotency key, replayed to the retries of the request until ExpiresAt.
+// RequestHash tells retries from other requests reusing the key. A zero
+// StatusCode marks a request still in progress.
+type IdempotencyKey struct {
+	UserID       int64     `db:"user_id"`
+	Key          string    `db:"idem_key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int32     `db:"status_code"`
+	ContentType  string    `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
 type NotificationEventState string
 
 const (
@@ -100,3 +675,14 @@ type NotificationEvent struct {
 	CreatedAt   time.Time   `db:"created_at"`
 	UpdatedAt   *time.Time  `db:"updated_at"`
 }
+
+// NotificationEventFilter pages through the notification queue, oldest
+// event first.
+type NotificationEventFilter struct {
+	PageSize  int
+	PageToken string
+}
+
+var notificationEventSortFields = map[string]func(*NotificationEvent) any{
+	"created_at": func(ev *NotificationEvent) any { return ev.CreatedAt },
+}
@@ -0,0 +1,166 @@
+// Package imaging checks the product images admins upload and scales them
+// down to thumbnails, with the decoders of the standard library only.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxSize is the largest image that can be uploaded, in bytes.
+	MaxSize = 5 << 20
+	// maxPixels bounds the decoded size of an image, which a small file
+	// can inflate to gigabytes.
+	maxPixels = 40_000_000
+	// ThumbnailSize is the side of the square thumbnails fit in, in pixels.
+	ThumbnailSize = 320
+)
+
+var (
+	// ErrUnsupportedType is returned for files that are not JPEG, PNG or
+	// GIF images.
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooLarge        = errors.New("image is too large")
+)
+
+// extensions maps the supported content types to their file extension.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Supported reports whether images of the content type can be uploaded.
+func Supported(contentType string) bool {
+	_, ok := extensions[contentType]
+	return ok
+}
+
+// Extension returns the file extension of a supported content type.
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Info describes an uploaded image.
+type Info struct {
+	// ContentType is sniffed from the data, whatever the uploader claims.
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Inspect checks that data is a supported image of at most MaxSize bytes and
+// maxPixels pixels, without decoding its pixels.
+func Inspect(data []byte) (*Info, error) {
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d", ErrTooLarge, len(data), MaxSize)
+	}
+	contentType := http.DetectContentType(data)
+	if !Supported(contentType) {
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedType, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	return &Info{ContentType: contentType, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnail scales an image down to fit in a square of size pixels, keeping
+// its aspect ratio; smaller images keep their size. Thumbnails of JPEG images
+// are JPEG, the others PNG to keep their transparency. It returns the
+// thumbnail and its content type.
+func Thumbnail(data []byte, size int) ([]byte, string, error) {
+	info, err := Inspect(data)
+	if err != nil {
+		return nil, "", err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding image: %w", err)
+	}
+
+	w, h := fit(info.Width, info.Height, size)
+	thumb := scale(src, w, h)
+
+	var buf bytes.Buffer
+	contentType := "image/png"
+	if info.ContentType == "image/jpeg" {
+		contentType = info.ContentType
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, thumb)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), contentType, nil
+}
+
+// fit returns the size of a w×h image scaled down to fit in a size×size
+// square.
+func fit(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+	return max(1, w*size/h), size
+}
+
+// scale resizes an image to w×h with a box filter: every pixel of the result
+// is the average of the source pixels it covers, which is sharp enough for
+// downscaling. Colors are averaged premultiplied, so that transparent pixels
+// do not darken their neighbours.
+func scale(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	sw, sh := b.Dx(), b.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+
+			var r, g, bl, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					bl += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8((r + n/2) / n)
+			d[1] = uint8((g + n/2) / n)
+			d[2] = uint8((bl + n/2) / n)
+			d[3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
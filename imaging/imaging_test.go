@@ -0,0 +1,103 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, format string, img image.Image) []byte {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func gradient(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestInspect(t *testing.T) {
+	tcs := []struct {
+		name     string
+		data     []byte
+		wantType string
+		wantErr  error
+	}{
+		{name: "png", data: encode(t, "png", gradient(40, 30)), wantType: "image/png"},
+		{name: "jpeg", data: encode(t, "jpeg", gradient(40, 30)), wantType: "image/jpeg"},
+		{name: "gif", data: encode(t, "gif", gradient(40, 30)), wantType: "image/gif"},
+		{name: "text", data: []byte("not an image"), wantErr: ErrUnsupportedType},
+		{name: "too large", data: make([]byte, MaxSize+1), wantErr: ErrTooLarge},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := Inspect(tc.data)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &Info{ContentType: tc.wantType, Width: 40, Height: 30}, info)
+		})
+	}
+
+	_, err := Inspect(append([]byte("\x89PNG\r\n\x1a\n"), "truncated"...))
+	require.Error(t, err, "a PNG signature is not enough")
+}
+
+func TestThumbnail(t *testing.T) {
+	tcs := []struct {
+		name       string
+		data       []byte
+		wantType   string
+		wantBounds image.Rectangle
+	}{
+		{name: "landscape", data: encode(t, "jpeg", gradient(1000, 500)), wantType: "image/jpeg", wantBounds: image.Rect(0, 0, 320, 160)},
+		{name: "portrait", data: encode(t, "png", gradient(300, 900)), wantType: "image/png", wantBounds: image.Rect(0, 0, 106, 320)},
+		{name: "gif", data: encode(t, "gif", gradient(640, 640)), wantType: "image/png", wantBounds: image.Rect(0, 0, 320, 320)},
+		{name: "small", data: encode(t, "png", gradient(100, 50)), wantType: "image/png", wantBounds: image.Rect(0, 0, 100, 50)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			thumb, contentType, err := Thumbnail(tc.data, ThumbnailSize)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantType, contentType)
+
+			img, _, err := image.Decode(bytes.NewReader(thumb))
+			require.NoError(t, err)
+			require.Equal(t, tc.wantBounds, img.Bounds())
+		})
+	}
+}
+
+func TestScaleTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{G: 255})
+
+	got := color.NRGBAModel.Convert(scale(img, 1, 1).At(0, 0)).(color.NRGBA)
+	require.Equal(t, color.NRGBA{R: 255, A: 128}, got, "transparent pixels do not tint their neighbours")
+}
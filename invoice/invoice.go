@@ -0,0 +1,80 @@
+// Package invoice holds the invoices the store issues for paid orders and
+// renders them as PDF files. Invoices are numbered without gaps in the order
+// they are issued and never change once issued: they copy the lines,
+// charges and addresses of their order.
+package invoice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// FormatNumber formats the sequential number of an invoice, e.g. INV-000042.
+func FormatNumber(n int64) string {
+	return fmt.Sprintf("INV-%06d", n)
+}
+
+// Seller identifies the store on its invoices.
+type Seller struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TaxID   string `json:"tax_id,omitempty"`
+}
+
+// Customer is who an invoice is addressed to. Address is where the order
+// ships, nil for orders placed without one.
+type Customer struct {
+	Name    string   `json:"name"`
+	Email   string   `json:"email"`
+	Address *Address `json:"address,omitempty"`
+}
+
+// Address is a postal address.
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// Line is an item of an invoice. TaxRate is in basis points and Amount is
+// UnitPrice times Quantity.
+type Line struct {
+	ProductID int64        `json:"product_id"`
+	Name      string       `json:"name"`
+	Quantity  int64        `json:"quantity"`
+	UnitPrice money.Amount `json:"unit_price"`
+	TaxRate   int64        `json:"tax_rate"`
+	Tax       money.Amount `json:"tax"`
+	Amount    money.Amount `json:"amount"`
+}
+
+// Invoice is an issued invoice. Amounts are in Currency. If TaxInclusive,
+// the line amounts include Tax, otherwise Tax is added to them.
+type Invoice struct {
+	Number         string       `json:"number"`
+	OrderID        int64        `json:"order_id"`
+	IssuedAt       time.Time    `json:"issued_at"`
+	Seller         Seller       `json:"seller"`
+	Customer       Customer     `json:"customer"`
+	Lines          []Line       `json:"lines"`
+	Currency       string       `json:"currency"`
+	Subtotal       money.Amount `json:"subtotal"`
+	Discount       money.Amount `json:"discount"`
+	Tax            money.Amount `json:"tax"`
+	TaxInclusive   bool         `json:"tax_inclusive"`
+	Shipping       money.Amount `json:"shipping"`
+	ShippingMethod string       `json:"shipping_method,omitempty"`
+	Total          money.Amount `json:"total"`
+}
+
+// Filename is the name invoice files are served under.
+func (inv *Invoice) Filename() string {
+	return inv.Number + ".pdf"
+}
@@ -0,0 +1,113 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testInvoice(lines int) *Invoice {
+	inv := &Invoice{
+		Number:   FormatNumber(42),
+		OrderID:  7,
+		IssuedAt: time.Date(2026, 5, 7, 10, 0, 0, 0, time.UTC),
+		Seller:   Seller{Name: "Conduit Ltd", Address: "1 Main St\nSpringfield", TaxID: "FR123"},
+		Customer: Customer{
+			Name:    "Ada (Lovelace)",
+			Email:   "ada@example.com",
+			Address: &Address{Name: "Ada", Line1: "2 Side St", City: "Paris", PostalCode: "75001", Country: "FR"},
+		},
+		Currency:       "EUR",
+		ShippingMethod: "standard",
+		Shipping:       500,
+	}
+	for i := range lines {
+		inv.Lines = append(inv.Lines, Line{
+			ProductID: int64(i + 1),
+			Name:      fmt.Sprintf("Product %d with a name far too long to fit in the description column", i+1),
+			Quantity:  2,
+			UnitPrice: 1000,
+			TaxRate:   2000,
+			Tax:       400,
+			Amount:    2000,
+		})
+		inv.Subtotal += 2000
+		inv.Tax += 400
+	}
+	inv.Total = inv.Subtotal + inv.Tax + inv.Shipping
+	return inv
+}
+
+// parsePDF checks the cross-reference table of a document and returns its
+// page count.
+func parsePDF(t *testing.T, b []byte) int {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(b, []byte("%%EOF\n")))
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(b)
+	require.NotNil(t, m)
+	xref, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(b[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(b[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		off, err := strconv.Atoi(string(e[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(b[off:], fmt.Appendf(nil, "%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	m = regexp.MustCompile(`/Count (\d+)`).FindSubmatch(b)
+	require.NotNil(t, m)
+	n, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.Equal(t, n, bytes.Count(b, []byte("/Type /Page /Parent")))
+	return n
+}
+
+func TestWritePDF(t *testing.T) {
+	tcs := []struct {
+		name  string
+		lines int
+		pages int
+	}{
+		{name: "single page", lines: 3, pages: 1},
+		{name: "many lines", lines: 120, pages: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := testInvoice(tc.lines).WritePDF(&b)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.pages, parsePDF(t, b.Bytes()))
+			out := b.String()
+			require.Contains(t, out, "(INV-000042)")
+			require.Contains(t, out, `(Ada \(Lovelace\))`)
+			require.Contains(t, out, "(Product 1 with a name far too long to fit in the description col...)")
+			require.Equal(t, tc.pages, strings.Count(out, "(Description)"))
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	require.Equal(t, []byte("Caf\xe9 \x80 5 ?"), encode("Café € 5 ✓"))
+}
+
+func TestFormat(t *testing.T) {
+	require.Equal(t, "INV-000042", FormatNumber(42))
+	require.Equal(t, "INV-1234567", FormatNumber(1234567))
+
+	for bp, want := range map[int64]string{0: "0%", 2000: "20%", 550: "5.5%", 725: "7.25%"} {
+		require.Equal(t, want, formatRate(bp))
+	}
+}
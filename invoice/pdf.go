@@ -0,0 +1,338 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// A4 page size and margin, in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// font names a font of the page resources.
+type font string
+
+const (
+	regular font = "F1"
+	bold    font = "F2"
+)
+
+// Right ends of the columns of the lines table, the description starting at
+// the margin.
+const (
+	descriptionEnd = 300.0
+	quantityEnd    = 335.0
+	unitPriceEnd   = 400.0
+	taxRateEnd     = 445.0
+	taxEnd         = 495.0
+	amountEnd      = pageWidth - margin
+)
+
+const (
+	textSize   = 9.0
+	lineHeight = 13.0
+)
+
+// WritePDF renders the invoice as a PDF document.
+func (inv *Invoice) WritePDF(w io.Writer) error {
+	p := &pdf{}
+	p.newPage()
+
+	p.text(bold, 20, margin, "INVOICE")
+	p.textRight(bold, textSize, amountEnd, inv.Number)
+	p.newline(lineHeight)
+	p.textRight(regular, textSize, amountEnd, "Issued "+inv.IssuedAt.UTC().Format("2006-01-02"))
+	p.newline(lineHeight)
+	p.textRight(regular, textSize, amountEnd, fmt.Sprintf("Order #%d", inv.OrderID))
+	p.newline(2 * lineHeight)
+
+	left := []string{inv.Seller.Name}
+	left = append(left, strings.Split(inv.Seller.Address, "\n")...)
+	if inv.Seller.TaxID != "" {
+		left = append(left, "Tax ID: "+inv.Seller.TaxID)
+	}
+	right := []string{"Bill to", inv.Customer.Name, inv.Customer.Email}
+	if a := inv.Customer.Address; a != nil {
+		right = append(right, "", "Ship to")
+		right = append(right, a.lines()...)
+	}
+	for i := range max(len(left), len(right)) {
+		if i < len(left) {
+			p.text(fontIf(i == 0, bold), textSize, margin, left[i])
+		}
+		if i < len(right) {
+			p.text(fontIf(right[i] == "Bill to" || right[i] == "Ship to", bold), textSize, 320, right[i])
+		}
+		p.newline(lineHeight)
+	}
+	p.newline(lineHeight)
+
+	p.tableHeader()
+	for _, l := range inv.Lines {
+		if p.full(lineHeight) {
+			p.newPage()
+			p.tableHeader()
+		}
+		p.text(regular, textSize, margin, fit(regular, textSize, l.Name, descriptionEnd-margin))
+		p.textRight(regular, textSize, quantityEnd, fmt.Sprint(l.Quantity))
+		p.textRight(regular, textSize, unitPriceEnd, l.UnitPrice.String())
+		p.textRight(regular, textSize, taxRateEnd, formatRate(l.TaxRate))
+		p.textRight(regular, textSize, taxEnd, l.Tax.String())
+		p.textRight(regular, textSize, amountEnd, l.Amount.String())
+		p.newline(lineHeight)
+	}
+	p.rule()
+	p.newline(lineHeight)
+
+	type total struct {
+		label  string
+		amount money.Amount
+	}
+	totals := []total{{"Subtotal", inv.Subtotal}}
+	if inv.Discount != 0 {
+		totals = append(totals, total{"Discount", -inv.Discount})
+	}
+	shipping := "Shipping"
+	if inv.ShippingMethod != "" {
+		shipping += " (" + inv.ShippingMethod + ")"
+	}
+	tax := "Tax"
+	if inv.TaxInclusive {
+		tax = "Tax included"
+	}
+	totals = append(totals, total{shipping, inv.Shipping}, total{tax, inv.Tax})
+
+	if p.full(float64(len(totals)+3) * lineHeight) {
+		p.newPage()
+	}
+	for _, t := range totals {
+		p.text(regular, textSize, taxRateEnd-80, t.label)
+		p.textRight(regular, textSize, amountEnd, t.amount.String())
+		p.newline(lineHeight)
+	}
+	p.text(bold, textSize+2, taxRateEnd-80, "Total "+inv.Currency)
+	p.textRight(bold, textSize+2, amountEnd, inv.Total.String())
+	p.newline(2 * lineHeight)
+	p.text(regular, textSize-1, margin, "Amounts in "+inv.Currency+".")
+
+	return p.writeTo(w)
+}
+
+func (a *Address) lines() []string {
+	lines := []string{a.Name, a.Line1}
+	if a.Line2 != "" {
+		lines = append(lines, a.Line2)
+	}
+	city := strings.TrimSpace(strings.Join([]string{a.PostalCode, a.City}, " "))
+	if a.Region != "" {
+		city += ", " + a.Region
+	}
+	lines = append(lines, city, a.Country)
+	if a.Phone != "" {
+		lines = append(lines, a.Phone)
+	}
+	return lines
+}
+
+func (p *pdf) tableHeader() {
+	p.text(bold, textSize, margin, "Description")
+	p.textRight(bold, textSize, quantityEnd, "Qty")
+	p.textRight(bold, textSize, unitPriceEnd, "Unit price")
+	p.textRight(bold, textSize, taxRateEnd, "Tax rate")
+	p.textRight(bold, textSize, taxEnd, "Tax")
+	p.textRight(bold, textSize, amountEnd, "Amount")
+	p.rule()
+	p.newline(lineHeight + 4)
+}
+
+// formatRate formats a rate in basis points as a percentage, e.g. 7.25%.
+func formatRate(bp int64) string {
+	s := fmt.Sprintf("%d.%02d", bp/100, bp%100)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".") + "%"
+}
+
+func fontIf(cond bool, f font) font {
+	if cond {
+		return f
+	}
+	return regular
+}
+
+// pdf lays out text on pages in the standard Helvetica fonts, which every
+// PDF reader provides, so that documents embed no font. Text is encoded in
+// WinAnsiEncoding.
+type pdf struct {
+	pages []*bytes.Buffer
+	// y is the baseline of the current line, from the bottom of the page.
+	y float64
+}
+
+func (p *pdf) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pageHeight - margin
+}
+
+func (p *pdf) page() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// text draws s on the current line, starting at x.
+func (p *pdf) text(f font, size, x float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(p.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", f, size, x, p.y, escape(encode(s)))
+}
+
+// textRight draws s on the current line, ending at x.
+func (p *pdf) textRight(f font, size, x float64, s string) {
+	p.text(f, size, x-textWidth(f, size, encode(s)), s)
+}
+
+// rule draws a line across the page under the current line.
+func (p *pdf) rule() {
+	fmt.Fprintf(p.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, p.y-4, pageWidth-margin, p.y-4)
+}
+
+func (p *pdf) newline(height float64) {
+	p.y -= height
+}
+
+// full reports whether the current page has no room left for height.
+func (p *pdf) full(height float64) bool {
+	return p.y-height < margin
+}
+
+// fit shortens s with an ellipsis until it is at most width wide.
+func fit(f font, size float64, s string, width float64) string {
+	if textWidth(f, size, encode(s)) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && textWidth(f, size, encode(string(r)+"...")) > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
+
+// writeTo writes the document: the catalog, the page tree, the two fonts,
+// then every page followed by its content stream, and the cross-reference
+// table locating them.
+func (p *pdf) writeTo(w io.Writer) error {
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(format string, args ...any) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString("\nendobj\n")
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range p.pages {
+		obj("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i)
+		obj("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes())
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// winAnsi maps the characters WinAnsiEncoding places outside Latin-1.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encode encodes s in WinAnsiEncoding, replacing the characters it lacks
+// with a question mark.
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b = append(b, byte(r))
+		case winAnsi[r] != 0:
+			b = append(b, winAnsi[r])
+		case r < 0x20:
+			b = append(b, ' ')
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// escape escapes an encoded string for a PDF literal string.
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// textWidth returns the width, in points, of encoded text set in the font.
+func textWidth(f font, size float64, b []byte) float64 {
+	widths := &helveticaWidths
+	if f == bold {
+		widths = &helveticaBoldWidths
+	}
+
+	var w int
+	for _, c := range b {
+		if c >= ' ' && c <= '~' {
+			w += widths[c-' ']
+		} else {
+			w += 556
+		}
+	}
+	return float64(w) * size / 1000
+}
+
+// Widths of the printable ASCII characters, from space to tilde, in
+// thousandths of the font size, from the metrics of the standard fonts.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
@@ -0,0 +1,187 @@
+// Package money represents amounts of money exactly, as integer counts of
+// the minor unit of their currency, so that prices never go through a float.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// StoreCurrency is the ISO 4217 code of the currency the store keeps its
+// books in. Orders are priced in it before being converted to the currency
+// the customer pays in.
+const StoreCurrency = "USD"
+
+// Amount is an amount of money in cents, the minor unit of its currency,
+// which is carried next to it. It is stored in DECIMAL(10,2) columns and
+// marshalled to JSON as a decimal string, e.g. "12.30".
+type Amount int64
+
+// minorUnits is the number of minor units in a major unit. Every currency
+// is taken to have two decimals, like the columns amounts are stored in.
+const minorUnits = 100
+
+// Parse parses a decimal string with at most two fractional digits, such as
+// "12", "12.3" or "-0.05".
+func Parse(s string) (Amount, error) {
+	v, err := parseDecimal(s, 2)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %w", err)
+	}
+	return Amount(v), nil
+}
+
+// parseDecimal parses s into an integer count of 10^-places units.
+func parseDecimal(s string, places int) (int64, error) {
+	digits, neg := strings.CutPrefix(s, "-")
+	whole, frac, dot := strings.Cut(digits, ".")
+	if whole == "" || (dot && frac == "") || len(frac) > places || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("malformed decimal %q", s)
+	}
+
+	scale := pow10(places)
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/scale-1 {
+		return 0, fmt.Errorf("decimal %q out of range", s)
+	}
+
+	f := int64(0)
+	if frac != "" {
+		f, _ = strconv.ParseInt(frac+strings.Repeat("0", places-len(frac)), 10, 64)
+	}
+
+	v := w*scale + f
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatDecimal formats v, a count of 10^-places units, with exactly places
+// fractional digits.
+func formatDecimal(v int64, places int) string {
+	sign, u := "", abs(v)
+	if v < 0 {
+		sign = "-"
+	}
+	scale := uint64(pow10(places))
+	return fmt.Sprintf("%s%d.%0*d", sign, u/scale, places, u%scale)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for range n {
+		p *= 10
+	}
+	return p
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
+
+// mulDiv returns a*b/d, rounded half away from zero, without overflowing on
+// the intermediate product. d must be positive.
+func mulDiv(a, b, d int64) int64 {
+	hi, lo := bits.Mul64(abs(a), abs(b))
+	lo, carry := bits.Add64(lo, uint64(d)/2, 0)
+	q, _ := bits.Div64(hi+carry, lo, uint64(d))
+	if (a < 0) != (b < 0) {
+		return -int64(q)
+	}
+	return int64(q)
+}
+
+// String formats the amount with exactly two fractional digits.
+func (a Amount) String() string {
+	return formatDecimal(int64(a), 2)
+}
+
+// Mul returns the amount n times, e.g. the price of n items.
+func (a Amount) Mul(n int64) Amount {
+	return a * Amount(n)
+}
+
+// MulBasisPoints returns bp hundredths of a percent of the amount, rounded
+// half away from zero to the cent. A 7.5% tax is 750 basis points.
+func (a Amount) MulBasisPoints(bp int64) Amount {
+	return Amount(mulDiv(int64(a), bp, 10000))
+}
+
+// IncludedBasisPoints returns the part of the amount that is a surcharge of
+// bp basis points on the rest, rounded half away from zero to the cent: a
+// price of 120.00 includes 20.00 of a 2000 basis points tax.
+func (a Amount) IncludedBasisPoints(bp int64) Amount {
+	return Amount(mulDiv(int64(a), bp, 10000+bp))
+}
+
+// Convert converts the amount with an exchange rate, rounded half away from
+// zero to the cent.
+func (a Amount) Convert(r Rate) Amount {
+	return Amount(mulDiv(int64(a), int64(r), int64(RateOne)))
+}
+
+// Scan reads a DECIMAL column, which MySQL sends as text.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return a.parse(string(v))
+	case string:
+		return a.parse(v)
+	case int64:
+		*a = Amount(v * minorUnits)
+	case float64:
+		*a = Amount(math.Round(v * minorUnits))
+	default:
+		return fmt.Errorf("cannot scan %T into an amount", src)
+	}
+	return nil
+}
+
+func (a *Amount) parse(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value writes the amount as a decimal string, which DECIMAL columns store
+// exactly.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, a.String()), nil
+}
+
+// UnmarshalJSON accepts both decimal strings and JSON numbers, which are
+// parsed from their text rather than through a float.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return a.parse(s)
+}
@@ -0,0 +1,117 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tcs := []struct {
+		name    string
+		in      string
+		want    Amount
+		wantErr bool
+	}{
+		{name: "whole", in: "12", want: 1200},
+		{name: "one decimal", in: "12.3", want: 1230},
+		{name: "two decimals", in: "0.05", want: 5},
+		{name: "negative", in: "-1.50", want: -150},
+		{name: "float rounding trap", in: "0.29", want: 29},
+		{name: "too many decimals", in: "1.005", wantErr: true},
+		{name: "trailing dot", in: "1.", wantErr: true},
+		{name: "missing whole", in: ".5", wantErr: true},
+		{name: "exponent", in: "1e2", wantErr: true},
+		{name: "empty", in: "", wantErr: true},
+		{name: "out of range", in: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.in)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "12.30", Amount(1230).String())
+	require.Equal(t, "0.05", Amount(5).String())
+	require.Equal(t, "-0.05", Amount(-5).String())
+	require.Equal(t, "0.00", Amount(0).String())
+}
+
+func TestMulBasisPoints(t *testing.T) {
+	tcs := []struct {
+		name string
+		a    Amount
+		bp   int64
+		want Amount
+	}{
+		{name: "exact", a: 10000, bp: 1000, want: 1000},
+		{name: "rounds down", a: 1234, bp: 1000, want: 123},
+		{name: "rounds half up", a: 1235, bp: 1000, want: 124},
+		{name: "rounds half away from zero", a: -1235, bp: 1000, want: -124},
+		{name: "fractional rate", a: 1999, bp: 825, want: 165},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.a.MulBasisPoints(tc.bp))
+		})
+	}
+}
+
+func TestIncludedBasisPoints(t *testing.T) {
+	tcs := []struct {
+		name string
+		a    Amount
+		bp   int64
+		want Amount
+	}{
+		{name: "exact", a: 12000, bp: 2000, want: 2000},
+		{name: "rounds", a: 1999, bp: 2000, want: 333},
+		{name: "zero rate", a: 1999, bp: 0, want: 0},
+		{name: "fractional rate", a: 1055, bp: 550, want: 55},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.a.IncludedBasisPoints(tc.bp))
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	var a Amount
+	require.NoError(t, a.Scan([]byte("99.99")))
+	require.Equal(t, Amount(9999), a)
+	require.NoError(t, a.Scan(int64(3)))
+	require.Equal(t, Amount(300), a)
+	require.NoError(t, a.Scan(19.99))
+	require.Equal(t, Amount(1999), a)
+	require.Error(t, a.Scan(nil))
+}
+
+func TestJSON(t *testing.T) {
+	b, err := json.Marshal(struct {
+		Price Amount `json:"price"`
+	}{Price: 1999})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"price":"19.99"}`, string(b))
+
+	var v struct {
+		Price Amount `json:"price"`
+		Tax   Amount `json:"tax"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":"19.99","tax":0.1}`), &v))
+	require.Equal(t, Amount(1999), v.Price)
+	require.Equal(t, Amount(10), v.Tax)
+	require.Error(t, json.Unmarshal([]byte(`{"price":"19.999"}`), &v))
+}
@@ -0,0 +1,162 @@
+package money
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedCurrency is returned for currencies a RateProvider has no
+// rate for.
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Rate is an exchange rate in millionths: converting an amount multiplies it
+// by Rate/1000000. It is stored in DECIMAL(18,6) columns and marshalled to
+// JSON as a decimal string, e.g. "0.921500".
+type Rate int64
+
+// rateDecimals is the number of fractional digits of a rate.
+const rateDecimals = 6
+
+// RateOne converts an amount to itself.
+const RateOne Rate = 1000000
+
+// ParseRate parses a positive decimal string with at most six fractional
+// digits, such as "0.9215".
+func ParseRate(s string) (Rate, error) {
+	v, err := parseDecimal(s, rateDecimals)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate: %w", err)
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: must be positive", s)
+	}
+	return Rate(v), nil
+}
+
+// String formats the rate with exactly six fractional digits.
+func (r Rate) String() string {
+	return formatDecimal(int64(r), rateDecimals)
+}
+
+// Scan reads a DECIMAL column, which MySQL sends as text.
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	case int64:
+		*r = Rate(v) * RateOne
+	case float64:
+		*r = Rate(math.Round(v * float64(RateOne)))
+	default:
+		return fmt.Errorf("cannot scan %T into a rate", src)
+	}
+	return nil
+}
+
+func (r *Rate) parse(s string) error {
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, r.String()), nil
+}
+
+// UnmarshalJSON accepts both decimal strings and JSON numbers, which are
+// parsed from their text rather than through a float.
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return r.parse(s)
+}
+
+// RateProvider is a source of exchange rates. Rate returns the rate
+// converting amounts in the from currency into the to currency, or
+// ErrUnsupportedCurrency if it does not know one of them. Callers ask for a
+// rate for every price they convert, so implementations fetching rates
+// remotely should cache them.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// StaticRates is a RateProvider with a fixed table of rates against a base
+// currency, for use offline.
+type StaticRates struct {
+	base  string
+	rates map[string]Rate
+}
+
+// NewStaticRates returns a provider converting between base and the
+// currencies of rates, which hold the value of one unit of base in each
+// currency.
+func NewStaticRates(base string, rates map[string]Rate) *StaticRates {
+	sr := &StaticRates{base: base, rates: map[string]Rate{base: RateOne}}
+	for currency, r := range rates {
+		sr.rates[strings.ToUpper(currency)] = r
+	}
+	return sr
+}
+
+// LoadStaticRates reads a provider from a JSON file such as
+//
+//	{"base": "USD", "rates": {"EUR": "0.9215", "GBP": "0.7890"}}
+func LoadStaticRates(path string) (*StaticRates, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rates: %w", err)
+	}
+
+	var f struct {
+		Base  string          `json:"base"`
+		Rates map[string]Rate `json:"rates"`
+	}
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rates: %w", err)
+	}
+	if f.Base == "" {
+		return nil, fmt.Errorf("error parsing rates: missing base currency")
+	}
+
+	return NewStaticRates(strings.ToUpper(f.Base), f.Rates), nil
+}
+
+// Rate converts through the base currency, rounding the rate to the
+// millionth.
+func (sr *StaticRates) Rate(ctx context.Context, from, to string) (Rate, error) {
+	fromRate, ok := sr.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, from)
+	}
+	toRate, ok := sr.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, to)
+	}
+	if from == to {
+		return RateOne, nil
+	}
+
+	return Rate(mulDiv(int64(toRate), int64(RateOne), int64(fromRate))), nil
+}
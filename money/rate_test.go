@@ -0,0 +1,76 @@
+package money
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	r, err := ParseRate("0.9215")
+	require.NoError(t, err)
+	require.Equal(t, Rate(921500), r)
+	require.Equal(t, "0.921500", r.String())
+
+	_, err = ParseRate("0")
+	require.Error(t, err)
+	_, err = ParseRate("1.0000001")
+	require.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	tcs := []struct {
+		name string
+		a    Amount
+		r    Rate
+		want Amount
+	}{
+		{name: "identity", a: 1999, r: RateOne, want: 1999},
+		{name: "rounds half up", a: 1000, r: 921550, want: 922},
+		{name: "rounds down", a: 1000, r: 921449, want: 921},
+		{name: "large rate", a: 99999999999, r: 16000 * RateOne, want: 1599999999984000},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.a.Convert(tc.r))
+		})
+	}
+}
+
+func TestStaticRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"base": "usd", "rates": {"EUR": "0.8", "gbp": 0.5}}`), 0o600)
+	require.NoError(t, err)
+
+	sr, err := LoadStaticRates(path)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		from, to string
+		want     Rate
+		wantErr  error
+	}{
+		{name: "from base", from: "USD", to: "EUR", want: 800000},
+		{name: "to base", from: "EUR", to: "USD", want: 1250000},
+		{name: "cross rate", from: "EUR", to: "GBP", want: 625000},
+		{name: "same currency", from: "GBP", to: "GBP", want: RateOne},
+		{name: "unknown currency", from: "USD", to: "JPY", wantErr: ErrUnsupportedCurrency},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := sr.Rate(context.Background(), tc.from, tc.to)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, r)
+		})
+	}
+}
@@ -14,6 +14,9 @@ import (
 	gomail "gopkg.in/gomail.v2"
 )
 
+// eventsPageSize is the number of notification events fetched per call.
+const eventsPageSize = 100
+
 type AdminInfo struct {
 	Email    string
 	Password string
@@ -52,14 +55,24 @@ func (s *Server) Run(ctx context.Context) {
 }
 
 func (s *Server) processNotificationEvents(ctx context.Context) error {
-	res, err := s.client.ListNotificationEvents(ctx, &pb.ListNotificationEventsReq{})
-	if err != nil {
-		return err
+	var events []*pb.NotificationEvent
+	req := &pb.ListNotificationEventsReq{PageSize: eventsPageSize}
+	for {
+		res, err := s.client.ListNotificationEvents(ctx, req)
+		if err != nil {
+			return err
+		}
+		events = append(events, res.Events...)
+
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
 	}
 
 	var wg sync.WaitGroup
 	sem := semaphore.NewWeighted(10)
-	for _, ev := range res.Events {
+	for _, ev := range events {
 		wg.Add(1)
 		if err := sem.Acquire(ctx, 1); err != nil {
 			return err
@@ -0,0 +1,191 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// Payment methods the fake gateway simulates an outcome for. Any other method
+// is authorized.
+const (
+	// FakeDecline is declined by the issuer.
+	FakeDecline = "fake_decline"
+	// FakeThreeDS requires a 3-D Secure challenge, which the customer passes.
+	FakeThreeDS = "fake_3ds"
+	// FakeThreeDSFail requires a 3-D Secure challenge, which the customer
+	// fails.
+	FakeThreeDSFail = "fake_3ds_fail"
+)
+
+// fakeActionDelay is how long the simulated customer takes to complete a
+// 3-D Secure challenge.
+const fakeActionDelay = 2 * time.Second
+
+// Fake is an in-process Gateway for local development and tests. It moves no
+// money: the payment method decides the outcome, see FakeDecline and
+// FakeThreeDS.
+type Fake struct {
+	secret     []byte
+	webhookURL string
+	client     *http.Client
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	lastID   int64
+	lastEvID int64
+}
+
+type fakePayment struct {
+	amount money.Amount
+	status Status
+	method string
+}
+
+// NewFake returns a fake gateway signing its webhooks with secret. If
+// webhookURL is set, it posts the outcome of 3-D Secure challenges there,
+// otherwise they stay pending until CompleteAction is called.
+func NewFake(secret []byte, webhookURL string) *Fake {
+	return &Fake{
+		secret:     secret,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		payments:   make(map[string]*fakePayment),
+	}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) Authorize(ctx context.Context, req *AuthorizeReq) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	ref := fmt.Sprintf("fake_pay_%d", f.lastID)
+	fp := &fakePayment{amount: req.Amount, method: req.Method, status: Authorized}
+	f.payments[ref] = fp
+
+	res := &Result{Ref: ref}
+	switch req.Method {
+	case FakeDecline:
+		fp.status = Declined
+		res.DeclineReason = "card declined"
+	case FakeThreeDS, FakeThreeDSFail:
+		fp.status = ActionRequired
+		res.NextActionURL = "https://fake-gateway.invalid/3ds/" + ref
+		if f.webhookURL != "" {
+			time.AfterFunc(fakeActionDelay, func() { f.deliver(ref) })
+		}
+	}
+	res.Status = fp.status
+
+	return res, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, ref string, amount money.Amount) (*Result, error) {
+	return f.move(ref, amount, Authorized, Captured)
+}
+
+func (f *Fake) Refund(ctx context.Context, ref string, amount money.Amount) (*Result, error) {
+	return f.move(ref, amount, Captured, Refunded)
+}
+
+// move changes the status of the full amount of a payment.
+func (f *Fake) move(ref string, amount money.Amount, from, to Status) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fp, ok := f.payments[ref]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPayment, ref)
+	}
+	if fp.status != from {
+		return nil, fmt.Errorf("payment %s is %s, not %s", ref, fp.status, from)
+	}
+	if amount != fp.amount {
+		return nil, fmt.Errorf("payment %s is of %s, not %s", ref, fp.amount, amount)
+	}
+
+	fp.status = to
+	return &Result{Ref: ref, Status: to}, nil
+}
+
+func (f *Fake) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if !Verify(f.secret, payload, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var ev Event
+	err := json.Unmarshal(payload, &ev)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding webhook: %w", err)
+	}
+	return &ev, nil
+}
+
+// CompleteAction completes the 3-D Secure challenge of a payment as its
+// method says and returns the signed webhook notifying the outcome.
+func (f *Fake) CompleteAction(ref string) ([]byte, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fp, ok := f.payments[ref]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownPayment, ref)
+	}
+	if fp.status != ActionRequired {
+		return nil, "", fmt.Errorf("payment %s is %s, not %s", ref, fp.status, ActionRequired)
+	}
+
+	ev := Event{Ref: ref, Status: Authorized}
+	if fp.method == FakeThreeDSFail {
+		ev.Status = Declined
+		ev.DeclineReason = "3-D Secure authentication failed"
+	}
+	fp.status = ev.Status
+	f.lastEvID++
+	ev.ID = fmt.Sprintf("fake_evt_%d", f.lastEvID)
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding webhook: %w", err)
+	}
+	return payload, Sign(f.secret, payload), nil
+}
+
+// deliver completes the challenge of a payment and posts the webhook to
+// f.webhookURL, the way a provider would.
+func (f *Fake) deliver(ref string) {
+	payload, signature, err := f.CompleteAction(ref)
+	if err != nil {
+		log.Printf("fake gateway: %v", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, f.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("fake gateway: error creating webhook request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		log.Printf("fake gateway: error posting webhook: %v", err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		log.Printf("fake gateway: webhook for %s rejected with status %d", ref, res.StatusCode)
+	}
+}
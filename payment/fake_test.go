@@ -0,0 +1,112 @@
+package payment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAuthorize(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake([]byte("secret"), "")
+
+	tcs := []struct {
+		name   string
+		method string
+		want   Status
+	}{
+		{name: "success", method: "card", want: Authorized},
+		{name: "decline", method: FakeDecline, want: Declined},
+		{name: "3ds", method: FakeThreeDS, want: ActionRequired},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := f.Authorize(ctx, &AuthorizeReq{OrderID: 1, Amount: 1000, Currency: "USD", Method: tc.method})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, res.Status)
+			require.NotEmpty(t, res.Ref)
+		})
+	}
+}
+
+func TestFakeCaptureRefund(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake([]byte("secret"), "")
+
+	res, err := f.Authorize(ctx, &AuthorizeReq{Amount: 1000, Method: "card"})
+	require.NoError(t, err)
+
+	_, err = f.Refund(ctx, res.Ref, 1000)
+	require.Error(t, err, "refunding an uncaptured payment")
+	_, err = f.Capture(ctx, res.Ref, 999)
+	require.Error(t, err, "capturing another amount")
+
+	captured, err := f.Capture(ctx, res.Ref, 1000)
+	require.NoError(t, err)
+	require.Equal(t, Captured, captured.Status)
+
+	refunded, err := f.Refund(ctx, res.Ref, 1000)
+	require.NoError(t, err)
+	require.Equal(t, Refunded, refunded.Status)
+
+	_, err = f.Capture(ctx, "fake_pay_404", 1000)
+	require.ErrorIs(t, err, ErrUnknownPayment)
+}
+
+func TestFakeWebhook(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake([]byte("secret"), "")
+
+	passed, err := f.Authorize(ctx, &AuthorizeReq{Amount: 1000, Method: FakeThreeDS})
+	require.NoError(t, err)
+	failed, err := f.Authorize(ctx, &AuthorizeReq{Amount: 1000, Method: FakeThreeDSFail})
+	require.NoError(t, err)
+
+	payload, signature, err := f.CompleteAction(passed.Ref)
+	require.NoError(t, err)
+	ev, err := f.VerifyWebhook(payload, signature)
+	require.NoError(t, err)
+	require.Equal(t, passed.Ref, ev.Ref)
+	require.Equal(t, Authorized, ev.Status)
+
+	_, _, err = f.CompleteAction(passed.Ref)
+	require.Error(t, err, "completing a challenge twice")
+
+	payload, signature, err = f.CompleteAction(failed.Ref)
+	require.NoError(t, err)
+	ev, err = f.VerifyWebhook(payload, signature)
+	require.NoError(t, err)
+	require.Equal(t, Declined, ev.Status)
+
+	_, err = f.VerifyWebhook(payload, Sign([]byte("other secret"), payload))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = f.VerifyWebhook(append(payload, ' '), signature)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestFakeDeliver(t *testing.T) {
+	got := make(chan *Event, 1)
+	var f *Fake
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		ev, err := f.VerifyWebhook(payload, r.Header.Get(SignatureHeader))
+		require.NoError(t, err)
+		got <- ev
+	}))
+	defer srv.Close()
+	f = NewFake([]byte("secret"), srv.URL)
+
+	res, err := f.Authorize(context.Background(), &AuthorizeReq{Amount: 1000, Method: FakeThreeDS})
+	require.NoError(t, err)
+
+	f.deliver(res.Ref)
+	ev := <-got
+	require.Equal(t, res.Ref, ev.Ref)
+	require.Equal(t, Authorized, ev.Status)
+}
@@ -0,0 +1,109 @@
+// Package payment connects the store to payment service providers. A Gateway
+// authorizes, captures and refunds the amount of an order, and reports the
+// outcomes it decides later, such as 3-D Secure challenges, through signed
+// webhooks.
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+var (
+	// ErrInvalidSignature is returned for webhooks that were not signed by
+	// the provider.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnknownPayment is returned for references the provider did not
+	// issue.
+	ErrUnknownPayment = errors.New("unknown payment")
+)
+
+// Status is the state of a payment at its provider.
+type Status string
+
+const (
+	// ActionRequired payments wait for the customer, e.g. to pass a 3-D
+	// Secure challenge, before they are authorized or declined.
+	ActionRequired Status = "action_required"
+	Authorized     Status = "authorized"
+	Captured       Status = "captured"
+	Declined       Status = "declined"
+	Refunded       Status = "refunded"
+)
+
+// AuthorizeReq asks a provider to hold the amount of an order on the payment
+// method of the customer.
+type AuthorizeReq struct {
+	OrderID  int64
+	Amount   money.Amount
+	Currency string
+	Method   string
+}
+
+// Result is the state of a payment after a call to its provider. Ref
+// identifies the payment at the provider. NextActionURL is where the customer
+// completes an ActionRequired payment.
+type Result struct {
+	Ref           string
+	Status        Status
+	NextActionURL string
+	DeclineReason string
+}
+
+// Event is the change of status of a payment notified by a webhook. Providers
+// may deliver an event more than once.
+type Event struct {
+	ID            string `json:"id"`
+	Ref           string `json:"ref"`
+	Status        Status `json:"status"`
+	DeclineReason string `json:"decline_reason,omitempty"`
+}
+
+// Gateway is a payment service provider. Declined payments are results, not
+// errors: errors mean the provider could not be reached or refused the call.
+type Gateway interface {
+	// Name identifies the provider in stored payments.
+	Name() string
+	Authorize(ctx context.Context, req *AuthorizeReq) (*Result, error)
+	Capture(ctx context.Context, ref string, amount money.Amount) (*Result, error)
+	Refund(ctx context.Context, ref string, amount money.Amount) (*Result, error)
+	// VerifyWebhook checks the signature of a webhook payload and returns
+	// the event it carries, or ErrInvalidSignature.
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// SignatureHeader is the HTTP header carrying the signature of a webhook.
+const SignatureHeader = "Webhook-Signature"
+
+// signaturePrefix names the algorithm of webhook signatures.
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of a webhook payload: the hex encoded
+// HMAC-SHA256 of the payload under secret, prefixed with "sha256=".
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of payload under secret.
+func Verify(secret, payload []byte, signature string) bool {
+	sum, ok := strings.CutPrefix(signature, signaturePrefix)
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package shipping
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+type methodConfig struct {
+	ID                string          `json:"id"`
+	Name              string          `json:"name"`
+	MinDays           int             `json:"min_days"`
+	MaxDays           int             `json:"max_days"`
+	Countries         []string        `json:"countries"`
+	Flat              *money.Amount   `json:"flat"`
+	WeightTable       []WeightBracket `json:"weight_table"`
+	VolumetricDivisor int64           `json:"volumetric_divisor"`
+	FreeOver          money.Amount    `json:"free_over"`
+}
+
+// LoadMethods reads the shipping methods of the store from a JSON file such
+// as
+//
+//	{"methods": [
+//	  {"id": "standard", "name": "Standard", "min_days": 3, "max_days": 5,
+//	   "countries": ["US", "CA"], "flat": "4.99", "free_over": "50.00"},
+//	  {"id": "express", "name": "Express", "min_days": 1, "max_days": 2,
+//	   "weight_table": [{"max_weight": 1000, "price": "9.99"}, {"max_weight": 5000, "price": "19.99"}],
+//	   "volumetric_divisor": 5000}
+//	]}
+//
+// Every method has either a flat price or a weight table, and ships for free
+// from its free_over value if set. Weights are in grams.
+func LoadMethods(path string) (Methods, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading shipping methods: %w", err)
+	}
+
+	var f struct {
+		Methods []methodConfig `json:"methods"`
+	}
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing shipping methods: %w", err)
+	}
+
+	ms := make(Methods, 0, len(f.Methods))
+	seen := make(map[string]bool)
+	for _, mc := range f.Methods {
+		m, err := mc.method()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing shipping methods: %w", err)
+		}
+		if seen[m.ID] {
+			return nil, fmt.Errorf("error parsing shipping methods: duplicate method %q", m.ID)
+		}
+		seen[m.ID] = true
+		ms = append(ms, m)
+	}
+
+	return ms, nil
+}
+
+func (mc *methodConfig) method() (*Method, error) {
+	if mc.ID == "" {
+		return nil, fmt.Errorf("method without id")
+	}
+	if mc.MinDays < 0 || mc.MaxDays < mc.MinDays {
+		return nil, fmt.Errorf("method %s: invalid delivery days %d-%d", mc.ID, mc.MinDays, mc.MaxDays)
+	}
+
+	var rate RateCalculator
+	switch {
+	case mc.Flat != nil && mc.WeightTable != nil:
+		return nil, fmt.Errorf("method %s: both flat and weight_table are set", mc.ID)
+	case mc.Flat != nil:
+		if *mc.Flat < 0 {
+			return nil, fmt.Errorf("method %s: negative flat price", mc.ID)
+		}
+		rate = &FlatRate{Price: *mc.Flat}
+	case len(mc.WeightTable) > 0:
+		for i, b := range mc.WeightTable {
+			if b.Price < 0 || b.MaxWeight <= 0 || (i > 0 && b.MaxWeight <= mc.WeightTable[i-1].MaxWeight) {
+				return nil, fmt.Errorf("method %s: weight brackets must have increasing weights and non-negative prices", mc.ID)
+			}
+		}
+		rate = &WeightTable{Brackets: mc.WeightTable, VolumetricDivisor: mc.VolumetricDivisor}
+	default:
+		return nil, fmt.Errorf("method %s: either flat or weight_table must be set", mc.ID)
+	}
+	if mc.FreeOver > 0 {
+		rate = &FreeOver{Threshold: mc.FreeOver, Base: rate}
+	}
+
+	countries := make([]string, 0, len(mc.Countries))
+	for _, c := range mc.Countries {
+		countries = append(countries, strings.ToUpper(c))
+	}
+
+	return &Method{
+		ID:        mc.ID,
+		Name:      cmp.Or(mc.Name, mc.ID),
+		MinDays:   mc.MinDays,
+		MaxDays:   mc.MaxDays,
+		Countries: countries,
+		Rate:      rate,
+	}, nil
+}
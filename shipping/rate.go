@@ -0,0 +1,65 @@
+package shipping
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// FlatRate charges the same price for every parcel.
+type FlatRate struct {
+	Price money.Amount
+}
+
+func (fr *FlatRate) Rate(ctx context.Context, p *Parcel, to *Destination) (money.Amount, error) {
+	return fr.Price, nil
+}
+
+// WeightBracket prices the parcels weighing up to MaxWeight grams.
+type WeightBracket struct {
+	MaxWeight int64        `json:"max_weight"`
+	Price     money.Amount `json:"price"`
+}
+
+// WeightTable charges the price of the lightest bracket a parcel fits in,
+// Brackets being sorted by weight. Parcels heavier than the last bracket are
+// not shippable. If VolumetricDivisor is set, bulky parcels are charged for
+// their volumetric weight, their volume in cubic millimetres divided by it,
+// when it exceeds their weight; carriers commonly use 5000.
+type WeightTable struct {
+	Brackets          []WeightBracket
+	VolumetricDivisor int64
+}
+
+func (wt *WeightTable) Rate(ctx context.Context, p *Parcel, to *Destination) (money.Amount, error) {
+	weight := p.Weight
+	if wt.VolumetricDivisor > 0 {
+		weight = max(weight, p.Volume/wt.VolumetricDivisor)
+	}
+
+	for _, b := range wt.Brackets {
+		if weight <= b.MaxWeight {
+			return b.Price, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d g is over the weight limit", ErrNotShippable, weight)
+}
+
+// FreeOver ships parcels worth Threshold or more for free, and charges the
+// price of Base for the others. Parcels Base cannot ship stay unshippable.
+type FreeOver struct {
+	Threshold money.Amount
+	Base      RateCalculator
+}
+
+func (fo *FreeOver) Rate(ctx context.Context, p *Parcel, to *Destination) (money.Amount, error) {
+	price, err := fo.Base.Rate(ctx, p, to)
+	if err != nil {
+		return 0, err
+	}
+	if p.Value >= fo.Threshold {
+		return 0, nil
+	}
+	return price, nil
+}
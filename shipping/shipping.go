@@ -0,0 +1,131 @@
+// Package shipping prices the delivery of orders. The store offers shipping
+// methods, each pricing parcels with a RateCalculator: a flat rate, a table
+// of weight brackets, or either of them made free over an order value.
+package shipping
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+var (
+	// ErrNotShippable is returned for parcels a method cannot ship, such as
+	// parcels heavier than its weight table allows.
+	ErrNotShippable = errors.New("parcel cannot be shipped")
+	// ErrUnknownMethod is returned for methods the store does not offer.
+	ErrUnknownMethod = errors.New("unknown shipping method")
+)
+
+// Parcel is what an order ships. Weight is in grams and Volume in cubic
+// millimetres. Value is the discounted subtotal of the order in the store
+// currency.
+type Parcel struct {
+	Weight int64
+	Volume int64
+	Value  money.Amount
+}
+
+// Add puts quantity items in the parcel, weighing weight grams and measuring
+// length, width and height millimetres each.
+func (p *Parcel) Add(weight, length, width, height, quantity int64) {
+	p.Weight += weight * quantity
+	p.Volume += length * width * height * quantity
+}
+
+// Destination is where a parcel ships to. Country is an ISO 3166-1 alpha-2
+// code.
+type Destination struct {
+	Country    string
+	Region     string
+	PostalCode string
+}
+
+// RateCalculator prices the shipping of a parcel in the store currency, or
+// fails with ErrNotShippable.
+type RateCalculator interface {
+	Rate(ctx context.Context, p *Parcel, to *Destination) (money.Amount, error)
+}
+
+// Method is a way the store ships parcels, taking from MinDays to MaxDays.
+// It ships to the countries listed in Countries, or everywhere if empty.
+type Method struct {
+	ID        string
+	Name      string
+	MinDays   int
+	MaxDays   int
+	Countries []string
+	Rate      RateCalculator
+}
+
+// ShipsTo reports whether the method ships to the destination.
+func (m *Method) ShipsTo(to *Destination) bool {
+	return len(m.Countries) == 0 || slices.Contains(m.Countries, to.Country)
+}
+
+// Option is a method able to ship a parcel, with the price it charges for it.
+type Option struct {
+	MethodID string
+	Name     string
+	MinDays  int
+	MaxDays  int
+	Price    money.Amount
+}
+
+// Methods are the shipping methods of the store.
+type Methods []*Method
+
+// Quote returns the options shipping the parcel to the destination, cheapest
+// first.
+func (ms Methods) Quote(ctx context.Context, p *Parcel, to *Destination) ([]*Option, error) {
+	var options []*Option
+	for _, m := range ms {
+		o, err := m.option(ctx, p, to)
+		if errors.Is(err, ErrNotShippable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+	slices.SortStableFunc(options, func(a, b *Option) int {
+		return cmp.Compare(a.Price, b.Price)
+	})
+
+	return options, nil
+}
+
+// Option returns the option of the method with the ID. It fails with
+// ErrUnknownMethod if the store has no such method, and with ErrNotShippable
+// if the method cannot ship the parcel to the destination.
+func (ms Methods) Option(ctx context.Context, id string, p *Parcel, to *Destination) (*Option, error) {
+	i := slices.IndexFunc(ms, func(m *Method) bool { return m.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, id)
+	}
+	return ms[i].option(ctx, p, to)
+}
+
+func (m *Method) option(ctx context.Context, p *Parcel, to *Destination) (*Option, error) {
+	if !m.ShipsTo(to) {
+		return nil, fmt.Errorf("%w: %s does not ship to %q", ErrNotShippable, m.ID, to.Country)
+	}
+
+	price, err := m.Rate.Rate(ctx, p, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.ID, err)
+	}
+
+	return &Option{
+		MethodID: m.ID,
+		Name:     m.Name,
+		MinDays:  m.MinDays,
+		MaxDays:  m.MaxDays,
+		Price:    price,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package shipping
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/niloy104/Conduit/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateCalculators(t *testing.T) {
+	ctx := context.Background()
+	table := &WeightTable{
+		Brackets: []WeightBracket{
+			{MaxWeight: 1000, Price: 500},
+			{MaxWeight: 5000, Price: 1500},
+		},
+		VolumetricDivisor: 5000,
+	}
+
+	tcs := []struct {
+		name    string
+		rate    RateCalculator
+		parcel  Parcel
+		want    money.Amount
+		wantErr error
+	}{
+		{name: "flat", rate: &FlatRate{Price: 499}, parcel: Parcel{Weight: 100000}, want: 499},
+		{name: "light bracket", rate: table, parcel: Parcel{Weight: 1000}, want: 500},
+		{name: "heavy bracket", rate: table, parcel: Parcel{Weight: 1001}, want: 1500},
+		{name: "volumetric weight", rate: table, parcel: Parcel{Weight: 200, Volume: 300 * 200 * 100}, want: 1500},
+		{name: "too heavy", rate: table, parcel: Parcel{Weight: 5001}, wantErr: ErrNotShippable},
+		{name: "under threshold", rate: &FreeOver{Threshold: 5000, Base: table}, parcel: Parcel{Weight: 10, Value: 4999}, want: 500},
+		{name: "over threshold", rate: &FreeOver{Threshold: 5000, Base: table}, parcel: Parcel{Weight: 10, Value: 5000}, want: 0},
+		{name: "free but too heavy", rate: &FreeOver{Threshold: 5000, Base: table}, parcel: Parcel{Weight: 9000, Value: 5000}, wantErr: ErrNotShippable},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.rate.Rate(ctx, &tc.parcel, &Destination{Country: "US"})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMethods(t *testing.T) {
+	ctx := context.Background()
+	ms := Methods{
+		{ID: "express", Rate: &FlatRate{Price: 1999}},
+		{ID: "standard", Countries: []string{"US"}, Rate: &FlatRate{Price: 499}},
+		{ID: "freight", Rate: &WeightTable{Brackets: []WeightBracket{{MaxWeight: 1000, Price: 999}}}},
+	}
+	parcel := &Parcel{Weight: 2000}
+
+	options, err := ms.Quote(ctx, parcel, &Destination{Country: "US"})
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	require.Equal(t, "standard", options[0].MethodID, "cheapest first")
+	require.Equal(t, "express", options[1].MethodID)
+
+	options, err = ms.Quote(ctx, parcel, &Destination{Country: "FR"})
+	require.NoError(t, err)
+	require.Len(t, options, 1)
+
+	o, err := ms.Option(ctx, "express", parcel, &Destination{Country: "FR"})
+	require.NoError(t, err)
+	require.Equal(t, money.Amount(1999), o.Price)
+	_, err = ms.Option(ctx, "standard", parcel, &Destination{Country: "FR"})
+	require.ErrorIs(t, err, ErrNotShippable)
+	_, err = ms.Option(ctx, "freight", parcel, &Destination{Country: "US"})
+	require.ErrorIs(t, err, ErrNotShippable)
+	_, err = ms.Option(ctx, "pigeon", parcel, &Destination{Country: "US"})
+	require.ErrorIs(t, err, ErrUnknownMethod)
+}
+
+func TestLoadMethods(t *testing.T) {
+	load := func(t *testing.T, config string) (Methods, error) {
+		path := filepath.Join(t.TempDir(), "shipping.json")
+		err := os.WriteFile(path, []byte(config), 0o600)
+		require.NoError(t, err)
+		return LoadMethods(path)
+	}
+
+	ms, err := load(t, `{"methods": [
+		{"id": "standard", "name": "Standard", "min_days": 3, "max_days": 5, "countries": ["us"], "flat": "4.99", "free_over": "50.00"},
+		{"id": "express", "weight_table": [{"max_weight": 1000, "price": "9.99"}, {"max_weight": 5000, "price": 19.99}], "volumetric_divisor": 5000}
+	]}`)
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	require.Equal(t, []string{"US"}, ms[0].Countries)
+	require.Equal(t, "express", ms[1].Name, "the name defaults to the ID")
+
+	ctx := context.Background()
+	o, err := ms.Option(ctx, "standard", &Parcel{Value: 5000}, &Destination{Country: "US"})
+	require.NoError(t, err)
+	require.Zero(t, o.Price)
+	o, err = ms.Option(ctx, "express", &Parcel{Weight: 1500}, &Destination{Country: "US"})
+	require.NoError(t, err)
+	require.Equal(t, money.Amount(1999), o.Price)
+
+	for name, config := range map[string]string{
+		"no rate":           `{"methods": [{"id": "standard"}]}`,
+		"two rates":         `{"methods": [{"id": "standard", "flat": "1", "weight_table": [{"max_weight": 1, "price": "1"}]}]}`,
+		"no id":             `{"methods": [{"flat": "1"}]}`,
+		"duplicate id":      `{"methods": [{"id": "a", "flat": "1"}, {"id": "a", "flat": "2"}]}`,
+		"unsorted brackets": `{"methods": [{"id": "a", "weight_table": [{"max_weight": 2, "price": "1"}, {"max_weight": 1, "price": "1"}]}]}`,
+		"invalid days":      `{"methods": [{"id": "a", "flat": "1", "min_days": 3, "max_days": 1}]}`,
+		"invalid price":     `{"methods": [{"id": "a", "flat": "1.999"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := load(t, config)
+			require.Error(t, err)
+		})
+	}
+}
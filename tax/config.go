@@ -0,0 +1,57 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxRate bounds the rates of a table to 100%.
+const maxRate = 10000
+
+// LoadTable reads the tax rates of the store from a JSON file such as
+//
+//	{"inclusive": false, "zones": [
+//	  {"country": "US", "region": "CA", "rates": {"standard": 725, "food": 0}},
+//	  {"country": "US", "region": "NY", "rates": {"standard": 400}},
+//	  {"country": "FR", "rates": {"standard": 2000, "books": 550}}
+//	]}
+//
+// Rates are in basis points, e.g. 725 for 7.25%.
+func LoadTable(path string) (*Table, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tax rates: %w", err)
+	}
+
+	var t Table
+	err = json.Unmarshal(b, &t)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing tax rates: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range t.Zones {
+		z := &t.Zones[i]
+		z.Country = strings.ToUpper(strings.TrimSpace(z.Country))
+		z.Region = strings.TrimSpace(z.Region)
+		if len(z.Country) != 2 {
+			return nil, fmt.Errorf("error parsing tax rates: invalid country code %q", z.Country)
+		}
+
+		key := z.Country + "/" + strings.ToUpper(z.Region)
+		if seen[key] {
+			return nil, fmt.Errorf("error parsing tax rates: duplicate zone %s", strings.TrimSuffix(key, "/"))
+		}
+		seen[key] = true
+
+		for category, r := range z.Rates {
+			if r < 0 || r > maxRate {
+				return nil, fmt.Errorf("error parsing tax rates: invalid rate %d for %s in %s", r, category, strings.TrimSuffix(key, "/"))
+			}
+		}
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,104 @@
+// Package tax computes the tax of orders from rate tables. The rate of an
+// order line depends on the tax category of its product and on the region
+// the order ships to. Prices either include the tax or have it added.
+package tax
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/niloy104/Conduit/money"
+)
+
+// DefaultCategory is the tax category of products without one, and the rate
+// zones apply to categories they have no rate for.
+const DefaultCategory = "standard"
+
+// Zone holds the rates of a country, or of a region of it if Region is set,
+// in basis points by tax category. Country is an ISO 3166-1 alpha-2 code.
+type Zone struct {
+	Country string           `json:"country"`
+	Region  string           `json:"region"`
+	Rates   map[string]int64 `json:"rates"`
+}
+
+// Table holds the tax rates of the store. Inclusive tables tax prices that
+// already include the tax; other tables add the tax to prices.
+type Table struct {
+	Inclusive bool   `json:"inclusive"`
+	Zones     []Zone `json:"zones"`
+}
+
+// Line is an order line to tax: the amount it is charged, discount taken
+// off, and the tax category of its product.
+type Line struct {
+	Amount   money.Amount
+	Category string
+}
+
+// LineTax is the tax of a line, charged at Rate basis points.
+type LineTax struct {
+	Rate int64
+	Tax  money.Amount
+}
+
+// Rate returns the rate, in basis points, of the tax category in a region of
+// a country. The zone of the region takes precedence over the zone of the
+// country, and categories neither zone has a rate for are charged the rate
+// of DefaultCategory. Countries without a zone are not taxed.
+func (t *Table) Rate(country, region, category string) int64 {
+	zones := t.zones(country, region)
+	for _, c := range []string{cmp.Or(category, DefaultCategory), DefaultCategory} {
+		for _, z := range zones {
+			if r, ok := z.Rates[c]; ok {
+				return r
+			}
+		}
+	}
+	return 0
+}
+
+// zones returns the zones of a region of a country, the zone of the region
+// first.
+func (t *Table) zones(country, region string) []*Zone {
+	var regional, national *Zone
+	for i := range t.Zones {
+		z := &t.Zones[i]
+		if !strings.EqualFold(z.Country, country) {
+			continue
+		}
+		switch {
+		case z.Region == "":
+			national = z
+		case region != "" && strings.EqualFold(z.Region, region):
+			regional = z
+		}
+	}
+
+	var zones []*Zone
+	for _, z := range []*Zone{regional, national} {
+		if z != nil {
+			zones = append(zones, z)
+		}
+	}
+	return zones
+}
+
+// Compute returns the tax of every line of an order shipping to a region of
+// a country, and their total. Each line is rounded to the cent on its own,
+// so that the total adds up on invoices.
+func (t *Table) Compute(country, region string, lines []Line) ([]LineTax, money.Amount) {
+	taxes := make([]LineTax, len(lines))
+	var total money.Amount
+	for i, l := range lines {
+		rate := t.Rate(country, region, l.Category)
+		tax := l.Amount.MulBasisPoints(rate)
+		if t.Inclusive {
+			tax = l.Amount.IncludedBasisPoints(rate)
+		}
+		taxes[i] = LineTax{Rate: rate, Tax: tax}
+		total += tax
+	}
+
+	return taxes, total
+}
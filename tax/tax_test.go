@@ -0,0 +1,92 @@
+package tax
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/niloy104/Conduit/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRate(t *testing.T) {
+	tbl := &Table{Zones: []Zone{
+		{Country: "US", Rates: map[string]int64{"standard": 500, "food": 100}},
+		{Country: "US", Region: "CA", Rates: map[string]int64{"standard": 725}},
+		{Country: "FR", Rates: map[string]int64{"standard": 2000, "books": 550}},
+	}}
+
+	tcs := []struct {
+		name     string
+		country  string
+		region   string
+		category string
+		want     int64
+	}{
+		{name: "country", country: "FR", category: "books", want: 550},
+		{name: "default category", country: "FR", want: 2000},
+		{name: "unknown category", country: "FR", category: "food", want: 2000},
+		{name: "region", country: "US", region: "CA", want: 725},
+		{name: "region case insensitive", country: "us", region: "ca", want: 725},
+		{name: "category of the country", country: "US", region: "CA", category: "food", want: 100},
+		{name: "region without zone", country: "US", region: "TX", want: 500},
+		{name: "country without zone", country: "DE", want: 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tbl.Rate(tc.country, tc.region, tc.category))
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	zones := []Zone{{Country: "FR", Rates: map[string]int64{"standard": 2000, "books": 550}}}
+	lines := []Line{{Amount: 1999}, {Amount: 1055, Category: "books"}}
+
+	exclusive := &Table{Zones: zones}
+	taxes, total := exclusive.Compute("FR", "", lines)
+	require.Equal(t, []LineTax{{Rate: 2000, Tax: 400}, {Rate: 550, Tax: 58}}, taxes)
+	require.Equal(t, money.Amount(458), total)
+
+	inclusive := &Table{Inclusive: true, Zones: zones}
+	taxes, total = inclusive.Compute("FR", "", lines)
+	require.Equal(t, []LineTax{{Rate: 2000, Tax: 333}, {Rate: 550, Tax: 55}}, taxes)
+	require.Equal(t, money.Amount(388), total)
+
+	taxes, total = inclusive.Compute("DE", "", lines)
+	require.Equal(t, []LineTax{{}, {}}, taxes)
+	require.Zero(t, total)
+}
+
+func TestLoadTable(t *testing.T) {
+	load := func(t *testing.T, config string) (*Table, error) {
+		path := filepath.Join(t.TempDir(), "tax.json")
+		err := os.WriteFile(path, []byte(config), 0o600)
+		require.NoError(t, err)
+		return LoadTable(path)
+	}
+
+	tbl, err := load(t, `{"inclusive": true, "zones": [
+		{"country": "us", "region": "CA", "rates": {"standard": 725, "food": 0}},
+		{"country": "US", "rates": {"standard": 500}}
+	]}`)
+	require.NoError(t, err)
+	require.True(t, tbl.Inclusive)
+	require.Equal(t, "US", tbl.Zones[0].Country)
+	require.Equal(t, int64(0), tbl.Rate("US", "CA", "food"))
+	require.Equal(t, int64(500), tbl.Rate("US", "", "food"))
+
+	for name, config := range map[string]string{
+		"invalid country": `{"zones": [{"country": "USA", "rates": {"standard": 500}}]}`,
+		"duplicate zone":  `{"zones": [{"country": "US", "region": "CA"}, {"country": "us", "region": "ca"}]}`,
+		"negative rate":   `{"zones": [{"country": "US", "rates": {"standard": -1}}]}`,
+		"rate over 100%":  `{"zones": [{"country": "US", "rates": {"standard": 10001}}]}`,
+		"invalid json":    `{"zones": `,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := load(t, config)
+			require.Error(t, err)
+		})
+	}
+}